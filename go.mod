@@ -12,6 +12,7 @@ require (
 	github.com/emirpasic/gods v1.9.0 // indirect
 	github.com/fatih/color v1.7.0
 	github.com/flynn/go-shlex v0.0.0-20150515145356-3f9db97f8568 // indirect
+	github.com/fsnotify/fsnotify v1.4.7
 	github.com/gliderlabs/ssh v0.2.2 // indirect
 	github.com/go-errors/errors v1.0.1
 	github.com/go-ini/ini v1.38.2 // indirect
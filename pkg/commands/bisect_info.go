@@ -0,0 +1,45 @@
+package commands
+
+import "strings"
+
+// BisectInfo : the state of a git bisect in progress. Bad is the commit marked
+// bad, if one is yet, and Current the commit checked out for you to test. Shas
+// are full length
+type BisectInfo struct {
+	Bad     string
+	Good    []string
+	Skipped []string
+	Current string
+	// Remaining is how many commits are left that could be the first bad one,
+	// not counting the bad commit itself, or -1 until there's both a good and a
+	// bad commit to count between
+	Remaining int
+}
+
+// Status returns "bad", "good", "skipped" or "current" for a commit we know
+// about, given its sha which may be abbreviated, and otherwise ""
+func (b *BisectInfo) Status(sha string) string {
+	if sha == "" {
+		return ""
+	}
+	matches := func(fullSha string) bool {
+		return strings.HasPrefix(fullSha, sha)
+	}
+	if matches(b.Bad) {
+		return "bad"
+	}
+	for _, good := range b.Good {
+		if matches(good) {
+			return "good"
+		}
+	}
+	for _, skipped := range b.Skipped {
+		if matches(skipped) {
+			return "skipped"
+		}
+	}
+	if matches(b.Current) {
+		return "current"
+	}
+	return ""
+}
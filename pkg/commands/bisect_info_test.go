@@ -0,0 +1,35 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestBisectInfoStatus is a function.
+func TestBisectInfoStatus(t *testing.T) {
+	info := &BisectInfo{
+		Bad:     "aaa111",
+		Good:    []string{"bbb111", "bbb222"},
+		Skipped: []string{"ccc111"},
+		Current: "ddd111",
+	}
+
+	type scenario struct {
+		sha      string
+		expected string
+	}
+
+	scenarios := []scenario{
+		{"aaa", "bad"},
+		{"bbb222", "good"},
+		{"ccc1", "skipped"},
+		{"ddd", "current"},
+		{"eee", ""},
+		{"", ""},
+	}
+
+	for _, s := range scenarios {
+		assert.EqualValues(t, s.expected, info.Status(s.sha))
+	}
+}
@@ -0,0 +1,27 @@
+package commands
+
+import (
+	"regexp"
+	"strings"
+)
+
+// blameHeaderRegexp matches the line `git blame --porcelain` starts each line
+// of the file with, which begins with the sha of the commit that last changed it
+var blameHeaderRegexp = regexp.MustCompile(`^([0-9a-f]{40}) \d+ \d+`)
+
+// ParseBlameShas reads the output of `git blame --porcelain`, returning the
+// sha of the commit that last changed each line, in the order of the lines
+func ParseBlameShas(output string) []string {
+	shas := []string{}
+	for _, line := range strings.Split(output, "\n") {
+		// the content of the line comes after a tab, so can't be mistaken for
+		// a header
+		if strings.HasPrefix(line, "\t") {
+			continue
+		}
+		if match := blameHeaderRegexp.FindStringSubmatch(line); match != nil {
+			shas = append(shas, match[1])
+		}
+	}
+	return shas
+}
@@ -0,0 +1,29 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParseBlameShas is a function.
+func TestParseBlameShas(t *testing.T) {
+	output := `aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa 3 3 1
+author Jesse
+summary first commit
+filename file.go
+	return 1
+bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb 7 4 2
+author Jesse
+summary second commit
+filename file.go
+	cccccccccccccccccccccccccccccccccccccccc 1 1
+bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb 8 5
+	y := 2
+`
+	assert.EqualValues(t, []string{
+		"aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+		"bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+		"bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+	}, ParseBlameShas(output))
+}
@@ -16,18 +16,43 @@ type Branch struct {
 	Pushables string
 	Pullables string
 	Selected  bool
+	// Detached is true for the HEAD when it's a commit rather than a branch,
+	// in which case Name is the commit's sha
+	Detached bool
+}
+
+// AheadBehindCounts are how many commits a branch is ahead of and behind its
+// upstream
+type AheadBehindCounts struct {
+	Pushables string
+	Pullables string
 }
 
 // GetDisplayStrings returns the display string of branch
 func (b *Branch) GetDisplayStrings(isFocused bool) []string {
-	displayName := utils.ColoredString(b.Name, b.GetColor())
-	if isFocused && b.Selected && b.Pushables != "" && b.Pullables != "" {
+	displayName := b.ColoredName()
+	// we know the counts of every branch with an upstream, but only say we
+	// don't know them for the selected one
+	if b.hasAheadBehindCounts() || (isFocused && b.Selected && b.Pushables != "" && b.Pullables != "") {
 		displayName = fmt.Sprintf("%s ↑%s↓%s", displayName, b.Pushables, b.Pullables)
 	}
 
 	return []string{b.Recency, displayName}
 }
 
+func (b *Branch) hasAheadBehindCounts() bool {
+	return b.Pushables != "" && b.Pushables != "?" && b.Pullables != "" && b.Pullables != "?"
+}
+
+// ColoredName returns the branch's name in its colour, or for a detached HEAD
+// says so the way git does
+func (b *Branch) ColoredName() string {
+	if b.Detached {
+		return utils.ColoredString(fmt.Sprintf("(HEAD detached at %s)", b.Name), color.FgYellow)
+	}
+	return utils.ColoredString(b.Name, b.GetColor())
+}
+
 // GetColor branch color
 func (b *Branch) GetColor() color.Attribute {
 	switch b.getType() {
@@ -13,8 +13,30 @@ type Commit struct {
 	DisplayString string
 	Action        string // one of "", "pick", "edit", "squash", "reword", "drop", "fixup"
 	Copied        bool   // to know if this commit is ready to be cherry-picked somewhere
+	Bisect        string // one of "", "bad", "good", "skipped" or "current"
+	Signature     string // git's one letter code for the GPG signature e.g. "G" for good, or "" if we didn't check it
+	Date          string // when it was authored or committed, formatted as configured
+	ShowDate      bool   // whether to show the date, which we do for every commit in the list or none
+	Marked        bool   // whether it's marked to be squashed, fixed up or dropped with other marked commits
 }
 
+// The ways the commits panel can show each commit's date
+const (
+	CommitDateNone     = "none"
+	CommitDateRelative = "relative"
+	CommitDateShort    = "short"
+	CommitDateFull     = "full"
+)
+
+// CommitDateFormats are the ways the commits panel can show each commit's date
+var CommitDateFormats = []string{CommitDateNone, CommitDateRelative, CommitDateShort, CommitDateFull}
+
+// The dates of a commit the commits panel can show
+const (
+	CommitDateSourceAuthor    = "author"
+	CommitDateSourceCommitter = "committer"
+)
+
 // GetDisplayStrings is a function.
 func (c *Commit) GetDisplayStrings(isFocused bool) []string {
 	red := color.New(color.FgRed)
@@ -55,5 +77,37 @@ func (c *Commit) GetDisplayStrings(isFocused bool) []string {
 		actionString = cyan.Sprint(utils.WithPadding(c.Action, 7)) + " "
 	}
 
-	return []string{shaColor.Sprint(c.Sha), actionString + white.Sprint(c.Name)}
+	bisectString := ""
+	switch c.Bisect {
+	case "bad":
+		bisectString = red.Sprint(utils.WithPadding(c.Bisect, 8))
+	case "good":
+		bisectString = green.Sprint(utils.WithPadding(c.Bisect, 8))
+	case "skipped":
+		bisectString = yellow.Sprint(utils.WithPadding(c.Bisect, 8))
+	case "current":
+		bisectString = magenta.Sprint(utils.WithPadding(c.Bisect, 8))
+	}
+
+	signatureString := ""
+	switch c.Signature {
+	case "G":
+		signatureString = green.Sprint("✓") + " "
+	case "B":
+		signatureString = red.Sprint("✗") + " "
+	case "U", "X", "Y", "R", "E":
+		// the key is untrusted, expired or revoked, or we couldn't check it
+		signatureString = yellow.Sprint("?") + " "
+	}
+
+	markedString := ""
+	if c.Marked {
+		markedString = green.Sprint("✓") + " "
+	}
+
+	description := markedString + bisectString + actionString + signatureString + white.Sprint(c.Name)
+	if c.ShowDate {
+		return []string{shaColor.Sprint(c.Sha), blue.Sprint(c.Date), description}
+	}
+	return []string{shaColor.Sprint(c.Sha), description}
 }
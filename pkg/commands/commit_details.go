@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"strings"
+)
+
+// commitDetailsFormat is the format we pass to `git log` to get a commit's
+// details, in the order ParseCommitDetails reads them
+const commitDetailsFormat = "%H%x00%an%x00%ae%x00%ad%x00%cn%x00%ce%x00%cd%x00%D%x00%G?%x00%GS%x00%GG%x00%(trailers:only,unfold)"
+
+// CommitDetails : what we know about a commit beyond its message and diff.
+// Refs are the refs pointing at it, and ContainingBranches the branches it's
+// part of. Signature is git's one letter code for the commit's GPG signature,
+// which is "N" if it isn't signed, and Verification what gpg said checking it
+type CommitDetails struct {
+	Sha                string
+	AuthorName         string
+	AuthorEmail        string
+	AuthorDate         string
+	CommitterName      string
+	CommitterEmail     string
+	CommitterDate      string
+	Refs               []string
+	ContainingBranches []string
+	Signature          string
+	Signer             string
+	Verification       string
+	Trailers           []string
+}
+
+// ParseCommitDetails reads a commit's details from the output of `git log`
+// with commitDetailsFormat, or returns nil if they aren't all there
+func ParseCommitDetails(output string) *CommitDetails {
+	fields := strings.SplitN(output, "\x00", 12)
+	if len(fields) < 12 {
+		return nil
+	}
+	details := &CommitDetails{
+		Sha:            fields[0],
+		AuthorName:     fields[1],
+		AuthorEmail:    fields[2],
+		AuthorDate:     fields[3],
+		CommitterName:  fields[4],
+		CommitterEmail: fields[5],
+		CommitterDate:  fields[6],
+		Refs:           []string{},
+		Signature:      fields[8],
+		Signer:         fields[9],
+		Verification:   strings.TrimSpace(fields[10]),
+		Trailers:       []string{},
+	}
+	for _, ref := range strings.Split(fields[7], ", ") {
+		if ref != "" {
+			details.Refs = append(details.Refs, ref)
+		}
+	}
+	for _, trailer := range strings.Split(fields[11], "\n") {
+		if trailer = strings.TrimSpace(trailer); trailer != "" {
+			details.Trailers = append(details.Trailers, trailer)
+		}
+	}
+	return details
+}
+
+// IsSigned says whether the commit has a GPG signature, whether or not it's a
+// good one
+func (d *CommitDetails) IsSigned() bool {
+	return d.Signature != "" && d.Signature != "N"
+}
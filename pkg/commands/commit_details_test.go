@@ -0,0 +1,72 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParseCommitDetails is a function.
+func TestParseCommitDetails(t *testing.T) {
+	type scenario struct {
+		testName string
+		output   string
+		expected *CommitDetails
+	}
+
+	scenarios := []scenario{
+		{
+			"a signed commit with refs and trailers",
+			"abc123\x00Jesse\x00jesse@example.com\x002020-01-02 10:00:00 +1100\x00Bot\x00bot@example.com\x002020-01-03 11:00:00 +1100\x00HEAD -> master, origin/master\x00G\x00Jesse <jesse@example.com>\x00gpg: Signature made Thu 02 Jan 2020\ngpg: Good signature from \"Jesse <jesse@example.com>\"\n\x00Co-authored-by: Sam <sam@example.com>\nSigned-off-by: Jesse <jesse@example.com>\n\n",
+			&CommitDetails{
+				Sha:            "abc123",
+				AuthorName:     "Jesse",
+				AuthorEmail:    "jesse@example.com",
+				AuthorDate:     "2020-01-02 10:00:00 +1100",
+				CommitterName:  "Bot",
+				CommitterEmail: "bot@example.com",
+				CommitterDate:  "2020-01-03 11:00:00 +1100",
+				Refs:           []string{"HEAD -> master", "origin/master"},
+				Signature:      "G",
+				Signer:         "Jesse <jesse@example.com>",
+				Verification:   "gpg: Signature made Thu 02 Jan 2020\ngpg: Good signature from \"Jesse <jesse@example.com>\"",
+				Trailers:       []string{"Co-authored-by: Sam <sam@example.com>", "Signed-off-by: Jesse <jesse@example.com>"},
+			},
+		},
+		{
+			"an unsigned commit without refs or trailers",
+			"abc123\x00Jesse\x00jesse@example.com\x00date\x00Jesse\x00jesse@example.com\x00date\x00\x00N\x00\x00\x00\n",
+			&CommitDetails{
+				Sha:            "abc123",
+				AuthorName:     "Jesse",
+				AuthorEmail:    "jesse@example.com",
+				AuthorDate:     "date",
+				CommitterName:  "Jesse",
+				CommitterEmail: "jesse@example.com",
+				CommitterDate:  "date",
+				Refs:           []string{},
+				Signature:      "N",
+				Trailers:       []string{},
+			},
+		},
+		{
+			"output that's cut short",
+			"abc123\x00Jesse",
+			nil,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			assert.EqualValues(t, s.expected, ParseCommitDetails(s.output))
+		})
+	}
+}
+
+// TestCommitDetailsIsSigned is a function.
+func TestCommitDetailsIsSigned(t *testing.T) {
+	assert.True(t, (&CommitDetails{Signature: "G"}).IsSigned())
+	assert.True(t, (&CommitDetails{Signature: "B"}).IsSigned())
+	assert.False(t, (&CommitDetails{Signature: "N"}).IsSigned())
+	assert.False(t, (&CommitDetails{}).IsSigned())
+}
@@ -5,6 +5,9 @@ type CommitFile struct {
 	Sha           string
 	Name          string
 	DisplayString string
+	// Base is the ref we're comparing Sha against when the file is one of the
+	// changes between two refs rather than a commit's, or empty
+	Base string
 }
 
 // GetDisplayStrings is a function.
@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// The kinds of problem LintCommitMessage finds
+const (
+	CommitSubjectLong        = "subjectLong"
+	CommitSubjectTooLong     = "subjectTooLong"
+	CommitBodyLineTooLong    = "bodyLineTooLong"
+	CommitTrailingWhitespace = "trailingWhitespace"
+)
+
+// CommitMessageLimits are how long the lines of a commit message can be. A
+// subject longer than SubjectWarning is long, and one longer than SubjectLimit
+// too long. A limit of 0 isn't checked
+type CommitMessageLimits struct {
+	SubjectWarning int
+	SubjectLimit   int
+	BodyLimit      int
+}
+
+// CommitMessageProblem is something about a line of a commit message that
+// goes against convention. Line counts from 1, and Length is the line's length
+type CommitMessageProblem struct {
+	Kind   string
+	Line   int
+	Length int
+}
+
+// LintCommitMessage finds the lines of the message that are too long or end in
+// whitespace. Comment lines are left out, as git strips them from the message
+func LintCommitMessage(message string, limits CommitMessageLimits) []*CommitMessageProblem {
+	problems := []*CommitMessageProblem{}
+	for i, line := range strings.Split(message, "\n") {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		length := utf8.RuneCountInString(line)
+		problem := func(kind string) {
+			problems = append(problems, &CommitMessageProblem{Kind: kind, Line: i + 1, Length: length})
+		}
+		if i == 0 {
+			if limits.SubjectLimit > 0 && length > limits.SubjectLimit {
+				problem(CommitSubjectTooLong)
+			} else if limits.SubjectWarning > 0 && length > limits.SubjectWarning {
+				problem(CommitSubjectLong)
+			}
+		} else if limits.BodyLimit > 0 && length > limits.BodyLimit {
+			problem(CommitBodyLineTooLong)
+		}
+		if strings.TrimRight(line, " \t") != line {
+			problem(CommitTrailingWhitespace)
+		}
+	}
+	return problems
+}
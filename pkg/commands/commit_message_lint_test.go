@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLintCommitMessage is a function.
+func TestLintCommitMessage(t *testing.T) {
+	limits := CommitMessageLimits{SubjectWarning: 10, SubjectLimit: 20, BodyLimit: 15}
+
+	type scenario struct {
+		testName string
+		message  string
+		limits   CommitMessageLimits
+		expected []*CommitMessageProblem
+	}
+
+	scenarios := []scenario{
+		{
+			"Nothing wrong",
+			"short\n\nshort body",
+			limits,
+			[]*CommitMessageProblem{},
+		},
+		{
+			"Long subject",
+			"a bit long subject",
+			limits,
+			[]*CommitMessageProblem{{Kind: CommitSubjectLong, Line: 1, Length: 18}},
+		},
+		{
+			"Too long subject",
+			strings.Repeat("x", 21),
+			limits,
+			[]*CommitMessageProblem{{Kind: CommitSubjectTooLong, Line: 1, Length: 21}},
+		},
+		{
+			"Long body line and trailing whitespace",
+			"short\n\nthis line is too long\nfine \n",
+			limits,
+			[]*CommitMessageProblem{
+				{Kind: CommitBodyLineTooLong, Line: 3, Length: 21},
+				{Kind: CommitTrailingWhitespace, Line: 4, Length: 5},
+			},
+		},
+		{
+			"Lengths are counted in characters",
+			"ünïcödé sü",
+			limits,
+			[]*CommitMessageProblem{},
+		},
+		{
+			"Comments are left out",
+			"short\n# this comment is much longer than the limit ",
+			limits,
+			[]*CommitMessageProblem{},
+		},
+		{
+			"Limits of 0 aren't checked",
+			strings.Repeat("x", 100) + "\n\n" + strings.Repeat("y", 100),
+			CommitMessageLimits{},
+			[]*CommitMessageProblem{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			assert.EqualValues(t, s.expected, LintCommitMessage(s.message, s.limits))
+		})
+	}
+}
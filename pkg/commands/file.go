@@ -1,6 +1,10 @@
 package commands
 
-import "github.com/fatih/color"
+import (
+	"strings"
+
+	"github.com/fatih/color"
+)
 
 // File : A file from git status
 // duplicating this for now
@@ -15,24 +19,77 @@ type File struct {
 	DisplayString           string
 	Type                    string // one of 'file', 'directory', and 'other'
 	ShortStatus             string // e.g. 'AD', ' A', 'M ', '??'
+	// SkipWorktree and AssumeUnchanged are set with `git update-index`, and
+	// tell git to ignore changes to the file
+	SkipWorktree    bool
+	AssumeUnchanged bool
+	// IsSubmodule is true for a submodule, whose changes are to the commit it
+	// points at rather than to a file's contents
+	IsSubmodule bool
+	// IsLFS is true if the file's content is stored with git LFS
+	IsLFS bool
+	// Additions and Deletions are how many lines have changed since HEAD
+	Additions int
+	Deletions int
 }
 
 // GetDisplayStrings returns the display string of a file
 func (f *File) GetDisplayStrings(isFocused bool) []string {
+	return []string{f.displayString(f.Name)}
+}
+
+// CurrentPath returns where the file is now, which for a renamed file is the
+// path after the arrow
+func (f *File) CurrentPath() string {
+	split := strings.Split(f.Name, " -> ")
+	return split[len(split)-1]
+}
+
+// displayString returns the file's status followed by the given name, which
+// in the tree view leaves out the file's directory, and how many lines have
+// changed
+func (f *File) displayString(name string) string {
+	output := f.statusAndName(name)
+	if f.IsLFS {
+		output += " " + color.New(color.FgCyan).Sprint("LFS")
+	}
+	return output + FormatLineStats(f.Additions, f.Deletions)
+}
+
+func (f *File) statusAndName(name string) string {
 	// potentially inefficient to be instantiating these color
 	// objects with each render
 	red := color.New(color.FgRed)
 	green := color.New(color.FgGreen)
+	if f.SkipWorktree || f.AssumeUnchanged {
+		return color.New(color.FgCyan).Sprint(f.DisplayString[0:3] + name)
+	}
+	if f.HasMergeConflicts {
+		// conflicts have to be resolved before anything else, so they stand out
+		return color.New(color.FgRed, color.Bold).Sprint(f.DisplayString[0:3] + name + " ✗")
+	}
 	if !f.Tracked && !f.HasStagedChanges {
-		return []string{red.Sprint(f.DisplayString)}
+		return red.Sprint(f.DisplayString[0:3] + name)
 	}
 
 	output := green.Sprint(f.DisplayString[0:1])
 	output += red.Sprint(f.DisplayString[1:3])
-	if f.HasUnstagedChanges {
-		output += red.Sprint(f.Name)
+	if f.IsSubmodule {
+		// a submodule looks like a modified file otherwise
+		output += color.New(color.FgMagenta).Sprint(name + " ⧉")
+	} else if f.HasUnstagedChanges {
+		output += red.Sprint(name)
 	} else {
-		output += green.Sprint(f.Name)
+		output += green.Sprint(name)
+	}
+	return output
+}
+
+// FormatLineStats shows how many lines have been added and deleted like
+// " +3 -1", or nothing if no lines have changed
+func FormatLineStats(additions int, deletions int) string {
+	if additions == 0 && deletions == 0 {
+		return ""
 	}
-	return []string{output}
+	return " " + color.New(color.FgGreen).Sprintf("+%d", additions) + " " + color.New(color.FgRed).Sprintf("-%d", deletions)
 }
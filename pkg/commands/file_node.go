@@ -0,0 +1,202 @@
+package commands
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// FileNode is a line of the files panel: either a file or, if the files are
+// shown as a tree, a directory containing files
+type FileNode struct {
+	// Name is what we show for the node e.g. the file's name without its
+	// directory in the tree view
+	Name string
+	// Path is the path of the file or directory
+	Path string
+	// File is nil if the node is a directory
+	File *File
+	// Files holds every file in the directory, including those in its
+	// subdirectories
+	Files     []*File
+	Depth     int
+	Collapsed bool
+	// Marked is true if the file, or every file in the directory, has been
+	// marked for acting on several files at once
+	Marked bool
+}
+
+// IsDirectory returns true if the node is a directory
+func (n *FileNode) IsDirectory() bool {
+	return n.File == nil
+}
+
+// GetDisplayStrings returns the display string of a file node
+func (n *FileNode) GetDisplayStrings(isFocused bool) []string {
+	indent := strings.Repeat("  ", n.Depth)
+	if n.Marked {
+		indent += color.New(color.FgGreen).Sprint("✓ ")
+	}
+	if !n.IsDirectory() {
+		return []string{indent + n.File.displayString(n.Name)}
+	}
+
+	arrow := "▼"
+	if n.Collapsed {
+		arrow = "▶"
+	}
+	// like a file, a directory is green once there's nothing left to stage in it
+	colour := color.New(color.FgGreen)
+	if n.HasUnstagedChanges() {
+		colour = color.New(color.FgRed)
+	}
+	additions, deletions := n.LineStats()
+	return []string{indent + arrow + " " + colour.Sprint(n.Name+"/") + FormatLineStats(additions, deletions)}
+}
+
+// LineStats returns how many lines have been added and deleted in the file,
+// or in all the files in the directory
+func (n *FileNode) LineStats() (int, int) {
+	additions, deletions := 0, 0
+	for _, file := range n.GetFiles() {
+		additions += file.Additions
+		deletions += file.Deletions
+	}
+	return additions, deletions
+}
+
+// GetFiles returns the file, or every file in the directory
+func (n *FileNode) GetFiles() []*File {
+	if n.IsDirectory() {
+		return n.Files
+	}
+	return []*File{n.File}
+}
+
+// HasUnstagedChanges returns true if the file, or any file in the directory,
+// has unstaged changes. This includes being untracked
+func (n *FileNode) HasUnstagedChanges() bool {
+	for _, file := range n.GetFiles() {
+		if file.HasUnstagedChanges {
+			return true
+		}
+	}
+	return false
+}
+
+// HasStagedChanges returns true if the file, or any file in the directory, has
+// staged changes
+func (n *FileNode) HasStagedChanges() bool {
+	for _, file := range n.GetFiles() {
+		if file.HasStagedChanges {
+			return true
+		}
+	}
+	return false
+}
+
+// HasMergeConflicts returns true if the file, or any file in the directory, has
+// merge conflicts
+func (n *FileNode) HasMergeConflicts() bool {
+	for _, file := range n.GetFiles() {
+		if file.HasMergeConflicts {
+			return true
+		}
+	}
+	return false
+}
+
+// BuildFlatFileList returns a node for each file, in the order given
+func BuildFlatFileList(files []*File) []*FileNode {
+	nodes := make([]*FileNode, len(files))
+	for i, file := range files {
+		nodes[i] = &FileNode{Name: file.Name, Path: file.Name, File: file}
+	}
+	return nodes
+}
+
+// fileTreeDir is a directory in the tree we build the file nodes from
+type fileTreeDir struct {
+	path  string
+	dirs  map[string]*fileTreeDir
+	files []*File
+}
+
+// BuildFileTree returns the nodes of the files grouped into their directories,
+// with each directory's subdirectories, in alphabetical order, before its
+// files, in the order given. A directory holding nothing but another directory
+// is merged with it e.g. `pkg/commands`, so that deep package structures don't
+// take up a line per level. The contents of collapsed directories are left out
+func BuildFileTree(files []*File, collapsedPaths map[string]bool) []*FileNode {
+	root := &fileTreeDir{dirs: map[string]*fileTreeDir{}}
+	for _, file := range files {
+		dir := root
+		for _, name := range strings.Split(path.Dir(file.CurrentPath()), "/") {
+			if name == "." {
+				break
+			}
+			if dir.dirs[name] == nil {
+				dir.dirs[name] = &fileTreeDir{path: path.Join(dir.path, name), dirs: map[string]*fileTreeDir{}}
+			}
+			dir = dir.dirs[name]
+		}
+		dir.files = append(dir.files, file)
+	}
+
+	return root.nodes(0, collapsedPaths)
+}
+
+// nodes returns the nodes of the directory's contents
+func (d *fileTreeDir) nodes(depth int, collapsedPaths map[string]bool) []*FileNode {
+	nodes := []*FileNode{}
+
+	names := make([]string, 0, len(d.dirs))
+	for name := range d.dirs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		dir := d.dirs[name]
+		for len(dir.files) == 0 && len(dir.dirs) == 1 {
+			for childName, child := range dir.dirs {
+				name += "/" + childName
+				dir = child
+			}
+		}
+
+		node := &FileNode{
+			Name:      name,
+			Path:      dir.path,
+			Files:     dir.allFiles(),
+			Depth:     depth,
+			Collapsed: collapsedPaths[dir.path],
+		}
+		nodes = append(nodes, node)
+		if !node.Collapsed {
+			nodes = append(nodes, dir.nodes(depth+1, collapsedPaths)...)
+		}
+	}
+
+	for _, file := range d.files {
+		name := path.Base(file.CurrentPath())
+		if file.CurrentPath() != file.Name {
+			// we keep the arrow for a renamed file so you can see where it came from
+			name = file.Name
+		}
+		nodes = append(nodes, &FileNode{Name: name, Path: file.Name, File: file, Depth: depth})
+	}
+
+	return nodes
+}
+
+// allFiles returns the files in the directory and its subdirectories
+func (d *fileTreeDir) allFiles() []*File {
+	files := append([]*File{}, d.files...)
+	for _, dir := range d.dirs {
+		files = append(files, dir.allFiles()...)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].CurrentPath() < files[j].CurrentPath() })
+	return files
+}
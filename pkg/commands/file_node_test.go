@@ -0,0 +1,182 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestBuildFileTree is a function.
+func TestBuildFileTree(t *testing.T) {
+	type node struct {
+		name  string
+		path  string
+		depth int
+	}
+
+	type scenario struct {
+		testName       string
+		fileNames      []string
+		collapsedPaths map[string]bool
+		expected       []node
+	}
+
+	scenarios := []scenario{
+		{
+			"No files",
+			[]string{},
+			map[string]bool{},
+			[]node{},
+		},
+		{
+			"Directories before files",
+			[]string{"b.txt", "dir/a.txt", "a.txt"},
+			map[string]bool{},
+			[]node{
+				{"dir", "dir", 0},
+				{"a.txt", "dir/a.txt", 1},
+				{"b.txt", "b.txt", 0},
+				{"a.txt", "a.txt", 0},
+			},
+		},
+		{
+			"Directories holding a single directory merged",
+			[]string{"pkg/commands/git.go", "pkg/gui/gui.go", "pkg/gui/view/view.go", "vendor/a/b/c.go"},
+			map[string]bool{},
+			[]node{
+				{"pkg", "pkg", 0},
+				{"commands", "pkg/commands", 1},
+				{"git.go", "pkg/commands/git.go", 2},
+				{"gui", "pkg/gui", 1},
+				{"view", "pkg/gui/view", 2},
+				{"view.go", "pkg/gui/view/view.go", 3},
+				{"gui.go", "pkg/gui/gui.go", 2},
+				{"vendor/a/b", "vendor/a/b", 0},
+				{"c.go", "vendor/a/b/c.go", 1},
+			},
+		},
+		{
+			"Contents of collapsed directories left out",
+			[]string{"pkg/commands/git.go", "pkg/gui/gui.go", "vendor/a/b/c.go"},
+			map[string]bool{"pkg/gui": true, "vendor/a/b": true},
+			[]node{
+				{"pkg", "pkg", 0},
+				{"commands", "pkg/commands", 1},
+				{"git.go", "pkg/commands/git.go", 2},
+				{"gui", "pkg/gui", 1},
+				{"vendor/a/b", "vendor/a/b", 0},
+			},
+		},
+		{
+			"Renamed file placed where it is now",
+			[]string{"a.txt -> dir/b.txt", "dir/c.txt"},
+			map[string]bool{},
+			[]node{
+				{"dir", "dir", 0},
+				{"a.txt -> dir/b.txt", "a.txt -> dir/b.txt", 1},
+				{"c.txt", "dir/c.txt", 1},
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			files := []*File{}
+			for _, name := range s.fileNames {
+				files = append(files, &File{Name: name})
+			}
+
+			nodes := []node{}
+			for _, n := range BuildFileTree(files, s.collapsedPaths) {
+				nodes = append(nodes, node{n.Name, n.Path, n.Depth})
+			}
+			assert.EqualValues(t, s.expected, nodes)
+		})
+	}
+}
+
+// TestBuildFileTreeDirectoryFiles is a function.
+func TestBuildFileTreeDirectoryFiles(t *testing.T) {
+	files := []*File{{Name: "pkg/b/c.go"}, {Name: "pkg/a.go"}, {Name: "d.go"}}
+	nodes := BuildFileTree(files, map[string]bool{"pkg": true})
+
+	assert.Len(t, nodes, 2)
+	assert.True(t, nodes[0].IsDirectory())
+	assert.True(t, nodes[0].Collapsed)
+	assert.EqualValues(t, []*File{files[1], files[0]}, nodes[0].Files)
+	assert.False(t, nodes[1].IsDirectory())
+	assert.EqualValues(t, files[2], nodes[1].File)
+}
+
+// TestBuildFlatFileList is a function.
+func TestBuildFlatFileList(t *testing.T) {
+	files := []*File{{Name: "z.txt"}, {Name: "dir/a.txt"}}
+	nodes := BuildFlatFileList(files)
+
+	assert.Len(t, nodes, 2)
+	for i, node := range nodes {
+		assert.EqualValues(t, files[i].Name, node.Name)
+		assert.EqualValues(t, files[i], node.File)
+		assert.EqualValues(t, 0, node.Depth)
+	}
+}
+
+// TestFileNodeChanges is a function.
+func TestFileNodeChanges(t *testing.T) {
+	type scenario struct {
+		testName                   string
+		files                      []*File
+		expectedHasUnstagedChanges bool
+		expectedHasStagedChanges   bool
+		expectedHasMergeConflicts  bool
+	}
+
+	scenarios := []scenario{
+		{
+			"Only staged changes",
+			[]*File{{Name: "dir/a", HasStagedChanges: true}, {Name: "dir/b", HasStagedChanges: true}},
+			false,
+			true,
+			false,
+		},
+		{
+			"An untracked file",
+			[]*File{{Name: "dir/a", HasStagedChanges: true}, {Name: "dir/b", HasUnstagedChanges: true}},
+			true,
+			true,
+			false,
+		},
+		{
+			"A file with merge conflicts",
+			[]*File{{Name: "dir/a", HasUnstagedChanges: true, HasMergeConflicts: true}},
+			true,
+			false,
+			true,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			node := BuildFileTree(s.files, map[string]bool{})[0]
+			assert.True(t, node.IsDirectory())
+			assert.EqualValues(t, s.files, node.GetFiles())
+			assert.EqualValues(t, s.expectedHasUnstagedChanges, node.HasUnstagedChanges())
+			assert.EqualValues(t, s.expectedHasStagedChanges, node.HasStagedChanges())
+			assert.EqualValues(t, s.expectedHasMergeConflicts, node.HasMergeConflicts())
+		})
+	}
+}
+
+// TestFileNodeLineStats is a function.
+func TestFileNodeLineStats(t *testing.T) {
+	files := []*File{
+		{Name: "dir/a.txt", Additions: 3, Deletions: 1},
+		{Name: "dir/b.txt", Additions: 10},
+	}
+
+	additions, deletions := (&FileNode{File: files[0]}).LineStats()
+	assert.EqualValues(t, []int{3, 1}, []int{additions, deletions})
+
+	additions, deletions = (&FileNode{Files: files}).LineStats()
+	assert.EqualValues(t, []int{13, 1}, []int{additions, deletions})
+}
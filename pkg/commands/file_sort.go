@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"os"
+	"sort"
+	"time"
+)
+
+// The orders the files panel can be sorted in
+const (
+	SortFilesByPath     = "path"
+	SortFilesByStatus   = "status"
+	SortFilesByModified = "modified"
+)
+
+// FileSortOrders are the orders the files panel can be sorted in
+var FileSortOrders = []string{SortFilesByPath, SortFilesByStatus, SortFilesByModified}
+
+// SortFiles returns the files sorted in the given order, which is one of
+// FileSortOrders. Files that are otherwise equal are sorted by path
+func SortFiles(files []*File, order string) []*File {
+	return sortFiles(files, order, os.Stat)
+}
+
+func sortFiles(files []*File, order string, stat func(string) (os.FileInfo, error)) []*File {
+	sorted := append([]*File{}, files...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].CurrentPath() < sorted[j].CurrentPath() })
+
+	switch order {
+	case SortFilesByStatus:
+		sort.SliceStable(sorted, func(i, j int) bool { return statusRank(sorted[i]) < statusRank(sorted[j]) })
+	case SortFilesByModified:
+		modTimes := map[*File]time.Time{}
+		for _, file := range sorted {
+			// a deleted file has no modification time, so it goes last
+			if info, err := stat(file.CurrentPath()); err == nil {
+				modTimes[file] = info.ModTime()
+			}
+		}
+		sort.SliceStable(sorted, func(i, j int) bool { return modTimes[sorted[i]].After(modTimes[sorted[j]]) })
+	}
+
+	return sorted
+}
+
+// statusRank puts the files that most need attention first: merge conflicts,
+// then unstaged changes, then staged changes, then untracked files, and last
+// of all files whose changes git has been told to ignore
+func statusRank(file *File) int {
+	switch {
+	case file.HasMergeConflicts:
+		return 0
+	case file.SkipWorktree || file.AssumeUnchanged:
+		return 4
+	case !file.Tracked && !file.HasStagedChanges:
+		return 3
+	case file.HasUnstagedChanges:
+		return 1
+	default:
+		return 2
+	}
+}
@@ -0,0 +1,76 @@
+package commands
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-errors/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSortFiles is a function.
+func TestSortFiles(t *testing.T) {
+	files := []*File{
+		{Name: "untracked.txt", HasUnstagedChanges: true},
+		{Name: "b/staged.txt", Tracked: true, HasStagedChanges: true},
+		{Name: "skipped.txt", Tracked: true, SkipWorktree: true},
+		{Name: "a/unstaged.txt", Tracked: true, HasUnstagedChanges: true},
+		{Name: "conflicted.txt", Tracked: true, HasUnstagedChanges: true, HasMergeConflicts: true},
+		{Name: "deleted.txt", Tracked: true, HasUnstagedChanges: true, Deleted: true},
+	}
+
+	modTimes := map[string]time.Time{
+		"untracked.txt":  time.Unix(3, 0),
+		"b/staged.txt":   time.Unix(1, 0),
+		"skipped.txt":    time.Unix(1, 0),
+		"a/unstaged.txt": time.Unix(5, 0),
+		"conflicted.txt": time.Unix(2, 0),
+	}
+	stat := func(path string) (os.FileInfo, error) {
+		modTime, ok := modTimes[path]
+		if !ok {
+			return nil, errors.New("no such file")
+		}
+		return fileInfoMock{name: path, fileModTime: modTime}, nil
+	}
+
+	type scenario struct {
+		testName string
+		order    string
+		expected []string
+	}
+
+	scenarios := []scenario{
+		{
+			"By path",
+			SortFilesByPath,
+			[]string{"a/unstaged.txt", "b/staged.txt", "conflicted.txt", "deleted.txt", "skipped.txt", "untracked.txt"},
+		},
+		{
+			"By status",
+			SortFilesByStatus,
+			[]string{"conflicted.txt", "a/unstaged.txt", "deleted.txt", "b/staged.txt", "untracked.txt", "skipped.txt"},
+		},
+		{
+			"By modification time",
+			SortFilesByModified,
+			[]string{"a/unstaged.txt", "untracked.txt", "conflicted.txt", "b/staged.txt", "skipped.txt", "deleted.txt"},
+		},
+		{
+			"Unknown order sorted by path",
+			"size",
+			[]string{"a/unstaged.txt", "b/staged.txt", "conflicted.txt", "deleted.txt", "skipped.txt", "untracked.txt"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			names := []string{}
+			for _, file := range sortFiles(files, s.order, stat) {
+				names = append(names, file.Name)
+			}
+			assert.EqualValues(t, s.expected, names)
+		})
+	}
+}
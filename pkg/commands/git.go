@@ -1,11 +1,17 @@
 package commands
 
 import (
+	"bytes"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/mgutz/str"
 
@@ -73,6 +79,12 @@ type GitCommand struct {
 	getLocalGitConfig  func(string) (string, error)
 	removeFile         func(string) error
 	DotGitDir          string
+
+	// the skip-worktree and assume-unchanged files, which we only look for
+	// again once the index they came from has changed
+	ignoredChangesMutex sync.Mutex
+	ignoredChangesFiles []*File
+	ignoredChangesIndex os.FileInfo
 }
 
 // NewGitCommand it runs git commands
@@ -158,6 +170,33 @@ func stashEntryFromLine(line string, index int) *StashEntry {
 	}
 }
 
+// GetReflogEntries returns the entries of HEAD's reflog, newest first
+func (c *GitCommand) GetReflogEntries() ([]*ReflogEntry, error) {
+	output, err := c.OSCommand.RunCommandWithOutput(`git reflog --abbrev-commit --format="%h %gd %gs"`)
+	if err != nil {
+		return nil, err
+	}
+	entries := []*ReflogEntry{}
+	for _, line := range utils.SplitLines(output) {
+		if entry := reflogEntryFromLine(line); entry != nil {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func reflogEntryFromLine(line string) *ReflogEntry {
+	fields := strings.SplitN(line, " ", 3)
+	if len(fields) < 2 {
+		return nil
+	}
+	entry := &ReflogEntry{Sha: fields[0], Selector: fields[1]}
+	if len(fields) == 3 {
+		entry.Message = fields[2]
+	}
+	return entry
+}
+
 // GetStashEntryDiff stash diff
 func (c *GitCommand) GetStashEntryDiff(index int) (string, error) {
 	return c.OSCommand.RunCommandWithOutput("git stash show -p --color stash@{" + fmt.Sprint(index) + "}")
@@ -167,6 +206,8 @@ func (c *GitCommand) GetStashEntryDiff(index int) (string, error) {
 func (c *GitCommand) GetStatusFiles() []*File {
 	statusOutput, _ := c.GitStatus()
 	statusStrings := utils.SplitLines(statusOutput)
+	submodulePaths := c.getSubmodulePaths()
+	lineStats := c.getLineStats()
 	files := []*File{}
 
 	for _, statusString := range statusStrings {
@@ -176,7 +217,7 @@ func (c *GitCommand) GetStatusFiles() []*File {
 		filename := c.OSCommand.Unquote(statusString[3:])
 		_, untracked := map[string]bool{"??": true, "A ": true, "AM": true}[change]
 		_, hasNoStagedChanges := map[string]bool{" ": true, "U": true, "?": true}[stagedChange]
-		hasMergeConflicts := change == "UU" || change == "AA" || change == "DU"
+		_, hasMergeConflicts := mergeConflictStatuses[change]
 		hasInlineMergeConflicts := change == "UU" || change == "AA"
 
 		file := &File{
@@ -190,23 +231,369 @@ func (c *GitCommand) GetStatusFiles() []*File {
 			HasInlineMergeConflicts: hasInlineMergeConflicts,
 			Type:                    c.OSCommand.FileType(filename),
 			ShortStatus:             change,
+			IsSubmodule:             submodulePaths[filename],
+		}
+		if stats, ok := lineStats[file.CurrentPath()]; ok {
+			file.Additions, file.Deletions = stats.additions, stats.deletions
+		} else if !file.Tracked && !file.HasStagedChanges && file.Type == "file" {
+			file.Additions = countLines(filename)
 		}
 		files = append(files, file)
 	}
+
+	lfsPaths := c.getLFSPaths(files)
+	for _, file := range files {
+		file.IsLFS = lfsPaths[file.CurrentPath()]
+	}
+
+	// git leaves files it's been told to ignore changes to out of the status,
+	// but we show them so that you can see which they are and undo it
+	fileNames := map[string]bool{}
+	for _, file := range files {
+		fileNames[file.Name] = true
+	}
+	for _, file := range c.getIgnoredChangesFiles() {
+		if !fileNames[file.Name] {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+type lineStats struct {
+	additions int
+	deletions int
+}
+
+// getLineStats returns how many lines have been added to and deleted from each
+// changed file since HEAD, by its current path. Binary files are left out
+func (c *GitCommand) getLineStats() map[string]lineStats {
+	output, err := c.OSCommand.RunCommandWithOutput("git diff --numstat -z HEAD")
+	if err != nil {
+		// there's no HEAD before the first commit, so everything staged is new
+		output, err = c.OSCommand.RunCommandWithOutput("git diff --numstat -z --cached")
+		if err != nil {
+			return map[string]lineStats{}
+		}
+	}
+	return parseNumstat(output)
+}
+
+// parseNumstat parses the output of `git diff --numstat -z`. Each file is like
+// "<additions>\t<deletions>\t<path>\0", except that a renamed file has an
+// empty path followed by "<old path>\0<new path>\0"
+func parseNumstat(output string) map[string]lineStats {
+	stats := map[string]lineStats{}
+	fields := strings.Split(output, "\x00")
+	for i := 0; i < len(fields); i++ {
+		split := strings.SplitN(fields[i], "\t", 3)
+		if len(split) != 3 {
+			continue
+		}
+		path := split[2]
+		if path == "" && i+2 < len(fields) {
+			path = fields[i+2]
+			i += 2
+		}
+		additions, addErr := strconv.Atoi(split[0])
+		deletions, delErr := strconv.Atoi(split[1])
+		// binary files have '-' for both
+		if addErr != nil || delErr != nil {
+			continue
+		}
+		stats[path] = lineStats{additions: additions, deletions: deletions}
+	}
+	return stats
+}
+
+// countLines returns how many lines an untracked file has, all of which count
+// as additions. It's 0 for binary files and those too big to diff
+func countLines(path string) int {
+	info, err := os.Stat(path)
+	if err != nil || info.Size() > maxUntrackedFileDiffSize {
+		return 0
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil || bytes.IndexByte(content, 0) != -1 {
+		return 0
+	}
+	count := bytes.Count(content, []byte("\n"))
+	if len(content) > 0 && content[len(content)-1] != '\n' {
+		count++
+	}
+	return count
+}
+
+// mergeConflictStatuses are the statuses of files with merge conflicts, which
+// are described in `git status --help`. Only when both sides have changed
+// the file (UU and AA) are there conflict markers in it
+var mergeConflictStatuses = map[string]string{
+	"DD": "deletedByBoth",
+	"AU": "addedByUs",
+	"UD": "deletedByThem",
+	"UA": "addedByThem",
+	"DU": "deletedByUs",
+	"AA": "addedByBoth",
+	"UU": "modifiedByBoth",
+}
+
+// DescribeMergeConflict says how the file's merge conflict came about e.g.
+// that it was deleted by us but modified by them
+func (c *GitCommand) DescribeMergeConflict(file *File) string {
+	return c.Tr.SLocalize(mergeConflictStatuses[file.ShortStatus])
+}
+
+// RemoveConflictedFile resolves a merge conflict by removing the file
+func (c *GitCommand) RemoveConflictedFile(fileName string) error {
+	return c.OSCommand.RunCommand(fmt.Sprintf("git rm -- %s", c.OSCommand.Quote(fileName)))
+}
+
+// getIgnoredChangesFiles returns the files marked skip-worktree or
+// assume-unchanged. Listing them goes through the whole index, so we only do
+// that again when the index has changed since last time
+func (c *GitCommand) getIgnoredChangesFiles() []*File {
+	c.ignoredChangesMutex.Lock()
+	defer c.ignoredChangesMutex.Unlock()
+
+	index, err := os.Stat(filepath.Join(c.DotGitDir, "index"))
+	if err != nil {
+		return c.loadIgnoredChangesFiles()
+	}
+	previous := c.ignoredChangesIndex
+	if previous == nil || !index.ModTime().Equal(previous.ModTime()) || index.Size() != previous.Size() {
+		c.ignoredChangesFiles = c.loadIgnoredChangesFiles()
+		c.ignoredChangesIndex = index
+	}
+
+	// the files are handed out to be changed, so each caller gets its own
+	files := []*File{}
+	for _, file := range c.ignoredChangesFiles {
+		fileCopy := *file
+		files = append(files, &fileCopy)
+	}
+	return files
+}
+
+// loadIgnoredChangesFiles lists the files `git ls-files -v` tags with an S,
+// for skip-worktree, or a lowercase letter, for assume-unchanged. Their tag
+// takes the place of the status in the display string
+func (c *GitCommand) loadIgnoredChangesFiles() []*File {
+	output, err := c.OSCommand.RunCommandWithOutput("git ls-files -v")
+	if err != nil {
+		return []*File{}
+	}
+
+	files := []*File{}
+	for _, line := range utils.SplitLines(output) {
+		if len(line) < 3 {
+			continue
+		}
+		tag := line[0:1]
+		skipWorktree := strings.ToUpper(tag) == "S"
+		assumeUnchanged := strings.ToLower(tag) == tag && strings.ToUpper(tag) != tag
+		if !skipWorktree && !assumeUnchanged {
+			continue
+		}
+
+		filename := c.OSCommand.Unquote(line[2:])
+		files = append(files, &File{
+			Name:            filename,
+			DisplayString:   tag + "  " + filename,
+			Tracked:         true,
+			Type:            c.OSCommand.FileType(filename),
+			ShortStatus:     tag + " ",
+			SkipWorktree:    skipWorktree,
+			AssumeUnchanged: assumeUnchanged,
+		})
+	}
 	return files
 }
 
+// GetIgnoredDirectories returns the directories that git ignores, which
+// needn't be watched for changes
+func (c *GitCommand) GetIgnoredDirectories() []string {
+	output, err := c.OSCommand.RunCommandWithOutput("git ls-files --others --ignored --exclude-standard --directory")
+	if err != nil {
+		return []string{}
+	}
+	directories := []string{}
+	for _, line := range utils.SplitLines(output) {
+		// ignored files are listed too, but directories end in a slash
+		path := c.OSCommand.Unquote(line)
+		if strings.HasSuffix(path, "/") {
+			directories = append(directories, strings.TrimSuffix(path, "/"))
+		}
+	}
+	return directories
+}
+
+// GetIgnoredPaths returns which of the given paths git ignores
+func (c *GitCommand) GetIgnoredPaths(paths []string) []string {
+	quotedPaths := []string{}
+	for _, path := range paths {
+		quotedPaths = append(quotedPaths, c.OSCommand.Quote(path))
+	}
+	// this fails when none of the paths are ignored
+	output, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git check-ignore -- %s", strings.Join(quotedPaths, " ")))
+	if err != nil {
+		return []string{}
+	}
+	ignoredPaths := []string{}
+	for _, line := range utils.SplitLines(output) {
+		ignoredPaths = append(ignoredPaths, c.OSCommand.Unquote(line))
+	}
+	return ignoredPaths
+}
+
+// getLFSPaths returns which of the files git LFS tracks. We only ask git if
+// the repo's attributes mention LFS at all
+func (c *GitCommand) getLFSPaths(files []*File) map[string]bool {
+	paths := map[string]bool{}
+	if len(files) == 0 || !c.usesLFS() {
+		return paths
+	}
+	quotedPaths := []string{}
+	for _, file := range files {
+		quotedPaths = append(quotedPaths, c.OSCommand.Quote(file.CurrentPath()))
+	}
+	output, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git check-attr -z filter -- %s", strings.Join(quotedPaths, " ")))
+	if err != nil {
+		return paths
+	}
+	// each file is like '<path>\0filter\0<value>\0'
+	fields := strings.Split(output, "\x00")
+	for i := 0; i+2 < len(fields); i += 3 {
+		if fields[i+2] == "lfs" {
+			paths[fields[i]] = true
+		}
+	}
+	return paths
+}
+
+// usesLFS says whether the repo's attributes send any files through git LFS
+func (c *GitCommand) usesLFS() bool {
+	for _, path := range []string{".gitattributes", filepath.Join(c.DotGitDir, "info", "attributes")} {
+		if content, err := ioutil.ReadFile(path); err == nil && strings.Contains(string(content), "filter=lfs") {
+			return true
+		}
+	}
+	return false
+}
+
+// LockLFSFile locks a file with git LFS, so that nobody else can push changes
+// to it until it's unlocked
+func (c *GitCommand) LockLFSFile(fileName string) error {
+	return c.OSCommand.RunCommand(fmt.Sprintf("git lfs lock %s", c.OSCommand.Quote(fileName)))
+}
+
+// UnlockLFSFile unlocks a file locked with LockLFSFile
+func (c *GitCommand) UnlockLFSFile(fileName string) error {
+	return c.OSCommand.RunCommand(fmt.Sprintf("git lfs unlock %s", c.OSCommand.Quote(fileName)))
+}
+
+// getSubmodulePaths returns the paths of the submodules listed in .gitmodules
+func (c *GitCommand) getSubmodulePaths() map[string]bool {
+	paths := map[string]bool{}
+	if exists, _ := c.OSCommand.FileExists(".gitmodules"); !exists {
+		return paths
+	}
+	output, err := c.OSCommand.RunCommandWithOutput(`git config --file .gitmodules --get-regexp "^submodule\..*\.path$"`)
+	if err != nil {
+		return paths
+	}
+	for _, line := range utils.SplitLines(output) {
+		// each line is like 'submodule.<name>.path <path>'
+		split := strings.SplitN(line, " ", 2)
+		if len(split) == 2 {
+			paths[split[1]] = true
+		}
+	}
+	return paths
+}
+
+// GetSubmoduleStatus compares the commit checked out in a submodule with the
+// one recorded for it in HEAD
+func (c *GitCommand) GetSubmoduleStatus(path string) *SubmoduleStatus {
+	quotedPath := c.OSCommand.Quote(path)
+	// these fail for a submodule that's new or not initialised, in which case
+	// we leave the sha empty
+	recordedSha, _ := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git rev-parse %s", c.OSCommand.Quote("HEAD:"+path)))
+	checkedOutSha, _ := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git -C %s rev-parse HEAD", quotedPath))
+	status, _ := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git -C %s status --porcelain", quotedPath))
+	return &SubmoduleStatus{
+		RecordedSha:   strings.TrimSpace(recordedSha),
+		CheckedOutSha: strings.TrimSpace(checkedOutSha),
+		Dirty:         strings.TrimSpace(status) != "",
+	}
+}
+
+// UpdateSubmodule checks out the commit recorded for the submodule, cloning
+// it first if need be
+func (c *GitCommand) UpdateSubmodule(path string) error {
+	return c.OSCommand.RunCommand(fmt.Sprintf("git submodule update --init -- %s", c.OSCommand.Quote(path)))
+}
+
+// StashSubmoduleChanges stashes the changes inside a submodule, so that it can
+// be updated without losing them
+func (c *GitCommand) StashSubmoduleChanges(path string) error {
+	return c.OSCommand.RunCommand(fmt.Sprintf("git -C %s stash --include-untracked", c.OSCommand.Quote(path)))
+}
+
+// SetSkipWorktree tells git to ignore changes to a file in the working tree,
+// or with value false, to stop doing so
+func (c *GitCommand) SetSkipWorktree(fileName string, value bool) error {
+	return c.updateIndexFlag("skip-worktree", fileName, value)
+}
+
+// SetAssumeUnchanged tells git to assume a file hasn't changed, or with value
+// false, to stop doing so
+func (c *GitCommand) SetAssumeUnchanged(fileName string, value bool) error {
+	return c.updateIndexFlag("assume-unchanged", fileName, value)
+}
+
+func (c *GitCommand) updateIndexFlag(flag string, fileName string, value bool) error {
+	if !value {
+		flag = "no-" + flag
+	}
+	err := c.OSCommand.RunCommand(fmt.Sprintf("git update-index --%s -- %s", flag, c.OSCommand.Quote(fileName)))
+	// the index may not look any different, so we make sure the files are
+	// looked for again
+	c.ignoredChangesMutex.Lock()
+	c.ignoredChangesIndex = nil
+	c.ignoredChangesMutex.Unlock()
+	return err
+}
+
 // StashDo modify stash
 func (c *GitCommand) StashDo(index int, method string) error {
 	return c.OSCommand.RunCommand(fmt.Sprintf("git stash %s stash@{%d}", method, index))
 }
 
+// StashBranch makes a branch at the commit the stash entry was made at, checks
+// it out and pops the entry onto it
+func (c *GitCommand) StashBranch(branchName string, index int) error {
+	return c.OSCommand.RunCommand(fmt.Sprintf("git stash branch %s stash@{%d}", c.OSCommand.Quote(branchName), index))
+}
+
 // StashSave save stash
 // TODO: before calling this, check if there is anything to save
 func (c *GitCommand) StashSave(message string) error {
 	return c.OSCommand.RunCommand(fmt.Sprintf("git stash save %s", c.OSCommand.Quote(message)))
 }
 
+// StashSaveFiles stashes the changes of the given files, leaving the rest of
+// the working tree alone. Untracked files are stashed too
+func (c *GitCommand) StashSaveFiles(fileNames []string, message string) error {
+	quotedFileNames := []string{}
+	for _, fileName := range fileNames {
+		// renamed files look like "file1 -> file2" and we want to stash both sides
+		for _, name := range strings.Split(fileName, " -> ") {
+			quotedFileNames = append(quotedFileNames, c.OSCommand.Quote(name))
+		}
+	}
+	return c.OSCommand.RunCommand(fmt.Sprintf("git stash push --include-untracked -m %s -- %s", c.OSCommand.Quote(message), strings.Join(quotedFileNames, " ")))
+}
+
 // MergeStatusFiles merge status files
 func (c *GitCommand) MergeStatusFiles(oldFiles, newFiles []*File) []*File {
 	if len(oldFiles) == 0 {
@@ -259,9 +646,46 @@ func (c *GitCommand) GetCurrentBranchUpstreamDifferenceCount() (string, string)
 	return c.GetCommitDifferences("HEAD", "@{u}")
 }
 
-func (c *GitCommand) GetBranchUpstreamDifferenceCount(branchName string) (string, string) {
-	upstream := "origin" // hardcoded for now
-	return c.GetCommitDifferences(branchName, fmt.Sprintf("%s/%s", upstream, branchName))
+// GetBranchesAheadBehindCounts returns how many commits each local branch is
+// ahead of and behind its upstream, keyed by the branch's name. Branches
+// without an upstream, or whose upstream is gone, get "?" for both
+func (c *GitCommand) GetBranchesAheadBehindCounts() (map[string]AheadBehindCounts, error) {
+	output, err := c.OSCommand.RunCommandWithOutput("git for-each-ref --format=%(refname:short)%00%(upstream)%00%(upstream:track) refs/heads")
+	if err != nil {
+		return nil, err
+	}
+	counts := map[string]AheadBehindCounts{}
+	for _, line := range utils.SplitLines(output) {
+		fields := strings.Split(line, "\x00")
+		if len(fields) != 3 {
+			continue
+		}
+		counts[fields[0]] = parseUpstreamTrack(fields[1], fields[2])
+	}
+	return counts, nil
+}
+
+// parseUpstreamTrack turns what for-each-ref's %(upstream:track) says, like
+// "[ahead 2, behind 5]", into counts. It says nothing when the branch is level
+// with its upstream, so we need the upstream to tell that apart from having none
+func parseUpstreamTrack(upstream string, track string) AheadBehindCounts {
+	if upstream == "" || track == "[gone]" {
+		return AheadBehindCounts{Pushables: "?", Pullables: "?"}
+	}
+	counts := AheadBehindCounts{Pushables: "0", Pullables: "0"}
+	for _, part := range strings.Split(strings.Trim(track, "[]"), ", ") {
+		fields := strings.Fields(part)
+		if len(fields) != 2 {
+			continue
+		}
+		switch fields[0] {
+		case "ahead":
+			counts.Pushables = fields[1]
+		case "behind":
+			counts.Pullables = fields[1]
+		}
+	}
+	return counts
 }
 
 // GetCommitDifferences checks how many pushables/pullables there are for the
@@ -284,6 +708,105 @@ func (c *GitCommand) RenameCommit(name string) error {
 	return c.OSCommand.RunCommand(fmt.Sprintf("git commit --allow-empty --amend -m %s", c.OSCommand.Quote(name)))
 }
 
+// GetCommitMessage returns the whole message of the commit, body included
+func (c *GitCommand) GetCommitMessage(sha string) (string, error) {
+	message, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git log -1 --format=%%B %s", sha))
+	return strings.TrimSpace(message), err
+}
+
+// GetCommitDetails returns what we know about the commit beyond its message
+// and diff, including the branches it's part of
+func (c *GitCommand) GetCommitDetails(sha string) (*CommitDetails, error) {
+	output, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git log -1 --date=iso --format=%s %s", commitDetailsFormat, sha))
+	if err != nil {
+		return nil, err
+	}
+	details := ParseCommitDetails(output)
+	if details == nil {
+		return nil, errors.New("unexpected output from git log: " + output)
+	}
+	details.ContainingBranches, err = c.GetBranchesContaining(sha)
+	if err != nil {
+		return nil, err
+	}
+	return details, nil
+}
+
+// GetBranchesContaining returns the local and remote branches the commit is
+// part of
+func (c *GitCommand) GetBranchesContaining(sha string) ([]string, error) {
+	output, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git for-each-ref --contains %s --format=%%(refname:short) refs/heads refs/remotes", sha))
+	if err != nil {
+		return nil, err
+	}
+	return utils.SplitLines(output), nil
+}
+
+// GetTagsContaining returns the tags the commit is part of, oldest first, so
+// that the first is the earliest release it shipped in
+func (c *GitCommand) GetTagsContaining(sha string) ([]string, error) {
+	output, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git tag --contains %s --sort=creatordate", sha))
+	if err != nil {
+		return nil, err
+	}
+	return utils.SplitLines(output), nil
+}
+
+// GetFullSha returns the full sha of the commit, for when we only have it
+// abbreviated
+func (c *GitCommand) GetFullSha(sha string) (string, error) {
+	fullSha, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git rev-parse %s", sha))
+	return strings.TrimSpace(fullSha), err
+}
+
+// ResolveCommit returns the full sha of the commit a sha, ref, or expression
+// like HEAD~20 points to
+func (c *GitCommand) ResolveCommit(ref string) (string, error) {
+	sha, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git rev-parse --verify --quiet %s", c.OSCommand.Quote(ref+"^{commit}")))
+	return strings.TrimSpace(sha), err
+}
+
+// IsAncestor says whether the commit is part of the history of the ref
+func (c *GitCommand) IsAncestor(sha string, ref string) bool {
+	return c.OSCommand.RunCommand(fmt.Sprintf("git merge-base --is-ancestor %s %s", sha, c.OSCommand.Quote(ref))) == nil
+}
+
+// CountCommitsAfter returns how many commits in the history of the ref aren't
+// in the history of the commit
+func (c *GitCommand) CountCommitsAfter(sha string, ref string) (int, error) {
+	output, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git rev-list --count %s..%s", sha, c.OSCommand.Quote(ref)))
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(output))
+}
+
+// CountCommitsOnlyOnBranch returns how many commits no ref but the branch
+// leads to, which deleting it would leave unreachable
+func (c *GitCommand) CountCommitsOnlyOnBranch(branchName string) (int, error) {
+	output, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git rev-list --count refs/heads/%s --not --exclude=refs/heads/%s --all", branchName, branchName))
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(output))
+}
+
+// RewordCommitWithMessage gives the commit at the index a new message. The top
+// commit is simply amended, while for an older one we stop at it in a rebase,
+// amend it there and carry on
+func (c *GitCommand) RewordCommitWithMessage(commits []*Commit, index int, message string) error {
+	if index == 0 {
+		return c.RenameCommit(message)
+	}
+	if err := c.InteractiveRebase(commits, index, "edit"); err != nil {
+		return err
+	}
+	if err := c.RenameCommit(message); err != nil {
+		return err
+	}
+	return c.GenericMerge("rebase", "continue")
+}
+
 // RebaseBranch interactive rebases onto a branch
 func (c *GitCommand) RebaseBranch(branchName string) error {
 	cmd, err := c.PrepareInteractiveRebaseCommand(branchName, "", false)
@@ -314,6 +837,11 @@ func (c *GitCommand) NewBranch(name string) error {
 	return c.OSCommand.RunCommand(fmt.Sprintf("git checkout -b %s", name))
 }
 
+// NewBranchAt creates a branch pointing at the commit and checks it out
+func (c *GitCommand) NewBranchAt(name string, sha string) error {
+	return c.OSCommand.RunCommand(fmt.Sprintf("git checkout -b %s %s", c.OSCommand.Quote(name), sha))
+}
+
 // CurrentBranchName is a function.
 func (c *GitCommand) CurrentBranchName() (string, error) {
 	branchName, err := c.OSCommand.RunCommandWithOutput("git symbolic-ref --short HEAD")
@@ -326,6 +854,45 @@ func (c *GitCommand) CurrentBranchName() (string, error) {
 	return utils.TrimTrailingNewline(branchName), nil
 }
 
+// IsHeadDetached says whether HEAD is a commit rather than a branch
+func (c *GitCommand) IsHeadDetached() bool {
+	return c.OSCommand.RunCommand("git symbolic-ref -q HEAD") != nil
+}
+
+// IsHeadOnABranch says whether any branch contains HEAD. If HEAD is detached
+// and no branch does, the commits made there will be hard to find again once
+// something else is checked out
+func (c *GitCommand) IsHeadOnABranch() bool {
+	output, err := c.OSCommand.RunCommandWithOutput("git for-each-ref --contains HEAD --format=%(refname) refs/heads")
+	return err == nil && strings.TrimSpace(output) != ""
+}
+
+// GetPreviousBranch returns the branch that was checked out most recently,
+// going by the reflog's checkouts, that still exists. This is where you'd
+// want to go back to after looking around at a detached HEAD
+func (c *GitCommand) GetPreviousBranch() (string, error) {
+	output, err := c.OSCommand.RunCommandWithOutput("git reflog -n100 --pretty=%gs --grep-reflog=checkout: HEAD")
+	if err != nil {
+		return "", err
+	}
+	branchNames, err := c.GetLocalBranchNames()
+	if err != nil {
+		return "", err
+	}
+	for _, line := range utils.SplitLines(output) {
+		words := strings.Fields(strings.TrimPrefix(line, "checkout: moving from "))
+		if len(words) == 0 {
+			continue
+		}
+		for _, branchName := range branchNames {
+			if branchName == words[0] {
+				return branchName, nil
+			}
+		}
+	}
+	return "", nil
+}
+
 // DeleteBranch delete branch
 func (c *GitCommand) DeleteBranch(branch string, force bool) error {
 	command := "git branch -d"
@@ -337,14 +904,114 @@ func (c *GitCommand) DeleteBranch(branch string, force bool) error {
 	return c.OSCommand.RunCommand(fmt.Sprintf("%s %s", command, branch))
 }
 
+// GetBranchUpstream returns the remote the branch tracks and the branch's name
+// there, or empty strings if it doesn't track a remote branch
+func (c *GitCommand) GetBranchUpstream(branchName string) (string, string) {
+	remoteName, _ := c.getLocalGitConfig(fmt.Sprintf("branch.%s.remote", branchName))
+	mergeRef, _ := c.getLocalGitConfig(fmt.Sprintf("branch.%s.merge", branchName))
+	// a remote of "." means the branch tracks another local branch
+	if remoteName == "" || remoteName == "." || mergeRef == "" {
+		return "", ""
+	}
+	return remoteName, strings.TrimPrefix(mergeRef, "refs/heads/")
+}
+
+// DeleteRemoteBranch deletes the branch from the remote
+func (c *GitCommand) DeleteRemoteBranch(remoteName string, branchName string, ask func(string) string) error {
+	return c.OSCommand.DetectUnamePass(fmt.Sprintf("git push %s --delete %s", remoteName, branchName), ask)
+}
+
+// RenameBranch renames the local branch, along with its config, so it keeps
+// tracking the same upstream
+func (c *GitCommand) RenameBranch(oldName string, newName string) error {
+	return c.OSCommand.RunCommand(fmt.Sprintf("git branch -m %s %s", oldName, newName))
+}
+
+// RenameRemoteBranch renames the branch on the remote to the local branch's
+// name, in one push that pushes the local branch, deletes the old remote
+// branch, and makes the local branch track the new one
+func (c *GitCommand) RenameRemoteBranch(remoteName string, oldRemoteBranchName string, branchName string, ask func(string) string) error {
+	return c.OSCommand.DetectUnamePass(fmt.Sprintf("git push --set-upstream %s %s :%s", remoteName, branchName, oldRemoteBranchName), ask)
+}
+
+// GetRemoteBranchNames returns the remote branches we know of, e.g.
+// origin/master, leaving out the remotes' HEADs
+func (c *GitCommand) GetRemoteBranchNames() ([]string, error) {
+	return c.getRefNames("refs/remotes/")
+}
+
+// GetRemoteBranches returns the remote-tracking branches, which come grouped
+// by remote
+func (c *GitCommand) GetRemoteBranches() ([]*RemoteBranch, error) {
+	names, err := c.GetRemoteBranchNames()
+	if err != nil {
+		return nil, err
+	}
+	branches := []*RemoteBranch{}
+	for _, name := range names {
+		parts := strings.SplitN(name, "/", 2)
+		if len(parts) < 2 {
+			continue
+		}
+		branches = append(branches, &RemoteBranch{RemoteName: parts[0], Name: parts[1]})
+	}
+	return branches, nil
+}
+
+// GetLocalBranchNames returns the names of the local branches
+func (c *GitCommand) GetLocalBranchNames() ([]string, error) {
+	return c.getRefNames("refs/heads/")
+}
+
+// GetTagNames returns the names of the tags
+func (c *GitCommand) GetTagNames() ([]string, error) {
+	return c.getRefNames("refs/tags/")
+}
+
+// getRefNames returns the names of the refs under the prefix, e.g.
+// refs/tags/, with the prefix taken off. Remotes' HEADs are left out
+func (c *GitCommand) getRefNames(prefix string) ([]string, error) {
+	output, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git for-each-ref --format=%%(refname) %s", strings.TrimSuffix(prefix, "/")))
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, ref := range utils.SplitLines(output) {
+		if prefix == "refs/remotes/" && strings.HasSuffix(ref, "/HEAD") {
+			continue
+		}
+		names = append(names, strings.TrimPrefix(ref, prefix))
+	}
+	return names, nil
+}
+
+// SetBranchUpstream makes the branch track the remote branch, e.g.
+// origin/master
+func (c *GitCommand) SetBranchUpstream(branchName string, remoteBranchName string) error {
+	return c.OSCommand.RunCommand(fmt.Sprintf("git branch --set-upstream-to=%s %s", remoteBranchName, branchName))
+}
+
+// UnsetBranchUpstream stops the branch tracking a remote branch
+func (c *GitCommand) UnsetBranchUpstream(branchName string) error {
+	return c.OSCommand.RunCommand(fmt.Sprintf("git branch --unset-upstream %s", branchName))
+}
+
 // ListStash list stash
 func (c *GitCommand) ListStash() (string, error) {
 	return c.OSCommand.RunCommandWithOutput("git stash list")
 }
 
-// Merge merge
-func (c *GitCommand) Merge(branchName string) error {
-	return c.OSCommand.RunCommand(fmt.Sprintf("git merge --no-edit %s", branchName))
+// MergeFlags are the ways of merging a branch besides a plain merge
+var MergeFlags = []string{"--no-ff", "--squash", "--ff-only"}
+
+// Merge merges the branch into the checked out one. flag is one of the
+// MergeFlags, or empty for a plain merge
+func (c *GitCommand) Merge(branchName string, flag string) error {
+	flagArg := ""
+	if flag != "" {
+		flagArg = flag + " "
+	}
+	return c.OSCommand.RunCommand(fmt.Sprintf("git merge --no-edit %s%s", flagArg, branchName))
 }
 
 // AbortMerge abort merge
@@ -364,33 +1031,235 @@ func (c *GitCommand) usingGpg() bool {
 	return value == "true" || value == "1" || value == "yes" || value == "on"
 }
 
-// Commit commits to git
-func (c *GitCommand) Commit(message string, flags string) (*exec.Cmd, error) {
-	command := fmt.Sprintf("git commit %s -m %s", flags, c.OSCommand.Quote(message))
-	if c.usingGpg() {
-		return c.OSCommand.PrepareSubProcess(c.OSCommand.Platform.shell, c.OSCommand.Platform.shellArg, command), nil
+// configForRepo returns the value for this repo in the config map at key,
+// which is keyed by the paths of repos, or nil if there isn't one
+func (c *GitCommand) configForRepo(key string) interface{} {
+	repoPath, err := os.Getwd()
+	if err != nil {
+		return nil
 	}
-
-	return nil, c.OSCommand.RunCommand(command)
+	// viper lowercases the keys of maps in the config
+	return c.Config.GetUserConfig().GetStringMap(key)[strings.ToLower(repoPath)]
 }
 
-// AmendHead amends HEAD with whatever is staged in your working tree
-func (c *GitCommand) AmendHead() (*exec.Cmd, error) {
-	command := "git commit --amend --no-edit"
-	if c.usingGpg() {
-		return c.OSCommand.PrepareSubProcess(c.OSCommand.Platform.shell, c.OSCommand.Platform.shellArg, command), nil
+// CommitTemplate returns the text to start commit messages from, which is read
+// from the file set for this repo in git.commitTemplates in lazygit's config,
+// or else the one git's commit.template is set to. It's empty if neither is
+// set
+func (c *GitCommand) CommitTemplate() (string, error) {
+	path, _ := c.configForRepo("git.commitTemplates").(string)
+	if path == "" {
+		// there's no template if this fails, because commit.template isn't set
+		output, _ := c.OSCommand.RunCommandWithOutput("git config --path --get commit.template")
+		path = strings.TrimSpace(output)
+	}
+	if path == "" {
+		return "", nil
 	}
 
-	return nil, c.OSCommand.RunCommand(command)
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(content), "\n"), nil
 }
 
-// Pull pulls from repo
-func (c *GitCommand) Pull(ask func(string) string) error {
-	return c.OSCommand.DetectUnamePass("git pull --no-edit", ask)
+// GetRecentAuthors returns the authors of recent commits, as "name <email>" and
+// most recent first, leaving out you
+func (c *GitCommand) GetRecentAuthors() ([]string, error) {
+	output, err := c.OSCommand.RunCommandWithOutput("git log -500 --format=%aN%x00%aE")
+	if err != nil {
+		// there are no commits yet
+		return []string{}, nil
+	}
+	// this is who git would make you the author as, which can come from the
+	// environment as well as the config
+	ident, _ := c.OSCommand.RunCommandWithOutput("git var GIT_AUTHOR_IDENT")
+	email := ""
+	if start, end := strings.Index(ident, "<"), strings.Index(ident, ">"); start != -1 && end > start {
+		email = ident[start+1 : end]
+	}
+
+	authors := []string{}
+	seen := map[string]bool{}
+	for _, line := range utils.SplitLines(output) {
+		split := strings.Split(line, "\x00")
+		if len(split) != 2 || strings.EqualFold(split[1], email) {
+			continue
+		}
+		author := fmt.Sprintf("%s <%s>", split[0], split[1])
+		if seen[author] {
+			continue
+		}
+		seen[author] = true
+		authors = append(authors, author)
+	}
+	return authors, nil
 }
 
-// Push pushes to a branch
-func (c *GitCommand) Push(branchName string, force bool, ask func(string) string) error {
+// defaultConventionalCommitTypes are the types of conventional commit we offer
+// for repos that git.conventionalCommitTypes doesn't have types for
+var defaultConventionalCommitTypes = []string{"feat", "fix", "docs", "style", "refactor", "perf", "test", "build", "ci", "chore", "revert"}
+
+// ConventionalCommitTypes returns the types of commit to offer when making a
+// conventional commit, i.e. one with a message like "type(scope): subject"
+func (c *GitCommand) ConventionalCommitTypes() []string {
+	configTypes, ok := c.configForRepo("git.conventionalCommitTypes").([]interface{})
+	if !ok || len(configTypes) == 0 {
+		return defaultConventionalCommitTypes
+	}
+	types := make([]string, len(configTypes))
+	for i, configType := range configTypes {
+		types[i] = fmt.Sprint(configType)
+	}
+	return types
+}
+
+// GetConventionalCommitScopes returns the scopes that recent conventional
+// commits have used, most recently used first
+func (c *GitCommand) GetConventionalCommitScopes() ([]string, error) {
+	output, err := c.OSCommand.RunCommandWithOutput("git log -200 --format=%s")
+	if err != nil {
+		// there are no commits yet
+		return []string{}, nil
+	}
+	scopeRegexp := regexp.MustCompile(`^\w+\(([^)]+)\)!?:`)
+	scopes := []string{}
+	seen := map[string]bool{}
+	for _, subject := range utils.SplitLines(output) {
+		match := scopeRegexp.FindStringSubmatch(subject)
+		if match == nil || seen[match[1]] {
+			continue
+		}
+		seen[match[1]] = true
+		scopes = append(scopes, match[1])
+	}
+	return scopes, nil
+}
+
+// Commit commits to git
+func (c *GitCommand) Commit(message string, flags string) (*exec.Cmd, error) {
+	command := fmt.Sprintf("git commit %s -m %s", flags, c.OSCommand.Quote(message))
+	if c.usingGpg() {
+		return c.OSCommand.PrepareSubProcess(c.OSCommand.Platform.shell, c.OSCommand.Platform.shellArg, command), nil
+	}
+
+	return nil, c.OSCommand.RunCommand(command)
+}
+
+// AmendHead amends HEAD with whatever is staged in your working tree
+func (c *GitCommand) AmendHead() (*exec.Cmd, error) {
+	command := "git commit --amend --no-edit"
+	if c.usingGpg() {
+		return c.OSCommand.PrepareSubProcess(c.OSCommand.Platform.shell, c.OSCommand.Platform.shellArg, command), nil
+	}
+
+	return nil, c.OSCommand.RunCommand(command)
+}
+
+// AmendFileIntoHead amends HEAD with the file's changes, leaving whatever else
+// is staged alone. We do that by building the amended commit in a temporary
+// index that starts out as HEAD. When we have to commit in a subprocess for
+// gpg, FinishAmendingFile must be called once it's done
+func (c *GitCommand) AmendFileIntoHead(fileName string) (*exec.Cmd, error) {
+	indexFile, err := c.amendIndexFile()
+	if err != nil {
+		return nil, err
+	}
+	commands := []string{
+		"git read-tree HEAD",
+		// -A so that a deleted file is removed from the commit
+		fmt.Sprintf("git add -A -- %s", c.quoteBothSides(fileName)),
+	}
+	for _, command := range commands {
+		if err := c.runWithIndexFile(indexFile, command); err != nil {
+			_ = os.Remove(indexFile)
+			return nil, err
+		}
+	}
+
+	if c.usingGpg() {
+		cmd := c.OSCommand.PrepareSubProcess("git", "commit", "--amend", "--no-edit")
+		cmd.Env = append(cmd.Env, "GIT_INDEX_FILE="+indexFile)
+		return cmd, nil
+	}
+	if err := c.runWithIndexFile(indexFile, "git commit --amend --no-edit"); err != nil {
+		_ = os.Remove(indexFile)
+		return nil, err
+	}
+	return nil, c.FinishAmendingFile(fileName)
+}
+
+// FinishAmendingFile cleans up after AmendFileIntoHead. The file's entry in
+// the real index is reset to the amended HEAD, so that the change we've just
+// committed doesn't show up as staged
+func (c *GitCommand) FinishAmendingFile(fileName string) error {
+	indexFile, err := c.amendIndexFile()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(indexFile); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return c.OSCommand.RunCommand(fmt.Sprintf("git reset -q HEAD -- %s", c.quoteBothSides(fileName)))
+}
+
+// quoteBothSides quotes a file name for the command line. Renamed files look
+// like "file1 -> file2", and we want to pass both sides
+func (c *GitCommand) quoteBothSides(fileName string) string {
+	quotedNames := []string{}
+	for _, name := range strings.Split(fileName, " -> ") {
+		quotedNames = append(quotedNames, c.OSCommand.Quote(name))
+	}
+	return strings.Join(quotedNames, " ")
+}
+
+// amendIndexFile is where AmendFileIntoHead keeps its temporary index. It's an
+// absolute path because git resolves GIT_INDEX_FILE against the directory it's
+// run in
+func (c *GitCommand) amendIndexFile() (string, error) {
+	return filepath.Abs(filepath.Join(c.DotGitDir, "lazygit_amend_index"))
+}
+
+func (c *GitCommand) runWithIndexFile(indexFile string, command string) error {
+	cmd := c.OSCommand.ExecutableFromString(command)
+	cmd.Env = append(cmd.Env, "GIT_INDEX_FILE="+indexFile)
+	return c.OSCommand.RunExecutable(cmd)
+}
+
+// The ways of pulling, which the git.pull.mode config chooses between. If it's
+// PullModeDefault git's own pull.rebase and pull.ff config decide, and if it's
+// PullModePrompt you're asked which to pull with each time
+const (
+	PullModeDefault = "default"
+	PullModeMerge   = "merge"
+	PullModeRebase  = "rebase"
+	PullModeFFOnly  = "ff-only"
+	PullModePrompt  = "prompt"
+)
+
+// PullModes are the modes you can pull with
+var PullModes = []string{PullModeMerge, PullModeRebase, PullModeFFOnly}
+
+var pullFlags = map[string]string{
+	PullModeMerge:  "--no-rebase",
+	PullModeRebase: "--rebase",
+	PullModeFFOnly: "--ff-only",
+}
+
+// Pull pulls from repo. mode is one of PullModes, saying whether to merge
+// what's pulled in, rebase onto it or only fast-forward. Any other mode, like
+// PullModeDefault, leaves that to git's config
+func (c *GitCommand) Pull(mode string, ask func(string) string) error {
+	modeArg := ""
+	if flag, ok := pullFlags[mode]; ok {
+		modeArg = " " + flag
+	}
+	return c.OSCommand.DetectUnamePass("git pull --no-edit"+modeArg, ask)
+}
+
+// Push pushes to a branch
+func (c *GitCommand) Push(branchName string, force bool, ask func(string) string) error {
 	forceFlag := ""
 	if force {
 		forceFlag = "--force-with-lease "
@@ -400,6 +1269,21 @@ func (c *GitCommand) Push(branchName string, force bool, ask func(string) string
 	return c.OSCommand.DetectUnamePass(cmd, ask)
 }
 
+// CreateTag tags the commit. The tag is annotated with the message, or is a
+// lightweight tag if the message is empty
+func (c *GitCommand) CreateTag(tagName string, sha string, message string) error {
+	if message == "" {
+		return c.OSCommand.RunCommand(fmt.Sprintf("git tag %s %s", c.OSCommand.Quote(tagName), sha))
+	}
+	return c.OSCommand.RunCommand(fmt.Sprintf("git tag -a %s %s -m %s", c.OSCommand.Quote(tagName), sha, c.OSCommand.Quote(message)))
+}
+
+// PushTag pushes the tag to origin
+func (c *GitCommand) PushTag(tagName string, ask func(string) string) error {
+	cmd := fmt.Sprintf("git push origin %s", c.OSCommand.Quote("refs/tags/"+tagName))
+	return c.OSCommand.DetectUnamePass(cmd, ask)
+}
+
 // CatFile obtains the content of a file
 func (c *GitCommand) CatFile(fileName string) (string, error) {
 	return c.OSCommand.RunCommandWithOutput(fmt.Sprintf("cat %s", c.OSCommand.Quote(fileName)))
@@ -415,6 +1299,12 @@ func (c *GitCommand) StageAll() error {
 	return c.OSCommand.RunCommand("git add -A")
 }
 
+// StageTracked stages the changes to every tracked file, leaving untracked
+// files alone
+func (c *GitCommand) StageTracked() error {
+	return c.OSCommand.RunCommand("git add -u")
+}
+
 // UnstageAll stages all files
 func (c *GitCommand) UnstageAll() error {
 	return c.OSCommand.RunCommand("git reset")
@@ -437,6 +1327,19 @@ func (c *GitCommand) UnStageFile(fileName string, tracked bool) error {
 	return nil
 }
 
+// MoveFile moves a file with `git mv`, creating the directory it's moved into
+// if need be. Git won't move a file it doesn't know about, so we move those
+// ourselves. A newly added file counts as untracked but is in the index
+func (c *GitCommand) MoveFile(file *File, newPath string) error {
+	if err := os.MkdirAll(filepath.Dir(newPath), 0755); err != nil {
+		return WrapError(err)
+	}
+	if !file.Tracked && !file.HasStagedChanges {
+		return WrapError(os.Rename(file.Name, newPath))
+	}
+	return c.OSCommand.RunCommand(fmt.Sprintf("git mv %s %s", c.OSCommand.Quote(file.CurrentPath()), c.OSCommand.Quote(newPath)))
+}
+
 // GitStatus returns the plaintext short status of the repo
 func (c *GitCommand) GitStatus() (string, error) {
 	return c.OSCommand.RunCommandWithOutput("git status --untracked-files=all --porcelain")
@@ -444,11 +1347,105 @@ func (c *GitCommand) GitStatus() (string, error) {
 
 // IsInMergeState states whether we are still mid-merge
 func (c *GitCommand) IsInMergeState() (bool, error) {
-	output, err := c.OSCommand.RunCommandWithOutput("git status --untracked-files=all")
+	return c.OSCommand.FileExists(fmt.Sprintf("%s/MERGE_HEAD", c.DotGitDir))
+}
+
+// HasUnmergedFiles states whether there are conflicts to resolve. Besides the
+// operations we can continue or abort, popping or applying a stash can leave
+// these
+func (c *GitCommand) HasUnmergedFiles() (bool, error) {
+	unmerged, err := c.OSCommand.RunCommandWithOutput("git ls-files -u")
 	if err != nil {
 		return false, err
 	}
-	return strings.Contains(output, "conclude merge") || strings.Contains(output, "unmerged paths"), nil
+	return strings.TrimSpace(unmerged) != "", nil
+}
+
+// IsCherryPicking states whether a cherry-pick has stopped on a conflict
+func (c *GitCommand) IsCherryPicking() (bool, error) {
+	return c.OSCommand.FileExists(fmt.Sprintf("%s/CHERRY_PICK_HEAD", c.DotGitDir))
+}
+
+// IsReverting states whether a revert has stopped on a conflict
+func (c *GitCommand) IsReverting() (bool, error) {
+	return c.OSCommand.FileExists(fmt.Sprintf("%s/REVERT_HEAD", c.DotGitDir))
+}
+
+// GetBisectInfo returns the state of the bisect in progress, or nil if there
+// isn't one
+func (c *GitCommand) GetBisectInfo() (*BisectInfo, error) {
+	bisecting, err := c.OSCommand.FileExists(fmt.Sprintf("%s/BISECT_START", c.DotGitDir))
+	if err != nil || !bisecting {
+		return nil, err
+	}
+
+	info := &BisectInfo{Remaining: -1}
+	output, err := c.OSCommand.RunCommandWithOutput("git for-each-ref --format=%(refname)%00%(objectname) refs/bisect")
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range utils.SplitLines(output) {
+		split := strings.Split(line, "\x00")
+		if len(split) != 2 {
+			continue
+		}
+		ref, sha := strings.TrimPrefix(split[0], "refs/bisect/"), split[1]
+		switch {
+		case ref == "bad":
+			info.Bad = sha
+		case strings.HasPrefix(ref, "good-"):
+			info.Good = append(info.Good, sha)
+		case strings.HasPrefix(ref, "skip-"):
+			info.Skipped = append(info.Skipped, sha)
+		}
+	}
+
+	current, err := c.OSCommand.RunCommandWithOutput("git rev-parse HEAD")
+	if err != nil {
+		return nil, err
+	}
+	info.Current = strings.TrimSpace(current)
+
+	if info.Bad != "" && len(info.Good) > 0 {
+		count, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git rev-list --count %s --not %s", info.Bad, strings.Join(info.Good, " ")))
+		if err != nil {
+			return nil, err
+		}
+		remaining, err := strconv.Atoi(strings.TrimSpace(count))
+		if err != nil {
+			return nil, err
+		}
+		info.Remaining = remaining - 1
+	}
+
+	return info, nil
+}
+
+// StartBisect starts bisecting, which you then do by marking commits
+func (c *GitCommand) StartBisect() error {
+	return c.OSCommand.RunCommand("git bisect start")
+}
+
+// MarkBisect marks the commit as "good", "bad" or "skip", after which git
+// checks out the next commit to test. Once git has found the first bad commit
+// its sha is returned, and otherwise it's empty
+func (c *GitCommand) MarkBisect(term string, sha string) (string, error) {
+	output, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git bisect %s %s", term, sha))
+	if err != nil {
+		return "", err
+	}
+	for _, line := range utils.SplitLines(output) {
+		if strings.HasSuffix(line, " is the first bad commit") {
+			return strings.TrimSuffix(line, " is the first bad commit"), nil
+		}
+	}
+	return "", nil
+}
+
+// ResetBisect ends the bisect and checks out whatever was checked out before it
+// started
+func (c *GitCommand) ResetBisect() error {
+	return c.OSCommand.RunCommand("git bisect reset")
 }
 
 // RebaseMode returns "" for non-rebase mode, "normal" for normal rebase
@@ -528,6 +1525,31 @@ func (c *GitCommand) Ignore(filename string) error {
 	return c.OSCommand.AppendLineToFile(".gitignore", filename)
 }
 
+// Exclude adds a file to the repo's exclude file, which works like the
+// .gitignore except that it's local to your clone
+func (c *GitCommand) Exclude(filename string) error {
+	excludeFile, err := c.ExcludeFilePath()
+	if err != nil {
+		return err
+	}
+	return c.OSCommand.AppendLineToFile(excludeFile, filename)
+}
+
+// ExcludeFilePath returns where the repo's exclude file is, creating the
+// directory it goes in if need be so that it can be written to
+func (c *GitCommand) ExcludeFilePath() (string, error) {
+	// asking git for the path means we get the right file in a worktree too
+	excludeFile, err := c.OSCommand.RunCommandWithOutput("git rev-parse --git-path info/exclude")
+	if err != nil {
+		return "", err
+	}
+	excludeFile = strings.TrimSpace(excludeFile)
+	if err := os.MkdirAll(filepath.Dir(excludeFile), 0755); err != nil {
+		return "", WrapError(err)
+	}
+	return excludeFile, nil
+}
+
 // Show shows the diff of a commit
 func (c *GitCommand) Show(sha string) (string, error) {
 	show, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git show --color %s", sha))
@@ -565,6 +1587,20 @@ func (c *GitCommand) Show(sha string) (string, error) {
 	return show + mergeDiff, nil
 }
 
+// ShowAgainstParent shows a merge commit with its diff against one of its
+// parents, counting from 1, in place of the combined diff
+func (c *GitCommand) ShowAgainstParent(sha string, parent int) (string, error) {
+	show, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git show --color --no-patch %s", sha))
+	if err != nil {
+		return "", err
+	}
+	diff, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git diff --color %s^%d %s", sha, parent, sha))
+	if err != nil {
+		return "", err
+	}
+	return show + "\n" + diff, nil
+}
+
 // GetRemoteURL returns current repo remote url
 func (c *GitCommand) GetRemoteURL() string {
 	url, _ := c.OSCommand.RunCommandWithOutput("git config --get remote.origin.url")
@@ -581,31 +1617,60 @@ func (c *GitCommand) CheckRemoteBranchExists(branch *Branch) bool {
 	return err == nil
 }
 
-// Diff returns the diff of a file
-func (c *GitCommand) Diff(file *File, plain bool) string {
+// maxUntrackedFileDiffSize is the size in bytes above which we don't show the
+// contents of an untracked file
+const maxUntrackedFileDiffSize = 1024 * 1024
+
+// Diff returns the diff of a file. If cached is true, the staged changes are
+// returned even when the file also has unstaged changes. contextSize is the
+// number of unchanged lines shown around each change
+func (c *GitCommand) Diff(file *File, plain bool, cached bool, ignoreWhitespace bool, contextSize int) string {
 	cachedArg := ""
 	trackedArg := "--"
 	colorArg := "--color"
+	whitespaceArg := ""
 	split := strings.Split(file.Name, " -> ") // in case of a renamed file we get the new filename
 	fileName := c.OSCommand.Quote(split[len(split)-1])
-	if file.HasStagedChanges && !file.HasUnstagedChanges {
+	if cached || (file.HasStagedChanges && !file.HasUnstagedChanges) {
 		cachedArg = "--cached"
 	}
-	if !file.Tracked && !file.HasStagedChanges {
+	if !cached && !file.Tracked && !file.HasStagedChanges {
+		// an untracked file is diffed against nothing, so all of it is shown as
+		// added. Git takes care of binary files, but not of huge ones or of
+		// directories, which are untracked as a whole if they're nested repos
+		if info, err := os.Stat(split[len(split)-1]); err == nil {
+			if info.IsDir() {
+				return c.Tr.SLocalize("UntrackedDirectory")
+			}
+			if info.Size() > maxUntrackedFileDiffSize {
+				return c.Tr.TemplateLocalize(
+					"UntrackedFileTooBig",
+					i18n.Teml{
+						"size":  info.Size() / 1024,
+						"limit": maxUntrackedFileDiffSize / 1024,
+					},
+				)
+			}
+		}
 		trackedArg = "--no-index /dev/null"
 	}
 	if plain {
 		colorArg = ""
 	}
+	if ignoreWhitespace {
+		whitespaceArg = "--ignore-all-space"
+	}
 
-	command := fmt.Sprintf("git diff %s %s %s %s", colorArg, cachedArg, trackedArg, fileName)
+	command := fmt.Sprintf("git diff --unified=%d %s %s %s %s %s", contextSize, colorArg, cachedArg, whitespaceArg, trackedArg, fileName)
 
 	// for now we assume an error means the file was deleted
 	s, _ := c.OSCommand.RunCommandWithOutput(command)
 	return s
 }
 
-func (c *GitCommand) ApplyPatch(patch string) (string, error) {
+// ApplyPatch applies the patch, passing each flag through to `git apply`
+// e.g. ApplyPatch(patch, "cached", "reverse") runs `git apply --cached --reverse`
+func (c *GitCommand) ApplyPatch(patch string, flags ...string) (string, error) {
 	filename, err := c.OSCommand.CreateTempFile("patch", patch)
 	if err != nil {
 		c.Log.Error(err)
@@ -614,9 +1679,57 @@ func (c *GitCommand) ApplyPatch(patch string) (string, error) {
 
 	defer func() { _ = c.OSCommand.Remove(filename) }()
 
-	return c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git apply --cached %s", c.OSCommand.Quote(filename)))
+	flagsStr := ""
+	for _, flag := range flags {
+		flagsStr += fmt.Sprintf("--%s ", flag)
+	}
+
+	return c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git apply %s%s", flagsStr, c.OSCommand.Quote(filename)))
 }
 
+// CheckPatch does a dry run of applying the patch with the given flags, so that
+// we can find out whether it applies without touching anything. If it
+// doesn't apply, the error names the hunk that failed where possible
+func (c *GitCommand) CheckPatch(patch string, flags ...string) error {
+	output, err := c.ApplyPatch(patch, append([]string{"check"}, flags...)...)
+	if err == nil {
+		return nil
+	}
+
+	matches := patchFailedRegexp.FindStringSubmatch(output)
+	if matches == nil {
+		return err
+	}
+	fileName, lineNumber := matches[1], matches[2]
+
+	inFile := false
+	for _, line := range strings.Split(patch, "\n") {
+		if strings.HasPrefix(line, "diff ") {
+			inFile = strings.HasSuffix(line, "/"+fileName)
+			continue
+		}
+		hunkMatches := hunkStartsRegexp.FindStringSubmatch(line)
+		if inFile && hunkMatches != nil && (hunkMatches[1] == lineNumber || hunkMatches[2] == lineNumber) {
+			return errors.New(c.Tr.TemplateLocalize(
+				"PatchDoesNotApply",
+				i18n.Teml{
+					"hunk":  line,
+					"file":  fileName,
+					"error": strings.TrimSpace(output),
+				},
+			))
+		}
+	}
+	return err
+}
+
+// patchFailedRegexp matches the file and line that `git apply` reports when a
+// hunk doesn't apply e.g. `error: patch failed: foo.txt:12`
+var patchFailedRegexp = regexp.MustCompile(`patch failed: (.+):(\d+)`)
+
+// hunkStartsRegexp captures the old and new start lines of a hunk header
+var hunkStartsRegexp = regexp.MustCompile(`^@@ -(\d+)(?:,\d+)? \+(\d+)(?:,\d+)? @@`)
+
 func (c *GitCommand) FastForward(branchName string) error {
 	upstream := "origin" // hardcoding for now
 	return c.OSCommand.RunCommand(fmt.Sprintf("git fetch %s %s:%s", upstream, branchName, branchName))
@@ -661,7 +1774,10 @@ func (c *GitCommand) MoveCommitDown(commits []*Commit, index int) error {
 	}
 
 	todo := ""
-	orderedCommits := append(commits[0:index], commits[index+1], commits[index])
+	// copying the commits rather than appending to a slice of them keeps us
+	// from swapping the caller's commits around
+	orderedCommits := append([]*Commit{}, commits[0:index]...)
+	orderedCommits = append(orderedCommits, commits[index+1], commits[index])
 	for _, commit := range orderedCommits {
 		todo = "pick " + commit.Sha + " " + commit.Name + "\n" + todo
 	}
@@ -734,33 +1850,79 @@ func (c *GitCommand) SoftReset(baseSha string) error {
 	return c.OSCommand.RunCommand("git reset --soft " + baseSha)
 }
 
+// GenerateGenericRebaseTodo returns the todo for an interactive rebase that
+// applies the action to the commit at actionIndex, along with the sha of the
+// commit to rebase onto
 func (c *GitCommand) GenerateGenericRebaseTodo(commits []*Commit, actionIndex int, action string) (string, string, error) {
-	baseIndex := actionIndex + 1
-
-	if len(commits) <= baseIndex {
+	if len(commits) <= actionIndex+1 {
 		return "", "", errors.New(c.Tr.SLocalize("CannotRebaseOntoFirstCommit"))
 	}
 
-	if action == "squash" || action == "fixup" {
-		baseIndex++
+	return c.GenerateRebaseTodo(commits, map[string]string{commits[actionIndex].Sha: action})
+}
 
+// GenerateRebaseTodo returns the todo for an interactive rebase that applies
+// the action each commit's sha maps to, picking the commits in between, along
+// with the sha of the commit to rebase onto
+func (c *GitCommand) GenerateRebaseTodo(commits []*Commit, actions map[string]string) (string, string, error) {
+	baseIndex := 0
+	for i, commit := range commits {
+		action, ok := actions[commit.Sha]
+		if !ok {
+			continue
+		}
+		if i+1 > baseIndex {
+			baseIndex = i + 1
+		}
 		if len(commits) <= baseIndex {
-			return "", "", errors.New(c.Tr.SLocalize("CannotSquashOntoSecondCommit"))
+			return "", "", errors.New(c.Tr.SLocalize("CannotRebaseOntoFirstCommit"))
+		}
+
+		if action == "squash" || action == "fixup" {
+			// the commit is melded into the one below it that we're keeping, so
+			// that has to be in the todo too
+			targetIndex := i + 1
+			for targetIndex < len(commits) && actions[commits[targetIndex].Sha] == "drop" {
+				targetIndex++
+			}
+			if targetIndex+1 > baseIndex {
+				baseIndex = targetIndex + 1
+			}
+			if len(commits) <= baseIndex {
+				return "", "", errors.New(c.Tr.SLocalize("CannotSquashOntoSecondCommit"))
+			}
 		}
 	}
 
 	todo := ""
-	for i, commit := range commits[0:baseIndex] {
-		a := "pick"
-		if i == actionIndex {
-			a = action
+	for _, commit := range commits[0:baseIndex] {
+		action, ok := actions[commit.Sha]
+		if !ok {
+			action = "pick"
 		}
-		todo = a + " " + commit.Sha + " " + commit.Name + "\n" + todo
+		todo = action + " " + commit.Sha + " " + commit.Name + "\n" + todo
 	}
 
 	return todo, commits[baseIndex].Sha, nil
 }
 
+// InteractiveRebaseCommits applies several actions in one rebase, e.g.
+// squashing some commits and dropping others. actions maps the sha of each
+// commit to act on to its action
+func (c *GitCommand) InteractiveRebaseCommits(commits []*Commit, actions map[string]string) error {
+	todo, sha, err := c.GenerateRebaseTodo(commits, actions)
+	if err != nil {
+		return err
+	}
+
+	cmd, err := c.PrepareInteractiveRebaseCommand(sha, todo, true)
+	if err != nil {
+		return err
+	}
+
+	return c.OSCommand.RunPreparedCommand(cmd)
+}
+
 // AmendTo amends the given commit with whatever files are staged
 func (c *GitCommand) AmendTo(sha string) error {
 	if err := c.CreateFixupCommit(sha); err != nil {
@@ -821,24 +1983,44 @@ func (c *GitCommand) MoveTodoDown(index int) error {
 	return ioutil.WriteFile(fileName, []byte(result), 0644)
 }
 
-// Revert reverts the selected commit by sha
-func (c *GitCommand) Revert(sha string) error {
-	return c.OSCommand.RunCommand(fmt.Sprintf("git revert %s", sha))
+// Revert reverts the selected commit by sha. A merge commit has to be reverted
+// against one of its parents, numbered from 1 as with `git revert -m`, while
+// for any other commit mainline is 0
+func (c *GitCommand) Revert(sha string, mainline int) error {
+	mainlineArg := ""
+	if mainline > 0 {
+		mainlineArg = fmt.Sprintf(" -m %d", mainline)
+	}
+	return c.OSCommand.RunCommand(fmt.Sprintf("git revert --no-edit%s %s", mainlineArg, sha))
 }
 
-// CherryPickCommits begins an interactive rebase with the given shas being cherry picked onto HEAD
-func (c *GitCommand) CherryPickCommits(commits []*Commit) error {
-	todo := ""
-	for _, commit := range commits {
-		todo = "pick " + commit.Sha + " " + commit.Name + "\n" + todo
+// GetCommitParents returns the parents of the commit, which for a merge commit
+// come in the order `git revert -m` numbers them
+func (c *GitCommand) GetCommitParents(sha string) ([]*Commit, error) {
+	output, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git log -1 --format=%%p %s", sha))
+	if err != nil {
+		return nil, err
 	}
+	parents := []*Commit{}
+	for _, parentSha := range strings.Fields(output) {
+		name, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git log -1 --format=%%s %s", parentSha))
+		if err != nil {
+			return nil, err
+		}
+		parents = append(parents, &Commit{Sha: parentSha, Name: strings.TrimSpace(name)})
+	}
+	return parents, nil
+}
 
-	cmd, err := c.PrepareInteractiveRebaseCommand("HEAD", todo, false)
-	if err != nil {
-		return err
+// CherryPickCommits cherry-picks the given commits onto HEAD. They come newest
+// first, as in the commits panel, so we pick them in reverse to keep their order
+func (c *GitCommand) CherryPickCommits(commits []*Commit) error {
+	shas := []string{}
+	for i := len(commits) - 1; i >= 0; i-- {
+		shas = append(shas, commits[i].Sha)
 	}
 
-	return c.OSCommand.RunPreparedCommand(cmd)
+	return c.OSCommand.RunCommand(fmt.Sprintf("git cherry-pick %s", strings.Join(shas, " ")))
 }
 
 // GetCommitFiles get the specified commit files
@@ -862,15 +2044,47 @@ func (c *GitCommand) GetCommitFiles(commitSha string) ([]*CommitFile, error) {
 	return commitFiles, nil
 }
 
+// GetCompareFiles gets the files changed on other since it branched off base
+func (c *GitCommand) GetCompareFiles(base, other string) ([]*CommitFile, error) {
+	files, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git diff --name-only %s...%s", base, other))
+	if err != nil {
+		return nil, err
+	}
+
+	compareFiles := make([]*CommitFile, 0)
+
+	for _, file := range utils.SplitLines(files) {
+		compareFiles = append(compareFiles, &CommitFile{
+			Sha:           other,
+			Base:          base,
+			Name:          file,
+			DisplayString: file,
+		})
+	}
+
+	return compareFiles, nil
+}
+
 // ShowCommitFile get the diff of specified commit file
 func (c *GitCommand) ShowCommitFile(commitSha, fileName string) (string, error) {
 	cmd := fmt.Sprintf("git show --color %s -- %s", commitSha, fileName)
 	return c.OSCommand.RunCommandWithOutput(cmd)
 }
 
-// CheckoutFile checks out the file for the given commit
+// CommitFileDiff returns the plain diff of the file against the commit's
+// parent, which unlike ShowCommitFile we can build patches from. The full blob
+// hashes let us merge those patches into other commits with `git apply --3way`.
+// For a merge commit we diff against the first parent, given we can't build a
+// patch from the combined diff git would otherwise give us
+func (c *GitCommand) CommitFileDiff(commitSha, fileName string, contextSize int) (string, error) {
+	cmd := fmt.Sprintf("git show --format= --full-index -m --first-parent --unified=%d %s -- %s", contextSize, commitSha, c.OSCommand.Quote(fileName))
+	return c.OSCommand.RunCommandWithOutput(cmd)
+}
+
+// CheckoutFile checks out the file as it is in the given commit, which can be
+// any ref e.g. a branch name
 func (c *GitCommand) CheckoutFile(commitSha, fileName string) error {
-	cmd := fmt.Sprintf("git checkout %s %s", commitSha, fileName)
+	cmd := fmt.Sprintf("git checkout %s -- %s", c.OSCommand.Quote(commitSha), c.OSCommand.Quote(fileName))
 	return c.OSCommand.RunCommand(cmd)
 }
 
@@ -926,6 +2140,253 @@ func (c *GitCommand) DiscardOldFileChanges(commits []*Commit, commitIndex int, f
 	return c.GenericMerge("rebase", "continue")
 }
 
+// ApplyPatchToCommit moves the changes in the patch, which must be applicable
+// to the working tree, into the given commit. We take the changes out of the
+// working tree before rebasing so that they don't conflict with the autostash
+// when it's popped at the end of the rebase
+func (c *GitCommand) ApplyPatchToCommit(commits []*Commit, commitIndex int, patch string) error {
+	if len(commits)-1 < commitIndex {
+		return errors.New("index outside of range of commits")
+	}
+
+	if c.usingGpg() {
+		return errors.New(c.Tr.SLocalize("DisabledForGPG"))
+	}
+
+	todo, sha, err := c.GenerateGenericRebaseTodo(commits, commitIndex, "edit")
+	if err != nil {
+		return err
+	}
+
+	cmd, err := c.PrepareInteractiveRebaseCommand(sha, todo, true)
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.ApplyPatch(patch, "reverse"); err != nil {
+		return err
+	}
+
+	if rebaseErr := c.OSCommand.RunPreparedCommand(cmd); rebaseErr != nil {
+		// we took the changes out of the working tree, so put them back
+		if _, err := c.ApplyPatch(patch); err != nil {
+			return err
+		}
+		return rebaseErr
+	}
+
+	if _, applyErr := c.ApplyPatch(patch, "index"); applyErr != nil {
+		// put things back the way they were, given the patch is no longer in the
+		// working tree
+		if err := c.GenericMerge("rebase", "abort"); err != nil {
+			return err
+		}
+		if _, err := c.ApplyPatch(patch); err != nil {
+			return err
+		}
+		return applyErr
+	}
+
+	// amend the commit
+	cmd, err = c.AmendHead()
+	if cmd != nil {
+		return errors.New("received unexpected pointer to cmd")
+	}
+	if err != nil {
+		return err
+	}
+
+	// continue
+	return c.GenericMerge("rebase", "continue")
+}
+
+// SplitCommit moves the changes in the patch out of the given commit and into a
+// new commit with the given message, which comes straight after it. The patch
+// must have been built from the commit's diff to be applied in reverse
+func (c *GitCommand) SplitCommit(commits []*Commit, commitIndex int, patch string, message string) error {
+	if len(commits)-1 < commitIndex {
+		return errors.New("index outside of range of commits")
+	}
+
+	if c.usingGpg() {
+		return errors.New(c.Tr.SLocalize("DisabledForGPG"))
+	}
+
+	todo, sha, err := c.GenerateGenericRebaseTodo(commits, commitIndex, "edit")
+	if err != nil {
+		return err
+	}
+
+	cmd, err := c.PrepareInteractiveRebaseCommand(sha, todo, true)
+	if err != nil {
+		return err
+	}
+
+	if err := c.OSCommand.RunPreparedCommand(cmd); err != nil {
+		return err
+	}
+
+	// take the patch out of the commit
+	if err := c.applyPatchAndAmend(patch, "index", "reverse"); err != nil {
+		return err
+	}
+
+	// and put it back in a commit of its own
+	if _, err := c.ApplyPatch(patch, "index"); err != nil {
+		return err
+	}
+
+	cmd, err = c.Commit(message, "")
+	if cmd != nil {
+		return errors.New("received unexpected pointer to cmd")
+	}
+	if err != nil {
+		return err
+	}
+
+	return c.GenericMerge("rebase", "continue")
+}
+
+// StartSplittingCommit stops a rebase at the commit at the index and undoes
+// it, leaving its changes unstaged for you to choose the first of the commits
+// it's split into. FinishSplittingCommit makes the rest into the second
+func (c *GitCommand) StartSplittingCommit(commits []*Commit, commitIndex int) error {
+	if len(commits)-1 < commitIndex {
+		return errors.New("index outside of range of commits")
+	}
+
+	if c.usingGpg() {
+		return errors.New(c.Tr.SLocalize("DisabledForGPG"))
+	}
+
+	if err := c.InteractiveRebase(commits, commitIndex, "edit"); err != nil {
+		return err
+	}
+
+	return c.OSCommand.RunCommand("git reset HEAD^")
+}
+
+// FinishSplittingCommit commits whatever's left of the changes of the commit
+// being split, with its message, and continues the rebase. If you put all of
+// its changes in the first commit, there's nothing left to commit
+func (c *GitCommand) FinishSplittingCommit(sha string) error {
+	files, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git show --pretty= --name-only --no-renames %s", sha))
+	if err != nil {
+		return err
+	}
+	quotedFiles := []string{}
+	for _, file := range utils.SplitLines(files) {
+		quotedFiles = append(quotedFiles, c.OSCommand.Quote(file))
+	}
+	if len(quotedFiles) > 0 {
+		if err := c.OSCommand.RunCommand(fmt.Sprintf("git add -A -- %s", strings.Join(quotedFiles, " "))); err != nil {
+			return err
+		}
+	}
+
+	// this fails if there are staged changes
+	if err := c.OSCommand.RunCommand("git diff --cached --quiet"); err != nil {
+		message, err := c.GetCommitMessage(sha)
+		if err != nil {
+			return err
+		}
+		cmd, err := c.Commit(message, "")
+		if cmd != nil {
+			return errors.New("received unexpected pointer to cmd")
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return c.GenericMerge("rebase", "continue")
+}
+
+// MovePatchToCommit moves the changes in a patch built from the diff of the
+// commit at sourceIndex into the commit at destinationIndex. We're given the
+// patch both ways round: reversePatch is applied in reverse to take the
+// changes out of the source commit exactly, whereas patch may have to be
+// merged into the destination commit, so it needs the full blob hashes in its
+// index lines for `git apply --3way`
+func (c *GitCommand) MovePatchToCommit(commits []*Commit, sourceIndex int, destinationIndex int, patch string, reversePatch string) error {
+	if len(commits)-1 < sourceIndex || len(commits)-1 < destinationIndex {
+		return errors.New("index outside of range of commits")
+	}
+
+	if c.usingGpg() {
+		return errors.New(c.Tr.SLocalize("DisabledForGPG"))
+	}
+
+	// if the destination commit comes first, we don't need to stop at the source
+	// commit: once the destination commit has the changes, replaying the source
+	// commit on top of it no longer makes them
+	editIndices := []int{destinationIndex}
+	if destinationIndex < sourceIndex {
+		editIndices = append(editIndices, sourceIndex)
+	}
+
+	todo, sha, err := c.generateEditRebaseTodo(commits, editIndices...)
+	if err != nil {
+		return err
+	}
+
+	cmd, err := c.PrepareInteractiveRebaseCommand(sha, todo, true)
+	if err != nil {
+		return err
+	}
+
+	if err := c.OSCommand.RunPreparedCommand(cmd); err != nil {
+		return err
+	}
+
+	if destinationIndex < sourceIndex {
+		if err := c.applyPatchAndAmend(reversePatch, "index", "reverse"); err != nil {
+			return err
+		}
+		if err := c.GenericMerge("rebase", "continue"); err != nil {
+			return err
+		}
+	}
+
+	if err := c.applyPatchAndAmend(patch, "3way"); err != nil {
+		return err
+	}
+
+	return c.GenericMerge("rebase", "continue")
+}
+
+// applyPatchAndAmend applies the patch while stopped at a commit in a rebase and
+// amends the commit with it. If the patch doesn't apply, we abort the rebase
+func (c *GitCommand) applyPatchAndAmend(patch string, flags ...string) error {
+	if _, applyErr := c.ApplyPatch(patch, flags...); applyErr != nil {
+		if err := c.GenericMerge("rebase", "abort"); err != nil {
+			return err
+		}
+		return applyErr
+	}
+
+	cmd, err := c.AmendHead()
+	if cmd != nil {
+		return errors.New("received unexpected pointer to cmd")
+	}
+	return err
+}
+
+// generateEditRebaseTodo returns the todo for an interactive rebase that stops
+// to edit each of the commits at the given indices, along with the sha of the
+// commit to rebase onto
+func (c *GitCommand) generateEditRebaseTodo(commits []*Commit, editIndices ...int) (string, string, error) {
+	actions := map[string]string{}
+	for _, index := range editIndices {
+		if len(commits) <= index+1 {
+			return "", "", errors.New(c.Tr.SLocalize("CannotRebaseOntoFirstCommit"))
+		}
+		actions[commits[index].Sha] = "edit"
+	}
+
+	return c.GenerateRebaseTodo(commits, actions)
+}
+
 // DiscardAnyUnstagedFileChanges discards any unstages file changes via `git checkout -- .`
 func (c *GitCommand) DiscardAnyUnstagedFileChanges() error {
 	return c.OSCommand.RunCommand("git checkout -- .")
@@ -936,6 +2397,44 @@ func (c *GitCommand) RemoveUntrackedFiles() error {
 	return c.OSCommand.RunCommand("git clean -fd")
 }
 
+// GetCleanablePaths returns the untracked files, and with includeIgnored the
+// ignored files too, that CleanPaths can delete. A directory holding nothing
+// but such files is listed by itself, ending in a slash
+func (c *GitCommand) GetCleanablePaths(includeIgnored bool) ([]string, error) {
+	commands := []string{"git ls-files -z --others --exclude-standard --directory --no-empty-directory"}
+	if includeIgnored {
+		commands = append(commands, "git ls-files -z --others --ignored --exclude-standard --directory --no-empty-directory")
+	}
+	paths := []string{}
+	for _, command := range commands {
+		output, err := c.OSCommand.RunCommandWithOutput(command)
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range strings.Split(output, "\x00") {
+			if path != "" {
+				paths = append(paths, path)
+			}
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// CleanPaths deletes the given untracked files and directories, which with
+// includeIgnored can include ignored ones
+func (c *GitCommand) CleanPaths(paths []string, includeIgnored bool) error {
+	flags := "-f -d"
+	if includeIgnored {
+		flags += " -x"
+	}
+	quotedPaths := []string{}
+	for _, path := range paths {
+		quotedPaths = append(quotedPaths, c.OSCommand.Quote(path))
+	}
+	return c.OSCommand.RunCommand(fmt.Sprintf("git clean %s -- %s", flags, strings.Join(quotedPaths, " ")))
+}
+
 // ResetHardHead runs `git reset --hard HEAD`
 func (c *GitCommand) ResetHardHead() error {
 	return c.OSCommand.RunCommand("git reset --hard HEAD")
@@ -952,22 +2451,202 @@ func (c *GitCommand) DiffCommits(sha1, sha2 string) (string, error) {
 	return c.OSCommand.RunCommandWithOutput(cmd)
 }
 
+// CompareRefs shows the commits on other that aren't on base, followed by the
+// changes other has made since it branched off base, i.e. what merging other
+// into base would bring in
+func (c *GitCommand) CompareRefs(base, other string) (string, error) {
+	log, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git log --color --oneline %s..%s", base, other))
+	if err != nil {
+		return "", err
+	}
+	diff, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git diff --color %s...%s", base, other))
+	if err != nil {
+		return "", err
+	}
+	return log + "\n" + diff, nil
+}
+
+// CompareRefsFile is CompareRefs' diff narrowed down to the one file
+func (c *GitCommand) CompareRefsFile(base, other, fileName string) (string, error) {
+	cmd := fmt.Sprintf("git diff --color %s...%s -- %s", base, other, c.OSCommand.Quote(fileName))
+	return c.OSCommand.RunCommandWithOutput(cmd)
+}
+
 // CreateFixupCommit creates a commit that fixes up a previous commit
 func (c *GitCommand) CreateFixupCommit(sha string) error {
 	cmd := fmt.Sprintf("git commit --fixup=%s", sha)
 	return c.OSCommand.RunCommand(cmd)
 }
 
-// SquashAllAboveFixupCommits squashes all fixup! commits above the given one
+// CreateSquashCommit creates a commit of what's staged to be squashed into the
+// given commit, combining their messages, when its autosquashed
+func (c *GitCommand) CreateSquashCommit(sha string) error {
+	return c.OSCommand.RunCommand(fmt.Sprintf("git commit --squash=%s --no-edit", sha))
+}
+
+// SquashAllAboveFixupCommits squashes all fixup! and squash! commits above the
+// given one
 func (c *GitCommand) SquashAllAboveFixupCommits(sha string) error {
+	upstream := sha + "^"
+	// the root commit has no parent to rebase onto
+	if err := c.OSCommand.RunCommand(fmt.Sprintf("git rev-parse --verify --quiet %s", upstream)); err != nil {
+		upstream = "--root"
+	}
 	return c.RunSkipEditorCommand(
 		fmt.Sprintf(
-			"git rebase --interactive --autostash --autosquash %s^",
-			sha,
+			"git rebase --interactive --autostash --autosquash %s",
+			upstream,
 		),
 	)
 }
 
+// BlameLines returns the sha of the commit that last changed each of the lines
+// of the file from start to end, as of HEAD
+func (c *GitCommand) BlameLines(fileName string, start int, end int) ([]string, error) {
+	output, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git blame --porcelain -L %d,%d HEAD -- %s", start, end, c.OSCommand.Quote(fileName)))
+	if err != nil {
+		return nil, err
+	}
+	return ParseBlameShas(output), nil
+}
+
+// AbsorbStagedChanges makes a fixup commit for each of the given commits that
+// last changed the lines of some of the staged hunks, out of those hunks, and
+// returns the commits it made them for, in the order given. Hunks whose lines
+// weren't last changed by exactly one of the commits stay staged
+func (c *GitCommand) AbsorbStagedChanges(commits []*Commit) ([]*Commit, error) {
+	if c.usingGpg() {
+		return nil, errors.New(c.Tr.SLocalize("DisabledForGPG"))
+	}
+
+	diff, err := c.OSCommand.RunCommandWithOutput("git diff --cached -U0 --no-color --no-ext-diff --no-renames")
+	if err != nil {
+		return nil, err
+	}
+	hunksByCommit := map[*Commit][]*StagedHunk{}
+	for _, hunk := range ParseStagedHunks(diff) {
+		if commit := c.absorbTarget(hunk, commits); commit != nil {
+			hunksByCommit[commit] = append(hunksByCommit[commit], hunk)
+		}
+	}
+	if len(hunksByCommit) == 0 {
+		return nil, nil
+	}
+
+	// once we've committed the hunks we can absorb we put back what was staged,
+	// which leaves staged only the hunks we couldn't
+	tree, err := c.OSCommand.RunCommandWithOutput("git write-tree")
+	if err != nil {
+		return nil, err
+	}
+	restoreIndex := fmt.Sprintf("git read-tree %s", strings.TrimSpace(tree))
+	if err := c.OSCommand.RunCommand("git reset -q"); err != nil {
+		return nil, err
+	}
+
+	targets := []*Commit{}
+	applied := map[string][]*StagedHunk{}
+	for _, commit := range commits {
+		hunks, ok := hunksByCommit[commit]
+		if !ok {
+			continue
+		}
+		if err := c.commitFixupOfHunks(commit, hunks, applied); err != nil {
+			_ = c.OSCommand.RunCommand(restoreIndex)
+			return nil, err
+		}
+		targets = append(targets, commit)
+	}
+	return targets, c.OSCommand.RunCommand(restoreIndex)
+}
+
+// absorbTarget returns the commit that last changed the lines the hunk belongs
+// to if it's one of the given commits, or nil if it isn't or they were last
+// changed by more than one commit
+func (c *GitCommand) absorbTarget(hunk *StagedHunk, commits []*Commit) *Commit {
+	start, end := hunk.BlameRange()
+	shas, err := c.BlameLines(hunk.FileName, start, end)
+	if err != nil && hunk.OldCount == 0 && end > start {
+		// lines added at the end of the file only have a line above them
+		shas, err = c.BlameLines(hunk.FileName, start, start)
+	}
+	if err != nil || len(shas) == 0 {
+		return nil
+	}
+	for _, sha := range shas[1:] {
+		if sha != shas[0] {
+			return nil
+		}
+	}
+	for _, commit := range commits {
+		if strings.HasPrefix(shas[0], commit.Sha) {
+			return commit
+		}
+	}
+	return nil
+}
+
+// commitFixupOfHunks stages the hunks, given the hunks of the same diff that
+// have been committed already, and makes a fixup commit of them
+func (c *GitCommand) commitFixupOfHunks(commit *Commit, hunks []*StagedHunk, applied map[string][]*StagedHunk) error {
+	fileNames := []string{}
+	hunksByFile := map[string][]*StagedHunk{}
+	for _, hunk := range hunks {
+		if _, ok := hunksByFile[hunk.FileName]; !ok {
+			fileNames = append(fileNames, hunk.FileName)
+		}
+		hunksByFile[hunk.FileName] = append(hunksByFile[hunk.FileName], hunk)
+	}
+
+	patch := ""
+	for _, fileName := range fileNames {
+		patch += RenderStagedHunks(hunksByFile[fileName], applied[fileName])
+	}
+	if _, err := c.ApplyPatch(patch, "cached", "unidiff-zero"); err != nil {
+		return err
+	}
+	for _, fileName := range fileNames {
+		applied[fileName] = append(applied[fileName], hunksByFile[fileName]...)
+	}
+
+	return c.CreateFixupCommit(commit.Sha)
+}
+
+// ExportPatches writes the commit to a patch file in the directory, or if upTo
+// is a ref, the commits from it up to the ref, one file per commit. It
+// returns the paths of the files
+func (c *GitCommand) ExportPatches(sha string, upTo string, directory string) ([]string, error) {
+	revisions := "-1 " + sha
+	if upTo != "" {
+		revisions = fmt.Sprintf("%s^..%s", sha, upTo)
+		// the root commit has no parent to start after
+		if err := c.OSCommand.RunCommand(fmt.Sprintf("git rev-parse --verify --quiet %s^", sha)); err != nil {
+			revisions = "--root " + upTo
+		}
+	}
+	output, err := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git format-patch -o %s %s", c.OSCommand.Quote(directory), revisions))
+	if err != nil {
+		return nil, err
+	}
+	return utils.SplitLines(output), nil
+}
+
+// ApplyPatchFileAsCommits makes commits of the patches in the file, which is
+// one written by `git format-patch` or a mailbox of them. If they don't apply
+// we leave things as they were
+func (c *GitCommand) ApplyPatchFileAsCommits(path string) error {
+	if err := c.OSCommand.RunCommand(fmt.Sprintf("git am --3way %s", c.OSCommand.Quote(path))); err != nil {
+		_ = c.OSCommand.RunCommand("git am --abort")
+		return err
+	}
+	return nil
+}
+
+// ApplyPatchFile applies the patch in the file to the working tree
+func (c *GitCommand) ApplyPatchFile(path string) error {
+	return c.OSCommand.RunCommand(fmt.Sprintf("git apply %s", c.OSCommand.Quote(path)))
+}
+
 // StashSaveStagedChanges stashes only the currently staged changes. This takes a few steps
 // shoutouts to Joe on https://stackoverflow.com/questions/14759748/stashing-only-staged-changes-in-git-is-it-possible
 func (c *GitCommand) StashSaveStagedChanges(message string) error {
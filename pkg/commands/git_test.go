@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -318,6 +319,62 @@ func TestGitCommandGetStashEntries(t *testing.T) {
 	}
 }
 
+// TestGitCommandGetReflogEntries is a function.
+func TestGitCommandGetReflogEntries(t *testing.T) {
+	type scenario struct {
+		testName string
+		command  func(string, ...string) *exec.Cmd
+		test     func([]*ReflogEntry, error)
+	}
+
+	scenarios := []scenario{
+		{
+			"No reflog entries found",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"reflog", "--abbrev-commit", "--format=%h %gd %gs"}, args)
+				return exec.Command("echo")
+			},
+			func(entries []*ReflogEntry, err error) {
+				assert.NoError(t, err)
+				assert.Len(t, entries, 0)
+			},
+		},
+		{
+			"Several reflog entries found",
+			func(string, ...string) *exec.Cmd {
+				return exec.Command("echo", "cbfff26 HEAD@{0} reset: moving to HEAD~2\n0abff48 HEAD@{1} commit: add a file\n8c103fa HEAD@{2}")
+			},
+			func(entries []*ReflogEntry, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, []*ReflogEntry{
+					{Sha: "cbfff26", Selector: "HEAD@{0}", Message: "reset: moving to HEAD~2"},
+					{Sha: "0abff48", Selector: "HEAD@{1}", Message: "commit: add a file"},
+					{Sha: "8c103fa", Selector: "HEAD@{2}"},
+				}, entries)
+			},
+		},
+		{
+			"git reflog fails",
+			func(string, ...string) *exec.Cmd {
+				return exec.Command("test", "1", "=", "2")
+			},
+			func(entries []*ReflogEntry, err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = s.command
+
+			s.test(gitCmd.GetReflogEntries())
+		})
+	}
+}
+
 // TestGitCommandGetStashEntryDiff is a function.
 func TestGitCommandGetStashEntryDiff(t *testing.T) {
 	gitCmd := NewDummyGitCommand()
@@ -425,6 +482,71 @@ func TestGitCommandGetStatusFiles(t *testing.T) {
 					},
 				}
 
+				assert.EqualValues(t, expected, files)
+			},
+		},
+		{
+			"Merge conflicts",
+			func(cmd string, args ...string) *exec.Cmd {
+				if args[0] == "ls-files" {
+					return exec.Command("echo")
+				}
+				return exec.Command("echo", "DD file1.txt\nAU file2.txt\nUD file3.txt\nUA file4.txt\nDU file5.txt\nAA file6.txt\nUU file7.txt")
+			},
+			func(files []*File) {
+				assert.Len(t, files, 7)
+				for _, file := range files {
+					assert.True(t, file.HasMergeConflicts, file.Name)
+					assert.EqualValues(t, file.ShortStatus == "AA" || file.ShortStatus == "UU", file.HasInlineMergeConflicts, file.Name)
+				}
+			},
+		},
+		{
+			"Files with changes ignored",
+			func(cmd string, args ...string) *exec.Cmd {
+				if args[0] == "ls-files" {
+					return exec.Command("echo", "H file1.txt\nS file2.txt\nh file3.txt\nH file4.txt\ns file5.txt")
+				}
+				return exec.Command("echo", " M file1.txt")
+			},
+			func(files []*File) {
+				expected := []*File{
+					{
+						Name:               "file1.txt",
+						HasStagedChanges:   false,
+						HasUnstagedChanges: true,
+						Tracked:            true,
+						DisplayString:      " M file1.txt",
+						Type:               "other",
+						ShortStatus:        " M",
+					},
+					{
+						Name:          "file2.txt",
+						Tracked:       true,
+						DisplayString: "S  file2.txt",
+						Type:          "other",
+						ShortStatus:   "S ",
+						SkipWorktree:  true,
+					},
+					{
+						Name:            "file3.txt",
+						Tracked:         true,
+						DisplayString:   "h  file3.txt",
+						Type:            "other",
+						ShortStatus:     "h ",
+						AssumeUnchanged: true,
+					},
+					{
+						Name:            "file5.txt",
+						Tracked:         true,
+						DisplayString:   "s  file5.txt",
+						Type:            "other",
+						ShortStatus:     "s ",
+						SkipWorktree:    true,
+						AssumeUnchanged: true,
+					},
+				}
+
 				assert.EqualValues(t, expected, files)
 			},
 		},
@@ -453,159 +575,69 @@ func TestGitCommandStashDo(t *testing.T) {
 	assert.NoError(t, gitCmd.StashDo(1, "drop"))
 }
 
-// TestGitCommandStashSave is a function.
-func TestGitCommandStashSave(t *testing.T) {
+// TestGitCommandStashBranch is a function.
+func TestGitCommandStashBranch(t *testing.T) {
 	gitCmd := NewDummyGitCommand()
 	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
 		assert.EqualValues(t, "git", cmd)
-		assert.EqualValues(t, []string{"stash", "save", "A stash message"}, args)
+		assert.EqualValues(t, []string{"stash", "branch", "feature", "stash@{1}"}, args)
 
 		return exec.Command("echo")
 	}
 
-	assert.NoError(t, gitCmd.StashSave("A stash message"))
+	assert.NoError(t, gitCmd.StashBranch("feature", 1))
 }
 
-// TestGitCommandCommitAmend is a function.
-func TestGitCommandCommitAmend(t *testing.T) {
+// TestGitCommandStashSave is a function.
+func TestGitCommandStashSave(t *testing.T) {
 	gitCmd := NewDummyGitCommand()
 	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
 		assert.EqualValues(t, "git", cmd)
-		assert.EqualValues(t, []string{"commit", "--amend", "--allow-empty"}, args)
+		assert.EqualValues(t, []string{"stash", "save", "A stash message"}, args)
 
 		return exec.Command("echo")
 	}
 
-	_, err := gitCmd.PrepareCommitAmendSubProcess().CombinedOutput()
-	assert.NoError(t, err)
+	assert.NoError(t, gitCmd.StashSave("A stash message"))
 }
 
-// TestGitCommandMergeStatusFiles is a function.
-func TestGitCommandMergeStatusFiles(t *testing.T) {
+// TestGitCommandStashSaveFiles is a function.
+func TestGitCommandStashSaveFiles(t *testing.T) {
 	type scenario struct {
-		testName string
-		oldFiles []*File
-		newFiles []*File
-		test     func([]*File)
+		testName  string
+		fileNames []string
+		command   func(string, ...string) *exec.Cmd
 	}
 
 	scenarios := []scenario{
 		{
-			"Old file and new file are the same",
-			[]*File{},
-			[]*File{
-				{
-					Name: "new_file.txt",
-				},
-			},
-			func(files []*File) {
-				expected := []*File{
-					{
-						Name: "new_file.txt",
-					},
-				}
-
-				assert.Len(t, files, 1)
-				assert.EqualValues(t, expected, files)
-			},
-		},
-		{
-			"Several files to merge, with some identical",
-			[]*File{
-				{
-					Name: "new_file1.txt",
-				},
-				{
-					Name: "new_file2.txt",
-				},
-				{
-					Name: "new_file3.txt",
-				},
-			},
-			[]*File{
-				{
-					Name: "new_file4.txt",
-				},
-				{
-					Name: "new_file5.txt",
-				},
-				{
-					Name: "new_file1.txt",
-				},
-			},
-			func(files []*File) {
-				expected := []*File{
-					{
-						Name: "new_file1.txt",
-					},
-					{
-						Name: "new_file4.txt",
-					},
-					{
-						Name: "new_file5.txt",
-					},
-				}
-
-				assert.Len(t, files, 3)
-				assert.EqualValues(t, expected, files)
-			},
-		},
-	}
-
-	for _, s := range scenarios {
-		t.Run(s.testName, func(t *testing.T) {
-			gitCmd := NewDummyGitCommand()
-
-			s.test(gitCmd.MergeStatusFiles(s.oldFiles, s.newFiles))
-		})
-	}
-}
-
-// TestGitCommandGetCommitDifferences is a function.
-func TestGitCommandGetCommitDifferences(t *testing.T) {
-	type scenario struct {
-		testName string
-		command  func(string, ...string) *exec.Cmd
-		test     func(string, string)
-	}
+			"A file",
+			[]string{"test.txt"},
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"stash", "push", "--include-untracked", "-m", "A stash message", "--", "test.txt"}, args)
 
-	scenarios := []scenario{
-		{
-			"Can't retrieve pushable count",
-			func(string, ...string) *exec.Cmd {
-				return exec.Command("test")
-			},
-			func(pushableCount string, pullableCount string) {
-				assert.EqualValues(t, "?", pushableCount)
-				assert.EqualValues(t, "?", pullableCount)
+				return exec.Command("echo")
 			},
 		},
 		{
-			"Can't retrieve pullable count",
+			"A renamed file",
+			[]string{"old.txt -> new.txt"},
 			func(cmd string, args ...string) *exec.Cmd {
-				if args[1] == "HEAD..@{u}" {
-					return exec.Command("test")
-				}
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"stash", "push", "--include-untracked", "-m", "A stash message", "--", "old.txt", "new.txt"}, args)
 
 				return exec.Command("echo")
 			},
-			func(pushableCount string, pullableCount string) {
-				assert.EqualValues(t, "?", pushableCount)
-				assert.EqualValues(t, "?", pullableCount)
-			},
 		},
 		{
-			"Retrieve pullable and pushable count",
+			"Several files",
+			[]string{"a.txt", "dir/b.txt"},
 			func(cmd string, args ...string) *exec.Cmd {
-				if args[1] == "HEAD..@{u}" {
-					return exec.Command("echo", "10")
-				}
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"stash", "push", "--include-untracked", "-m", "A stash message", "--", "a.txt", "dir/b.txt"}, args)
 
-				return exec.Command("echo", "11")
-			},
-			func(pushableCount string, pullableCount string) {
-				assert.EqualValues(t, "11", pushableCount)
-				assert.EqualValues(t, "10", pullableCount)
+				return exec.Command("echo")
 			},
 		},
 	}
@@ -614,414 +646,525 @@ func TestGitCommandGetCommitDifferences(t *testing.T) {
 		t.Run(s.testName, func(t *testing.T) {
 			gitCmd := NewDummyGitCommand()
 			gitCmd.OSCommand.command = s.command
-			s.test(gitCmd.GetCommitDifferences("HEAD", "@{u}"))
+			assert.NoError(t, gitCmd.StashSaveFiles(s.fileNames, "A stash message"))
 		})
 	}
 }
 
-// TestGitCommandRenameCommit is a function.
-func TestGitCommandRenameCommit(t *testing.T) {
+// TestGitCommandMoveFile is a function.
+func TestGitCommandMoveFile(t *testing.T) {
 	gitCmd := NewDummyGitCommand()
 	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
 		assert.EqualValues(t, "git", cmd)
-		assert.EqualValues(t, []string{"commit", "--allow-empty", "--amend", "-m", "test"}, args)
+		assert.EqualValues(t, []string{"mv", "new.txt", "newer.txt"}, args)
 
 		return exec.Command("echo")
 	}
 
-	assert.NoError(t, gitCmd.RenameCommit("test"))
+	assert.NoError(t, gitCmd.MoveFile(&File{Name: "old.txt -> new.txt", Tracked: true}, "newer.txt"))
 }
 
-// TestGitCommandResetToCommit is a function.
-func TestGitCommandResetToCommit(t *testing.T) {
+// TestGitCommandMoveFileAdded is a function.
+func TestGitCommandMoveFileAdded(t *testing.T) {
 	gitCmd := NewDummyGitCommand()
 	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
 		assert.EqualValues(t, "git", cmd)
-		assert.EqualValues(t, []string{"reset", "--hard", "78976bc"}, args)
+		assert.EqualValues(t, []string{"mv", "new.txt", "newer.txt"}, args)
 
 		return exec.Command("echo")
 	}
 
-	assert.NoError(t, gitCmd.ResetToCommit("78976bc", "hard"))
+	assert.NoError(t, gitCmd.MoveFile(&File{Name: "new.txt", HasStagedChanges: true}, "newer.txt"))
 }
 
-// TestGitCommandNewBranch is a function.
-func TestGitCommandNewBranch(t *testing.T) {
+// TestGitCommandMoveFileUntracked is a function.
+func TestGitCommandMoveFileUntracked(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lazygit-move")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	assert.NoError(t, ioutil.WriteFile(dir+"/a.txt", []byte("a"), 0644))
+
 	gitCmd := NewDummyGitCommand()
 	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
-		assert.EqualValues(t, "git", cmd)
-		assert.EqualValues(t, []string{"checkout", "-b", "test"}, args)
-
-		return exec.Command("echo")
+		t.Fatalf("unexpected command: %s %v", cmd, args)
+		return nil
 	}
 
-	assert.NoError(t, gitCmd.NewBranch("test"))
+	assert.NoError(t, gitCmd.MoveFile(&File{Name: dir + "/a.txt"}, dir+"/sub/b.txt"))
+	content, err := ioutil.ReadFile(dir + "/sub/b.txt")
+	assert.NoError(t, err)
+	assert.EqualValues(t, "a", string(content))
 }
 
-// TestGitCommandDeleteBranch is a function.
-func TestGitCommandDeleteBranch(t *testing.T) {
+// TestGitCommandUpdateIndexFlags is a function.
+func TestGitCommandUpdateIndexFlags(t *testing.T) {
 	type scenario struct {
-		testName string
-		branch   string
-		force    bool
-		command  func(string, ...string) *exec.Cmd
-		test     func(error)
+		testName     string
+		run          func(*GitCommand) error
+		expectedArgs []string
 	}
 
 	scenarios := []scenario{
 		{
-			"Delete a branch",
-			"test",
-			false,
-			func(cmd string, args ...string) *exec.Cmd {
-				assert.EqualValues(t, "git", cmd)
-				assert.EqualValues(t, []string{"branch", "-d", "test"}, args)
-
-				return exec.Command("echo")
-			},
-			func(err error) {
-				assert.NoError(t, err)
-			},
+			"Skip worktree",
+			func(gitCmd *GitCommand) error { return gitCmd.SetSkipWorktree("test.txt", true) },
+			[]string{"update-index", "--skip-worktree", "--", "test.txt"},
 		},
 		{
-			"Force delete a branch",
-			"test",
-			true,
-			func(cmd string, args ...string) *exec.Cmd {
-				assert.EqualValues(t, "git", cmd)
-				assert.EqualValues(t, []string{"branch", "-D", "test"}, args)
-
-				return exec.Command("echo")
-			},
-			func(err error) {
-				assert.NoError(t, err)
-			},
+			"No skip worktree",
+			func(gitCmd *GitCommand) error { return gitCmd.SetSkipWorktree("test.txt", false) },
+			[]string{"update-index", "--no-skip-worktree", "--", "test.txt"},
+		},
+		{
+			"Assume unchanged",
+			func(gitCmd *GitCommand) error { return gitCmd.SetAssumeUnchanged("test.txt", true) },
+			[]string{"update-index", "--assume-unchanged", "--", "test.txt"},
+		},
+		{
+			"No assume unchanged",
+			func(gitCmd *GitCommand) error { return gitCmd.SetAssumeUnchanged("test.txt", false) },
+			[]string{"update-index", "--no-assume-unchanged", "--", "test.txt"},
 		},
 	}
 
 	for _, s := range scenarios {
 		t.Run(s.testName, func(t *testing.T) {
 			gitCmd := NewDummyGitCommand()
-			gitCmd.OSCommand.command = s.command
-			s.test(gitCmd.DeleteBranch(s.branch, s.force))
+			gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, s.expectedArgs, args)
+
+				return exec.Command("echo")
+			}
+			assert.NoError(t, s.run(gitCmd))
 		})
 	}
 }
 
-// TestGitCommandMerge is a function.
-func TestGitCommandMerge(t *testing.T) {
+// TestGitCommandGetIgnoredChangesFiles is a function.
+func TestGitCommandGetIgnoredChangesFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lazygit-dotgit")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	indexPath := filepath.Join(dir, "index")
+	assert.NoError(t, ioutil.WriteFile(indexPath, []byte("index"), 0644))
+
+	gitCmd := NewDummyGitCommand()
+	gitCmd.DotGitDir = dir
+	listings := 0
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		if args[0] == "ls-files" {
+			listings++
+			return exec.Command("echo", "S skipped.txt\nh assumed.txt\nH tracked.txt")
+		}
+		return exec.Command("echo")
+	}
+
+	files := gitCmd.getIgnoredChangesFiles()
+	assert.Len(t, files, 2)
+	assert.True(t, files[0].SkipWorktree)
+	assert.True(t, files[1].AssumeUnchanged)
+	assert.EqualValues(t, 1, listings)
+
+	// the index hasn't changed so we don't list the files again
+	files[0].Name = "changed by the caller"
+	files = gitCmd.getIgnoredChangesFiles()
+	assert.EqualValues(t, "skipped.txt", files[0].Name)
+	assert.EqualValues(t, 1, listings)
+
+	later := time.Now().Add(time.Minute)
+	assert.NoError(t, os.Chtimes(indexPath, later, later))
+	gitCmd.getIgnoredChangesFiles()
+	assert.EqualValues(t, 2, listings)
+
+	// setting a flag may leave the index looking the same
+	assert.NoError(t, gitCmd.SetSkipWorktree("tracked.txt", true))
+	gitCmd.getIgnoredChangesFiles()
+	assert.EqualValues(t, 3, listings)
+}
+
+// TestGitCommandRemoveConflictedFile is a function.
+func TestGitCommandRemoveConflictedFile(t *testing.T) {
 	gitCmd := NewDummyGitCommand()
 	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
 		assert.EqualValues(t, "git", cmd)
-		assert.EqualValues(t, []string{"merge", "--no-edit", "test"}, args)
+		assert.EqualValues(t, []string{"rm", "--", "test.txt"}, args)
 
 		return exec.Command("echo")
 	}
 
-	assert.NoError(t, gitCmd.Merge("test"))
+	assert.NoError(t, gitCmd.RemoveConflictedFile("test.txt"))
 }
 
-// TestGitCommandUsingGpg is a function.
-func TestGitCommandUsingGpg(t *testing.T) {
+// TestGitCommandDescribeMergeConflict is a function.
+func TestGitCommandDescribeMergeConflict(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	assert.EqualValues(t, "Merge conflict: deleted by us", gitCmd.DescribeMergeConflict(&File{ShortStatus: "DU"}))
+}
+
+// TestParseNumstat is a function.
+func TestParseNumstat(t *testing.T) {
 	type scenario struct {
-		testName           string
-		getLocalGitConfig  func(string) (string, error)
-		getGlobalGitConfig func(string) (string, error)
-		test               func(bool)
+		testName string
+		output   string
+		expected map[string]lineStats
 	}
 
 	scenarios := []scenario{
 		{
-			"Option global and local config commit.gpgsign is not set",
-			func(string) (string, error) {
-				return "", nil
-			},
-			func(string) (string, error) {
-				return "", nil
-			},
-			func(gpgEnabled bool) {
-				assert.False(t, gpgEnabled)
-			},
-		},
-		{
-			"Option global config commit.gpgsign is not set, fallback on local config",
-			func(string) (string, error) {
-				return "", nil
-			},
-			func(string) (string, error) {
-				return "true", nil
-			},
-			func(gpgEnabled bool) {
-				assert.True(t, gpgEnabled)
-			},
-		},
-		{
-			"Option commit.gpgsign is true",
-			func(string) (string, error) {
-				return "True", nil
-			},
-			func(string) (string, error) {
-				return "", nil
-			},
-			func(gpgEnabled bool) {
-				assert.True(t, gpgEnabled)
-			},
+			"No changes",
+			"",
+			map[string]lineStats{},
 		},
 		{
-			"Option commit.gpgsign is on",
-			func(string) (string, error) {
-				return "ON", nil
-			},
-			func(string) (string, error) {
-				return "", nil
-			},
-			func(gpgEnabled bool) {
-				assert.True(t, gpgEnabled)
+			"Changed files",
+			"3\t1\tfile1.txt\x0010\t0\tdir/file 2.txt\x00",
+			map[string]lineStats{
+				"file1.txt":      {additions: 3, deletions: 1},
+				"dir/file 2.txt": {additions: 10, deletions: 0},
 			},
 		},
 		{
-			"Option commit.gpgsign is yes",
-			func(string) (string, error) {
-				return "YeS", nil
-			},
-			func(string) (string, error) {
-				return "", nil
-			},
-			func(gpgEnabled bool) {
-				assert.True(t, gpgEnabled)
+			"Renamed file stored by its new path",
+			"1\t2\t\x00old.txt\x00new.txt\x004\t0\tfile1.txt\x00",
+			map[string]lineStats{
+				"new.txt":   {additions: 1, deletions: 2},
+				"file1.txt": {additions: 4, deletions: 0},
 			},
 		},
 		{
-			"Option commit.gpgsign is 1",
-			func(string) (string, error) {
-				return "1", nil
-			},
-			func(string) (string, error) {
-				return "", nil
-			},
-			func(gpgEnabled bool) {
-				assert.True(t, gpgEnabled)
+			"Binary file left out",
+			"-\t-\timage.png\x002\t2\tfile1.txt\x00",
+			map[string]lineStats{
+				"file1.txt": {additions: 2, deletions: 2},
 			},
 		},
 	}
 
 	for _, s := range scenarios {
 		t.Run(s.testName, func(t *testing.T) {
-			gitCmd := NewDummyGitCommand()
-			gitCmd.getGlobalGitConfig = s.getGlobalGitConfig
-			gitCmd.getLocalGitConfig = s.getLocalGitConfig
-			s.test(gitCmd.usingGpg())
+			assert.EqualValues(t, s.expected, parseNumstat(s.output))
 		})
 	}
 }
 
-// TestGitCommandCommit is a function.
-func TestGitCommandCommit(t *testing.T) {
+// TestGitCommandGetIgnoredDirectories is a function.
+func TestGitCommandGetIgnoredDirectories(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"ls-files", "--others", "--ignored", "--exclude-standard", "--directory"}, args)
+
+		return exec.Command("printf", `node_modules/\nbuild/out/\ndebug.log\n"with space/"\n`)
+	}
+
+	assert.EqualValues(t, []string{"node_modules", "build/out", "with space"}, gitCmd.GetIgnoredDirectories())
+}
+
+// TestGitCommandGetIgnoredPaths is a function.
+func TestGitCommandGetIgnoredPaths(t *testing.T) {
 	type scenario struct {
-		testName           string
-		command            func(string, ...string) *exec.Cmd
-		getGlobalGitConfig func(string) (string, error)
-		test               func(*exec.Cmd, error)
-		flags              string
+		testName string
+		command  func(string, ...string) *exec.Cmd
+		expected []string
 	}
 
 	scenarios := []scenario{
 		{
-			"Commit using gpg",
+			"Some paths ignored",
 			func(cmd string, args ...string) *exec.Cmd {
-				assert.EqualValues(t, "bash", cmd)
-				assert.EqualValues(t, []string{"-c", `git commit  -m 'test'`}, args)
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"check-ignore", "--", "main.go", "debug.log", "build/out"}, args)
 
-				return exec.Command("echo")
-			},
-			func(string) (string, error) {
-				return "true", nil
-			},
-			func(cmd *exec.Cmd, err error) {
-				assert.NotNil(t, cmd)
-				assert.Nil(t, err)
+				return exec.Command("printf", `debug.log\nbuild/out\n`)
 			},
-			"",
+			[]string{"debug.log", "build/out"},
 		},
 		{
-			"Commit without using gpg",
+			"No paths ignored",
 			func(cmd string, args ...string) *exec.Cmd {
-				assert.EqualValues(t, "git", cmd)
-				assert.EqualValues(t, []string{"commit", "-m", "test"}, args)
-
-				return exec.Command("echo")
-			},
-			func(string) (string, error) {
-				return "false", nil
-			},
-			func(cmd *exec.Cmd, err error) {
-				assert.Nil(t, cmd)
-				assert.Nil(t, err)
+				return exec.Command("test")
 			},
-			"",
+			[]string{},
 		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = s.command
+			assert.EqualValues(t, s.expected, gitCmd.GetIgnoredPaths([]string{"main.go", "debug.log", "build/out"}))
+		})
+	}
+}
+
+// TestGitCommandGetSubmoduleStatus is a function.
+func TestGitCommandGetSubmoduleStatus(t *testing.T) {
+	type scenario struct {
+		testName string
+		command  func(string, ...string) *exec.Cmd
+		expected *SubmoduleStatus
+	}
+
+	scenarios := []scenario{
 		{
-			"Commit with --no-verify flag",
+			"Submodule moved on with changes of its own",
 			func(cmd string, args ...string) *exec.Cmd {
 				assert.EqualValues(t, "git", cmd)
-				assert.EqualValues(t, []string{"commit", "--no-verify", "-m", "test"}, args)
-
-				return exec.Command("echo")
-			},
-			func(string) (string, error) {
-				return "false", nil
-			},
-			func(cmd *exec.Cmd, err error) {
-				assert.Nil(t, cmd)
-				assert.Nil(t, err)
+				switch args[len(args)-1] {
+				case "HEAD:sub":
+					return exec.Command("echo", "abc123")
+				case "HEAD":
+					assert.EqualValues(t, []string{"-C", "sub", "rev-parse", "HEAD"}, args)
+					return exec.Command("echo", "def456")
+				}
+				assert.EqualValues(t, []string{"-C", "sub", "status", "--porcelain"}, args)
+				return exec.Command("echo", " M file.txt")
 			},
-			"--no-verify",
+			&SubmoduleStatus{RecordedSha: "abc123", CheckedOutSha: "def456", Dirty: true},
 		},
 		{
-			"Commit without using gpg with an error",
+			"New submodule that isn't initialised",
 			func(cmd string, args ...string) *exec.Cmd {
-				assert.EqualValues(t, "git", cmd)
-				assert.EqualValues(t, []string{"commit", "-m", "test"}, args)
-
+				if args[len(args)-1] == "--porcelain" {
+					return exec.Command("echo")
+				}
 				return exec.Command("test")
 			},
-			func(string) (string, error) {
-				return "false", nil
-			},
-			func(cmd *exec.Cmd, err error) {
-				assert.Nil(t, cmd)
-				assert.Error(t, err)
-			},
-			"",
+			&SubmoduleStatus{},
 		},
 	}
 
 	for _, s := range scenarios {
 		t.Run(s.testName, func(t *testing.T) {
 			gitCmd := NewDummyGitCommand()
-			gitCmd.getGlobalGitConfig = s.getGlobalGitConfig
 			gitCmd.OSCommand.command = s.command
-			s.test(gitCmd.Commit("test", s.flags))
+			assert.EqualValues(t, s.expected, gitCmd.GetSubmoduleStatus("sub"))
 		})
 	}
 }
 
-// TestGitCommandAmendHead is a function.
-func TestGitCommandAmendHead(t *testing.T) {
+// TestGitCommandCommitAmend is a function.
+func TestGitCommandCommitAmend(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"commit", "--amend", "--allow-empty"}, args)
+
+		return exec.Command("echo")
+	}
+
+	_, err := gitCmd.PrepareCommitAmendSubProcess().CombinedOutput()
+	assert.NoError(t, err)
+}
+
+// TestGitCommandAmendFileIntoHead is a function.
+func TestGitCommandAmendFileIntoHead(t *testing.T) {
 	type scenario struct {
-		testName           string
-		command            func(string, ...string) *exec.Cmd
-		getGlobalGitConfig func(string) (string, error)
-		test               func(*exec.Cmd, error)
+		testName     string
+		fileName     string
+		gpgsign      string
+		expectedArgs [][]string
+		test         func(*exec.Cmd, error)
 	}
 
 	scenarios := []scenario{
 		{
-			"Amend commit using gpg",
-			func(cmd string, args ...string) *exec.Cmd {
-				assert.EqualValues(t, "bash", cmd)
-				assert.EqualValues(t, []string{"-c", "git commit --amend --no-edit"}, args)
-
-				return exec.Command("echo")
-			},
-			func(string) (string, error) {
-				return "true", nil
+			"A file",
+			"test.txt",
+			"",
+			[][]string{
+				{"read-tree", "HEAD"},
+				{"add", "-A", "--", "test.txt"},
+				{"commit", "--amend", "--no-edit"},
+				{"reset", "-q", "HEAD", "--", "test.txt"},
 			},
 			func(cmd *exec.Cmd, err error) {
-				assert.NotNil(t, cmd)
-				assert.Nil(t, err)
+				assert.Nil(t, cmd)
+				assert.NoError(t, err)
 			},
 		},
 		{
-			"Amend commit without using gpg",
-			func(cmd string, args ...string) *exec.Cmd {
-				assert.EqualValues(t, "git", cmd)
-				assert.EqualValues(t, []string{"commit", "--amend", "--no-edit"}, args)
-
-				return exec.Command("echo")
-			},
-			func(string) (string, error) {
-				return "false", nil
+			"A renamed file",
+			"old.txt -> new.txt",
+			"",
+			[][]string{
+				{"read-tree", "HEAD"},
+				{"add", "-A", "--", "old.txt", "new.txt"},
+				{"commit", "--amend", "--no-edit"},
+				{"reset", "-q", "HEAD", "--", "old.txt", "new.txt"},
 			},
 			func(cmd *exec.Cmd, err error) {
 				assert.Nil(t, cmd)
-				assert.Nil(t, err)
+				assert.NoError(t, err)
 			},
 		},
 		{
-			"Amend commit without using gpg with an error",
-			func(cmd string, args ...string) *exec.Cmd {
-				assert.EqualValues(t, "git", cmd)
-				assert.EqualValues(t, []string{"commit", "--amend", "--no-edit"}, args)
-
-				return exec.Command("test")
-			},
-			func(string) (string, error) {
-				return "false", nil
+			"Commit in a subprocess with gpg",
+			"test.txt",
+			"true",
+			[][]string{
+				{"read-tree", "HEAD"},
+				{"add", "-A", "--", "test.txt"},
+				{"commit", "--amend", "--no-edit"},
 			},
 			func(cmd *exec.Cmd, err error) {
-				assert.Nil(t, cmd)
-				assert.Error(t, err)
+				assert.NotNil(t, cmd)
+				assert.NoError(t, err)
 			},
 		},
 	}
 
 	for _, s := range scenarios {
 		t.Run(s.testName, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "lazygit-dotgit")
+			assert.NoError(t, err)
+			defer os.RemoveAll(dir)
+
 			gitCmd := NewDummyGitCommand()
-			gitCmd.getGlobalGitConfig = s.getGlobalGitConfig
-			gitCmd.OSCommand.command = s.command
-			s.test(gitCmd.AmendHead())
+			gitCmd.DotGitDir = dir
+			gitCmd.getLocalGitConfig = func(string) (string, error) { return s.gpgsign, nil }
+			commandIndex := 0
+			gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, s.expectedArgs[commandIndex], args)
+				commandIndex++
+
+				return exec.Command("echo")
+			}
+
+			s.test(gitCmd.AmendFileIntoHead(s.fileName))
+			assert.EqualValues(t, len(s.expectedArgs), commandIndex)
 		})
 	}
 }
 
-// TestGitCommandPush is a function.
-func TestGitCommandPush(t *testing.T) {
+// TestGitCommandMergeStatusFiles is a function.
+func TestGitCommandMergeStatusFiles(t *testing.T) {
 	type scenario struct {
-		testName  string
-		command   func(string, ...string) *exec.Cmd
-		forcePush bool
-		test      func(error)
+		testName string
+		oldFiles []*File
+		newFiles []*File
+		test     func([]*File)
 	}
 
 	scenarios := []scenario{
 		{
-			"Push with force disabled",
-			func(cmd string, args ...string) *exec.Cmd {
-				assert.EqualValues(t, "git", cmd)
-				assert.EqualValues(t, []string{"push", "-u", "origin", "test"}, args)
+			"Old file and new file are the same",
+			[]*File{},
+			[]*File{
+				{
+					Name: "new_file.txt",
+				},
+			},
+			func(files []*File) {
+				expected := []*File{
+					{
+						Name: "new_file.txt",
+					},
+				}
 
-				return exec.Command("echo")
+				assert.Len(t, files, 1)
+				assert.EqualValues(t, expected, files)
 			},
-			false,
-			func(err error) {
-				assert.NoError(t, err)
+		},
+		{
+			"Several files to merge, with some identical",
+			[]*File{
+				{
+					Name: "new_file1.txt",
+				},
+				{
+					Name: "new_file2.txt",
+				},
+				{
+					Name: "new_file3.txt",
+				},
+			},
+			[]*File{
+				{
+					Name: "new_file4.txt",
+				},
+				{
+					Name: "new_file5.txt",
+				},
+				{
+					Name: "new_file1.txt",
+				},
+			},
+			func(files []*File) {
+				expected := []*File{
+					{
+						Name: "new_file1.txt",
+					},
+					{
+						Name: "new_file4.txt",
+					},
+					{
+						Name: "new_file5.txt",
+					},
+				}
+
+				assert.Len(t, files, 3)
+				assert.EqualValues(t, expected, files)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+
+			s.test(gitCmd.MergeStatusFiles(s.oldFiles, s.newFiles))
+		})
+	}
+}
+
+// TestGitCommandGetCommitDifferences is a function.
+func TestGitCommandGetCommitDifferences(t *testing.T) {
+	type scenario struct {
+		testName string
+		command  func(string, ...string) *exec.Cmd
+		test     func(string, string)
+	}
+
+	scenarios := []scenario{
+		{
+			"Can't retrieve pushable count",
+			func(string, ...string) *exec.Cmd {
+				return exec.Command("test")
+			},
+			func(pushableCount string, pullableCount string) {
+				assert.EqualValues(t, "?", pushableCount)
+				assert.EqualValues(t, "?", pullableCount)
 			},
 		},
 		{
-			"Push with force enabled",
+			"Can't retrieve pullable count",
 			func(cmd string, args ...string) *exec.Cmd {
-				assert.EqualValues(t, "git", cmd)
-				assert.EqualValues(t, []string{"push", "--force-with-lease", "-u", "origin", "test"}, args)
+				if args[1] == "HEAD..@{u}" {
+					return exec.Command("test")
+				}
 
 				return exec.Command("echo")
 			},
-			true,
-			func(err error) {
-				assert.NoError(t, err)
+			func(pushableCount string, pullableCount string) {
+				assert.EqualValues(t, "?", pushableCount)
+				assert.EqualValues(t, "?", pullableCount)
 			},
 		},
 		{
-			"Push with an error occurring",
+			"Retrieve pullable and pushable count",
 			func(cmd string, args ...string) *exec.Cmd {
-				assert.EqualValues(t, "git", cmd)
-				assert.EqualValues(t, []string{"push", "-u", "origin", "test"}, args)
-				return exec.Command("test")
+				if args[1] == "HEAD..@{u}" {
+					return exec.Command("echo", "10")
+				}
+
+				return exec.Command("echo", "11")
 			},
-			false,
-			func(err error) {
-				assert.Error(t, err)
+			func(pushableCount string, pullableCount string) {
+				assert.EqualValues(t, "11", pushableCount)
+				assert.EqualValues(t, "10", pullableCount)
 			},
 		},
 	}
@@ -1030,75 +1173,105 @@ func TestGitCommandPush(t *testing.T) {
 		t.Run(s.testName, func(t *testing.T) {
 			gitCmd := NewDummyGitCommand()
 			gitCmd.OSCommand.command = s.command
-			err := gitCmd.Push("test", s.forcePush, func(passOrUname string) string {
-				return "\n"
-			})
-			s.test(err)
+			s.test(gitCmd.GetCommitDifferences("HEAD", "@{u}"))
 		})
 	}
 }
 
-// TestGitCommandCatFile is a function.
-func TestGitCommandCatFile(t *testing.T) {
+// TestGitCommandGetBranchesAheadBehindCounts is a function.
+func TestGitCommandGetBranchesAheadBehindCounts(t *testing.T) {
 	gitCmd := NewDummyGitCommand()
 	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
-		assert.EqualValues(t, "cat", cmd)
-		assert.EqualValues(t, []string{"test.txt"}, args)
-
-		return exec.Command("echo", "-n", "test")
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"for-each-ref", "--format=%(refname:short)%00%(upstream)%00%(upstream:track)", "refs/heads"}, args)
+
+		return exec.Command("printf", "%s\\0%s\\0%s\\n",
+			"master", "refs/remotes/origin/master", "",
+			"feature", "refs/remotes/origin/feature", "[ahead 2, behind 5]",
+			"ahead", "refs/remotes/origin/ahead", "[ahead 3]",
+			"behind", "refs/remotes/origin/behind", "[behind 1]",
+			"gone", "refs/remotes/origin/gone", "[gone]",
+			"local-only", "", "",
+		)
 	}
 
-	o, err := gitCmd.CatFile("test.txt")
+	counts, err := gitCmd.GetBranchesAheadBehindCounts()
 	assert.NoError(t, err)
-	assert.Equal(t, "test", o)
+	assert.EqualValues(t, map[string]AheadBehindCounts{
+		"master":     {Pushables: "0", Pullables: "0"},
+		"feature":    {Pushables: "2", Pullables: "5"},
+		"ahead":      {Pushables: "3", Pullables: "0"},
+		"behind":     {Pushables: "0", Pullables: "1"},
+		"gone":       {Pushables: "?", Pullables: "?"},
+		"local-only": {Pushables: "?", Pullables: "?"},
+	}, counts)
 }
 
-// TestGitCommandStageFile is a function.
-func TestGitCommandStageFile(t *testing.T) {
+// TestGitCommandRenameCommit is a function.
+func TestGitCommandRenameCommit(t *testing.T) {
 	gitCmd := NewDummyGitCommand()
 	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
 		assert.EqualValues(t, "git", cmd)
-		assert.EqualValues(t, []string{"add", "test.txt"}, args)
+		assert.EqualValues(t, []string{"commit", "--allow-empty", "--amend", "-m", "test"}, args)
 
 		return exec.Command("echo")
 	}
 
-	assert.NoError(t, gitCmd.StageFile("test.txt"))
+	assert.NoError(t, gitCmd.RenameCommit("test"))
 }
 
-// TestGitCommandUnstageFile is a function.
-func TestGitCommandUnstageFile(t *testing.T) {
+// TestGitCommandGetCommitMessage is a function.
+func TestGitCommandGetCommitMessage(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"log", "-1", "--format=%B", "abc123"}, args)
+
+		return exec.Command("echo", "subject\n\nbody\n")
+	}
+
+	message, err := gitCmd.GetCommitMessage("abc123")
+	assert.NoError(t, err)
+	assert.EqualValues(t, "subject\n\nbody", message)
+}
+
+// TestGitCommandGetFullSha is a function.
+func TestGitCommandGetFullSha(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"rev-parse", "abc123"}, args)
+
+		return exec.Command("echo", "abc123def4567890abc123def4567890abc123de")
+	}
+
+	sha, err := gitCmd.GetFullSha("abc123")
+	assert.NoError(t, err)
+	assert.EqualValues(t, "abc123def4567890abc123def4567890abc123de", sha)
+}
+
+// TestGitCommandIsHeadDetached is a function.
+func TestGitCommandIsHeadDetached(t *testing.T) {
 	type scenario struct {
 		testName string
 		command  func(string, ...string) *exec.Cmd
-		test     func(error)
-		tracked  bool
+		expected bool
 	}
 
 	scenarios := []scenario{
 		{
-			"Remove an untracked file from staging",
+			"on a branch",
 			func(cmd string, args ...string) *exec.Cmd {
 				assert.EqualValues(t, "git", cmd)
-				assert.EqualValues(t, []string{"rm", "--cached", "test.txt"}, args)
-
-				return exec.Command("echo")
-			},
-			func(err error) {
-				assert.NoError(t, err)
+				assert.EqualValues(t, []string{"symbolic-ref", "-q", "HEAD"}, args)
+				return exec.Command("echo", "refs/heads/master")
 			},
 			false,
 		},
 		{
-			"Remove a tracked file from staging",
+			"detached",
 			func(cmd string, args ...string) *exec.Cmd {
-				assert.EqualValues(t, "git", cmd)
-				assert.EqualValues(t, []string{"reset", "HEAD", "test.txt"}, args)
-
-				return exec.Command("echo")
-			},
-			func(err error) {
-				assert.NoError(t, err)
+				return exec.Command("test", "1", "=", "2")
 			},
 			true,
 		},
@@ -1108,67 +1281,136 @@ func TestGitCommandUnstageFile(t *testing.T) {
 		t.Run(s.testName, func(t *testing.T) {
 			gitCmd := NewDummyGitCommand()
 			gitCmd.OSCommand.command = s.command
-			s.test(gitCmd.UnStageFile("test.txt", s.tracked))
+			assert.EqualValues(t, s.expected, gitCmd.IsHeadDetached())
 		})
 	}
 }
 
-// TestGitCommandIsInMergeState is a function.
-func TestGitCommandIsInMergeState(t *testing.T) {
+// TestGitCommandIsHeadOnABranch is a function.
+func TestGitCommandIsHeadOnABranch(t *testing.T) {
 	type scenario struct {
 		testName string
 		command  func(string, ...string) *exec.Cmd
-		test     func(bool, error)
+		expected bool
 	}
 
 	scenarios := []scenario{
 		{
-			"An error occurred when running status command",
+			"a branch contains HEAD",
 			func(cmd string, args ...string) *exec.Cmd {
 				assert.EqualValues(t, "git", cmd)
-				assert.EqualValues(t, []string{"status", "--untracked-files=all"}, args)
-
-				return exec.Command("test")
-			},
-			func(isInMergeState bool, err error) {
-				assert.Error(t, err)
-				assert.False(t, isInMergeState)
+				assert.EqualValues(t, []string{"for-each-ref", "--contains", "HEAD", "--format=%(refname)", "refs/heads"}, args)
+				return exec.Command("echo", "refs/heads/master")
 			},
+			true,
 		},
 		{
-			"Is not in merge state",
+			"no branch contains HEAD",
 			func(cmd string, args ...string) *exec.Cmd {
-				assert.EqualValues(t, "git", cmd)
-				assert.EqualValues(t, []string{"status", "--untracked-files=all"}, args)
 				return exec.Command("echo")
 			},
-			func(isInMergeState bool, err error) {
-				assert.False(t, isInMergeState)
-				assert.NoError(t, err)
+			false,
+		},
+		{
+			"command fails",
+			func(cmd string, args ...string) *exec.Cmd {
+				return exec.Command("test", "1", "=", "2")
 			},
+			false,
 		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = s.command
+			assert.EqualValues(t, s.expected, gitCmd.IsHeadOnABranch())
+		})
+	}
+}
+
+// TestGitCommandGetBisectInfo is a function.
+func TestGitCommandGetBisectInfo(t *testing.T) {
+	dotGitDir, err := ioutil.TempDir("", "lazygit-bisect")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dotGitDir)
+
+	gitCmd := NewDummyGitCommand()
+	gitCmd.DotGitDir = dotGitDir
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.Fail(t, "there's no bisect so git shouldn't be asked about it")
+		return nil
+	}
+
+	info, err := gitCmd.GetBisectInfo()
+	assert.NoError(t, err)
+	assert.Nil(t, info)
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dotGitDir, "BISECT_START"), []byte("master\n"), 0644))
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		switch args[0] {
+		case "for-each-ref":
+			assert.EqualValues(t, []string{"for-each-ref", "--format=%(refname)%00%(objectname)", "refs/bisect"}, args)
+			return exec.Command("printf", `refs/bisect/bad\0aaa\nrefs/bisect/good-bbb\0bbb\nrefs/bisect/skip-ccc\0ccc\n`)
+		case "rev-parse":
+			assert.EqualValues(t, []string{"rev-parse", "HEAD"}, args)
+			return exec.Command("echo", "ddd")
+		default:
+			assert.EqualValues(t, []string{"rev-list", "--count", "aaa", "--not", "bbb"}, args)
+			return exec.Command("echo", "5")
+		}
+	}
+
+	info, err = gitCmd.GetBisectInfo()
+	assert.NoError(t, err)
+	assert.EqualValues(t, &BisectInfo{
+		Bad:       "aaa",
+		Good:      []string{"bbb"},
+		Skipped:   []string{"ccc"},
+		Current:   "ddd",
+		Remaining: 4,
+	}, info)
+}
+
+// TestGitCommandMarkBisect is a function.
+func TestGitCommandMarkBisect(t *testing.T) {
+	type scenario struct {
+		testName string
+		command  func(string, ...string) *exec.Cmd
+		test     func(string, error)
+	}
+
+	scenarios := []scenario{
 		{
-			"Command output contains conclude merge",
+			"more commits to test",
 			func(cmd string, args ...string) *exec.Cmd {
 				assert.EqualValues(t, "git", cmd)
-				assert.EqualValues(t, []string{"status", "--untracked-files=all"}, args)
-				return exec.Command("echo", "'conclude merge'")
+				assert.EqualValues(t, []string{"bisect", "good", "abc123"}, args)
+				return exec.Command("echo", "Bisecting: 1 revision left to test after this (roughly 1 step)\n[def456] second")
 			},
-			func(isInMergeState bool, err error) {
-				assert.True(t, isInMergeState)
+			func(firstBad string, err error) {
 				assert.NoError(t, err)
+				assert.EqualValues(t, "", firstBad)
 			},
 		},
 		{
-			"Command output contains unmerged paths",
+			"first bad commit found",
 			func(cmd string, args ...string) *exec.Cmd {
-				assert.EqualValues(t, "git", cmd)
-				assert.EqualValues(t, []string{"status", "--untracked-files=all"}, args)
-				return exec.Command("echo", "'unmerged paths'")
+				return exec.Command("echo", "def456 is the first bad commit\ncommit def456\nAuthor: me")
 			},
-			func(isInMergeState bool, err error) {
-				assert.True(t, isInMergeState)
+			func(firstBad string, err error) {
 				assert.NoError(t, err)
+				assert.EqualValues(t, "def456", firstBad)
+			},
+		},
+		{
+			"git fails",
+			func(cmd string, args ...string) *exec.Cmd {
+				return exec.Command("test", "1", "=", "2")
+			},
+			func(firstBad string, err error) {
+				assert.Error(t, err)
 			},
 		},
 	}
@@ -1177,288 +1419,2528 @@ func TestGitCommandIsInMergeState(t *testing.T) {
 		t.Run(s.testName, func(t *testing.T) {
 			gitCmd := NewDummyGitCommand()
 			gitCmd.OSCommand.command = s.command
-			s.test(gitCmd.IsInMergeState())
+			s.test(gitCmd.MarkBisect("good", "abc123"))
 		})
 	}
 }
 
-// TestGitCommandDiscardAllFileChanges is a function.
-func TestGitCommandDiscardAllFileChanges(t *testing.T) {
+// TestGitCommandRewordCommitWithMessage is a function.
+func TestGitCommandRewordCommitWithMessage(t *testing.T) {
 	type scenario struct {
-		testName   string
-		command    func() (func(string, ...string) *exec.Cmd, *[][]string)
-		test       func(*[][]string, error)
-		file       *File
-		removeFile func(string) error
+		testName string
+		index    int
+		command  func(string, ...string) *exec.Cmd
+	}
+
+	commits := []*Commit{
+		{Sha: "a", Name: "third"},
+		{Sha: "b", Name: "second"},
+		{Sha: "c", Name: "first"},
 	}
 
 	scenarios := []scenario{
 		{
-			"An error occurred when resetting",
-			func() (func(string, ...string) *exec.Cmd, *[][]string) {
-				cmdsCalled := [][]string{}
-				return func(cmd string, args ...string) *exec.Cmd {
-					cmdsCalled = append(cmdsCalled, args)
-
-					return exec.Command("test")
-				}, &cmdsCalled
-			},
-			func(cmdsCalled *[][]string, err error) {
-				assert.Error(t, err)
-				assert.Len(t, *cmdsCalled, 1)
+			"top commit",
+			0,
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git commit --allow-empty --amend -m \"new message\"",
+					Replace: "echo",
+				},
+			}),
+		},
+		{
+			"older commit",
+			1,
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git rebase --interactive --autostash c",
+					Replace: "echo",
+				},
+				{
+					Expect:  "git commit --allow-empty --amend -m \"new message\"",
+					Replace: "echo",
+				},
+				{
+					Expect:  "git rebase --continue",
+					Replace: "echo",
+				},
+			}),
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = s.command
+			assert.NoError(t, gitCmd.RewordCommitWithMessage(commits, s.index, "new message"))
+		})
+	}
+}
+
+// TestGitCommandResetToCommit is a function.
+func TestGitCommandResetToCommit(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"reset", "--hard", "78976bc"}, args)
+
+		return exec.Command("echo")
+	}
+
+	assert.NoError(t, gitCmd.ResetToCommit("78976bc", "hard"))
+}
+
+// TestGitCommandNewBranch is a function.
+func TestGitCommandNewBranch(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"checkout", "-b", "test"}, args)
+
+		return exec.Command("echo")
+	}
+
+	assert.NoError(t, gitCmd.NewBranch("test"))
+}
+
+// TestGitCommandGetBranchUpstream is a function.
+func TestGitCommandGetBranchUpstream(t *testing.T) {
+	type scenario struct {
+		testName           string
+		config             map[string]string
+		expectedRemote     string
+		expectedBranchName string
+	}
+
+	scenarios := []scenario{
+		{
+			"Tracks a remote branch",
+			map[string]string{"branch.test.remote": "origin", "branch.test.merge": "refs/heads/other"},
+			"origin",
+			"other",
+		},
+		{
+			"Tracks a local branch",
+			map[string]string{"branch.test.remote": ".", "branch.test.merge": "refs/heads/master"},
+			"",
+			"",
+		},
+		{
+			"Doesn't track a branch",
+			map[string]string{},
+			"",
+			"",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.getLocalGitConfig = func(key string) (string, error) {
+				return s.config[key], nil
+			}
+			remoteName, branchName := gitCmd.GetBranchUpstream("test")
+			assert.EqualValues(t, s.expectedRemote, remoteName)
+			assert.EqualValues(t, s.expectedBranchName, branchName)
+		})
+	}
+}
+
+// TestGitCommandDeleteRemoteBranch is a function.
+func TestGitCommandDeleteRemoteBranch(t *testing.T) {
+	type scenario struct {
+		testName string
+		command  func(string, ...string) *exec.Cmd
+		test     func(error)
+	}
+
+	scenarios := []scenario{
+		{
+			"Deletes the branch",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"push", "origin", "--delete", "test"}, args)
+
+				return exec.Command("echo")
+			},
+			func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			"Deleting fails",
+			func(cmd string, args ...string) *exec.Cmd {
+				return exec.Command("test")
+			},
+			func(err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.DeleteRemoteBranch("origin", "test", func(passOrUname string) string {
+				return "-"
+			}))
+		})
+	}
+}
+
+// TestGitCommandRenameBranch is a function.
+func TestGitCommandRenameBranch(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"branch", "-m", "old", "new"}, args)
+
+		return exec.Command("echo")
+	}
+
+	assert.NoError(t, gitCmd.RenameBranch("old", "new"))
+}
+
+// TestGitCommandRenameRemoteBranch is a function.
+func TestGitCommandRenameRemoteBranch(t *testing.T) {
+	type scenario struct {
+		testName string
+		command  func(string, ...string) *exec.Cmd
+		test     func(error)
+	}
+
+	scenarios := []scenario{
+		{
+			"Renames the branch",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"push", "--set-upstream", "origin", "new", ":old"}, args)
+
+				return exec.Command("echo")
+			},
+			func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			"Pushing fails",
+			func(cmd string, args ...string) *exec.Cmd {
+				return exec.Command("test")
+			},
+			func(err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.RenameRemoteBranch("origin", "old", "new", func(passOrUname string) string {
+				return "-"
+			}))
+		})
+	}
+}
+
+// TestGitCommandGetRemoteBranchNames is a function.
+func TestGitCommandGetRemoteBranchNames(t *testing.T) {
+	type scenario struct {
+		testName string
+		command  func(string, ...string) *exec.Cmd
+		test     func([]string, error)
+	}
+
+	scenarios := []scenario{
+		{
+			"Lists the remote branches without the remotes' HEADs",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git for-each-ref --format=%(refname) refs/remotes",
+					Replace: "echo \"refs/remotes/origin/HEAD\nrefs/remotes/origin/master\nrefs/remotes/origin/feature/a\nrefs/remotes/fork/master\"",
+				},
+			}),
+			func(names []string, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, []string{"origin/master", "origin/feature/a", "fork/master"}, names)
+			},
+		},
+		{
+			"for-each-ref fails",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git for-each-ref --format=%(refname) refs/remotes",
+					Replace: "test 1 = 2",
+				},
+			}),
+			func(names []string, err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	gitCmd := NewDummyGitCommand()
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.GetRemoteBranchNames())
+		})
+	}
+}
+
+// TestGitCommandGetRemoteBranches is a function.
+func TestGitCommandGetRemoteBranches(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = test.CreateMockCommand(t, []*test.CommandSwapper{
+		{
+			Expect:  "git for-each-ref --format=%(refname) refs/remotes",
+			Replace: "echo \"refs/remotes/origin/HEAD\nrefs/remotes/origin/master\nrefs/remotes/origin/feature/a\nrefs/remotes/upstream/master\"",
+		},
+	})
+
+	branches, err := gitCmd.GetRemoteBranches()
+	assert.NoError(t, err)
+	assert.EqualValues(t, []*RemoteBranch{
+		{RemoteName: "origin", Name: "master"},
+		{RemoteName: "origin", Name: "feature/a"},
+		{RemoteName: "upstream", Name: "master"},
+	}, branches)
+}
+
+// TestGitCommandGetLocalBranchNames is a function.
+func TestGitCommandGetLocalBranchNames(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = test.CreateMockCommand(t, []*test.CommandSwapper{
+		{
+			Expect:  "git for-each-ref --format=%(refname) refs/heads",
+			Replace: "echo \"refs/heads/master\nrefs/heads/feature/a\"",
+		},
+	})
+
+	names, err := gitCmd.GetLocalBranchNames()
+	assert.NoError(t, err)
+	assert.EqualValues(t, []string{"master", "feature/a"}, names)
+}
+
+// TestGitCommandGetPreviousBranch is a function.
+func TestGitCommandGetPreviousBranch(t *testing.T) {
+	type scenario struct {
+		testName string
+		reflog   string
+		expected string
+	}
+
+	scenarios := []scenario{
+		{
+			"The last branch checked out",
+			"checkout: moving from feature/a to abc1234\ncheckout: moving from master to feature/a",
+			"feature/a",
+		},
+		{
+			"Commits and deleted branches are skipped",
+			"checkout: moving from abc1234 to def5678\ncheckout: moving from gone to abc1234\ncheckout: moving from master to gone",
+			"master",
+		},
+		{
+			"No checkouts",
+			"",
+			"",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git reflog -n100 --pretty=%gs --grep-reflog=checkout: HEAD",
+					Replace: fmt.Sprintf("echo \"%s\"", s.reflog),
+				},
+				{
+					Expect:  "git for-each-ref --format=%(refname) refs/heads",
+					Replace: "echo \"refs/heads/master\nrefs/heads/feature/a\"",
+				},
+			})
+
+			branchName, err := gitCmd.GetPreviousBranch()
+			assert.NoError(t, err)
+			assert.EqualValues(t, s.expected, branchName)
+		})
+	}
+}
+
+// TestGitCommandGetTagNames is a function.
+func TestGitCommandGetTagNames(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = test.CreateMockCommand(t, []*test.CommandSwapper{
+		{
+			Expect:  "git for-each-ref --format=%(refname) refs/tags",
+			Replace: "echo \"refs/tags/v1.0\nrefs/tags/release/2\"",
+		},
+	})
+
+	names, err := gitCmd.GetTagNames()
+	assert.NoError(t, err)
+	assert.EqualValues(t, []string{"v1.0", "release/2"}, names)
+}
+
+// TestGitCommandSetBranchUpstream is a function.
+func TestGitCommandSetBranchUpstream(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"branch", "--set-upstream-to=origin/other", "test"}, args)
+
+		return exec.Command("echo")
+	}
+
+	assert.NoError(t, gitCmd.SetBranchUpstream("test", "origin/other"))
+}
+
+// TestGitCommandUnsetBranchUpstream is a function.
+func TestGitCommandUnsetBranchUpstream(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"branch", "--unset-upstream", "test"}, args)
+
+		return exec.Command("echo")
+	}
+
+	assert.NoError(t, gitCmd.UnsetBranchUpstream("test"))
+}
+
+// TestGitCommandDeleteBranch is a function.
+func TestGitCommandDeleteBranch(t *testing.T) {
+	type scenario struct {
+		testName string
+		branch   string
+		force    bool
+		command  func(string, ...string) *exec.Cmd
+		test     func(error)
+	}
+
+	scenarios := []scenario{
+		{
+			"Delete a branch",
+			"test",
+			false,
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"branch", "-d", "test"}, args)
+
+				return exec.Command("echo")
+			},
+			func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			"Force delete a branch",
+			"test",
+			true,
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"branch", "-D", "test"}, args)
+
+				return exec.Command("echo")
+			},
+			func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.DeleteBranch(s.branch, s.force))
+		})
+	}
+}
+
+// TestGitCommandMerge is a function.
+func TestGitCommandMerge(t *testing.T) {
+	type scenario struct {
+		testName string
+		flag     string
+		expected []string
+	}
+
+	scenarios := []scenario{
+		{
+			"Plain merge",
+			"",
+			[]string{"merge", "--no-edit", "test"},
+		},
+		{
+			"Merge with a flag",
+			"--squash",
+			[]string{"merge", "--no-edit", "--squash", "test"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, s.expected, args)
+
+				return exec.Command("echo")
+			}
+
+			assert.NoError(t, gitCmd.Merge("test", s.flag))
+		})
+	}
+}
+
+// TestGitCommandUsingGpg is a function.
+func TestGitCommandUsingGpg(t *testing.T) {
+	type scenario struct {
+		testName           string
+		getLocalGitConfig  func(string) (string, error)
+		getGlobalGitConfig func(string) (string, error)
+		test               func(bool)
+	}
+
+	scenarios := []scenario{
+		{
+			"Option global and local config commit.gpgsign is not set",
+			func(string) (string, error) {
+				return "", nil
+			},
+			func(string) (string, error) {
+				return "", nil
+			},
+			func(gpgEnabled bool) {
+				assert.False(t, gpgEnabled)
+			},
+		},
+		{
+			"Option global config commit.gpgsign is not set, fallback on local config",
+			func(string) (string, error) {
+				return "", nil
+			},
+			func(string) (string, error) {
+				return "true", nil
+			},
+			func(gpgEnabled bool) {
+				assert.True(t, gpgEnabled)
+			},
+		},
+		{
+			"Option commit.gpgsign is true",
+			func(string) (string, error) {
+				return "True", nil
+			},
+			func(string) (string, error) {
+				return "", nil
+			},
+			func(gpgEnabled bool) {
+				assert.True(t, gpgEnabled)
+			},
+		},
+		{
+			"Option commit.gpgsign is on",
+			func(string) (string, error) {
+				return "ON", nil
+			},
+			func(string) (string, error) {
+				return "", nil
+			},
+			func(gpgEnabled bool) {
+				assert.True(t, gpgEnabled)
+			},
+		},
+		{
+			"Option commit.gpgsign is yes",
+			func(string) (string, error) {
+				return "YeS", nil
+			},
+			func(string) (string, error) {
+				return "", nil
+			},
+			func(gpgEnabled bool) {
+				assert.True(t, gpgEnabled)
+			},
+		},
+		{
+			"Option commit.gpgsign is 1",
+			func(string) (string, error) {
+				return "1", nil
+			},
+			func(string) (string, error) {
+				return "", nil
+			},
+			func(gpgEnabled bool) {
+				assert.True(t, gpgEnabled)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.getGlobalGitConfig = s.getGlobalGitConfig
+			gitCmd.getLocalGitConfig = s.getLocalGitConfig
+			s.test(gitCmd.usingGpg())
+		})
+	}
+}
+
+// TestGitCommandCommit is a function.
+func TestGitCommandCommit(t *testing.T) {
+	type scenario struct {
+		testName           string
+		command            func(string, ...string) *exec.Cmd
+		getGlobalGitConfig func(string) (string, error)
+		test               func(*exec.Cmd, error)
+		flags              string
+	}
+
+	scenarios := []scenario{
+		{
+			"Commit using gpg",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "bash", cmd)
+				assert.EqualValues(t, []string{"-c", `git commit  -m 'test'`}, args)
+
+				return exec.Command("echo")
+			},
+			func(string) (string, error) {
+				return "true", nil
+			},
+			func(cmd *exec.Cmd, err error) {
+				assert.NotNil(t, cmd)
+				assert.Nil(t, err)
+			},
+			"",
+		},
+		{
+			"Commit without using gpg",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"commit", "-m", "test"}, args)
+
+				return exec.Command("echo")
+			},
+			func(string) (string, error) {
+				return "false", nil
+			},
+			func(cmd *exec.Cmd, err error) {
+				assert.Nil(t, cmd)
+				assert.Nil(t, err)
+			},
+			"",
+		},
+		{
+			"Commit with --no-verify flag",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"commit", "--no-verify", "-m", "test"}, args)
+
+				return exec.Command("echo")
+			},
+			func(string) (string, error) {
+				return "false", nil
+			},
+			func(cmd *exec.Cmd, err error) {
+				assert.Nil(t, cmd)
+				assert.Nil(t, err)
+			},
+			"--no-verify",
+		},
+		{
+			"Commit without using gpg with an error",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"commit", "-m", "test"}, args)
+
+				return exec.Command("test")
+			},
+			func(string) (string, error) {
+				return "false", nil
+			},
+			func(cmd *exec.Cmd, err error) {
+				assert.Nil(t, cmd)
+				assert.Error(t, err)
+			},
+			"",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.getGlobalGitConfig = s.getGlobalGitConfig
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.Commit("test", s.flags))
+		})
+	}
+}
+
+// TestGitCommandAmendHead is a function.
+func TestGitCommandAmendHead(t *testing.T) {
+	type scenario struct {
+		testName           string
+		command            func(string, ...string) *exec.Cmd
+		getGlobalGitConfig func(string) (string, error)
+		test               func(*exec.Cmd, error)
+	}
+
+	scenarios := []scenario{
+		{
+			"Amend commit using gpg",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "bash", cmd)
+				assert.EqualValues(t, []string{"-c", "git commit --amend --no-edit"}, args)
+
+				return exec.Command("echo")
+			},
+			func(string) (string, error) {
+				return "true", nil
+			},
+			func(cmd *exec.Cmd, err error) {
+				assert.NotNil(t, cmd)
+				assert.Nil(t, err)
+			},
+		},
+		{
+			"Amend commit without using gpg",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"commit", "--amend", "--no-edit"}, args)
+
+				return exec.Command("echo")
+			},
+			func(string) (string, error) {
+				return "false", nil
+			},
+			func(cmd *exec.Cmd, err error) {
+				assert.Nil(t, cmd)
+				assert.Nil(t, err)
+			},
+		},
+		{
+			"Amend commit without using gpg with an error",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"commit", "--amend", "--no-edit"}, args)
+
+				return exec.Command("test")
+			},
+			func(string) (string, error) {
+				return "false", nil
+			},
+			func(cmd *exec.Cmd, err error) {
+				assert.Nil(t, cmd)
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.getGlobalGitConfig = s.getGlobalGitConfig
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.AmendHead())
+		})
+	}
+}
+
+// TestGitCommandPull is a function.
+func TestGitCommandPull(t *testing.T) {
+	type scenario struct {
+		testName     string
+		mode         string
+		expectedArgs []string
+	}
+
+	scenarios := []scenario{
+		{
+			"Pull with a merge",
+			PullModeMerge,
+			[]string{"pull", "--no-edit", "--no-rebase"},
+		},
+		{
+			"Pull with a rebase",
+			PullModeRebase,
+			[]string{"pull", "--no-edit", "--rebase"},
+		},
+		{
+			"Pull fast-forward only",
+			PullModeFFOnly,
+			[]string{"pull", "--no-edit", "--ff-only"},
+		},
+		{
+			"Pull the way git's config says to",
+			PullModeDefault,
+			[]string{"pull", "--no-edit"},
+		},
+		{
+			"Pull with an unknown mode",
+			"sideways",
+			[]string{"pull", "--no-edit"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, s.expectedArgs, args)
+
+				return exec.Command("echo")
+			}
+
+			err := gitCmd.Pull(s.mode, func(passOrUname string) string {
+				return "\n"
+			})
+			assert.NoError(t, err)
+		})
+	}
+}
+
+// TestGitCommandPush is a function.
+func TestGitCommandPush(t *testing.T) {
+	type scenario struct {
+		testName  string
+		command   func(string, ...string) *exec.Cmd
+		forcePush bool
+		test      func(error)
+	}
+
+	scenarios := []scenario{
+		{
+			"Push with force disabled",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"push", "-u", "origin", "test"}, args)
+
+				return exec.Command("echo")
+			},
+			false,
+			func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			"Push with force enabled",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"push", "--force-with-lease", "-u", "origin", "test"}, args)
+
+				return exec.Command("echo")
+			},
+			true,
+			func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			"Push with an error occurring",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"push", "-u", "origin", "test"}, args)
+				return exec.Command("test")
+			},
+			false,
+			func(err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = s.command
+			err := gitCmd.Push("test", s.forcePush, func(passOrUname string) string {
+				return "\n"
+			})
+			s.test(err)
+		})
+	}
+}
+
+// TestGitCommandCreateTag is a function.
+func TestGitCommandCreateTag(t *testing.T) {
+	type scenario struct {
+		testName     string
+		message      string
+		expectedArgs []string
+	}
+
+	scenarios := []scenario{
+		{
+			"lightweight tag",
+			"",
+			[]string{"tag", "v1.0", "abc123"},
+		},
+		{
+			"annotated tag",
+			"first release",
+			[]string{"tag", "-a", "v1.0", "abc123", "-m", "first release"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, s.expectedArgs, args)
+
+				return exec.Command("echo")
+			}
+
+			assert.NoError(t, gitCmd.CreateTag("v1.0", "abc123", s.message))
+		})
+	}
+}
+
+// TestGitCommandPushTag is a function.
+func TestGitCommandPushTag(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"push", "origin", "refs/tags/v1.0"}, args)
+
+		return exec.Command("echo")
+	}
+
+	err := gitCmd.PushTag("v1.0", func(passOrUname string) string {
+		return "\n"
+	})
+	assert.NoError(t, err)
+}
+
+// TestGitCommandCatFile is a function.
+func TestGitCommandCatFile(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "cat", cmd)
+		assert.EqualValues(t, []string{"test.txt"}, args)
+
+		return exec.Command("echo", "-n", "test")
+	}
+
+	o, err := gitCmd.CatFile("test.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "test", o)
+}
+
+// TestGitCommandStageFile is a function.
+func TestGitCommandStageFile(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"add", "test.txt"}, args)
+
+		return exec.Command("echo")
+	}
+
+	assert.NoError(t, gitCmd.StageFile("test.txt"))
+}
+
+// TestGitCommandStageTracked is a function.
+func TestGitCommandStageTracked(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"add", "-u"}, args)
+
+		return exec.Command("echo")
+	}
+
+	assert.NoError(t, gitCmd.StageTracked())
+}
+
+// TestGitCommandLockLFSFile is a function.
+func TestGitCommandLockLFSFile(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"lfs", "lock", "assets/my image.png"}, args)
+
+		return exec.Command("echo")
+	}
+
+	assert.NoError(t, gitCmd.LockLFSFile("assets/my image.png"))
+}
+
+// TestGitCommandUnlockLFSFile is a function.
+func TestGitCommandUnlockLFSFile(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"lfs", "unlock", "image.png"}, args)
+
+		return exec.Command("echo")
+	}
+
+	assert.NoError(t, gitCmd.UnlockLFSFile("image.png"))
+}
+
+// TestGitCommandUnstageFile is a function.
+func TestGitCommandUnstageFile(t *testing.T) {
+	type scenario struct {
+		testName string
+		command  func(string, ...string) *exec.Cmd
+		test     func(error)
+		tracked  bool
+	}
+
+	scenarios := []scenario{
+		{
+			"Remove an untracked file from staging",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"rm", "--cached", "test.txt"}, args)
+
+				return exec.Command("echo")
+			},
+			func(err error) {
+				assert.NoError(t, err)
+			},
+			false,
+		},
+		{
+			"Remove a tracked file from staging",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"reset", "HEAD", "test.txt"}, args)
+
+				return exec.Command("echo")
+			},
+			func(err error) {
+				assert.NoError(t, err)
+			},
+			true,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.UnStageFile("test.txt", s.tracked))
+		})
+	}
+}
+
+// TestGitCommandIsInMergeState is a function.
+func TestGitCommandIsInMergeState(t *testing.T) {
+	type scenario struct {
+		testName   string
+		headFile   string
+		unmerged   string
+		merging    bool
+		picking    bool
+		reverting  bool
+		conflicted bool
+	}
+
+	scenarios := []scenario{
+		{
+			"No operation in progress",
+			"",
+			"",
+			false,
+			false,
+			false,
+			false,
+		},
+		{
+			"Merging",
+			"MERGE_HEAD",
+			"",
+			true,
+			false,
+			false,
+			false,
+		},
+		{
+			"Conflicts from popping a stash",
+			"",
+			"100644 abc123 1\tfile.txt",
+			false,
+			false,
+			false,
+			true,
+		},
+		{
+			"Cherry-picking",
+			"CHERRY_PICK_HEAD",
+			"100644 abc123 1\tfile.txt",
+			false,
+			true,
+			false,
+			true,
+		},
+		{
+			"Reverting",
+			"REVERT_HEAD",
+			"",
+			false,
+			false,
+			true,
+			false,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "lazygit-dotgit")
+			assert.NoError(t, err)
+			defer os.RemoveAll(dir)
+			if s.headFile != "" {
+				assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, s.headFile), []byte("abc123\n"), 0644))
+			}
+
+			gitCmd := NewDummyGitCommand()
+			gitCmd.DotGitDir = dir
+			gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, []string{"ls-files", "-u"}, args)
+
+				return exec.Command("echo", s.unmerged)
+			}
+
+			merging, err := gitCmd.IsInMergeState()
+			assert.NoError(t, err)
+			assert.EqualValues(t, s.merging, merging)
+
+			picking, err := gitCmd.IsCherryPicking()
+			assert.NoError(t, err)
+			assert.EqualValues(t, s.picking, picking)
+
+			reverting, err := gitCmd.IsReverting()
+			assert.NoError(t, err)
+			assert.EqualValues(t, s.reverting, reverting)
+
+			conflicted, err := gitCmd.HasUnmergedFiles()
+			assert.NoError(t, err)
+			assert.EqualValues(t, s.conflicted, conflicted)
+		})
+	}
+}
+
+// TestGitCommandDiscardAllFileChanges is a function.
+func TestGitCommandDiscardAllFileChanges(t *testing.T) {
+	type scenario struct {
+		testName   string
+		command    func() (func(string, ...string) *exec.Cmd, *[][]string)
+		test       func(*[][]string, error)
+		file       *File
+		removeFile func(string) error
+	}
+
+	scenarios := []scenario{
+		{
+			"An error occurred when resetting",
+			func() (func(string, ...string) *exec.Cmd, *[][]string) {
+				cmdsCalled := [][]string{}
+				return func(cmd string, args ...string) *exec.Cmd {
+					cmdsCalled = append(cmdsCalled, args)
+
+					return exec.Command("test")
+				}, &cmdsCalled
+			},
+			func(cmdsCalled *[][]string, err error) {
+				assert.Error(t, err)
+				assert.Len(t, *cmdsCalled, 1)
+				assert.EqualValues(t, *cmdsCalled, [][]string{
+					{"reset", "--", "test"},
+				})
+			},
+			&File{
+				Name:             "test",
+				HasStagedChanges: true,
+			},
+			func(string) error {
+				return nil
+			},
+		},
+		{
+			"An error occurred when removing file",
+			func() (func(string, ...string) *exec.Cmd, *[][]string) {
+				cmdsCalled := [][]string{}
+				return func(cmd string, args ...string) *exec.Cmd {
+					cmdsCalled = append(cmdsCalled, args)
+
+					return exec.Command("test")
+				}, &cmdsCalled
+			},
+			func(cmdsCalled *[][]string, err error) {
+				assert.Error(t, err)
+				assert.EqualError(t, err, "an error occurred when removing file")
+				assert.Len(t, *cmdsCalled, 0)
+			},
+			&File{
+				Name:    "test",
+				Tracked: false,
+			},
+			func(string) error {
+				return fmt.Errorf("an error occurred when removing file")
+			},
+		},
+		{
+			"An error occurred with checkout",
+			func() (func(string, ...string) *exec.Cmd, *[][]string) {
+				cmdsCalled := [][]string{}
+				return func(cmd string, args ...string) *exec.Cmd {
+					cmdsCalled = append(cmdsCalled, args)
+
+					return exec.Command("test")
+				}, &cmdsCalled
+			},
+			func(cmdsCalled *[][]string, err error) {
+				assert.Error(t, err)
+				assert.Len(t, *cmdsCalled, 1)
+				assert.EqualValues(t, *cmdsCalled, [][]string{
+					{"checkout", "--", "test"},
+				})
+			},
+			&File{
+				Name:             "test",
+				Tracked:          true,
+				HasStagedChanges: false,
+			},
+			func(string) error {
+				return nil
+			},
+		},
+		{
+			"Checkout only",
+			func() (func(string, ...string) *exec.Cmd, *[][]string) {
+				cmdsCalled := [][]string{}
+				return func(cmd string, args ...string) *exec.Cmd {
+					cmdsCalled = append(cmdsCalled, args)
+
+					return exec.Command("echo")
+				}, &cmdsCalled
+			},
+			func(cmdsCalled *[][]string, err error) {
+				assert.NoError(t, err)
+				assert.Len(t, *cmdsCalled, 1)
+				assert.EqualValues(t, *cmdsCalled, [][]string{
+					{"checkout", "--", "test"},
+				})
+			},
+			&File{
+				Name:             "test",
+				Tracked:          true,
+				HasStagedChanges: false,
+			},
+			func(string) error {
+				return nil
+			},
+		},
+		{
+			"Reset and checkout staged changes",
+			func() (func(string, ...string) *exec.Cmd, *[][]string) {
+				cmdsCalled := [][]string{}
+				return func(cmd string, args ...string) *exec.Cmd {
+					cmdsCalled = append(cmdsCalled, args)
+
+					return exec.Command("echo")
+				}, &cmdsCalled
+			},
+			func(cmdsCalled *[][]string, err error) {
+				assert.NoError(t, err)
+				assert.Len(t, *cmdsCalled, 2)
+				assert.EqualValues(t, *cmdsCalled, [][]string{
+					{"reset", "--", "test"},
+					{"checkout", "--", "test"},
+				})
+			},
+			&File{
+				Name:             "test",
+				Tracked:          true,
+				HasStagedChanges: true,
+			},
+			func(string) error {
+				return nil
+			},
+		},
+		{
+			"Reset and checkout merge conflicts",
+			func() (func(string, ...string) *exec.Cmd, *[][]string) {
+				cmdsCalled := [][]string{}
+				return func(cmd string, args ...string) *exec.Cmd {
+					cmdsCalled = append(cmdsCalled, args)
+
+					return exec.Command("echo")
+				}, &cmdsCalled
+			},
+			func(cmdsCalled *[][]string, err error) {
+				assert.NoError(t, err)
+				assert.Len(t, *cmdsCalled, 2)
+				assert.EqualValues(t, *cmdsCalled, [][]string{
+					{"reset", "--", "test"},
+					{"checkout", "--", "test"},
+				})
+			},
+			&File{
+				Name:              "test",
+				Tracked:           true,
+				HasMergeConflicts: true,
+			},
+			func(string) error {
+				return nil
+			},
+		},
+		{
+			"Reset and remove",
+			func() (func(string, ...string) *exec.Cmd, *[][]string) {
+				cmdsCalled := [][]string{}
+				return func(cmd string, args ...string) *exec.Cmd {
+					cmdsCalled = append(cmdsCalled, args)
+
+					return exec.Command("echo")
+				}, &cmdsCalled
+			},
+			func(cmdsCalled *[][]string, err error) {
+				assert.NoError(t, err)
+				assert.Len(t, *cmdsCalled, 1)
 				assert.EqualValues(t, *cmdsCalled, [][]string{
 					{"reset", "--", "test"},
 				})
 			},
 			&File{
-				Name:             "test",
-				HasStagedChanges: true,
-			},
-			func(string) error {
-				return nil
+				Name:             "test",
+				Tracked:          false,
+				HasStagedChanges: true,
+			},
+			func(filename string) error {
+				assert.Equal(t, "test", filename)
+				return nil
+			},
+		},
+		{
+			"Remove only",
+			func() (func(string, ...string) *exec.Cmd, *[][]string) {
+				cmdsCalled := [][]string{}
+				return func(cmd string, args ...string) *exec.Cmd {
+					cmdsCalled = append(cmdsCalled, args)
+
+					return exec.Command("echo")
+				}, &cmdsCalled
+			},
+			func(cmdsCalled *[][]string, err error) {
+				assert.NoError(t, err)
+				assert.Len(t, *cmdsCalled, 0)
+			},
+			&File{
+				Name:             "test",
+				Tracked:          false,
+				HasStagedChanges: false,
+			},
+			func(filename string) error {
+				assert.Equal(t, "test", filename)
+				return nil
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			var cmdsCalled *[][]string
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command, cmdsCalled = s.command()
+			gitCmd.removeFile = s.removeFile
+			s.test(cmdsCalled, gitCmd.DiscardAllFileChanges(s.file))
+		})
+	}
+}
+
+// TestGitCommandShow is a function.
+func TestGitCommandShow(t *testing.T) {
+	type scenario struct {
+		testName string
+		arg      string
+		command  func(string, ...string) *exec.Cmd
+		test     func(string, error)
+	}
+
+	scenarios := []scenario{
+		{
+			"regular commit",
+			"456abcde",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git show --color 456abcde",
+					Replace: "echo \"commit ccc771d8b13d5b0d4635db4463556366470fd4f6\nblah\"",
+				},
+				{
+					Expect:  "git rev-list -1 --merges 456abcde^...456abcde",
+					Replace: "echo",
+				},
+			}),
+			func(result string, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "commit ccc771d8b13d5b0d4635db4463556366470fd4f6\nblah\n", result)
+			},
+		},
+		{
+			"merge commit",
+			"456abcde",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git show --color 456abcde",
+					Replace: "echo \"commit ccc771d8b13d5b0d4635db4463556366470fd4f6\nMerge: 1a6a69a 3b51d7c\"",
+				},
+				{
+					Expect:  "git rev-list -1 --merges 456abcde^...456abcde",
+					Replace: "echo aa30e006433628ba9281652952b34d8aacda9c01",
+				},
+				{
+					Expect:  "git diff --color 1a6a69a...3b51d7c",
+					Replace: "echo blah",
+				},
+			}),
+			func(result string, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "commit ccc771d8b13d5b0d4635db4463556366470fd4f6\nMerge: 1a6a69a 3b51d7c\nblah\n", result)
+			},
+		},
+	}
+
+	gitCmd := NewDummyGitCommand()
+
+	for _, s := range scenarios {
+		gitCmd.OSCommand.command = s.command
+		s.test(gitCmd.Show(s.arg))
+	}
+}
+
+// TestGitCommandGetCommitDetails is a function.
+func TestGitCommandGetCommitDetails(t *testing.T) {
+	type scenario struct {
+		testName string
+		command  func(string, ...string) *exec.Cmd
+		test     func(*CommitDetails, error)
+	}
+
+	scenarios := []scenario{
+		{
+			"gets the details and the branches the commit is in",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				switch args[0] {
+				case "log":
+					assert.EqualValues(t, []string{"log", "-1", "--date=iso", "--format=" + commitDetailsFormat, "abc123"}, args)
+					return exec.Command("printf", `abc123\0Jesse\0j@example.com\0date\0Jesse\0j@example.com\0date\0master\0N\0\0\0\n`)
+				case "for-each-ref":
+					assert.EqualValues(t, []string{"for-each-ref", "--contains", "abc123", "--format=%(refname:short)", "refs/heads", "refs/remotes"}, args)
+					return exec.Command("printf", `master\nfeature\n`)
+				}
+				return nil
+			},
+			func(details *CommitDetails, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, "Jesse", details.AuthorName)
+				assert.EqualValues(t, []string{"master"}, details.Refs)
+				assert.EqualValues(t, []string{"master", "feature"}, details.ContainingBranches)
+			},
+		},
+		{
+			"returns error when git log fails",
+			func(cmd string, args ...string) *exec.Cmd {
+				return exec.Command("test", "1", "=", "2")
+			},
+			func(details *CommitDetails, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, details)
+			},
+		},
+	}
+
+	gitCmd := NewDummyGitCommand()
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.GetCommitDetails("abc123"))
+		})
+	}
+}
+
+// TestGitCommandGetTagsContaining is a function.
+func TestGitCommandGetTagsContaining(t *testing.T) {
+	type scenario struct {
+		testName string
+		command  func(string, ...string) *exec.Cmd
+		test     func([]string, error)
+	}
+
+	scenarios := []scenario{
+		{
+			"lists the tags oldest first",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git tag --contains abc123 --sort=creatordate",
+					Replace: "echo \"v1.0.0\nv1.1.0\"",
+				},
+			}),
+			func(tags []string, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, []string{"v1.0.0", "v1.1.0"}, tags)
+			},
+		},
+		{
+			"no tags",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git tag --contains abc123 --sort=creatordate",
+					Replace: "echo -n",
+				},
+			}),
+			func(tags []string, err error) {
+				assert.NoError(t, err)
+				assert.Len(t, tags, 0)
+			},
+		},
+		{
+			"returns error when git tag fails",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git tag --contains abc123 --sort=creatordate",
+					Replace: "test 1 = 2",
+				},
+			}),
+			func(tags []string, err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	gitCmd := NewDummyGitCommand()
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.GetTagsContaining("abc123"))
+		})
+	}
+}
+
+// TestGitCommandShowAgainstParent is a function.
+func TestGitCommandShowAgainstParent(t *testing.T) {
+	type scenario struct {
+		testName string
+		parent   int
+		command  func(string, ...string) *exec.Cmd
+		test     func(string, error)
+	}
+
+	scenarios := []scenario{
+		{
+			"against the second parent",
+			2,
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git show --color --no-patch 456abcde",
+					Replace: "echo \"commit ccc771d8b13d5b0d4635db4463556366470fd4f6\nMerge: 1a6a69a 3b51d7c\"",
+				},
+				{
+					Expect:  "git diff --color 456abcde^2 456abcde",
+					Replace: "echo blah",
+				},
+			}),
+			func(result string, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "commit ccc771d8b13d5b0d4635db4463556366470fd4f6\nMerge: 1a6a69a 3b51d7c\n\nblah\n", result)
+			},
+		},
+		{
+			"diff fails",
+			1,
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git show --color --no-patch 456abcde",
+					Replace: "echo commit",
+				},
+				{
+					Expect:  "git diff --color 456abcde^1 456abcde",
+					Replace: "test 1 = 2",
+				},
+			}),
+			func(result string, err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	gitCmd := NewDummyGitCommand()
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.ShowAgainstParent("456abcde", s.parent))
+		})
+	}
+}
+
+// TestGitCommandExportPatches is a function.
+func TestGitCommandExportPatches(t *testing.T) {
+	type scenario struct {
+		testName string
+		upTo     string
+		command  func(string, ...string) *exec.Cmd
+		test     func([]string, error)
+	}
+
+	scenarios := []scenario{
+		{
+			"one commit",
+			"",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git format-patch -o patches -1 456abcde",
+					Replace: "echo patches/0001-blah.patch",
+				},
+			}),
+			func(paths []string, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, []string{"patches/0001-blah.patch"}, paths)
+			},
+		},
+		{
+			"the commit and the ones after it",
+			"HEAD",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git rev-parse --verify --quiet 456abcde^",
+					Replace: "echo 123abcde",
+				},
+				{
+					Expect:  "git format-patch -o patches 456abcde^..HEAD",
+					Replace: "printf patches/0001-blah.patch\\\\npatches/0002-more.patch",
+				},
+			}),
+			func(paths []string, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, []string{"patches/0001-blah.patch", "patches/0002-more.patch"}, paths)
+			},
+		},
+		{
+			"the root commit and the ones after it",
+			"develop",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git rev-parse --verify --quiet 456abcde^",
+					Replace: "test 1 = 2",
+				},
+				{
+					Expect:  "git format-patch -o patches --root develop",
+					Replace: "echo patches/0001-root.patch",
+				},
+			}),
+			func(paths []string, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, []string{"patches/0001-root.patch"}, paths)
+			},
+		},
+		{
+			"format-patch fails",
+			"",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git format-patch -o patches -1 456abcde",
+					Replace: "test 1 = 2",
+				},
+			}),
+			func(paths []string, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, paths)
+			},
+		},
+	}
+
+	gitCmd := NewDummyGitCommand()
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.ExportPatches("456abcde", s.upTo, "patches"))
+		})
+	}
+}
+
+// TestGitCommandApplyPatchFileAsCommits is a function.
+func TestGitCommandApplyPatchFileAsCommits(t *testing.T) {
+	type scenario struct {
+		testName string
+		command  func(string, ...string) *exec.Cmd
+		test     func(error)
+	}
+
+	scenarios := []scenario{
+		{
+			"patches apply",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git am --3way patches/0001-blah.patch",
+					Replace: "echo",
+				},
+			}),
+			func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			"patches don't apply",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git am --3way patches/0001-blah.patch",
+					Replace: "test 1 = 2",
+				},
+				{
+					Expect:  "git am --abort",
+					Replace: "echo",
+				},
+			}),
+			func(err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	gitCmd := NewDummyGitCommand()
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.ApplyPatchFileAsCommits("patches/0001-blah.patch"))
+		})
+	}
+}
+
+// TestGitCommandResolveCommit is a function.
+func TestGitCommandResolveCommit(t *testing.T) {
+	type scenario struct {
+		testName string
+		command  func(string, ...string) *exec.Cmd
+		test     func(string, error)
+	}
+
+	scenarios := []scenario{
+		{
+			"ref points to a commit",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git rev-parse --verify --quiet HEAD~20^{commit}",
+					Replace: "echo 456abcde456abcde456abcde456abcde456abcde",
+				},
+			}),
+			func(sha string, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, "456abcde456abcde456abcde456abcde456abcde", sha)
+			},
+		},
+		{
+			"ref doesn't point to a commit",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git rev-parse --verify --quiet HEAD~20^{commit}",
+					Replace: "test 1 = 2",
+				},
+			}),
+			func(sha string, err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	gitCmd := NewDummyGitCommand()
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.ResolveCommit("HEAD~20"))
+		})
+	}
+}
+
+// TestGitCommandCountCommitsAfter is a function.
+func TestGitCommandCountCommitsAfter(t *testing.T) {
+	type scenario struct {
+		testName string
+		command  func(string, ...string) *exec.Cmd
+		test     func(int, error)
+	}
+
+	scenarios := []scenario{
+		{
+			"count commits",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git rev-list --count 456abcde..develop",
+					Replace: "echo 42",
+				},
+			}),
+			func(count int, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, 42, count)
+			},
+		},
+		{
+			"rev-list fails",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git rev-list --count 456abcde..develop",
+					Replace: "test 1 = 2",
+				},
+			}),
+			func(count int, err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	gitCmd := NewDummyGitCommand()
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.CountCommitsAfter("456abcde", "develop"))
+		})
+	}
+}
+
+// TestGitCommandCountCommitsOnlyOnBranch is a function.
+func TestGitCommandCountCommitsOnlyOnBranch(t *testing.T) {
+	type scenario struct {
+		testName string
+		command  func(string, ...string) *exec.Cmd
+		test     func(int, error)
+	}
+
+	scenarios := []scenario{
+		{
+			"count commits",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git rev-list --count refs/heads/feature --not --exclude=refs/heads/feature --all",
+					Replace: "echo 3",
+				},
+			}),
+			func(count int, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, 3, count)
+			},
+		},
+		{
+			"rev-list fails",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git rev-list --count refs/heads/feature --not --exclude=refs/heads/feature --all",
+					Replace: "test 1 = 2",
+				},
+			}),
+			func(count int, err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	gitCmd := NewDummyGitCommand()
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.CountCommitsOnlyOnBranch("feature"))
+		})
+	}
+}
+
+// TestGitCommandCheckout is a function.
+func TestGitCommandCheckout(t *testing.T) {
+	type scenario struct {
+		testName string
+		command  func(string, ...string) *exec.Cmd
+		test     func(error)
+		force    bool
+	}
+
+	scenarios := []scenario{
+		{
+			"Checkout",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"checkout", "test"}, args)
+
+				return exec.Command("echo")
+			},
+			func(err error) {
+				assert.NoError(t, err)
+			},
+			false,
+		},
+		{
+			"Checkout forced",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"checkout", "--force", "test"}, args)
+
+				return exec.Command("echo")
+			},
+			func(err error) {
+				assert.NoError(t, err)
+			},
+			true,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.Checkout("test", s.force))
+		})
+	}
+}
+
+// TestGitCommandGetBranchGraph is a function.
+func TestGitCommandGetBranchGraph(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"log", "--graph", "--color", "--abbrev-commit", "--decorate", "--date=relative", "--pretty=medium", "-100", "test"}, args)
+
+		return exec.Command("echo")
+	}
+
+	_, err := gitCmd.GetBranchGraph("test")
+	assert.NoError(t, err)
+}
+
+// TestGitCommandDiff is a function.
+func TestGitCommandDiff(t *testing.T) {
+	type scenario struct {
+		testName         string
+		command          func(string, ...string) *exec.Cmd
+		file             *File
+		plain            bool
+		cached           bool
+		ignoreWhitespace bool
+	}
+
+	scenarios := []scenario{
+		{
+			"Default case",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"diff", "--unified=3", "--color", "--", "test.txt"}, args)
+
+				return exec.Command("echo")
+			},
+			&File{
+				Name:             "test.txt",
+				HasStagedChanges: false,
+				Tracked:          true,
+			},
+			false,
+			false,
+			false,
+		},
+		{
+			"Default case",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"diff", "--unified=3", "--", "test.txt"}, args)
+
+				return exec.Command("echo")
+			},
+			&File{
+				Name:             "test.txt",
+				HasStagedChanges: false,
+				Tracked:          true,
 			},
+			true,
+			false,
+			false,
 		},
 		{
-			"An error occurred when removing file",
-			func() (func(string, ...string) *exec.Cmd, *[][]string) {
-				cmdsCalled := [][]string{}
-				return func(cmd string, args ...string) *exec.Cmd {
-					cmdsCalled = append(cmdsCalled, args)
+			"All changes staged",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"diff", "--unified=3", "--color", "--cached", "--", "test.txt"}, args)
 
-					return exec.Command("test")
-				}, &cmdsCalled
-			},
-			func(cmdsCalled *[][]string, err error) {
-				assert.Error(t, err)
-				assert.EqualError(t, err, "an error occurred when removing file")
-				assert.Len(t, *cmdsCalled, 0)
+				return exec.Command("echo")
 			},
 			&File{
-				Name:    "test",
-				Tracked: false,
+				Name:               "test.txt",
+				HasStagedChanges:   true,
+				HasUnstagedChanges: false,
+				Tracked:            true,
 			},
-			func(string) error {
-				return fmt.Errorf("an error occurred when removing file")
+			false,
+			false,
+			false,
+		},
+		{
+			"File not tracked and file has no staged changes",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"diff", "--unified=3", "--color", "--no-index", "/dev/null", "test.txt"}, args)
+
+				return exec.Command("echo")
+			},
+			&File{
+				Name:             "test.txt",
+				HasStagedChanges: false,
+				Tracked:          false,
 			},
+			false,
+			false,
+			false,
 		},
 		{
-			"An error occurred with checkout",
-			func() (func(string, ...string) *exec.Cmd, *[][]string) {
-				cmdsCalled := [][]string{}
-				return func(cmd string, args ...string) *exec.Cmd {
-					cmdsCalled = append(cmdsCalled, args)
+			"Cached changes requested",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"diff", "--unified=3", "--color", "--cached", "--", "test.txt"}, args)
 
-					return exec.Command("test")
-				}, &cmdsCalled
+				return exec.Command("echo")
 			},
-			func(cmdsCalled *[][]string, err error) {
-				assert.Error(t, err)
-				assert.Len(t, *cmdsCalled, 1)
-				assert.EqualValues(t, *cmdsCalled, [][]string{
-					{"checkout", "--", "test"},
-				})
+			&File{
+				Name:               "test.txt",
+				HasStagedChanges:   true,
+				HasUnstagedChanges: true,
+				Tracked:            true,
+			},
+			false,
+			true,
+			false,
+		},
+		{
+			"Ignoring whitespace",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"diff", "--unified=3", "--color", "--ignore-all-space", "--", "test.txt"}, args)
+
+				return exec.Command("echo")
 			},
 			&File{
-				Name:             "test",
-				Tracked:          true,
+				Name:             "test.txt",
 				HasStagedChanges: false,
+				Tracked:          true,
 			},
-			func(string) error {
+			false,
+			false,
+			true,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = s.command
+			gitCmd.Diff(s.file, s.plain, s.cached, s.ignoreWhitespace, 3)
+		})
+	}
+}
+
+// TestGitCommandDiffUntrackedNotShown is a function.
+func TestGitCommandDiffUntrackedNotShown(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lazygit-diff")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	bigFileName := dir + "/big.txt"
+	assert.NoError(t, ioutil.WriteFile(bigFileName, []byte{}, 0644))
+	assert.NoError(t, os.Truncate(bigFileName, maxUntrackedFileDiffSize+1))
+
+	type scenario struct {
+		testName string
+		fileName string
+		expected string
+	}
+
+	scenarios := []scenario{
+		{
+			"File too big",
+			bigFileName,
+			"This untracked file is too big to show (1024 KB). Only files up to 1024 KB are shown",
+		},
+		{
+			"Directory",
+			dir,
+			"This untracked directory is probably a repository of its own, so its contents aren't shown",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+				t.Fatalf("unexpected command: %s %v", cmd, args)
 				return nil
+			}
+			assert.EqualValues(t, s.expected, gitCmd.Diff(&File{Name: s.fileName}, false, false, false, 3))
+		})
+	}
+}
+
+// TestGitCommandCurrentBranchName is a function.
+func TestGitCommandCurrentBranchName(t *testing.T) {
+	type scenario struct {
+		testName string
+		command  func(string, ...string) *exec.Cmd
+		test     func(string, error)
+	}
+
+	scenarios := []scenario{
+		{
+			"says we are on the master branch if we are",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.Equal(t, "git", cmd)
+				return exec.Command("echo", "master")
+			},
+			func(output string, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, "master", output)
 			},
 		},
 		{
-			"Checkout only",
-			func() (func(string, ...string) *exec.Cmd, *[][]string) {
-				cmdsCalled := [][]string{}
-				return func(cmd string, args ...string) *exec.Cmd {
-					cmdsCalled = append(cmdsCalled, args)
+			"falls back to git rev-parse if symbolic-ref fails",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
 
-					return exec.Command("echo")
-				}, &cmdsCalled
+				switch args[0] {
+				case "symbolic-ref":
+					assert.EqualValues(t, []string{"symbolic-ref", "--short", "HEAD"}, args)
+					return exec.Command("test")
+				case "rev-parse":
+					assert.EqualValues(t, []string{"rev-parse", "--short", "HEAD"}, args)
+					return exec.Command("echo", "master")
+				}
+
+				return nil
 			},
-			func(cmdsCalled *[][]string, err error) {
+			func(output string, err error) {
 				assert.NoError(t, err)
-				assert.Len(t, *cmdsCalled, 1)
-				assert.EqualValues(t, *cmdsCalled, [][]string{
-					{"checkout", "--", "test"},
-				})
+				assert.EqualValues(t, "master", output)
 			},
-			&File{
-				Name:             "test",
-				Tracked:          true,
-				HasStagedChanges: false,
+		},
+		{
+			"bubbles up error if there is one",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.Equal(t, "git", cmd)
+				return exec.Command("test")
 			},
-			func(string) error {
-				return nil
+			func(output string, err error) {
+				assert.Error(t, err)
+				assert.EqualValues(t, "", output)
 			},
 		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.CurrentBranchName())
+		})
+	}
+}
+
+func TestGitCommandApplyPatch(t *testing.T) {
+	type scenario struct {
+		testName string
+		flags    []string
+		command  func(string, ...string) *exec.Cmd
+		test     func(string, error)
+	}
+
+	scenarios := []scenario{
 		{
-			"Reset and checkout staged changes",
-			func() (func(string, ...string) *exec.Cmd, *[][]string) {
-				cmdsCalled := [][]string{}
-				return func(cmd string, args ...string) *exec.Cmd {
-					cmdsCalled = append(cmdsCalled, args)
+			"valid case",
+			[]string{"cached"},
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.Equal(t, "git", cmd)
+				assert.EqualValues(t, []string{"apply", "--cached"}, args[0:2])
+				filename := args[2]
+				content, err := ioutil.ReadFile(filename)
+				assert.NoError(t, err)
 
-					return exec.Command("echo")
-				}, &cmdsCalled
+				assert.Equal(t, "test", string(content))
+
+				return exec.Command("echo", "done")
 			},
-			func(cmdsCalled *[][]string, err error) {
+			func(output string, err error) {
 				assert.NoError(t, err)
-				assert.Len(t, *cmdsCalled, 2)
-				assert.EqualValues(t, *cmdsCalled, [][]string{
-					{"reset", "--", "test"},
-					{"checkout", "--", "test"},
-				})
-			},
-			&File{
-				Name:             "test",
-				Tracked:          true,
-				HasStagedChanges: true,
+				assert.EqualValues(t, "done\n", output)
 			},
-			func(string) error {
-				return nil
+		},
+		{
+			"command returns error",
+			[]string{"cached"},
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.Equal(t, "git", cmd)
+				assert.EqualValues(t, []string{"apply", "--cached"}, args[0:2])
+				filename := args[2]
+				// TODO: Ideally we want to mock out OSCommand here so that we're not
+				// double handling testing it's CreateTempFile functionality,
+				// but it is going to take a bit of work to make a proper mock for it
+				// so I'm leaving it for another PR
+				content, err := ioutil.ReadFile(filename)
+				assert.NoError(t, err)
+
+				assert.Equal(t, "test", string(content))
+
+				return exec.Command("test")
+			},
+			func(output string, err error) {
+				assert.Error(t, err)
 			},
 		},
 		{
-			"Reset and checkout merge conflicts",
-			func() (func(string, ...string) *exec.Cmd, *[][]string) {
-				cmdsCalled := [][]string{}
-				return func(cmd string, args ...string) *exec.Cmd {
-					cmdsCalled = append(cmdsCalled, args)
+			"working tree",
+			[]string{"reverse"},
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.Equal(t, "git", cmd)
+				assert.EqualValues(t, []string{"apply", "--reverse"}, args[0:2])
+				filename := args[2]
+				content, err := ioutil.ReadFile(filename)
+				assert.NoError(t, err)
 
-					return exec.Command("echo")
-				}, &cmdsCalled
+				assert.Equal(t, "test", string(content))
+
+				return exec.Command("echo", "done")
 			},
-			func(cmdsCalled *[][]string, err error) {
+			func(output string, err error) {
 				assert.NoError(t, err)
-				assert.Len(t, *cmdsCalled, 2)
-				assert.EqualValues(t, *cmdsCalled, [][]string{
-					{"reset", "--", "test"},
-					{"checkout", "--", "test"},
-				})
-			},
-			&File{
-				Name:              "test",
-				Tracked:           true,
-				HasMergeConflicts: true,
-			},
-			func(string) error {
-				return nil
+				assert.EqualValues(t, "done\n", output)
 			},
 		},
 		{
-			"Reset and remove",
-			func() (func(string, ...string) *exec.Cmd, *[][]string) {
-				cmdsCalled := [][]string{}
-				return func(cmd string, args ...string) *exec.Cmd {
-					cmdsCalled = append(cmdsCalled, args)
+			"reverse",
+			[]string{"cached", "reverse"},
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.Equal(t, "git", cmd)
+				assert.EqualValues(t, []string{"apply", "--cached", "--reverse"}, args[0:3])
+				filename := args[3]
+				content, err := ioutil.ReadFile(filename)
+				assert.NoError(t, err)
 
-					return exec.Command("echo")
-				}, &cmdsCalled
+				assert.Equal(t, "test", string(content))
+
+				return exec.Command("echo", "done")
 			},
-			func(cmdsCalled *[][]string, err error) {
+			func(output string, err error) {
 				assert.NoError(t, err)
-				assert.Len(t, *cmdsCalled, 1)
-				assert.EqualValues(t, *cmdsCalled, [][]string{
-					{"reset", "--", "test"},
-				})
-			},
-			&File{
-				Name:             "test",
-				Tracked:          false,
-				HasStagedChanges: true,
-			},
-			func(filename string) error {
-				assert.Equal(t, "test", filename)
-				return nil
+				assert.EqualValues(t, "done\n", output)
 			},
 		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.ApplyPatch("test", s.flags...))
+		})
+	}
+}
+
+// TestGitCommandMoveCommitDown is a function.
+func TestGitCommandMoveCommitDown(t *testing.T) {
+	commits := []*Commit{
+		{Sha: "a", Name: "third"},
+		{Sha: "b", Name: "second"},
+		{Sha: "c", Name: "first"},
+	}
+
+	var rebaseCmd *exec.Cmd
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"rebase", "--interactive", "--autostash", "c"}, args)
+
+		rebaseCmd = exec.Command("echo")
+		return rebaseCmd
+	}
+
+	assert.NoError(t, gitCmd.MoveCommitDown(commits, 0))
+	assert.Contains(t, rebaseCmd.Env, "LAZYGIT_REBASE_TODO=pick a third\npick b second\n")
+	// the commits we were given are left as they were
+	assert.EqualValues(t, []string{"a", "b", "c"}, []string{commits[0].Sha, commits[1].Sha, commits[2].Sha})
+
+	assert.Error(t, gitCmd.MoveCommitDown(commits, 1))
+}
+
+// TestGitCommandRevert is a function.
+func TestGitCommandRevert(t *testing.T) {
+	type scenario struct {
+		testName     string
+		mainline     int
+		expectedArgs []string
+	}
+
+	scenarios := []scenario{
 		{
-			"Remove only",
-			func() (func(string, ...string) *exec.Cmd, *[][]string) {
-				cmdsCalled := [][]string{}
-				return func(cmd string, args ...string) *exec.Cmd {
-					cmdsCalled = append(cmdsCalled, args)
+			"regular commit",
+			0,
+			[]string{"revert", "--no-edit", "abc123"},
+		},
+		{
+			"merge commit against its first parent",
+			1,
+			[]string{"revert", "--no-edit", "-m", "1", "abc123"},
+		},
+	}
 
-					return exec.Command("echo")
-				}, &cmdsCalled
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, s.expectedArgs, args)
+
+				return exec.Command("echo")
+			}
+
+			assert.NoError(t, gitCmd.Revert("abc123", s.mainline))
+		})
+	}
+}
+
+// TestGitCommandGetCommitParents is a function.
+func TestGitCommandGetCommitParents(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = test.CreateMockCommand(t, []*test.CommandSwapper{
+		{
+			Expect:  "git log -1 --format=%p abc123",
+			Replace: "echo def456 789aaa",
+		},
+		{
+			Expect:  "git log -1 --format=%s def456",
+			Replace: "echo first parent",
+		},
+		{
+			Expect:  "git log -1 --format=%s 789aaa",
+			Replace: "echo second parent",
+		},
+	})
+
+	parents, err := gitCmd.GetCommitParents("abc123")
+	assert.NoError(t, err)
+	assert.EqualValues(t, []*Commit{{Sha: "def456", Name: "first parent"}, {Sha: "789aaa", Name: "second parent"}}, parents)
+}
+
+// TestGitCommandCherryPickCommits is a function.
+func TestGitCommandCherryPickCommits(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"cherry-pick", "c", "b", "a"}, args)
+
+		return exec.Command("echo")
+	}
+
+	commits := []*Commit{{Sha: "a", Name: "third"}, {Sha: "b", Name: "second"}, {Sha: "c", Name: "first"}}
+	assert.NoError(t, gitCmd.CherryPickCommits(commits))
+}
+
+// TestGitCommandExclude is a function.
+func TestGitCommandExclude(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lazygit-exclude")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// the info directory doesn't exist yet, so it has to be created
+	excludeFile := dir + "/info/exclude"
+
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"rev-parse", "--git-path", "info/exclude"}, args)
+		return exec.Command("echo", excludeFile)
+	}
+
+	assert.NoError(t, gitCmd.Exclude("a.txt"))
+	assert.NoError(t, gitCmd.Exclude("*.log"))
+
+	content, err := ioutil.ReadFile(excludeFile)
+	assert.NoError(t, err)
+	assert.EqualValues(t, "\na.txt\n*.log", string(content))
+}
+
+// TestGitCommandCheckPatch is a function.
+func TestGitCommandCheckPatch(t *testing.T) {
+	type scenario struct {
+		testName string
+		command  func(string, ...string) *exec.Cmd
+		test     func(error)
+	}
+
+	patch := `diff --git a/f.txt b/f.txt
+index 1a2b3c4..5d6e7f8 100644
+--- a/f.txt
++++ b/f.txt
+@@ -1,2 +1,2 @@
+ a
+-b
++c
+@@ -10,2 +10,3 @@
+ x
+ y
++z
+`
+
+	scenarios := []scenario{
+		{
+			"patch applies",
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.Equal(t, "git", cmd)
+				assert.EqualValues(t, []string{"apply", "--check", "--cached"}, args[0:3])
+				return exec.Command("echo")
 			},
-			func(cmdsCalled *[][]string, err error) {
+			func(err error) {
 				assert.NoError(t, err)
-				assert.Len(t, *cmdsCalled, 0)
 			},
-			&File{
-				Name:             "test",
-				Tracked:          false,
-				HasStagedChanges: false,
+		},
+		{
+			"patch doesn't apply",
+			func(cmd string, args ...string) *exec.Cmd {
+				return exec.Command("sh", "-c", "echo 'error: patch failed: f.txt:10'; exit 1")
 			},
-			func(filename string) error {
-				assert.Equal(t, "test", filename)
-				return nil
+			func(err error) {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), "@@ -10,2 +10,3 @@")
+				assert.Contains(t, err.Error(), "error: patch failed: f.txt:10")
+			},
+		},
+		{
+			"failing hunk can't be found",
+			func(cmd string, args ...string) *exec.Cmd {
+				return exec.Command("sh", "-c", "echo 'error: patch failed: other.txt:10'; exit 1")
+			},
+			func(err error) {
+				assert.EqualError(t, err, "error: patch failed: other.txt:10\n")
 			},
 		},
 	}
 
 	for _, s := range scenarios {
 		t.Run(s.testName, func(t *testing.T) {
-			var cmdsCalled *[][]string
 			gitCmd := NewDummyGitCommand()
-			gitCmd.OSCommand.command, cmdsCalled = s.command()
-			gitCmd.removeFile = s.removeFile
-			s.test(cmdsCalled, gitCmd.DiscardAllFileChanges(s.file))
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.CheckPatch(patch, "cached"))
 		})
 	}
 }
 
-// TestGitCommandShow is a function.
-func TestGitCommandShow(t *testing.T) {
+// TestGitCommandRebaseBranch is a function.
+func TestGitCommandRebaseBranch(t *testing.T) {
 	type scenario struct {
 		testName string
 		arg      string
 		command  func(string, ...string) *exec.Cmd
-		test     func(string, error)
+		test     func(error)
 	}
 
 	scenarios := []scenario{
 		{
-			"regular commit",
-			"456abcde",
+			"successful rebase",
+			"master",
 			test.CreateMockCommand(t, []*test.CommandSwapper{
 				{
-					Expect:  "git show --color 456abcde",
-					Replace: "echo \"commit ccc771d8b13d5b0d4635db4463556366470fd4f6\nblah\"",
-				},
-				{
-					Expect:  "git rev-list -1 --merges 456abcde^...456abcde",
+					Expect:  "git rebase --interactive --autostash master",
 					Replace: "echo",
 				},
 			}),
-			func(result string, err error) {
+			func(err error) {
 				assert.NoError(t, err)
-				assert.Equal(t, "commit ccc771d8b13d5b0d4635db4463556366470fd4f6\nblah\n", result)
 			},
 		},
 		{
-			"merge commit",
-			"456abcde",
+			"unsuccessful rebase",
+			"master",
 			test.CreateMockCommand(t, []*test.CommandSwapper{
 				{
-					Expect:  "git show --color 456abcde",
-					Replace: "echo \"commit ccc771d8b13d5b0d4635db4463556366470fd4f6\nMerge: 1a6a69a 3b51d7c\"",
+					Expect:  "git rebase --interactive --autostash master",
+					Replace: "test",
+				},
+			}),
+			func(err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	gitCmd := NewDummyGitCommand()
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.RebaseBranch(s.arg))
+		})
+	}
+}
+
+// TestGitCommandCheckoutFile is a function.
+func TestGitCommandCheckoutFile(t *testing.T) {
+	type scenario struct {
+		testName  string
+		commitSha string
+		fileName  string
+		command   func(string, ...string) *exec.Cmd
+		test      func(error)
+	}
+
+	scenarios := []scenario{
+		{
+			"typical case",
+			"11af912",
+			"test999.txt",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git checkout 11af912 -- test999.txt",
+					Replace: "echo",
 				},
+			}),
+			func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			"file name with a space",
+			"origin/master",
+			"test 999.txt",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
 				{
-					Expect:  "git rev-list -1 --merges 456abcde^...456abcde",
-					Replace: "echo aa30e006433628ba9281652952b34d8aacda9c01",
+					Expect:  `git checkout origin/master -- "test 999.txt"`,
+					Replace: "echo",
 				},
+			}),
+			func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			"returns error if there is one",
+			"11af912",
+			"test999.txt",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
 				{
-					Expect:  "git diff --color 1a6a69a...3b51d7c",
-					Replace: "echo blah",
+					Expect:  "git checkout 11af912 -- test999.txt",
+					Replace: "test",
 				},
 			}),
-			func(result string, err error) {
-				assert.NoError(t, err)
-				assert.Equal(t, "commit ccc771d8b13d5b0d4635db4463556366470fd4f6\nMerge: 1a6a69a 3b51d7c\nblah\n", result)
+			func(err error) {
+				assert.Error(t, err)
 			},
 		},
 	}
@@ -1466,309 +3948,349 @@ func TestGitCommandShow(t *testing.T) {
 	gitCmd := NewDummyGitCommand()
 
 	for _, s := range scenarios {
-		gitCmd.OSCommand.command = s.command
-		s.test(gitCmd.Show(s.arg))
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.CheckoutFile(s.commitSha, s.fileName))
+		})
 	}
 }
 
-// TestGitCommandCheckout is a function.
-func TestGitCommandCheckout(t *testing.T) {
+// TestGitCommandDiscardOldFileChanges is a function.
+func TestGitCommandDiscardOldFileChanges(t *testing.T) {
 	type scenario struct {
-		testName string
-		command  func(string, ...string) *exec.Cmd
-		test     func(error)
-		force    bool
+		testName          string
+		getLocalGitConfig func(string) (string, error)
+		commits           []*Commit
+		commitIndex       int
+		fileName          string
+		command           func(string, ...string) *exec.Cmd
+		test              func(error)
 	}
 
 	scenarios := []scenario{
 		{
-			"Checkout",
-			func(cmd string, args ...string) *exec.Cmd {
-				assert.EqualValues(t, "git", cmd)
-				assert.EqualValues(t, []string{"checkout", "test"}, args)
-
-				return exec.Command("echo")
+			"returns error when index outside of range of commits",
+			func(string) (string, error) {
+				return "", nil
 			},
+			[]*Commit{},
+			0,
+			"test999.txt",
+			nil,
 			func(err error) {
-				assert.NoError(t, err)
+				assert.Error(t, err)
 			},
-			false,
 		},
 		{
-			"Checkout forced",
-			func(cmd string, args ...string) *exec.Cmd {
-				assert.EqualValues(t, "git", cmd)
-				assert.EqualValues(t, []string{"checkout", "--force", "test"}, args)
-
-				return exec.Command("echo")
+			"returns error when using gpg",
+			func(string) (string, error) {
+				return "true", nil
 			},
+			[]*Commit{{Name: "commit", Sha: "123456"}},
+			0,
+			"test999.txt",
+			nil,
+			func(err error) {
+				assert.Error(t, err)
+			},
+		},
+		{
+			"checks out file if it already existed",
+			func(string) (string, error) {
+				return "", nil
+			},
+			[]*Commit{
+				{Name: "commit", Sha: "123456"},
+				{Name: "commit2", Sha: "abcdef"},
+			},
+			0,
+			"test999.txt",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git rebase --interactive --autostash abcdef",
+					Replace: "echo",
+				},
+				{
+					Expect:  "git cat-file -e HEAD^:test999.txt",
+					Replace: "echo",
+				},
+				{
+					Expect:  "git checkout HEAD^ -- test999.txt",
+					Replace: "echo",
+				},
+				{
+					Expect:  "git commit --amend --no-edit",
+					Replace: "echo",
+				},
+				{
+					Expect:  "git rebase --continue",
+					Replace: "echo",
+				},
+			}),
 			func(err error) {
 				assert.NoError(t, err)
 			},
-			true,
 		},
+		// test for when the file was created within the commit requires a refactor to support proper mocks
+		// currently we'd need to mock out the os.Remove function and that's gonna introduce tech debt
 	}
 
+	gitCmd := NewDummyGitCommand()
+
 	for _, s := range scenarios {
 		t.Run(s.testName, func(t *testing.T) {
-			gitCmd := NewDummyGitCommand()
 			gitCmd.OSCommand.command = s.command
-			s.test(gitCmd.Checkout("test", s.force))
+			gitCmd.getLocalGitConfig = s.getLocalGitConfig
+			s.test(gitCmd.DiscardOldFileChanges(s.commits, s.commitIndex, s.fileName))
 		})
 	}
 }
 
-// TestGitCommandGetBranchGraph is a function.
-func TestGitCommandGetBranchGraph(t *testing.T) {
-	gitCmd := NewDummyGitCommand()
-	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
-		assert.EqualValues(t, "git", cmd)
-		assert.EqualValues(t, []string{"log", "--graph", "--color", "--abbrev-commit", "--decorate", "--date=relative", "--pretty=medium", "-100", "test"}, args)
-
-		return exec.Command("echo")
-	}
-
-	_, err := gitCmd.GetBranchGraph("test")
-	assert.NoError(t, err)
-}
-
-// TestGitCommandDiff is a function.
-func TestGitCommandDiff(t *testing.T) {
+// TestGitCommandApplyPatchToCommit is a function.
+func TestGitCommandApplyPatchToCommit(t *testing.T) {
 	type scenario struct {
-		testName string
-		command  func(string, ...string) *exec.Cmd
-		file     *File
-		plain    bool
+		testName          string
+		getLocalGitConfig func(string) (string, error)
+		commits           []*Commit
+		commitIndex       int
+		test              func(error)
 	}
 
 	scenarios := []scenario{
 		{
-			"Default case",
-			func(cmd string, args ...string) *exec.Cmd {
-				assert.EqualValues(t, "git", cmd)
-				assert.EqualValues(t, []string{"diff", "--color", "--", "test.txt"}, args)
-
-				return exec.Command("echo")
-			},
-			&File{
-				Name:             "test.txt",
-				HasStagedChanges: false,
-				Tracked:          true,
-			},
-			false,
-		},
-		{
-			"Default case",
-			func(cmd string, args ...string) *exec.Cmd {
-				assert.EqualValues(t, "git", cmd)
-				assert.EqualValues(t, []string{"diff", "--", "test.txt"}, args)
-
-				return exec.Command("echo")
+			"returns error when index outside of range of commits",
+			func(string) (string, error) {
+				return "", nil
 			},
-			&File{
-				Name:             "test.txt",
-				HasStagedChanges: false,
-				Tracked:          true,
+			[]*Commit{},
+			0,
+			func(err error) {
+				assert.Error(t, err)
 			},
-			true,
 		},
 		{
-			"All changes staged",
-			func(cmd string, args ...string) *exec.Cmd {
-				assert.EqualValues(t, "git", cmd)
-				assert.EqualValues(t, []string{"diff", "--color", "--cached", "--", "test.txt"}, args)
-
-				return exec.Command("echo")
+			"returns error when using gpg",
+			func(string) (string, error) {
+				return "true", nil
 			},
-			&File{
-				Name:               "test.txt",
-				HasStagedChanges:   true,
-				HasUnstagedChanges: false,
-				Tracked:            true,
+			[]*Commit{{Name: "commit", Sha: "123456"}},
+			0,
+			func(err error) {
+				assert.Error(t, err)
 			},
-			false,
 		},
 		{
-			"File not tracked and file has no staged changes",
-			func(cmd string, args ...string) *exec.Cmd {
-				assert.EqualValues(t, "git", cmd)
-				assert.EqualValues(t, []string{"diff", "--color", "--no-index", "/dev/null", "test.txt"}, args)
-
-				return exec.Command("echo")
+			"returns error when there is no commit to rebase onto",
+			func(string) (string, error) {
+				return "", nil
 			},
-			&File{
-				Name:             "test.txt",
-				HasStagedChanges: false,
-				Tracked:          false,
+			[]*Commit{{Name: "commit", Sha: "123456"}},
+			0,
+			func(err error) {
+				assert.Error(t, err)
 			},
-			false,
 		},
+		// the temp file holding the patch has a random name, so testing the
+		// commands run in the happy path requires better mocks than we have
 	}
 
+	gitCmd := NewDummyGitCommand()
+
 	for _, s := range scenarios {
 		t.Run(s.testName, func(t *testing.T) {
-			gitCmd := NewDummyGitCommand()
-			gitCmd.OSCommand.command = s.command
-			gitCmd.Diff(s.file, s.plain)
+			gitCmd.getLocalGitConfig = s.getLocalGitConfig
+			s.test(gitCmd.ApplyPatchToCommit(s.commits, s.commitIndex, "test"))
 		})
 	}
 }
 
-// TestGitCommandCurrentBranchName is a function.
-func TestGitCommandCurrentBranchName(t *testing.T) {
+// TestGitCommandApplyPatchToCommitRebaseFails is a function.
+func TestGitCommandApplyPatchToCommitRebaseFails(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.getLocalGitConfig = func(string) (string, error) {
+		return "", nil
+	}
+	applied := [][]string{}
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		switch args[0] {
+		case "apply":
+			// the last arg is the temp file holding the patch
+			applied = append(applied, args[1:len(args)-1])
+			return exec.Command("echo")
+		case "rebase":
+			return exec.Command("test")
+		}
+		return exec.Command("echo")
+	}
+
+	commits := []*Commit{{Name: "commit", Sha: "123456"}, {Name: "parent", Sha: "abcdef"}}
+	assert.Error(t, gitCmd.ApplyPatchToCommit(commits, 0, "test"))
+	assert.EqualValues(t, [][]string{{"--reverse"}, {}}, applied)
+}
+
+// TestGitCommandSplitCommit is a function.
+func TestGitCommandSplitCommit(t *testing.T) {
 	type scenario struct {
-		testName string
-		command  func(string, ...string) *exec.Cmd
-		test     func(string, error)
+		testName          string
+		getLocalGitConfig func(string) (string, error)
+		commits           []*Commit
+		commitIndex       int
+		test              func(error)
 	}
 
 	scenarios := []scenario{
 		{
-			"says we are on the master branch if we are",
-			func(cmd string, args ...string) *exec.Cmd {
-				assert.Equal(t, "git", cmd)
-				return exec.Command("echo", "master")
+			"returns error when index outside of range of commits",
+			func(string) (string, error) {
+				return "", nil
 			},
-			func(output string, err error) {
-				assert.NoError(t, err)
-				assert.EqualValues(t, "master", output)
+			[]*Commit{},
+			0,
+			func(err error) {
+				assert.Error(t, err)
 			},
-		},
-		{
-			"falls back to git rev-parse if symbolic-ref fails",
-			func(cmd string, args ...string) *exec.Cmd {
-				assert.EqualValues(t, "git", cmd)
-
-				switch args[0] {
-				case "symbolic-ref":
-					assert.EqualValues(t, []string{"symbolic-ref", "--short", "HEAD"}, args)
-					return exec.Command("test")
-				case "rev-parse":
-					assert.EqualValues(t, []string{"rev-parse", "--short", "HEAD"}, args)
-					return exec.Command("echo", "master")
-				}
-
-				return nil
+		},
+		{
+			"returns error when using gpg",
+			func(string) (string, error) {
+				return "true", nil
 			},
-			func(output string, err error) {
-				assert.NoError(t, err)
-				assert.EqualValues(t, "master", output)
+			[]*Commit{{Name: "commit", Sha: "123456"}},
+			0,
+			func(err error) {
+				assert.Error(t, err)
 			},
 		},
 		{
-			"bubbles up error if there is one",
-			func(cmd string, args ...string) *exec.Cmd {
-				assert.Equal(t, "git", cmd)
-				return exec.Command("test")
+			"returns error when there is no commit to rebase onto",
+			func(string) (string, error) {
+				return "", nil
 			},
-			func(output string, err error) {
+			[]*Commit{{Name: "commit", Sha: "123456"}},
+			0,
+			func(err error) {
 				assert.Error(t, err)
-				assert.EqualValues(t, "", output)
 			},
 		},
 	}
 
+	gitCmd := NewDummyGitCommand()
+
 	for _, s := range scenarios {
 		t.Run(s.testName, func(t *testing.T) {
-			gitCmd := NewDummyGitCommand()
-			gitCmd.OSCommand.command = s.command
-			s.test(gitCmd.CurrentBranchName())
+			gitCmd.getLocalGitConfig = s.getLocalGitConfig
+			s.test(gitCmd.SplitCommit(s.commits, s.commitIndex, "test", "message"))
 		})
 	}
 }
 
-func TestGitCommandApplyPatch(t *testing.T) {
+// TestGitCommandAbsorbStagedChanges is a function.
+func TestGitCommandAbsorbStagedChanges(t *testing.T) {
 	type scenario struct {
-		testName string
-		command  func(string, ...string) *exec.Cmd
-		test     func(string, error)
+		testName          string
+		getLocalGitConfig func(string) (string, error)
+		command           func(string, ...string) *exec.Cmd
+		test              func([]*Commit, error)
 	}
 
+	diff := "diff --git a/file.go b/file.go\n--- a/file.go\n+++ b/file.go\n@@ -3 +3 @@\n-a\n+b"
+
 	scenarios := []scenario{
 		{
-			"valid case",
-			func(cmd string, args ...string) *exec.Cmd {
-				assert.Equal(t, "git", cmd)
-				assert.EqualValues(t, []string{"apply", "--cached"}, args[0:2])
-				filename := args[2]
-				content, err := ioutil.ReadFile(filename)
-				assert.NoError(t, err)
-
-				assert.Equal(t, "test", string(content))
-
-				return exec.Command("echo", "done")
+			"returns error when using gpg",
+			func(string) (string, error) {
+				return "true", nil
 			},
-			func(output string, err error) {
-				assert.NoError(t, err)
-				assert.EqualValues(t, "done\n", output)
+			test.CreateMockCommand(t, []*test.CommandSwapper{}),
+			func(commits []*Commit, err error) {
+				assert.Error(t, err)
 			},
 		},
 		{
-			"command returns error",
-			func(cmd string, args ...string) *exec.Cmd {
-				assert.Equal(t, "git", cmd)
-				assert.EqualValues(t, []string{"apply", "--cached"}, args[0:2])
-				filename := args[2]
-				// TODO: Ideally we want to mock out OSCommand here so that we're not
-				// double handling testing it's CreateTempFile functionality,
-				// but it is going to take a bit of work to make a proper mock for it
-				// so I'm leaving it for another PR
-				content, err := ioutil.ReadFile(filename)
-				assert.NoError(t, err)
-
-				assert.Equal(t, "test", string(content))
-
-				return exec.Command("test")
+			"absorbs nothing when the lines were changed by a commit we can't change",
+			func(string) (string, error) {
+				return "", nil
 			},
-			func(output string, err error) {
-				assert.Error(t, err)
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git diff --cached -U0 --no-color --no-ext-diff --no-renames",
+					Replace: "printf \"" + diff + "\"",
+				},
+				{
+					Expect:  "git blame --porcelain -L 3,3 HEAD -- file.go",
+					Replace: "echo \"cccccccccccccccccccccccccccccccccccccccc 3 3 1\"",
+				},
+			}),
+			func(commits []*Commit, err error) {
+				assert.NoError(t, err)
+				assert.Len(t, commits, 0)
 			},
 		},
 	}
 
+	gitCmd := NewDummyGitCommand()
+
 	for _, s := range scenarios {
 		t.Run(s.testName, func(t *testing.T) {
-			gitCmd := NewDummyGitCommand()
 			gitCmd.OSCommand.command = s.command
-			s.test(gitCmd.ApplyPatch("test"))
+			gitCmd.getLocalGitConfig = s.getLocalGitConfig
+			s.test(gitCmd.AbsorbStagedChanges([]*Commit{{Name: "commit", Sha: "aaaaaaa"}, {Name: "commit2", Sha: "bbbbbbb"}}))
 		})
 	}
 }
 
-// TestGitCommandRebaseBranch is a function.
-func TestGitCommandRebaseBranch(t *testing.T) {
+// TestGitCommandStartSplittingCommit is a function.
+func TestGitCommandStartSplittingCommit(t *testing.T) {
 	type scenario struct {
-		testName string
-		arg      string
-		command  func(string, ...string) *exec.Cmd
-		test     func(error)
+		testName          string
+		getLocalGitConfig func(string) (string, error)
+		commits           []*Commit
+		command           func(string, ...string) *exec.Cmd
+		test              func(error)
 	}
 
 	scenarios := []scenario{
 		{
-			"successful rebase",
-			"master",
-			test.CreateMockCommand(t, []*test.CommandSwapper{
-				{
-					Expect:  "git rebase --interactive --autostash master",
-					Replace: "echo",
-				},
-			}),
+			"returns error when index outside of range of commits",
+			func(string) (string, error) {
+				return "", nil
+			},
+			[]*Commit{},
+			test.CreateMockCommand(t, []*test.CommandSwapper{}),
 			func(err error) {
-				assert.NoError(t, err)
+				assert.Error(t, err)
 			},
 		},
 		{
-			"unsuccessful rebase",
-			"master",
+			"returns error when using gpg",
+			func(string) (string, error) {
+				return "true", nil
+			},
+			[]*Commit{{Name: "commit", Sha: "123456"}, {Name: "commit2", Sha: "abcdef"}},
+			test.CreateMockCommand(t, []*test.CommandSwapper{}),
+			func(err error) {
+				assert.Error(t, err)
+			},
+		},
+		{
+			"stops at the commit and undoes it",
+			func(string) (string, error) {
+				return "", nil
+			},
+			[]*Commit{{Name: "commit", Sha: "123456"}, {Name: "commit2", Sha: "abcdef"}},
 			test.CreateMockCommand(t, []*test.CommandSwapper{
 				{
-					Expect:  "git rebase --interactive --autostash master",
-					Replace: "test",
+					Expect:  "git rebase --interactive --autostash abcdef",
+					Replace: "echo",
+				},
+				{
+					Expect:  "git reset HEAD^",
+					Replace: "echo",
 				},
 			}),
 			func(err error) {
-				assert.Error(t, err)
+				assert.NoError(t, err)
 			},
 		},
 	}
@@ -1778,29 +4300,46 @@ func TestGitCommandRebaseBranch(t *testing.T) {
 	for _, s := range scenarios {
 		t.Run(s.testName, func(t *testing.T) {
 			gitCmd.OSCommand.command = s.command
-			s.test(gitCmd.RebaseBranch(s.arg))
+			gitCmd.getLocalGitConfig = s.getLocalGitConfig
+			s.test(gitCmd.StartSplittingCommit(s.commits, 0))
 		})
 	}
 }
 
-// TestGitCommandCheckoutFile is a function.
-func TestGitCommandCheckoutFile(t *testing.T) {
+// TestGitCommandFinishSplittingCommit is a function.
+func TestGitCommandFinishSplittingCommit(t *testing.T) {
 	type scenario struct {
-		testName  string
-		commitSha string
-		fileName  string
-		command   func(string, ...string) *exec.Cmd
-		test      func(error)
+		testName string
+		command  func(string, ...string) *exec.Cmd
+		test     func(error)
 	}
 
 	scenarios := []scenario{
 		{
-			"typical case",
-			"11af912",
-			"test999.txt",
+			"commits the rest of the changes",
 			test.CreateMockCommand(t, []*test.CommandSwapper{
 				{
-					Expect:  "git checkout 11af912 test999.txt",
+					Expect:  "git show --pretty= --name-only --no-renames 123456",
+					Replace: "echo \"file1\nmy file2\"",
+				},
+				{
+					Expect:  "git add -A -- file1 \"my file2\"",
+					Replace: "echo",
+				},
+				{
+					Expect:  "git diff --cached --quiet",
+					Replace: "test 1 = 2",
+				},
+				{
+					Expect:  "git log -1 --format=%B 123456",
+					Replace: "echo message",
+				},
+				{
+					Expect:  "git commit -m message",
+					Replace: "echo",
+				},
+				{
+					Expect:  "git rebase --continue",
 					Replace: "echo",
 				},
 			}),
@@ -1809,17 +4348,27 @@ func TestGitCommandCheckoutFile(t *testing.T) {
 			},
 		},
 		{
-			"returns error if there is one",
-			"11af912",
-			"test999.txt",
+			"continues straight away when nothing is left",
 			test.CreateMockCommand(t, []*test.CommandSwapper{
 				{
-					Expect:  "git checkout 11af912 test999.txt",
-					Replace: "test",
+					Expect:  "git show --pretty= --name-only --no-renames 123456",
+					Replace: "echo file1",
+				},
+				{
+					Expect:  "git add -A -- file1",
+					Replace: "echo",
+				},
+				{
+					Expect:  "git diff --cached --quiet",
+					Replace: "echo",
+				},
+				{
+					Expect:  "git rebase --continue",
+					Replace: "echo",
 				},
 			}),
 			func(err error) {
-				assert.Error(t, err)
+				assert.NoError(t, err)
 			},
 		},
 	}
@@ -1829,20 +4378,19 @@ func TestGitCommandCheckoutFile(t *testing.T) {
 	for _, s := range scenarios {
 		t.Run(s.testName, func(t *testing.T) {
 			gitCmd.OSCommand.command = s.command
-			s.test(gitCmd.CheckoutFile(s.commitSha, s.fileName))
+			s.test(gitCmd.FinishSplittingCommit("123456"))
 		})
 	}
 }
 
-// TestGitCommandDiscardOldFileChanges is a function.
-func TestGitCommandDiscardOldFileChanges(t *testing.T) {
+// TestGitCommandMovePatchToCommit is a function.
+func TestGitCommandMovePatchToCommit(t *testing.T) {
 	type scenario struct {
 		testName          string
 		getLocalGitConfig func(string) (string, error)
 		commits           []*Commit
-		commitIndex       int
-		fileName          string
-		command           func(string, ...string) *exec.Cmd
+		sourceIndex       int
+		destinationIndex  int
 		test              func(error)
 	}
 
@@ -1852,10 +4400,9 @@ func TestGitCommandDiscardOldFileChanges(t *testing.T) {
 			func(string) (string, error) {
 				return "", nil
 			},
-			[]*Commit{},
+			[]*Commit{{Name: "commit", Sha: "123456"}},
 			0,
-			"test999.txt",
-			nil,
+			1,
 			func(err error) {
 				assert.Error(t, err)
 			},
@@ -1865,62 +4412,158 @@ func TestGitCommandDiscardOldFileChanges(t *testing.T) {
 			func(string) (string, error) {
 				return "true", nil
 			},
-			[]*Commit{{Name: "commit", Sha: "123456"}},
+			[]*Commit{{Name: "commit", Sha: "123456"}, {Name: "commit2", Sha: "abcdef"}},
 			0,
-			"test999.txt",
-			nil,
+			1,
 			func(err error) {
 				assert.Error(t, err)
 			},
 		},
 		{
-			"checks out file if it already existed",
+			"returns error when there is no commit to rebase onto",
 			func(string) (string, error) {
 				return "", nil
 			},
-			[]*Commit{
-				{Name: "commit", Sha: "123456"},
-				{Name: "commit2", Sha: "abcdef"},
-			},
+			[]*Commit{{Name: "commit", Sha: "123456"}, {Name: "commit2", Sha: "abcdef"}},
 			0,
-			"test999.txt",
-			test.CreateMockCommand(t, []*test.CommandSwapper{
-				{
-					Expect:  "git rebase --interactive --autostash abcdef",
-					Replace: "echo",
-				},
-				{
-					Expect:  "git cat-file -e HEAD^:test999.txt",
-					Replace: "echo",
-				},
-				{
-					Expect:  "git checkout HEAD^ test999.txt",
-					Replace: "echo",
-				},
-				{
-					Expect:  "git commit --amend --no-edit",
-					Replace: "echo",
-				},
-				{
-					Expect:  "git rebase --continue",
-					Replace: "echo",
-				},
-			}),
+			1,
 			func(err error) {
-				assert.NoError(t, err)
+				assert.Error(t, err)
 			},
 		},
-		// test for when the file was created within the commit requires a refactor to support proper mocks
-		// currently we'd need to mock out the os.Remove function and that's gonna introduce tech debt
 	}
 
 	gitCmd := NewDummyGitCommand()
 
 	for _, s := range scenarios {
 		t.Run(s.testName, func(t *testing.T) {
-			gitCmd.OSCommand.command = s.command
-			gitCmd.getLocalGitConfig = s.getLocalGitConfig
-			s.test(gitCmd.DiscardOldFileChanges(s.commits, s.commitIndex, s.fileName))
+			gitCmd.getLocalGitConfig = s.getLocalGitConfig
+			s.test(gitCmd.MovePatchToCommit(s.commits, s.sourceIndex, s.destinationIndex, "test", "test"))
+		})
+	}
+}
+
+// TestGitCommandGenerateEditRebaseTodo is a function.
+func TestGitCommandGenerateEditRebaseTodo(t *testing.T) {
+	type scenario struct {
+		testName     string
+		editIndices  []int
+		expectedTodo string
+		expectedSha  string
+	}
+
+	commits := []*Commit{
+		{Name: "commit1", Sha: "111111"},
+		{Name: "commit2", Sha: "222222"},
+		{Name: "commit3", Sha: "333333"},
+		{Name: "commit4", Sha: "444444"},
+	}
+
+	scenarios := []scenario{
+		{
+			"one commit edited",
+			[]int{1},
+			"edit 222222 commit2\npick 111111 commit1\n",
+			"333333",
+		},
+		{
+			"two commits edited",
+			[]int{0, 2},
+			"edit 333333 commit3\npick 222222 commit2\nedit 111111 commit1\n",
+			"444444",
+		},
+	}
+
+	gitCmd := NewDummyGitCommand()
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			todo, sha, err := gitCmd.generateEditRebaseTodo(commits, s.editIndices...)
+			assert.NoError(t, err)
+			assert.EqualValues(t, s.expectedTodo, todo)
+			assert.EqualValues(t, s.expectedSha, sha)
+		})
+	}
+
+	_, _, err := gitCmd.generateEditRebaseTodo(commits, 3)
+	assert.Error(t, err)
+}
+
+// TestGitCommandGenerateRebaseTodo is a function.
+func TestGitCommandGenerateRebaseTodo(t *testing.T) {
+	type scenario struct {
+		testName     string
+		actions      map[string]string
+		expectedTodo string
+		expectedSha  string
+		expectedErr  string
+	}
+
+	commits := []*Commit{
+		{Name: "commit1", Sha: "111111"},
+		{Name: "commit2", Sha: "222222"},
+		{Name: "commit3", Sha: "333333"},
+		{Name: "commit4", Sha: "444444"},
+		{Name: "commit5", Sha: "555555"},
+	}
+
+	scenarios := []scenario{
+		{
+			"one commit dropped",
+			map[string]string{"222222": "drop"},
+			"drop 222222 commit2\npick 111111 commit1\n",
+			"333333",
+			"",
+		},
+		{
+			"one commit squashed",
+			map[string]string{"222222": "squash"},
+			"pick 333333 commit3\nsquash 222222 commit2\npick 111111 commit1\n",
+			"444444",
+			"",
+		},
+		{
+			"several commits squashed, fixed up and dropped",
+			map[string]string{"111111": "fixup", "222222": "squash", "333333": "drop"},
+			"pick 444444 commit4\ndrop 333333 commit3\nsquash 222222 commit2\nfixup 111111 commit1\n",
+			"555555",
+			"",
+		},
+		{
+			"commit squashed past a dropped commit",
+			map[string]string{"111111": "squash", "222222": "drop"},
+			"pick 333333 commit3\ndrop 222222 commit2\nsquash 111111 commit1\n",
+			"444444",
+			"",
+		},
+		{
+			"first commit dropped",
+			map[string]string{"555555": "drop"},
+			"",
+			"",
+			"You cannot interactive rebase onto the first commit",
+		},
+		{
+			"second commit squashed",
+			map[string]string{"444444": "squash"},
+			"",
+			"",
+			"You cannot squash/fixup onto the second commit",
+		},
+	}
+
+	gitCmd := NewDummyGitCommand()
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			todo, sha, err := gitCmd.GenerateRebaseTodo(commits, s.actions)
+			if s.expectedErr != "" {
+				assert.EqualError(t, err, s.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.EqualValues(t, s.expectedTodo, todo)
+			assert.EqualValues(t, s.expectedSha, sha)
 		})
 	}
 }
@@ -1963,6 +4606,21 @@ func TestGitCommandShowCommitFile(t *testing.T) {
 	}
 }
 
+// TestGitCommandCommitFileDiff is a function.
+func TestGitCommandCommitFileDiff(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = test.CreateMockCommand(t, []*test.CommandSwapper{
+		{
+			Expect:  "git show --format= --full-index -m --first-parent --unified=3 123456 -- \"hello.txt\"",
+			Replace: "echo -n hello",
+		},
+	})
+
+	diff, err := gitCmd.CommitFileDiff("123456", "hello.txt", 3)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", diff)
+}
+
 // TestGitCommandGetCommitFiles is a function.
 func TestGitCommandGetCommitFiles(t *testing.T) {
 	type scenario struct {
@@ -2002,6 +4660,90 @@ func TestGitCommandGetCommitFiles(t *testing.T) {
 	}
 }
 
+// TestGitCommandGetCompareFiles is a function.
+func TestGitCommandGetCompareFiles(t *testing.T) {
+	type scenario struct {
+		testName string
+		command  func(string, ...string) *exec.Cmd
+		test     func([]*CommitFile, error)
+	}
+
+	scenarios := []scenario{
+		{
+			"Files changed",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git diff --name-only master...feature",
+					Replace: "echo 'hello\nworld'",
+				},
+			}),
+			func(compareFiles []*CommitFile, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []*CommitFile{
+					{Sha: "feature", Base: "master", Name: "hello", DisplayString: "hello"},
+					{Sha: "feature", Base: "master", Name: "world", DisplayString: "world"},
+				}, compareFiles)
+			},
+		},
+		{
+			"No files changed",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git diff --name-only master...feature",
+					Replace: "echo -n",
+				},
+			}),
+			func(compareFiles []*CommitFile, err error) {
+				assert.NoError(t, err)
+				assert.Len(t, compareFiles, 0)
+			},
+		},
+	}
+
+	gitCmd := NewDummyGitCommand()
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.GetCompareFiles("master", "feature"))
+		})
+	}
+}
+
+// TestGitCommandCompareRefs is a function.
+func TestGitCommandCompareRefs(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = test.CreateMockCommand(t, []*test.CommandSwapper{
+		{
+			Expect:  "git log --color --oneline master..feature",
+			Replace: "echo -n log",
+		},
+		{
+			Expect:  "git diff --color master...feature",
+			Replace: "echo -n diff",
+		},
+	})
+
+	comparison, err := gitCmd.CompareRefs("master", "feature")
+	assert.NoError(t, err)
+	assert.Equal(t, "log\ndiff", comparison)
+}
+
+// TestGitCommandCompareRefsFile is a function.
+func TestGitCommandCompareRefsFile(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = test.CreateMockCommand(t, []*test.CommandSwapper{
+		{
+			Expect:  "git diff --color master...feature -- \"hello.txt\"",
+			Replace: "echo -n hello",
+		},
+	})
+
+	diff, err := gitCmd.CompareRefsFile("master", "feature", "hello.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", diff)
+}
+
 // TestGitCommandDiscardUnstagedFileChanges is a function.
 func TestGitCommandDiscardUnstagedFileChanges(t *testing.T) {
 	type scenario struct {
@@ -2070,6 +4812,83 @@ func TestGitCommandDiscardAnyUnstagedFileChanges(t *testing.T) {
 	}
 }
 
+// TestGitCommandGetCleanablePaths is a function.
+func TestGitCommandGetCleanablePaths(t *testing.T) {
+	type scenario struct {
+		testName       string
+		includeIgnored bool
+		expected       []string
+	}
+
+	scenarios := []scenario{
+		{
+			"Untracked files",
+			false,
+			[]string{"build/", "notes.txt"},
+		},
+		{
+			"Untracked and ignored files",
+			true,
+			[]string{"build/", "debug.log", "node_modules/", "notes.txt"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				if args[3] == "--ignored" {
+					assert.EqualValues(t, []string{"ls-files", "-z", "--others", "--ignored", "--exclude-standard", "--directory", "--no-empty-directory"}, args)
+					return exec.Command("printf", `node_modules/\000debug.log\000`)
+				}
+				assert.EqualValues(t, []string{"ls-files", "-z", "--others", "--exclude-standard", "--directory", "--no-empty-directory"}, args)
+				return exec.Command("printf", `notes.txt\000build/\000`)
+			}
+
+			paths, err := gitCmd.GetCleanablePaths(s.includeIgnored)
+			assert.NoError(t, err)
+			assert.EqualValues(t, s.expected, paths)
+		})
+	}
+}
+
+// TestGitCommandCleanPaths is a function.
+func TestGitCommandCleanPaths(t *testing.T) {
+	type scenario struct {
+		testName       string
+		includeIgnored bool
+		expectedArgs   []string
+	}
+
+	scenarios := []scenario{
+		{
+			"Untracked files",
+			false,
+			[]string{"clean", "-f", "-d", "--", "build/", "my notes.txt"},
+		},
+		{
+			"Untracked and ignored files",
+			true,
+			[]string{"clean", "-f", "-d", "-x", "--", "build/", "my notes.txt"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, s.expectedArgs, args)
+
+				return exec.Command("echo")
+			}
+
+			assert.NoError(t, gitCmd.CleanPaths([]string{"build/", "my notes.txt"}, s.includeIgnored))
+		})
+	}
+}
+
 // TestGitCommandRemoveUntrackedFiles is a function.
 func TestGitCommandRemoveUntrackedFiles(t *testing.T) {
 	type scenario struct {
@@ -2171,6 +4990,213 @@ func TestGitCommandCreateFixupCommit(t *testing.T) {
 	}
 }
 
+// TestGitCommandCreateSquashCommit is a function.
+func TestGitCommandCreateSquashCommit(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = test.CreateMockCommand(t, []*test.CommandSwapper{
+		{
+			Expect:  `git commit --squash=12345 --no-edit`,
+			Replace: "echo",
+		},
+	})
+
+	assert.NoError(t, gitCmd.CreateSquashCommit("12345"))
+}
+
+// TestGitCommandSquashAllAboveFixupCommits is a function.
+func TestGitCommandSquashAllAboveFixupCommits(t *testing.T) {
+	type scenario struct {
+		testName string
+		command  func(string, ...string) *exec.Cmd
+	}
+
+	scenarios := []scenario{
+		{
+			"commit with a parent",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git rev-parse --verify --quiet 12345^",
+					Replace: "echo",
+				},
+				{
+					Expect:  "git rebase --interactive --autostash --autosquash 12345^",
+					Replace: "echo",
+				},
+			}),
+		},
+		{
+			"root commit",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git rev-parse --verify --quiet 12345^",
+					Replace: "test 1 = 2",
+				},
+				{
+					Expect:  "git rebase --interactive --autostash --autosquash --root",
+					Replace: "echo",
+				},
+			}),
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.OSCommand.command = s.command
+			assert.NoError(t, gitCmd.SquashAllAboveFixupCommits("12345"))
+		})
+	}
+}
+
+// TestGitCommandCommitTemplate is a function.
+func TestGitCommandCommitTemplate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lazygit-template")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	gitTemplate := filepath.Join(dir, "git-template")
+	assert.NoError(t, ioutil.WriteFile(gitTemplate, []byte("\n# from git\n"), 0644))
+	repoTemplate := filepath.Join(dir, "repo-template")
+	assert.NoError(t, ioutil.WriteFile(repoTemplate, []byte("feat: \n\n# from lazygit\n"), 0644))
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+
+	type scenario struct {
+		testName        string
+		commitTemplates map[string]interface{}
+		command         func(string, ...string) *exec.Cmd
+		test            func(string, error)
+	}
+
+	scenarios := []scenario{
+		{
+			"no template",
+			nil,
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"config", "--path", "--get", "commit.template"}, args)
+				return exec.Command("test", "1", "=", "2")
+			},
+			func(template string, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, "", template)
+			},
+		},
+		{
+			"git's commit.template",
+			map[string]interface{}{"/some/other/repo": repoTemplate},
+			func(cmd string, args ...string) *exec.Cmd {
+				return exec.Command("echo", gitTemplate)
+			},
+			func(template string, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, "\n# from git", template)
+			},
+		},
+		{
+			"a template for this repo",
+			map[string]interface{}{wd: repoTemplate},
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.Fail(t, "git's template shouldn't be needed")
+				return nil
+			},
+			func(template string, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, "feat: \n\n# from lazygit", template)
+			},
+		},
+		{
+			"template file missing",
+			nil,
+			func(cmd string, args ...string) *exec.Cmd {
+				return exec.Command("echo", filepath.Join(dir, "missing"))
+			},
+			func(template string, err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.Config.GetUserConfig().Set("git.commitTemplates", s.commitTemplates)
+			gitCmd.OSCommand.command = s.command
+			s.test(gitCmd.CommitTemplate())
+		})
+	}
+}
+
+// TestGitCommandConventionalCommitTypes is a function.
+func TestGitCommandConventionalCommitTypes(t *testing.T) {
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+
+	type scenario struct {
+		testName string
+		types    map[string]interface{}
+		expected []string
+	}
+
+	scenarios := []scenario{
+		{
+			"nothing configured",
+			nil,
+			defaultConventionalCommitTypes,
+		},
+		{
+			"types for another repo",
+			map[string]interface{}{"/some/other/repo": []interface{}{"feature", "bugfix"}},
+			defaultConventionalCommitTypes,
+		},
+		{
+			"types for this repo",
+			map[string]interface{}{wd: []interface{}{"feature", "bugfix"}},
+			[]string{"feature", "bugfix"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			gitCmd := NewDummyGitCommand()
+			gitCmd.Config.GetUserConfig().Set("git.conventionalCommitTypes", s.types)
+			assert.EqualValues(t, s.expected, gitCmd.ConventionalCommitTypes())
+		})
+	}
+}
+
+// TestGitCommandGetConventionalCommitScopes is a function.
+func TestGitCommandGetConventionalCommitScopes(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		assert.EqualValues(t, []string{"log", "-200", "--format=%s"}, args)
+		return exec.Command("echo", "fix(gui): a\nfeat(commands)!: b\nchore: c\nnot (a scope): d\nfeat(gui): e")
+	}
+
+	scopes, err := gitCmd.GetConventionalCommitScopes()
+	assert.NoError(t, err)
+	assert.EqualValues(t, []string{"gui", "commands"}, scopes)
+}
+
+// TestGitCommandGetRecentAuthors is a function.
+func TestGitCommandGetRecentAuthors(t *testing.T) {
+	gitCmd := NewDummyGitCommand()
+	gitCmd.OSCommand.command = func(cmd string, args ...string) *exec.Cmd {
+		assert.EqualValues(t, "git", cmd)
+		if args[0] == "var" {
+			assert.EqualValues(t, []string{"var", "GIT_AUTHOR_IDENT"}, args)
+			return exec.Command("echo", "Me <me@example.com> 1571000000 +1100")
+		}
+		assert.EqualValues(t, []string{"log", "-500", "--format=%aN%x00%aE"}, args)
+		return exec.Command("printf", `Jane Doe\0jane@example.com\nMe\0ME@example.com\nJohn Smith\0john@example.com\nJane Doe\0jane@example.com\n`)
+	}
+
+	authors, err := gitCmd.GetRecentAuthors()
+	assert.NoError(t, err)
+	assert.EqualValues(t, []string{"Jane Doe <jane@example.com>", "John Smith <john@example.com>"}, authors)
+}
+
 func TestFindDotGitDir(t *testing.T) {
 	type scenario struct {
 		testName string
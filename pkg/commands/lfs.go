@@ -0,0 +1,55 @@
+package commands
+
+import (
+	"strconv"
+	"strings"
+)
+
+// LFSPointer : what git keeps in place of a file that git LFS stores
+// elsewhere. Oid is the hash of the real content and Size its size in bytes
+type LFSPointer struct {
+	Oid  string
+	Size int64
+}
+
+// ParseLFSPointerDiff reads the pointers from each side of a file's diff, for
+// showing what's changed in place of the pointer text. Either side is nil if
+// the file didn't exist there, and ok is false if the diff isn't of pointers
+// at all, e.g. because git LFS isn't installed and the real content is shown
+func ParseLFSPointerDiff(diff string) (oldPointer *LFSPointer, newPointer *LFSPointer, ok bool) {
+	sides := map[byte]*LFSPointer{}
+	for _, line := range strings.Split(diff, "\n") {
+		if len(line) < 2 || strings.HasPrefix(line, "---") || strings.HasPrefix(line, "+++") {
+			continue
+		}
+		prefix, content := line[0], line[1:]
+		if prefix != '-' && prefix != '+' && prefix != ' ' {
+			continue
+		}
+		split := strings.SplitN(content, " ", 2)
+		if len(split) != 2 || (split[0] != "version" && split[0] != "oid" && split[0] != "size") {
+			// the pointer has nothing else in it, so this is real content
+			return nil, nil, false
+		}
+		// an unchanged line belongs to both sides
+		for _, side := range map[byte][]byte{'-': {'-'}, '+': {'+'}, ' ': {'-', '+'}}[prefix] {
+			if sides[side] == nil {
+				sides[side] = &LFSPointer{}
+			}
+			switch split[0] {
+			case "oid":
+				sides[side].Oid = split[1]
+			case "size":
+				size, err := strconv.ParseInt(split[1], 10, 64)
+				if err != nil {
+					return nil, nil, false
+				}
+				sides[side].Size = size
+			}
+		}
+	}
+	if sides['-'] == nil && sides['+'] == nil {
+		return nil, nil, false
+	}
+	return sides['-'], sides['+'], true
+}
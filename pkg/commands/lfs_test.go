@@ -0,0 +1,84 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParseLFSPointerDiff is a function.
+func TestParseLFSPointerDiff(t *testing.T) {
+	type scenario struct {
+		testName    string
+		diff        string
+		expectedOld *LFSPointer
+		expectedNew *LFSPointer
+		expectedOk  bool
+	}
+
+	scenarios := []scenario{
+		{
+			"new file",
+			`diff --git a/image.png b/image.png
+new file mode 100644
+index 0000000..c2d4a1b
+--- /dev/null
++++ b/image.png
+@@ -0,0 +1,3 @@
++version https://git-lfs.github.com/spec/v1
++oid sha256:4d7a214614ab2935c943f9e0ff69d22eadbb8f32b1258daaa5e2ca24d17e2393
++size 12345
+`,
+			nil,
+			&LFSPointer{Oid: "sha256:4d7a214614ab2935c943f9e0ff69d22eadbb8f32b1258daaa5e2ca24d17e2393", Size: 12345},
+			true,
+		},
+		{
+			"modified file",
+			`diff --git a/image.png b/image.png
+index c2d4a1b..9e1f3a0 100644
+--- a/image.png
++++ b/image.png
+@@ -1,3 +1,3 @@
+ version https://git-lfs.github.com/spec/v1
+-oid sha256:aaaa
+-size 10
++oid sha256:bbbb
++size 20
+`,
+			&LFSPointer{Oid: "sha256:aaaa", Size: 10},
+			&LFSPointer{Oid: "sha256:bbbb", Size: 20},
+			true,
+		},
+		{
+			"real content",
+			`diff --git a/image.png b/image.png
+index c2d4a1b..9e1f3a0 100644
+--- a/image.png
++++ b/image.png
+@@ -1 +1 @@
+-hello
++world
+`,
+			nil,
+			nil,
+			false,
+		},
+		{
+			"no diff",
+			"",
+			nil,
+			nil,
+			false,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			oldPointer, newPointer, ok := ParseLFSPointerDiff(s.diff)
+			assert.EqualValues(t, s.expectedOld, oldPointer)
+			assert.EqualValues(t, s.expectedNew, newPointer)
+			assert.EqualValues(t, s.expectedOk, ok)
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -64,6 +65,15 @@ func (c *OSCommand) RunCommandWithOutput(command string) (string, error) {
 	return sanitisedCommandOutput(cmd.CombinedOutput())
 }
 
+// RunCommandWithInput runs the command with the input on its stdin, returning
+// its output and error
+func (c *OSCommand) RunCommandWithInput(command string, input string) (string, error) {
+	c.Log.WithField("command", command).Info("RunCommand")
+	cmd := c.ExecutableFromString(command)
+	cmd.Stdin = strings.NewReader(input)
+	return sanitisedCommandOutput(cmd.CombinedOutput())
+}
+
 // RunExecutableWithOutput runs an executable file and returns its output
 func (c *OSCommand) RunExecutableWithOutput(cmd *exec.Cmd) (string, error) {
 	return sanitisedCommandOutput(cmd.CombinedOutput())
@@ -181,6 +191,44 @@ func (c *OSCommand) OpenLink(link string) error {
 // EditFile opens a file in a subprocess using whatever editor is available,
 // falling back to core.editor, VISUAL, EDITOR, then vi
 func (c *OSCommand) EditFile(filename string) (*exec.Cmd, error) {
+	editor, err := c.getEditor()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.PrepareSubProcess(editor, filename), nil
+}
+
+// EditFileAtLine is like EditFile but puts the cursor on the given line. Editors
+// differ in how they take the line, so the arguments come from the template in
+// os.editAtLineTemplates for the editor, or are `+<line> <filename>` which most
+// terminal editors understand
+func (c *OSCommand) EditFileAtLine(filename string, lineNumber int) (*exec.Cmd, error) {
+	editor, err := c.getEditor()
+	if err != nil {
+		return nil, err
+	}
+
+	template := "+{{line}} {{filename}}"
+	templates := c.Config.GetUserConfig().GetStringMapString("os.editAtLineTemplates")
+	if editorTemplate, ok := templates[filepath.Base(editor)]; ok {
+		template = editorTemplate
+	}
+	templateValues := map[string]string{
+		"filename": filename,
+		"line":     strconv.Itoa(lineNumber),
+	}
+	// we resolve each argument separately so that a filename with spaces in it
+	// stays one argument
+	args := strings.Fields(template)
+	for i, arg := range args {
+		args[i] = utils.ResolvePlaceholderString(arg, templateValues)
+	}
+
+	return c.PrepareSubProcess(editor, args...), nil
+}
+
+func (c *OSCommand) getEditor() (string, error) {
 	editor, _ := c.getGlobalGitConfig("core.editor")
 
 	if editor == "" {
@@ -195,10 +243,10 @@ func (c *OSCommand) EditFile(filename string) (*exec.Cmd, error) {
 		}
 	}
 	if editor == "" {
-		return nil, errors.New("No editor defined in $VISUAL, $EDITOR, or git config")
+		return "", errors.New("No editor defined in $VISUAL, $EDITOR, or git config")
 	}
 
-	return c.PrepareSubProcess(editor, filename), nil
+	return editor, nil
 }
 
 // PrepareSubProcess iniPrepareSubProcessrocess then tells the Gui to switch to it
@@ -37,6 +37,37 @@ func TestOSCommandRunCommandWithOutput(t *testing.T) {
 	}
 }
 
+// TestOSCommandRunCommandWithInput is a function.
+func TestOSCommandRunCommandWithInput(t *testing.T) {
+	type scenario struct {
+		command string
+		input   string
+		test    func(string, error)
+	}
+
+	scenarios := []scenario{
+		{
+			"cat",
+			"some input",
+			func(output string, err error) {
+				assert.NoError(t, err)
+				assert.EqualValues(t, "some input", output)
+			},
+		},
+		{
+			"grep nothing",
+			"some input",
+			func(output string, err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		s.test(NewDummyOSCommand().RunCommandWithInput(s.command, s.input))
+	}
+}
+
 // TestOSCommandRunCommand is a function.
 func TestOSCommandRunCommand(t *testing.T) {
 	type scenario struct {
@@ -239,6 +270,55 @@ func TestOSCommandEditFile(t *testing.T) {
 	}
 }
 
+// TestOSCommandEditFileAtLine is a function.
+func TestOSCommandEditFileAtLine(t *testing.T) {
+	type scenario struct {
+		testName     string
+		editor       string
+		expectedArgs []string
+	}
+
+	scenarios := []scenario{
+		{
+			"Editor without a template",
+			"vim",
+			[]string{"+12", "my file.txt"},
+		},
+		{
+			"Editor with a template",
+			"code",
+			[]string{"--goto", "my file.txt:12"},
+		},
+		{
+			"Editor given as a path",
+			"/usr/local/bin/subl",
+			[]string{"my file.txt:12"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			OSCmd := NewDummyOSCommand()
+			OSCmd.command = func(name string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, s.editor, name)
+				assert.EqualValues(t, s.expectedArgs, args)
+
+				return exec.Command("echo")
+			}
+			OSCmd.getGlobalGitConfig = func(cf string) (string, error) {
+				return s.editor, nil
+			}
+			OSCmd.Config.GetUserConfig().Set("os.editAtLineTemplates", map[string]string{
+				"code": "--goto {{filename}}:{{line}}",
+				"subl": "{{filename}}:{{line}}",
+			})
+
+			_, err := OSCmd.EditFileAtLine("my file.txt", 12)
+			assert.NoError(t, err)
+		})
+	}
+}
+
 // TestOSCommandQuote is a function.
 func TestOSCommandQuote(t *testing.T) {
 	osCommand := NewDummyOSCommand()
@@ -0,0 +1,23 @@
+package commands
+
+import (
+	"github.com/fatih/color"
+)
+
+// ReflogEntry : An entry in HEAD's reflog, which is a commit HEAD pointed to
+// and what moved it there. Selector is how git refers to the entry e.g.
+// HEAD@{2}
+type ReflogEntry struct {
+	Sha      string
+	Selector string
+	Message  string
+}
+
+// GetDisplayStrings is a function.
+func (r *ReflogEntry) GetDisplayStrings(isFocused bool) []string {
+	return []string{
+		color.New(color.FgBlue).Sprint(r.Sha),
+		color.New(color.FgMagenta).Sprint(r.Selector),
+		r.Message,
+	}
+}
@@ -0,0 +1,28 @@
+package commands
+
+import (
+	"github.com/fatih/color"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+)
+
+// RemoteBranch : A remote-tracking branch, which is where a branch on a remote
+// was when we last fetched it. Name is without the remote's name e.g. feature
+// rather than origin/feature
+type RemoteBranch struct {
+	RemoteName string
+	Name       string
+}
+
+// FullName is how git refers to the branch e.g. origin/feature
+func (b *RemoteBranch) FullName() string {
+	return b.RemoteName + "/" + b.Name
+}
+
+// GetDisplayStrings is a function.
+func (b *RemoteBranch) GetDisplayStrings(isFocused bool) []string {
+	branch := &Branch{Name: b.Name}
+	return []string{
+		color.New(color.FgMagenta).Sprint(b.RemoteName),
+		utils.ColoredString(b.Name, branch.GetColor()),
+	}
+}
@@ -0,0 +1,140 @@
+package commands
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// StagedHunk : a hunk of a diff made without context lines, i.e. with -U0.
+// OldStart and OldCount are the lines it changes in the old version of the
+// file, where a hunk that only adds lines has an OldCount of 0 and adds them
+// after line OldStart. FileHeader is the diff's header for the file
+type StagedHunk struct {
+	FileName   string
+	FileHeader string
+	OldStart   int
+	OldCount   int
+	NewStart   int
+	NewCount   int
+	Body       string
+}
+
+var hunkHeaderRegexp = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// ParseStagedHunks splits a diff made with -U0 and --no-renames into its hunks.
+// Files that are added, deleted, binary or have had their mode changed are
+// left out, as their hunks can't be applied on their own
+func ParseStagedHunks(diff string) []*StagedHunk {
+	hunks := []*StagedHunk{}
+	for _, fileDiff := range strings.Split(diff, "\ndiff --git ") {
+		fileDiff = strings.TrimPrefix(fileDiff, "diff --git ")
+		// splitting took the newline off the end of all but the last file
+		if !strings.HasSuffix(fileDiff, "\n") {
+			fileDiff += "\n"
+		}
+		lines := strings.SplitAfter(fileDiff, "\n")
+
+		header := "diff --git "
+		fileName := ""
+		i := 0
+		for ; i < len(lines) && !strings.HasPrefix(lines[i], "@@"); i++ {
+			line := lines[i]
+			switch {
+			case strings.HasPrefix(line, "new file mode"), strings.HasPrefix(line, "deleted file mode"),
+				strings.HasPrefix(line, "old mode"), strings.HasPrefix(line, "Binary files"):
+				fileName = ""
+				i = len(lines)
+				continue
+			case strings.HasPrefix(line, "+++ b/"):
+				fileName = strings.TrimSuffix(strings.TrimPrefix(line, "+++ b/"), "\n")
+			}
+			header += line
+		}
+		if fileName == "" {
+			continue
+		}
+
+		var hunk *StagedHunk
+		for ; i < len(lines); i++ {
+			line := lines[i]
+			if match := hunkHeaderRegexp.FindStringSubmatch(line); match != nil {
+				hunk = &StagedHunk{
+					FileName:   fileName,
+					FileHeader: header,
+					OldStart:   hunkRangeNumber(match[1]),
+					OldCount:   hunkRangeCount(match[2]),
+					NewStart:   hunkRangeNumber(match[3]),
+					NewCount:   hunkRangeCount(match[4]),
+				}
+				hunks = append(hunks, hunk)
+				continue
+			}
+			if hunk != nil && line != "" {
+				hunk.Body += line
+			}
+		}
+	}
+	return hunks
+}
+
+func hunkRangeNumber(value string) int {
+	number, _ := strconv.Atoi(value)
+	return number
+}
+
+// hunkRangeCount reads how many lines a side of a hunk has, which the header
+// leaves out when it's one
+func hunkRangeCount(value string) int {
+	if value == "" {
+		return 1
+	}
+	return hunkRangeNumber(value)
+}
+
+// BlameRange returns the lines of the old version of the file that we take the
+// hunk to belong to: the ones it changes, or if it only adds lines, the ones
+// either side of them. The end is past the end of the file if the lines are
+// added at the end
+func (h *StagedHunk) BlameRange() (int, int) {
+	if h.OldCount > 0 {
+		return h.OldStart, h.OldStart + h.OldCount - 1
+	}
+	if h.OldStart == 0 {
+		return 1, 1
+	}
+	return h.OldStart, h.OldStart + 1
+}
+
+// RenderStagedHunks renders the hunks, which must all be of the same file and
+// in order, as a patch to be applied with --unidiff-zero to the file once the
+// applied hunks, which came from the same diff, have been applied to it
+func RenderStagedHunks(hunks []*StagedHunk, applied []*StagedHunk) string {
+	if len(hunks) == 0 {
+		return ""
+	}
+	patch := hunks[0].FileHeader
+	// how many lines the hunks before this one in the patch add
+	added := 0
+	for _, hunk := range hunks {
+		oldStart := hunk.OldStart
+		for _, other := range applied {
+			if other.OldStart < hunk.OldStart {
+				oldStart += other.NewCount - other.OldCount
+			}
+		}
+		// a side without lines is numbered from the line before where they'd be
+		firstLine := oldStart
+		if hunk.OldCount == 0 {
+			firstLine++
+		}
+		newStart := firstLine + added
+		if hunk.NewCount == 0 {
+			newStart--
+		}
+		patch += fmt.Sprintf("@@ -%d,%d +%d,%d @@\n", oldStart, hunk.OldCount, newStart, hunk.NewCount) + hunk.Body
+		added += hunk.NewCount - hunk.OldCount
+	}
+	return patch
+}
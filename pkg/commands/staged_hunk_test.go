@@ -0,0 +1,105 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const stagedHunksDiff = `diff --git a/file.go b/file.go
+index 1111111..2222222 100644
+--- a/file.go
++++ b/file.go
+@@ -3 +3 @@ func a() {
+-	return 1
++	return 2
+@@ -10,0 +11,2 @@ func b() {
++	x := 1
++	y := 2
+@@ -20,2 +21,0 @@ func c() {
+-	z := 3
+-	w := 4
+diff --git a/new.go b/new.go
+new file mode 100644
+index 0000000..3333333
+--- /dev/null
++++ b/new.go
+@@ -0,0 +1 @@
++package new
+diff --git a/image.png b/image.png
+index 4444444..5555555 100644
+Binary files a/image.png and b/image.png differ
+`
+
+// TestParseStagedHunks is a function.
+func TestParseStagedHunks(t *testing.T) {
+	hunks := ParseStagedHunks(stagedHunksDiff)
+	header := "diff --git a/file.go b/file.go\nindex 1111111..2222222 100644\n--- a/file.go\n+++ b/file.go\n"
+
+	assert.EqualValues(t, []*StagedHunk{
+		{FileName: "file.go", FileHeader: header, OldStart: 3, OldCount: 1, NewStart: 3, NewCount: 1, Body: "-\treturn 1\n+\treturn 2\n"},
+		{FileName: "file.go", FileHeader: header, OldStart: 10, OldCount: 0, NewStart: 11, NewCount: 2, Body: "+\tx := 1\n+\ty := 2\n"},
+		{FileName: "file.go", FileHeader: header, OldStart: 20, OldCount: 2, NewStart: 21, NewCount: 0, Body: "-\tz := 3\n-\tw := 4\n"},
+	}, hunks)
+}
+
+// TestStagedHunkBlameRange is a function.
+func TestStagedHunkBlameRange(t *testing.T) {
+	type scenario struct {
+		hunk  *StagedHunk
+		start int
+		end   int
+	}
+
+	scenarios := []scenario{
+		{&StagedHunk{OldStart: 3, OldCount: 2}, 3, 4},
+		{&StagedHunk{OldStart: 10, OldCount: 0}, 10, 11},
+		{&StagedHunk{OldStart: 0, OldCount: 0}, 1, 1},
+	}
+
+	for _, s := range scenarios {
+		start, end := s.hunk.BlameRange()
+		assert.EqualValues(t, s.start, start)
+		assert.EqualValues(t, s.end, end)
+	}
+}
+
+// TestRenderStagedHunks is a function.
+func TestRenderStagedHunks(t *testing.T) {
+	hunks := ParseStagedHunks(stagedHunksDiff)
+	header := hunks[0].FileHeader
+
+	type scenario struct {
+		testName string
+		hunks    []*StagedHunk
+		applied  []*StagedHunk
+		expected string
+	}
+
+	scenarios := []scenario{
+		{
+			"renders hunks as they were",
+			hunks,
+			nil,
+			header + "@@ -3,1 +3,1 @@\n-\treturn 1\n+\treturn 2\n@@ -10,0 +11,2 @@\n+\tx := 1\n+\ty := 2\n@@ -20,2 +21,0 @@\n-\tz := 3\n-\tw := 4\n",
+		},
+		{
+			"moves hunks below ones that have been applied",
+			hunks[2:],
+			hunks[:2],
+			header + "@@ -22,2 +21,0 @@\n-\tz := 3\n-\tw := 4\n",
+		},
+		{
+			"leaves hunks above ones that have been applied",
+			hunks[:1],
+			hunks[1:],
+			header + "@@ -3,1 +3,1 @@\n-\treturn 1\n+\treturn 2\n",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			assert.EqualValues(t, s.expected, RenderStagedHunks(s.hunks, s.applied))
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package commands
+
+// SubmoduleStatus : how a submodule's checkout compares with the commit the
+// parent repo has recorded for it
+type SubmoduleStatus struct {
+	// RecordedSha is the commit HEAD points the submodule at. It's empty for a
+	// submodule that hasn't been committed yet
+	RecordedSha string
+	// CheckedOutSha is the commit checked out in the submodule. It's empty if
+	// the submodule hasn't been initialised
+	CheckedOutSha string
+	// Dirty is true if the submodule has changes of its own
+	Dirty bool
+}
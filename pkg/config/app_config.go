@@ -243,6 +243,10 @@ func GetDefaultConfig() []byte {
   scrollHeight: 2
   scrollPastBottom: true
   mouseEvents: false # will default to true when the feature is complete
+  syntaxHighlighting: false
+  showFileTree: true
+  fileSortOrder: path
+  branchSortOrder: recency
   theme:
     activeBorderColor:
       - white
@@ -253,24 +257,43 @@ func GetDefaultConfig() []byte {
       - blue
   commitLength:
     show: true
+    subjectWarning: 50
+    subjectLimit: 72
+    bodyLimit: 72
+  commitDate:
+    format: none
+    source: author
 git:
   merging:
     manualCommit: false
   skipHookPrefix: 'WIP'
   autoFetch: true
+  autoRefresh: true
+  commitTemplates: {}
+  conventionalCommitTypes: {}
+  showSignatures: false
+  commitLintCommand: ''
+  pull:
+    mode: 'default' # one of: 'default' | 'merge' | 'rebase' | 'ff-only' | 'prompt'
 update:
   method: prompt # can be: prompt | background | never
   days: 14 # how often a update is checked for
+os:
+  editAtLineTemplates:
+    code: '--goto {{filename}}:{{line}}'
+    subl: '{{filename}}:{{line}}'
 reporting: 'undetermined' # one of: 'on' | 'off' | 'undetermined'
 confirmOnQuit: false
 `)
 }
 
 // AppState stores data between runs of the app like when the last update check
-// was performed and which other repos have been checked out
+// was performed and which other repos have been checked out. MergeFlags is the
+// flag last merged with in each repo, keyed by the repo's path
 type AppState struct {
 	LastUpdateCheck int64
 	RecentRepos     []string
+	MergeFlags      map[string]string
 }
 
 func getDefaultAppState() []byte {
@@ -2,6 +2,7 @@ package git
 
 import (
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/jesseduffield/lazygit/pkg/commands"
@@ -23,17 +24,33 @@ import (
 // if we find out we need to use one of these functions in the git.go file, we
 // can just pull them out of here and put them there and then call them from in here
 
+// The orders the branches can be listed in. Whichever it is, the checked out
+// branch comes first
+const (
+	SortBranchesByRecency = "recency"
+	SortBranchesByDate    = "date"
+	SortBranchesByName    = "name"
+)
+
+// BranchSortOrders are the orders the branches can be listed in. By recency
+// is the order we last checked them out in, and by date the order of their
+// last commits, newest first
+var BranchSortOrders = []string{SortBranchesByRecency, SortBranchesByDate, SortBranchesByName}
+
 // BranchListBuilder returns a list of Branch objects for the current repo
 type BranchListBuilder struct {
 	Log        *logrus.Entry
 	GitCommand *commands.GitCommand
+	SortOrder  string
 }
 
-// NewBranchListBuilder builds a new branch list builder
-func NewBranchListBuilder(log *logrus.Entry, gitCommand *commands.GitCommand) (*BranchListBuilder, error) {
+// NewBranchListBuilder builds a new branch list builder. sortOrder is one of
+// BranchSortOrders
+func NewBranchListBuilder(log *logrus.Entry, gitCommand *commands.GitCommand, sortOrder string) (*BranchListBuilder, error) {
 	return &BranchListBuilder{
 		Log:        log,
 		GitCommand: gitCommand,
+		SortOrder:  sortOrder,
 	}, nil
 }
 
@@ -43,7 +60,7 @@ func (b *BranchListBuilder) obtainCurrentBranch() *commands.Branch {
 		panic(err.Error())
 	}
 
-	return &commands.Branch{Name: strings.TrimSpace(branchName)}
+	return &commands.Branch{Name: strings.TrimSpace(branchName), Detached: b.GitCommand.IsHeadDetached()}
 }
 
 func (b *BranchListBuilder) obtainReflogBranches() []*commands.Branch {
@@ -63,6 +80,23 @@ func (b *BranchListBuilder) obtainReflogBranches() []*commands.Branch {
 	return uniqueByName(branches)
 }
 
+// obtainDatedBranches gets the branches ordered by the date of their last
+// commit, newest first, with how long ago that was as their recency
+func (b *BranchListBuilder) obtainDatedBranches() []*commands.Branch {
+	branches := make([]*commands.Branch, 0)
+	rawString, err := b.GitCommand.OSCommand.RunCommandWithOutput("git for-each-ref --sort=-committerdate --format='%(committerdate:relative)|%(refname:short)' refs/heads")
+	if err != nil {
+		return branches
+	}
+
+	for _, line := range utils.SplitLines(rawString) {
+		if branch := datedBranchFromLine(line); branch != nil {
+			branches = append(branches, branch)
+		}
+	}
+	return branches
+}
+
 func (b *BranchListBuilder) obtainSafeBranches() []*commands.Branch {
 	branches := make([]*commands.Branch, 0)
 
@@ -115,11 +149,55 @@ func (b *BranchListBuilder) Build() []*commands.Branch {
 		branches = append([]*commands.Branch{head}, branches...)
 	}
 
+	switch b.SortOrder {
+	case SortBranchesByDate:
+		branches = sortBranchesByDate(branches, b.obtainDatedBranches())
+	case SortBranchesByName:
+		branches = sortBranchesByName(branches)
+	}
+
 	branches[0].Recency = "  *"
 
 	return branches
 }
 
+// sortBranchesByDate puts the branches in the order of the dated branches,
+// taking their recency from them. Branches that aren't dated go last. The
+// first branch is the checked out one, which stays where it is
+func sortBranchesByDate(branches []*commands.Branch, datedBranches []*commands.Branch) []*commands.Branch {
+	positions := map[string]int{}
+	for i, datedBranch := range datedBranches {
+		positions[datedBranch.Name] = i
+	}
+	position := func(branch *commands.Branch) int {
+		if i, ok := positions[branch.Name]; ok {
+			return i
+		}
+		return len(datedBranches)
+	}
+
+	for _, branch := range branches {
+		if i, ok := positions[branch.Name]; ok {
+			branch.Recency = datedBranches[i].Recency
+		}
+	}
+	rest := branches[1:]
+	sort.SliceStable(rest, func(i, j int) bool {
+		return position(rest[i]) < position(rest[j])
+	})
+	return branches
+}
+
+// sortBranchesByName sorts the branches alphabetically, ignoring case, except
+// for the first branch, which is the checked out one and stays where it is
+func sortBranchesByName(branches []*commands.Branch) []*commands.Branch {
+	rest := branches[1:]
+	sort.SliceStable(rest, func(i, j int) bool {
+		return strings.ToLower(rest[i].Name) < strings.ToLower(rest[j].Name)
+	})
+	return branches
+}
+
 func branchIncluded(branchName string, branches []*commands.Branch) bool {
 	for _, existingBranch := range branches {
 		if strings.ToLower(existingBranch.Name) == strings.ToLower(branchName) {
@@ -149,7 +227,24 @@ func branchInfoFromLine(line string) (string, string, string) {
 	return words[0], words[1], words[len(words)-1]
 }
 
+// A line will have the form '3 days ago|master', or for older commits
+// '2 years, 3 months ago|master', of which we only keep the largest unit
+func datedBranchFromLine(line string) *commands.Branch {
+	parts := strings.SplitN(line, "|", 2)
+	if len(parts) < 2 {
+		return nil
+	}
+	words := strings.Split(parts[0], " ")
+	recency := ""
+	if len(words) >= 2 {
+		recency = words[0] + abbreviatedTimeUnit(words[1])
+	}
+	return &commands.Branch{Name: parts[1], Recency: recency}
+}
+
 func abbreviatedTimeUnit(timeUnit string) string {
+	// in '2 years, 3 months ago' the first unit is followed by a comma
+	timeUnit = strings.TrimSuffix(timeUnit, ",")
 	r := regexp.MustCompile("s$")
 	timeUnit = r.ReplaceAllString(timeUnit, "")
 	timeUnitMap := map[string]string{
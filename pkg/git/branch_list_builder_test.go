@@ -0,0 +1,98 @@
+package git
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/jesseduffield/lazygit/pkg/commands"
+	"github.com/jesseduffield/lazygit/pkg/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// NewDummyBranchListBuilder creates a new dummy BranchListBuilder for testing
+func NewDummyBranchListBuilder() *BranchListBuilder {
+	osCommand := commands.NewDummyOSCommand()
+
+	return &BranchListBuilder{
+		Log:        commands.NewDummyLog(),
+		GitCommand: commands.NewDummyGitCommandWithOSCommand(osCommand),
+		SortOrder:  SortBranchesByRecency,
+	}
+}
+
+// TestBranchListBuilderObtainDatedBranches is a function.
+func TestBranchListBuilderObtainDatedBranches(t *testing.T) {
+	type scenario struct {
+		testName string
+		command  func(string, ...string) *exec.Cmd
+		expected []*commands.Branch
+	}
+
+	scenarios := []scenario{
+		{
+			"Can't retrieve the branches",
+			func(string, ...string) *exec.Cmd {
+				return exec.Command("test")
+			},
+			[]*commands.Branch{},
+		},
+		{
+			"Branches newest first",
+			test.CreateMockCommand(t, []*test.CommandSwapper{
+				{
+					Expect:  "git for-each-ref --sort=-committerdate --format=%(committerdate:relative)|%(refname:short) refs/heads",
+					Replace: "echo \"3 hours ago|feature\n2 years, 3 months ago|master\"",
+				},
+			}),
+			[]*commands.Branch{
+				{Name: "feature", Recency: "3h"},
+				{Name: "master", Recency: "2y"},
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			b := NewDummyBranchListBuilder()
+			b.GitCommand.OSCommand.SetCommand(s.command)
+
+			assert.EqualValues(t, s.expected, b.obtainDatedBranches())
+		})
+	}
+}
+
+// TestSortBranches is a function.
+func TestSortBranches(t *testing.T) {
+	branches := func() []*commands.Branch {
+		return []*commands.Branch{
+			{Name: "master", Recency: "1d"},
+			{Name: "zebra", Recency: "2d"},
+			{Name: "Apple", Recency: "3d"},
+			{Name: "local-only"},
+		}
+	}
+	names := func(branches []*commands.Branch) []string {
+		result := []string{}
+		for _, branch := range branches {
+			result = append(result, branch.Name)
+		}
+		return result
+	}
+
+	t.Run("By name", func(t *testing.T) {
+		sorted := sortBranchesByName(branches())
+		assert.EqualValues(t, []string{"master", "Apple", "local-only", "zebra"}, names(sorted))
+	})
+
+	t.Run("By date", func(t *testing.T) {
+		datedBranches := []*commands.Branch{
+			{Name: "Apple", Recency: "5m"},
+			{Name: "master", Recency: "1w"},
+			{Name: "zebra", Recency: "2y"},
+		}
+		sorted := sortBranchesByDate(branches(), datedBranches)
+		assert.EqualValues(t, []string{"master", "Apple", "zebra", "local-only"}, names(sorted))
+		assert.EqualValues(t, "5m", sorted[1].Recency)
+		assert.EqualValues(t, "1w", sorted[0].Recency)
+	})
+}
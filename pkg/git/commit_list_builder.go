@@ -32,10 +32,20 @@ type CommitListBuilder struct {
 	Tr                  *i18n.Localizer
 	CherryPickedCommits []*commands.Commit
 	DiffEntries         []*commands.Commit
+	// FilterPath limits the commits to those that changed this file or
+	// directory, unless it's empty
+	FilterPath string
+	// BisectInfo is the bisect in progress, if any, whose commits we mark
+	BisectInfo *commands.BisectInfo
+	// Limit is how many commits to get from the log
+	Limit int
+	// Ref is the branch whose commits we get, or the checked out branch's if
+	// it's empty
+	Ref string
 }
 
 // NewCommitListBuilder builds a new commit list builder
-func NewCommitListBuilder(log *logrus.Entry, gitCommand *commands.GitCommand, osCommand *commands.OSCommand, tr *i18n.Localizer, cherryPickedCommits []*commands.Commit, diffEntries []*commands.Commit) (*CommitListBuilder, error) {
+func NewCommitListBuilder(log *logrus.Entry, gitCommand *commands.GitCommand, osCommand *commands.OSCommand, tr *i18n.Localizer, cherryPickedCommits []*commands.Commit, diffEntries []*commands.Commit, filterPath string, bisectInfo *commands.BisectInfo, limit int, ref string) (*CommitListBuilder, error) {
 	return &CommitListBuilder{
 		Log:                 log,
 		GitCommand:          gitCommand,
@@ -43,6 +53,10 @@ func NewCommitListBuilder(log *logrus.Entry, gitCommand *commands.GitCommand, os
 		Tr:                  tr,
 		CherryPickedCommits: cherryPickedCommits,
 		DiffEntries:         diffEntries,
+		FilterPath:          filterPath,
+		BisectInfo:          bisectInfo,
+		Limit:               limit,
+		Ref:                 ref,
 	}, nil
 }
 
@@ -54,6 +68,12 @@ func (c *CommitListBuilder) GetCommits() ([]*commands.Commit, error) {
 	if err != nil {
 		return nil, err
 	}
+	// a rebase in progress is only on the checked out branch, and when we're
+	// only showing the commits that changed a path we can't say which of them
+	// the rebase is up to
+	if c.Ref != "" || c.FilterPath != "" {
+		rebaseMode = ""
+	}
 	if rebaseMode != "" {
 		// here we want to also prepend the commits that we're in the process of rebasing
 		rebasingCommits, err = c.getRebasingCommits(rebaseMode)
@@ -70,24 +90,26 @@ func (c *CommitListBuilder) GetCommits() ([]*commands.Commit, error) {
 
 	// now we can split it up and turn it into commits
 	for _, line := range utils.SplitLines(log) {
-		splitLine := strings.Split(line, " ")
-		sha := splitLine[0]
-		_, unpushed := unpushedCommits[sha]
-		status := map[bool]string{true: "unpushed", false: "pushed"}[unpushed]
-		commits = append(commits, &commands.Commit{
-			Sha:           sha,
-			Name:          strings.Join(splitLine[1:], " "),
-			Status:        status,
-			DisplayString: strings.Join(splitLine, " "),
-		})
+		commit := commitFromLogLine(line)
+		_, unpushed := unpushedCommits[commit.Sha]
+		commit.Status = map[bool]string{true: "unpushed", false: "pushed"}[unpushed]
+		commits = append(commits, commit)
 	}
-	if rebaseMode != "" {
+	if rebaseMode != "" && len(commits) > len(rebasingCommits) {
 		currentCommit := commits[len(rebasingCommits)]
 		blue := color.New(color.FgYellow)
 		youAreHere := blue.Sprintf("<-- %s ---", c.Tr.SLocalize("YouAreHere"))
 		currentCommit.Name = fmt.Sprintf("%s %s", youAreHere, currentCommit.Name)
 	}
 
+	if dateField, _ := c.dateFormat(); dateField != "" {
+		// commits still to be picked in a rebase have no date, but still need
+		// the column
+		for _, commit := range commits {
+			commit.ShowDate = true
+		}
+	}
+
 	commits, err = c.setCommitMergedStatuses(commits)
 	if err != nil {
 		return nil, err
@@ -106,6 +128,12 @@ func (c *CommitListBuilder) GetCommits() ([]*commands.Commit, error) {
 		}
 	}
 
+	if c.BisectInfo != nil {
+		for _, commit := range commits {
+			commit.Bisect = c.BisectInfo.Status(commit.Sha)
+		}
+	}
+
 	return commits, nil
 }
 
@@ -251,9 +279,14 @@ func (c *CommitListBuilder) setCommitCherryPickStatuses(commits []*commands.Comm
 }
 
 func (c *CommitListBuilder) getMergeBase() (string, error) {
-	currentBranch, err := c.GitCommand.CurrentBranchName()
-	if err != nil {
-		return "", err
+	currentBranch, ref := c.Ref, c.Ref
+	if ref == "" {
+		var err error
+		currentBranch, err = c.GitCommand.CurrentBranchName()
+		if err != nil {
+			return "", err
+		}
+		ref = "HEAD"
 	}
 
 	baseBranch := "master"
@@ -262,7 +295,7 @@ func (c *CommitListBuilder) getMergeBase() (string, error) {
 	}
 
 	// swallowing error because it's not a big deal; probably because there are no commits yet
-	output, _ := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git merge-base HEAD %s", baseBranch))
+	output, _ := c.OSCommand.RunCommandWithOutput(fmt.Sprintf("git merge-base %s %s", ref, baseBranch))
 	return output, nil
 }
 
@@ -270,7 +303,11 @@ func (c *CommitListBuilder) getMergeBase() (string, error) {
 // to the remote branch of the current branch, a map is returned to ease look up
 func (c *CommitListBuilder) getUnpushedCommits() map[string]bool {
 	pushables := map[string]bool{}
-	o, err := c.OSCommand.RunCommandWithOutput("git rev-list @{u}..HEAD --abbrev-commit")
+	revisions := "@{u}..HEAD"
+	if c.Ref != "" {
+		revisions = fmt.Sprintf("%s@{u}..%s", c.Ref, c.Ref)
+	}
+	o, err := c.OSCommand.RunCommandWithOutput("git rev-list " + revisions + " --abbrev-commit")
 	if err != nil {
 		return pushables
 	}
@@ -281,12 +318,35 @@ func (c *CommitListBuilder) getUnpushedCommits() map[string]bool {
 	return pushables
 }
 
-// getLog gets the git log (currently limited to 30 commits for performance
-// until we work out lazy loading
+// getLog gets the git log, up to Limit commits. The commits panel loads them a
+// page at a time, raising the limit when you scroll to the last one
 func (c *CommitListBuilder) getLog() string {
-	// currently limiting to 30 for performance reasons
-	// TODO: add lazyloading when you scroll down
-	result, err := c.OSCommand.RunCommandWithOutput("git log --oneline -30")
+	// we only get as many commits as we're showing, which on a large repo is far
+	// quicker than getting all of them
+	filterArg := ""
+	if c.FilterPath != "" {
+		filterArg = " -- " + c.OSCommand.Quote(c.FilterPath)
+	}
+	// while bisecting HEAD is the commit being tested, and we also want to see
+	// the ones between it and the bad commit, in the order they were made
+	refsArg := ""
+	if c.Ref != "" {
+		refsArg = " " + c.OSCommand.Quote(c.Ref)
+	} else if c.BisectInfo != nil && c.BisectInfo.Bad != "" {
+		refsArg = " --topo-order HEAD " + c.BisectInfo.Bad
+	}
+	formatArg := " --oneline"
+	dateField, dateArg := c.dateFormat()
+	// checking signatures means running gpg for each signed commit, so we only
+	// do it if asked to
+	signatureField := ""
+	if c.showSignatures() {
+		signatureField = "%G?"
+	}
+	if signatureField != "" || dateField != "" {
+		formatArg = fmt.Sprintf(` --format="%%h%%x00%s%%x00%s%%x00%%s"%s`, signatureField, dateField, dateArg)
+	}
+	result, err := c.OSCommand.RunCommandWithOutput("git log" + formatArg + fmt.Sprintf(" -%d", c.Limit) + refsArg + filterArg)
 	if err != nil {
 		// assume if there is an error there are no commits yet for this branch
 		return ""
@@ -294,3 +354,50 @@ func (c *CommitListBuilder) getLog() string {
 
 	return result
 }
+
+// showSignatures says whether we mark each commit with whether its GPG
+// signature is good
+func (c *CommitListBuilder) showSignatures() bool {
+	return c.GitCommand.Config.GetUserConfig().GetBool("git.showSignatures")
+}
+
+// dateFormat returns the git log placeholder for the date we show next to each
+// commit, which is its author date or its commit date, and the --date option
+// to format it as configured. Both are empty if we don't show dates
+func (c *CommitListBuilder) dateFormat() (string, string) {
+	userConfig := c.GitCommand.Config.GetUserConfig()
+	dateArgs := map[string]string{
+		commands.CommitDateRelative: " --date=relative",
+		commands.CommitDateShort:    " --date=short",
+		commands.CommitDateFull:     " --date=iso",
+	}
+	dateArg, ok := dateArgs[userConfig.GetString("gui.commitDate.format")]
+	if !ok {
+		return "", ""
+	}
+	if userConfig.GetString("gui.commitDate.source") == commands.CommitDateSourceCommitter {
+		return "%cd", dateArg
+	}
+	return "%ad", dateArg
+}
+
+// commitFromLogLine reads a commit from a line of getLog's output, which is
+// either the sha and subject, or those with the signature and date between
+// them, separated by null bytes
+func commitFromLogLine(line string) *commands.Commit {
+	if fields := strings.SplitN(line, "\x00", 4); len(fields) == 4 {
+		return &commands.Commit{
+			Sha:           fields[0],
+			Name:          fields[3],
+			DisplayString: fields[0] + " " + fields[3],
+			Signature:     fields[1],
+			Date:          fields[2],
+		}
+	}
+	splitLine := strings.Split(line, " ")
+	return &commands.Commit{
+		Sha:           splitLine[0],
+		Name:          strings.Join(splitLine[1:], " "),
+		DisplayString: line,
+	}
+}
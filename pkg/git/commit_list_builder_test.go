@@ -1,7 +1,10 @@
 package git
 
 import (
+	"io/ioutil"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"testing"
 
 	"github.com/jesseduffield/lazygit/pkg/commands"
@@ -19,6 +22,7 @@ func NewDummyCommitListBuilder() *CommitListBuilder {
 		OSCommand:           osCommand,
 		Tr:                  i18n.NewLocalizer(commands.NewDummyLog()),
 		CherryPickedCommits: []*commands.Commit{},
+		Limit:               30,
 	}
 }
 
@@ -154,14 +158,20 @@ func TestCommitListBuilderGetMergeBase(t *testing.T) {
 // TestCommitListBuilderGetLog is a function.
 func TestCommitListBuilderGetLog(t *testing.T) {
 	type scenario struct {
-		testName string
-		command  func(string, ...string) *exec.Cmd
-		test     func(string)
+		testName   string
+		filterPath string
+		ref        string
+		bisectInfo *commands.BisectInfo
+		command    func(string, ...string) *exec.Cmd
+		test       func(string)
 	}
 
 	scenarios := []scenario{
 		{
 			"Retrieves logs",
+			"",
+			"",
+			nil,
 			func(cmd string, args ...string) *exec.Cmd {
 				assert.EqualValues(t, "git", cmd)
 				assert.EqualValues(t, []string{"log", "--oneline", "-30"}, args)
@@ -174,6 +184,9 @@ func TestCommitListBuilderGetLog(t *testing.T) {
 		},
 		{
 			"An error occurred when retrieving logs",
+			"",
+			"",
+			nil,
 			func(cmd string, args ...string) *exec.Cmd {
 				assert.EqualValues(t, "git", cmd)
 				assert.EqualValues(t, []string{"log", "--oneline", "-30"}, args)
@@ -183,11 +196,59 @@ func TestCommitListBuilderGetLog(t *testing.T) {
 				assert.Empty(t, output)
 			},
 		},
+		{
+			"Retrieves logs for a path",
+			"pkg/my file.go",
+			"",
+			nil,
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"log", "--oneline", "-30", "--", "pkg/my file.go"}, args)
+
+				return exec.Command("echo", "6f0b32f commands/git : add GetCommits tests refactor")
+			},
+			func(output string) {
+				assert.EqualValues(t, "6f0b32f commands/git : add GetCommits tests refactor\n", output)
+			},
+		},
+		{
+			"Retrieves logs up to the bad commit while bisecting",
+			"",
+			"",
+			&commands.BisectInfo{Bad: "9d9d775", Current: "6f0b32f", Remaining: -1},
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"log", "--oneline", "-30", "--topo-order", "HEAD", "9d9d775"}, args)
+
+				return exec.Command("echo", "9d9d775 circle : remove new line\n6f0b32f commands/git : add GetCommits tests refactor")
+			},
+			func(output string) {
+				assert.EqualValues(t, "9d9d775 circle : remove new line\n6f0b32f commands/git : add GetCommits tests refactor\n", output)
+			},
+		},
+		{
+			"Retrieves logs of another branch",
+			"",
+			"feature",
+			nil,
+			func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+				assert.EqualValues(t, []string{"log", "--oneline", "-30", "feature"}, args)
+
+				return exec.Command("echo", "9d9d775 circle : remove new line")
+			},
+			func(output string) {
+				assert.EqualValues(t, "9d9d775 circle : remove new line\n", output)
+			},
+		},
 	}
 
 	for _, s := range scenarios {
 		t.Run(s.testName, func(t *testing.T) {
 			c := NewDummyCommitListBuilder()
+			c.FilterPath = s.filterPath
+			c.Ref = s.ref
+			c.BisectInfo = s.bisectInfo
 			c.OSCommand.SetCommand(s.command)
 			s.test(c.getLog())
 		})
@@ -313,3 +374,138 @@ func TestCommitListBuilderGetCommits(t *testing.T) {
 		})
 	}
 }
+
+// TestCommitListBuilderGetCommitsFilteredDuringRebase is a function.
+func TestCommitListBuilderGetCommitsFilteredDuringRebase(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lazygit-rebase")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "rebase-merge"), 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "rebase-merge", "git-rebase-todo"), []byte("pick 8a2bb0e5 commit 1\n"), 0644))
+
+	c := NewDummyCommitListBuilder()
+	c.GitCommand.DotGitDir = dir
+	c.FilterPath = "nothing-changed-this"
+	c.OSCommand.SetCommand(func(cmd string, args ...string) *exec.Cmd {
+		switch args[0] {
+		case "log":
+			assert.EqualValues(t, []string{"log", "--oneline", "-30", "--", "nothing-changed-this"}, args)
+			return exec.Command("echo")
+		case "symbolic-ref":
+			return exec.Command("echo", "master")
+		}
+		return exec.Command("test")
+	})
+
+	commits, err := c.GetCommits()
+	assert.NoError(t, err)
+	assert.Len(t, commits, 0)
+}
+
+// TestCommitListBuilderGetCommitsWithSignaturesAndDates is a function.
+func TestCommitListBuilderGetCommitsWithSignaturesAndDates(t *testing.T) {
+	type scenario struct {
+		testName       string
+		showSignatures bool
+		dateFormat     string
+		dateSource     string
+		expectedArgs   []string
+		output         string
+		expected       []*commands.Commit
+	}
+
+	scenarios := []scenario{
+		{
+			"signatures",
+			true,
+			"none",
+			"author",
+			[]string{"log", "--format=%h%x00%G?%x00%x00%s", "-30"},
+			`8a2bb0e\x00G\x00\x00commit 1\n78976bc\x00N\x00\x00commit 2`,
+			[]*commands.Commit{
+				{
+					Sha:           "8a2bb0e",
+					Name:          "commit 1",
+					Status:        "pushed",
+					DisplayString: "8a2bb0e commit 1",
+					Signature:     "G",
+				},
+				{
+					Sha:           "78976bc",
+					Name:          "commit 2",
+					Status:        "pushed",
+					DisplayString: "78976bc commit 2",
+					Signature:     "N",
+				},
+			},
+		},
+		{
+			"relative author dates",
+			false,
+			"relative",
+			"author",
+			[]string{"log", "--format=%h%x00%x00%ad%x00%s", "--date=relative", "-30"},
+			`8a2bb0e\x00\x003 hours ago\x00commit 1`,
+			[]*commands.Commit{
+				{
+					Sha:           "8a2bb0e",
+					Name:          "commit 1",
+					Status:        "pushed",
+					DisplayString: "8a2bb0e commit 1",
+					Date:          "3 hours ago",
+					ShowDate:      true,
+				},
+			},
+		},
+		{
+			"short commit dates with signatures",
+			true,
+			"short",
+			"committer",
+			[]string{"log", "--format=%h%x00%G?%x00%cd%x00%s", "--date=short", "-30"},
+			`8a2bb0e\x00B\x002020-01-02\x00commit 1`,
+			[]*commands.Commit{
+				{
+					Sha:           "8a2bb0e",
+					Name:          "commit 1",
+					Status:        "pushed",
+					DisplayString: "8a2bb0e commit 1",
+					Signature:     "B",
+					Date:          "2020-01-02",
+					ShowDate:      true,
+				},
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			c := NewDummyCommitListBuilder()
+			userConfig := c.GitCommand.Config.GetUserConfig()
+			userConfig.Set("git.showSignatures", s.showSignatures)
+			userConfig.Set("gui.commitDate.format", s.dateFormat)
+			userConfig.Set("gui.commitDate.source", s.dateSource)
+			c.OSCommand.SetCommand(func(cmd string, args ...string) *exec.Cmd {
+				assert.EqualValues(t, "git", cmd)
+
+				switch args[0] {
+				case "rev-list":
+					return exec.Command("echo")
+				case "log":
+					assert.EqualValues(t, s.expectedArgs, args)
+					return exec.Command("printf", s.output)
+				case "merge-base":
+					return exec.Command("test")
+				case "symbolic-ref":
+					return exec.Command("echo", "master")
+				}
+
+				return nil
+			})
+
+			commits, err := c.GetCommits()
+			assert.NoError(t, err)
+			assert.EqualValues(t, s.expected, commits)
+		})
+	}
+}
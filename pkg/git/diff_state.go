@@ -0,0 +1,120 @@
+package git
+
+import (
+	"github.com/go-errors/errors"
+
+	"github.com/jesseduffield/lazygit/pkg/i18n"
+	"github.com/sirupsen/logrus"
+)
+
+// DiffState is the parsed diff of a single file. It's created once per diff and
+// then used both to find our way around the diff and to build patches out of
+// it. The hunk starts, stageable lines, line numbers and rendered diff are
+// worked out the first time they're asked for and kept until the diff changes
+type DiffState struct {
+	Log *logrus.Entry
+	Tr  *i18n.Localizer
+
+	patch          *Patch
+	source         string
+	diff           string
+	hunkStarts     []int
+	stageableLines []int
+	oldLineNumbers []int
+	newLineNumbers []int
+}
+
+// NewDiffState parses the diff of a single file
+func NewDiffState(log *logrus.Entry, tr *i18n.Localizer, diff string) *DiffState {
+	return &DiffState{
+		Log:    log,
+		Tr:     tr,
+		patch:  NewPatch(diff),
+		source: diff,
+		diff:   diff,
+	}
+}
+
+// Source returns the diff as it was parsed, before any changes made to it
+func (d *DiffState) Source() string {
+	return d.source
+}
+
+// Diff returns the diff, including any changes made to it since it was parsed
+// e.g. hunks being split
+func (d *DiffState) Diff() string {
+	if d.diff == "" {
+		d.diff = d.patch.Render()
+	}
+	return d.diff
+}
+
+// HunkStarts returns the line numbers of the hunk headers
+func (d *DiffState) HunkStarts() []int {
+	if d.hunkStarts == nil {
+		d.hunkStarts = d.patch.HunkStarts()
+	}
+	return d.hunkStarts
+}
+
+// StageableLines returns the line numbers of the lines that can be staged
+func (d *DiffState) StageableLines() []int {
+	if d.stageableLines == nil {
+		d.stageableLines = d.patch.StageableLines()
+	}
+	return d.stageableLines
+}
+
+// LineNumbers returns, for each line of the diff, its line number in the old
+// and new versions of the file. See Patch.LineNumbers
+func (d *DiffState) LineNumbers() ([]int, []int) {
+	if d.oldLineNumbers == nil {
+		d.oldLineNumbers, d.newLineNumbers = d.patch.LineNumbers()
+	}
+	return d.oldLineNumbers, d.newLineNumbers
+}
+
+// FirstChangedLine returns the line number of the first change in the new
+// version of the file. See Patch.FirstChangedLine
+func (d *DiffState) FirstChangedLine() int {
+	return d.patch.FirstChangedLine()
+}
+
+// IsBinary returns true if the diff is of a binary file
+func (d *DiffState) IsBinary() bool {
+	return d.patch.IsBinary()
+}
+
+// PatchForHunk returns a patch of just the hunk containing the given line. A
+// whole hunk applies in either direction, so there's nothing to reverse
+func (d *DiffState) PatchForHunk(lineNumber int) (string, error) {
+	if !d.patch.HasHunks() {
+		return "", errors.New(d.Tr.SLocalize("CantFindHunks"))
+	}
+	return d.patch.PatchForHunk(lineNumber).Render(), nil
+}
+
+// PatchForLines returns a patch of the given lines, which may be spread across
+// several hunks. If reverse is true the patch is built to be applied with
+// `git apply --reverse` e.g. to unstage the lines. See Patch.FilterLines
+func (d *DiffState) PatchForLines(lineNumbers []int, reverse bool) (string, error) {
+	if !d.patch.HasHunks() {
+		return "", errors.New(d.Tr.SLocalize("CantFindHunks"))
+	}
+	return d.patch.FilterLines(lineNumbers, reverse).Render(), nil
+}
+
+// SplitHunk breaks up the hunk containing the given line into smaller hunks.
+// See Patch.SplitHunk
+func (d *DiffState) SplitHunk(lineNumber int) error {
+	if !d.patch.SplitHunk(lineNumber) {
+		return errors.New(d.Tr.SLocalize("CantSplitHunk"))
+	}
+
+	d.diff = ""
+	d.hunkStarts = nil
+	d.stageableLines = nil
+	d.oldLineNumbers = nil
+	d.newLineNumbers = nil
+	return nil
+}
@@ -0,0 +1,275 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/jesseduffield/lazygit/pkg/commands"
+	"github.com/jesseduffield/lazygit/pkg/i18n"
+	"github.com/stretchr/testify/assert"
+)
+
+// NewDummyDiffState constructs a new dummy diff state for testing
+func NewDummyDiffState(diff string) *DiffState {
+	return NewDiffState(commands.NewDummyLog(), i18n.NewLocalizer(commands.NewDummyLog()), diff)
+}
+
+// lineRange returns the line numbers from first to last inclusive
+func lineRange(first int, last int) []int {
+	lineNumbers := []int{}
+	for lineNumber := first; lineNumber <= last; lineNumber++ {
+		lineNumbers = append(lineNumbers, lineNumber)
+	}
+	return lineNumbers
+}
+
+// TestDiffStateParse is a function.
+func TestDiffStateParse(t *testing.T) {
+	type scenario struct {
+		testName               string
+		patchFilename          string
+		expectedStageableLines []int
+		expectedHunkStarts     []int
+	}
+
+	scenarios := []scenario{
+		{
+			"Diff with one hunk",
+			"testdata/testPatchBefore.diff",
+			[]int{8, 9, 10, 11},
+			[]int{4},
+		},
+		{
+			"Diff with two hunks",
+			"testdata/testPatchBefore2.diff",
+			[]int{8, 9, 10, 11, 12, 13, 20, 21, 22, 23, 24, 25, 26, 27, 28, 33, 34, 35, 36, 37, 45, 46, 47, 48, 49, 50, 51, 52, 53},
+			[]int{4, 41},
+		},
+		{
+			"Unstaged file",
+			"testdata/addedFile.diff",
+			[]int{6},
+			[]int{5},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			d := NewDummyDiffState(readTestFile(s.patchFilename))
+			assert.Equal(t, s.expectedStageableLines, d.StageableLines())
+			assert.Equal(t, s.expectedHunkStarts, d.HunkStarts())
+		})
+	}
+}
+
+// TestDiffStateLineNumbers is a function.
+func TestDiffStateLineNumbers(t *testing.T) {
+	type scenario struct {
+		testName               string
+		patchFilename          string
+		expectedOldLineNumbers []int
+		expectedNewLineNumbers []int
+	}
+
+	scenarios := []scenario{
+		{
+			"Diff with several changes in one hunk",
+			"testdata/testPatchBefore4.diff",
+			[]int{0, 0, 0, 0, 0, 2, 3, 4, 5, 0, 6, 7, 8, 9, 0, 0, 10, 11, 0, 12, 13, 14},
+			[]int{0, 0, 0, 0, 0, 2, 3, 4, 0, 5, 6, 7, 8, 0, 9, 10, 11, 0, 12, 13, 14, 15},
+		},
+		{
+			"Unstaged file",
+			"testdata/addedFile.diff",
+			[]int{0, 0, 0, 0, 0, 0, 0},
+			[]int{0, 0, 0, 0, 0, 0, 1},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			oldLineNumbers, newLineNumbers := NewDummyDiffState(readTestFile(s.patchFilename)).LineNumbers()
+			assert.Equal(t, s.expectedOldLineNumbers, oldLineNumbers)
+			assert.Equal(t, s.expectedNewLineNumbers, newLineNumbers)
+		})
+	}
+}
+
+// TestDiffStatePatchForLines is a function.
+func TestDiffStatePatchForLines(t *testing.T) {
+	type scenario struct {
+		testName              string
+		patchFilename         string
+		lineNumbers           []int
+		reverse               bool
+		expectedPatchFilename string
+	}
+
+	scenarios := []scenario{
+		{
+			"Removing one line",
+			"testdata/testPatchBefore.diff",
+			[]int{8},
+			false,
+			"testdata/testPatchAfter1.diff",
+		},
+		{
+			"Adding one line",
+			"testdata/testPatchBefore.diff",
+			[]int{10},
+			false,
+			"testdata/testPatchAfter2.diff",
+		},
+		{
+			"Adding one line in top hunk in diff with multiple hunks",
+			"testdata/testPatchBefore2.diff",
+			[]int{20},
+			false,
+			"testdata/testPatchAfter3.diff",
+		},
+		{
+			"Adding one line in bottom hunk in diff with multiple hunks",
+			"testdata/testPatchBefore2.diff",
+			[]int{53},
+			false,
+			"testdata/testPatchAfter4.diff",
+		},
+		{
+			"Adding unstaged file with a single line",
+			"testdata/addedFile.diff",
+			[]int{6},
+			false,
+			"testdata/addedFile.diff",
+		},
+		{
+			"Staging a range spanning two hunks",
+			"testdata/testPatchBefore2.diff",
+			lineRange(33, 52),
+			false,
+			"testdata/testPatchAfter5.diff",
+		},
+		{
+			"Unstaging one line of a staged patch",
+			"testdata/testPatchBefore3.diff",
+			[]int{9, 10},
+			true,
+			"testdata/testPatchAfter6.diff",
+		},
+		{
+			"Unstaging a single line",
+			"testdata/testPatchBefore3.diff",
+			[]int{10},
+			true,
+			"testdata/testPatchAfter18.diff",
+		},
+		{
+			"Lines selected from two hunks",
+			"testdata/testPatchBefore2.diff",
+			[]int{8, 9, 45},
+			false,
+			"testdata/testPatchAfter19.diff",
+		},
+		{
+			"Lines selected out of order",
+			"testdata/testPatchBefore2.diff",
+			[]int{45, 9, 8},
+			false,
+			"testdata/testPatchAfter19.diff",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			d := NewDummyDiffState(readTestFile(s.patchFilename))
+			patch, err := d.PatchForLines(s.lineNumbers, s.reverse)
+			assert.NoError(t, err)
+			assert.Equal(t, readTestFile(s.expectedPatchFilename), patch)
+		})
+	}
+}
+
+// TestDiffStatePatchForHunk is a function.
+func TestDiffStatePatchForHunk(t *testing.T) {
+	type scenario struct {
+		testName              string
+		patch                 string
+		lineNumber            int
+		shouldError           bool
+		expectedPatchFilename string
+	}
+
+	scenarios := []scenario{
+		{
+			"Unstaging a whole hunk",
+			readTestFile("testdata/testPatchBefore3.diff"),
+			10,
+			false,
+			"testdata/testPatchBefore3.diff",
+		},
+		{
+			"Diff with no hunks",
+			readTestFile("testdata/binaryFile.diff"),
+			0,
+			true,
+			"",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			patch, err := NewDummyDiffState(s.patch).PatchForHunk(s.lineNumber)
+			if s.shouldError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, readTestFile(s.expectedPatchFilename), patch)
+			}
+		})
+	}
+}
+
+// TestDiffStateSplitHunk is a function.
+func TestDiffStateSplitHunk(t *testing.T) {
+	type scenario struct {
+		testName              string
+		patchFilename         string
+		lineNumber            int
+		shouldError           bool
+		expectedPatchFilename string
+	}
+
+	scenarios := []scenario{
+		{
+			"Splitting a hunk with three runs of changes",
+			"testdata/testPatchBefore4.diff",
+			8,
+			false,
+			"testdata/testPatchAfter7.diff",
+		},
+		{
+			"Splitting a hunk with a single run of changes",
+			"testdata/addedFile.diff",
+			6,
+			true,
+			"",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			d := NewDummyDiffState(readTestFile(s.patchFilename))
+			// asking for these first so that we know they aren't left stale
+			hunkStarts := d.HunkStarts()
+			err := d.SplitHunk(s.lineNumber)
+			if s.shouldError {
+				assert.Error(t, err)
+				assert.Equal(t, hunkStarts, d.HunkStarts())
+			} else {
+				assert.NoError(t, err)
+				expected := readTestFile(s.expectedPatchFilename)
+				assert.Equal(t, expected, d.Diff())
+				assert.Equal(t, NewPatch(expected).HunkStarts(), d.HunkStarts())
+				assert.Equal(t, NewPatch(expected).StageableLines(), d.StageableLines())
+			}
+		})
+	}
+}
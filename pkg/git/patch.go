@@ -0,0 +1,550 @@
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Patch is a parsed diff made up of the diffs of one or more files. Line
+// numbers passed to and returned from its methods are indices into the lines of
+// the rendered patch, which match the lines of the diff it was parsed from
+type Patch struct {
+	FileDiffs []*FileDiff
+}
+
+// FileDiff is the part of a patch concerning a single file
+type FileDiff struct {
+	// Header holds the lines before the first hunk e.g. `diff --git a/foo b/foo`.
+	// This includes any `rename from`/`rename to` and `old mode`/`new mode`
+	// lines, which we keep as they are so that patches built from the file diff
+	// still rename the file or change its mode
+	Header []string
+	Hunks  []*Hunk
+}
+
+// Hunk is a single hunk of a file diff. Its header is recomputed from its lines
+// when it's rendered, so lines can be added and removed without worrying
+// about the line counts
+type Hunk struct {
+	OldStart int
+	NewStart int
+	// Heading is whatever follows the line ranges in the hunk header, usually
+	// the enclosing function
+	Heading string
+	// Lines holds the body of the hunk, each line keeping its prefix
+	Lines []string
+}
+
+// NewPatch parses a diff into a Patch
+func NewPatch(diff string) *Patch {
+	patch := &Patch{FileDiffs: []*FileDiff{}}
+
+	var fileDiff *FileDiff
+	var hunk *Hunk
+	for _, line := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+		// anything before the first `diff` line (e.g. a commit message) is kept in
+		// the header of the first file diff
+		if fileDiff == nil || (strings.HasPrefix(line, "diff ") && fileDiff.hasHeaderLine("diff ")) {
+			fileDiff = &FileDiff{Header: []string{}, Hunks: []*Hunk{}}
+			patch.FileDiffs = append(patch.FileDiffs, fileDiff)
+			hunk = nil
+		}
+
+		if newHunk := parseHunkHeader(line); newHunk != nil {
+			hunk = newHunk
+			fileDiff.Hunks = append(fileDiff.Hunks, hunk)
+			continue
+		}
+
+		if hunk != nil {
+			hunk.Lines = append(hunk.Lines, line)
+		} else {
+			fileDiff.Header = append(fileDiff.Header, line)
+		}
+	}
+
+	return patch
+}
+
+// hunkHeaderRegexp matches a hunk header like `@@ -14,8 +14,9 @@ import (`,
+// capturing the start of each side and the heading. Git leaves the length of a
+// side out when it's 1
+var hunkHeaderRegexp = regexp.MustCompile(`^@@ -(\d+)(?:,\d+)? \+(\d+)(?:,\d+)? @@(.*)$`)
+
+// parseHunkHeader returns an empty hunk for the given hunk header, or nil if the
+// line isn't a hunk header. We don't need the lengths, given the header is
+// recomputed from the hunk's lines when it's rendered, but the heading (e.g. the
+// enclosing function) is kept as it is, including any `@@` in it
+func parseHunkHeader(line string) *Hunk {
+	matches := hunkHeaderRegexp.FindStringSubmatch(line)
+	if matches == nil {
+		return nil
+	}
+	oldStart, _ := strconv.Atoi(matches[1])
+	newStart, _ := strconv.Atoi(matches[2])
+	return &Hunk{OldStart: oldStart, NewStart: newStart, Heading: matches[3], Lines: []string{}}
+}
+
+// Render returns the patch as a string that can be passed to `git apply`
+func (p *Patch) Render() string {
+	lines := p.lines()
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func (p *Patch) lines() []string {
+	lines := []string{}
+	for _, fileDiff := range p.FileDiffs {
+		lines = append(lines, fileDiff.lines()...)
+	}
+	return lines
+}
+
+func (f *FileDiff) lines() []string {
+	lines := append([]string{}, f.Header...)
+	for _, hunk := range f.Hunks {
+		lines = append(lines, hunk.header())
+		lines = append(lines, hunk.Lines...)
+	}
+	return lines
+}
+
+// header returns the hunk's header with line ranges matching its lines, e.g.
+// `@@ -14,8 +14,9 @@ import (`
+func (h *Hunk) header() string {
+	oldLength, newLength := countHunkLines(h.Lines)
+	return fmt.Sprintf("@@ -%s +%s @@%s", formatHunkRange(h.OldStart, oldLength), formatHunkRange(h.NewStart, newLength), h.Heading)
+}
+
+// formatHunkRange formats one side of a hunk header's line range. Like git, we
+// leave the length out when it's 1. An empty file starts at line 0, so if
+// we've put lines into one (e.g. by turning additions into context) it needs
+// to start at line 1 instead
+func formatHunkRange(start int, length int) string {
+	if start == 0 && length > 0 {
+		start = 1
+	}
+	if length == 1 {
+		return strconv.Itoa(start)
+	}
+	return fmt.Sprintf("%d,%d", start, length)
+}
+
+// countHunkLines returns how many lines of the old and new file are covered by
+// the given lines of a hunk
+func countHunkLines(lines []string) (int, int) {
+	oldCount, newCount := 0, 0
+	for _, line := range lines {
+		switch {
+		case strings.HasPrefix(line, " "):
+			oldCount++
+			newCount++
+		case strings.HasPrefix(line, "-"):
+			oldCount++
+		case strings.HasPrefix(line, "+"):
+			newCount++
+		}
+	}
+	return oldCount, newCount
+}
+
+func isChange(line string) bool {
+	return strings.HasPrefix(line, "-") || strings.HasPrefix(line, "+")
+}
+
+// isNoNewlineMarker returns true for the `\ No newline at end of file` line
+// that follows the last line of a file if it has no trailing newline
+func isNoNewlineMarker(line string) bool {
+	return strings.HasPrefix(line, "\\")
+}
+
+// fixNoNewlineContext deals with a context line that's missing its newline,
+// which can only be the last line of a hunk. We get one of those when we turn
+// a removal of the last line of the old file into context, but if we're also
+// adding lines after it then the line needs a newline in the new file, so we
+// have to keep the removal and add the line back with a newline
+func (h *Hunk) fixNoNewlineContext() {
+	for index := 0; index < len(h.Lines)-2; index++ {
+		if strings.HasPrefix(h.Lines[index], " ") && isNoNewlineMarker(h.Lines[index+1]) {
+			content := h.Lines[index][1:]
+			lines := append([]string{}, h.Lines[:index]...)
+			lines = append(lines, "-"+content, h.Lines[index+1], "+"+content)
+			h.Lines = append(lines, h.Lines[index+2:]...)
+			return
+		}
+	}
+}
+
+// patchLine describes where a line of the rendered patch comes from. hunk is nil
+// for lines of a file header, and bodyIndex is -1 for a hunk header
+type patchLine struct {
+	fileDiff  *FileDiff
+	hunk      *Hunk
+	bodyIndex int
+}
+
+// patchLines returns a description of each line of the rendered patch
+func (p *Patch) patchLines() []patchLine {
+	result := []patchLine{}
+	for _, fileDiff := range p.FileDiffs {
+		for range fileDiff.Header {
+			result = append(result, patchLine{fileDiff: fileDiff, bodyIndex: -1})
+		}
+		for _, hunk := range fileDiff.Hunks {
+			result = append(result, patchLine{fileDiff: fileDiff, hunk: hunk, bodyIndex: -1})
+			for index := range hunk.Lines {
+				result = append(result, patchLine{fileDiff: fileDiff, hunk: hunk, bodyIndex: index})
+			}
+		}
+	}
+	return result
+}
+
+// HunkStarts returns the line numbers of the hunk headers
+func (p *Patch) HunkStarts() []int {
+	hunkStarts := []int{}
+	for lineNumber, line := range p.patchLines() {
+		if line.hunk != nil && line.bodyIndex == -1 {
+			hunkStarts = append(hunkStarts, lineNumber)
+		}
+	}
+	return hunkStarts
+}
+
+// StageableLines returns the line numbers of the added and removed lines
+func (p *Patch) StageableLines() []int {
+	stageableLines := []int{}
+	for lineNumber, line := range p.patchLines() {
+		if line.hunk != nil && line.bodyIndex != -1 && isChange(line.hunk.Lines[line.bodyIndex]) {
+			stageableLines = append(stageableLines, lineNumber)
+		}
+	}
+	return stageableLines
+}
+
+// LineNumbers returns, for each line of the patch, its line number in the old
+// and new versions of its file. Where a line doesn't exist in a version, or
+// isn't part of a hunk body, its line number is 0
+func (p *Patch) LineNumbers() ([]int, []int) {
+	patchLines := p.patchLines()
+	oldLineNumbers := make([]int, len(patchLines))
+	newLineNumbers := make([]int, len(patchLines))
+
+	oldLineNumber, newLineNumber := 0, 0
+	for lineNumber, line := range patchLines {
+		if line.hunk == nil {
+			continue
+		}
+		if line.bodyIndex == -1 {
+			oldLineNumber, newLineNumber = line.hunk.OldStart, line.hunk.NewStart
+			continue
+		}
+
+		body := line.hunk.Lines[line.bodyIndex]
+		switch {
+		case strings.HasPrefix(body, " "):
+			oldLineNumbers[lineNumber] = oldLineNumber
+			newLineNumbers[lineNumber] = newLineNumber
+			oldLineNumber++
+			newLineNumber++
+		case strings.HasPrefix(body, "-"):
+			oldLineNumbers[lineNumber] = oldLineNumber
+			oldLineNumber++
+		case strings.HasPrefix(body, "+"):
+			newLineNumbers[lineNumber] = newLineNumber
+			newLineNumber++
+		}
+	}
+
+	return oldLineNumbers, newLineNumbers
+}
+
+// HasHunks returns true if the patch contains at least one hunk
+func (p *Patch) HasHunks() bool {
+	for _, fileDiff := range p.FileDiffs {
+		if len(fileDiff.Hunks) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// FirstChangedLine returns the line number in the new version of the file of
+// the patch's first added or removed line. A removed line isn't in the new
+// version, so we give the line that took its place. 0 means there are no
+// changes, or the file has been deleted
+func (p *Patch) FirstChangedLine() int {
+	for _, fileDiff := range p.FileDiffs {
+		for _, hunk := range fileDiff.Hunks {
+			newLineNumber := hunk.NewStart
+			for _, line := range hunk.Lines {
+				switch {
+				case strings.HasPrefix(line, " "):
+					newLineNumber++
+				case strings.HasPrefix(line, "+"), strings.HasPrefix(line, "-"):
+					return newLineNumber
+				}
+			}
+		}
+	}
+	return 0
+}
+
+// IsBinary returns true if the patch contains changes to a binary file, which
+// have no lines for us to work with
+func (p *Patch) IsBinary() bool {
+	for _, fileDiff := range p.FileDiffs {
+		if fileDiff.hasHeaderLine("Binary files ") || fileDiff.hasHeaderLine("GIT binary patch") {
+			return true
+		}
+	}
+	return false
+}
+
+// hunkAt returns the file diff and hunk that the line belongs to. Lines in a
+// file header belong to the file's first hunk
+func (p *Patch) hunkAt(lineNumber int) (*FileDiff, *Hunk) {
+	patchLines := p.patchLines()
+	if lineNumber < 0 || lineNumber >= len(patchLines) {
+		return nil, nil
+	}
+	line := patchLines[lineNumber]
+	if line.hunk == nil && len(line.fileDiff.Hunks) > 0 {
+		return line.fileDiff, line.fileDiff.Hunks[0]
+	}
+	return line.fileDiff, line.hunk
+}
+
+// PatchForHunk returns a patch containing only the hunk that the line belongs
+// to, along with its file's header
+func (p *Patch) PatchForHunk(lineNumber int) *Patch {
+	fileDiff, hunk := p.hunkAt(lineNumber)
+	if hunk == nil {
+		return &Patch{FileDiffs: []*FileDiff{}}
+	}
+	return &Patch{FileDiffs: []*FileDiff{{
+		Header: append([]string{}, fileDiff.Header...),
+		Hunks:  []*Hunk{hunk.copy()},
+	}}}
+}
+
+func (h *Hunk) copy() *Hunk {
+	return &Hunk{
+		OldStart: h.OldStart,
+		NewStart: h.NewStart,
+		Heading:  h.Heading,
+		Lines:    append([]string{}, h.Lines...),
+	}
+}
+
+// FilterLines returns a patch containing only the changes on the given lines.
+// Other removals are turned into context and other additions are dropped, so
+// that the patch can be applied on its own. Hunks and files with no selected
+// changes are left out entirely. If reverse is true, the roles of additions and
+// removals are swapped so that the patch can be applied with `git apply --reverse`
+func (p *Patch) FilterLines(lineNumbers []int, reverse bool) *Patch {
+	selected := map[int]bool{}
+	for _, lineNumber := range lineNumbers {
+		selected[lineNumber] = true
+	}
+
+	// when applying in reverse, the additions are what already exist in the
+	// target, so they take the place of removals
+	keptPrefix := "-"
+	if reverse {
+		keptPrefix = "+"
+	}
+
+	result := &Patch{FileDiffs: []*FileDiff{}}
+	var sourceFileDiff, fileDiff *FileDiff
+	var hunk *Hunk
+	hasSelectedChange := false
+	droppedPreviousLine := false
+	for lineNumber, line := range p.patchLines() {
+		if line.hunk == nil {
+			continue
+		}
+		if line.bodyIndex == -1 {
+			if line.fileDiff != sourceFileDiff {
+				sourceFileDiff = line.fileDiff
+				fileDiff = &FileDiff{Header: append([]string{}, line.fileDiff.Header...), Hunks: []*Hunk{}}
+			}
+			hunk = &Hunk{OldStart: line.hunk.OldStart, NewStart: line.hunk.NewStart, Heading: line.hunk.Heading, Lines: []string{}}
+			hasSelectedChange = false
+			continue
+		}
+
+		body := line.hunk.Lines[line.bodyIndex]
+		droppedLine := false
+		switch {
+		case isNoNewlineMarker(body):
+			// the marker belongs to the line before it, so it goes wherever that
+			// line went
+			if !droppedPreviousLine {
+				hunk.Lines = append(hunk.Lines, body)
+			}
+		case !isChange(body):
+			hunk.Lines = append(hunk.Lines, body)
+		case selected[lineNumber]:
+			hunk.Lines = append(hunk.Lines, body)
+			if !hasSelectedChange {
+				hasSelectedChange = true
+				fileDiff.Hunks = append(fileDiff.Hunks, hunk)
+				if len(result.FileDiffs) == 0 || result.FileDiffs[len(result.FileDiffs)-1] != fileDiff {
+					result.FileDiffs = append(result.FileDiffs, fileDiff)
+				}
+			}
+		case strings.HasPrefix(body, keptPrefix):
+			hunk.Lines = append(hunk.Lines, " "+body[1:])
+		default:
+			droppedLine = true
+		}
+		if !isNoNewlineMarker(body) {
+			droppedPreviousLine = droppedLine
+		}
+	}
+
+	for _, fileDiff := range result.FileDiffs {
+		for _, hunk := range fileDiff.Hunks {
+			hunk.fixNoNewlineContext()
+		}
+		fileDiff.fixCreationOrDeletionHeader()
+	}
+
+	return result
+}
+
+// fixCreationOrDeletionHeader turns the header of a new or deleted file into
+// that of a plain modification if the patch no longer creates or deletes the
+// whole file, e.g. when unstaging only some lines of a new file. Otherwise git
+// refuses to apply the patch
+func (f *FileDiff) fixCreationOrDeletionHeader() {
+	oldLength, newLength := 0, 0
+	for _, hunk := range f.Hunks {
+		hunkOldLength, hunkNewLength := countHunkLines(hunk.Lines)
+		oldLength += hunkOldLength
+		newLength += hunkNewLength
+	}
+
+	isCreation := f.hasHeaderLine("new file mode ") && oldLength > 0
+	isDeletion := f.hasHeaderLine("deleted file mode ") && newLength > 0
+	if !isCreation && !isDeletion {
+		return
+	}
+
+	oldPath, newPath := "", ""
+	for _, line := range f.Header {
+		if strings.HasPrefix(line, "--- ") {
+			oldPath = strings.TrimPrefix(line, "--- ")
+		} else if strings.HasPrefix(line, "+++ ") {
+			newPath = strings.TrimPrefix(line, "+++ ")
+		}
+	}
+	// we take the missing path from the `diff --git` line rather than assuming
+	// `a/` and `b/` prefixes, because staged diffs use `c/` and `i/` when
+	// diff.mnemonicPrefix is set
+	gitPaths := ""
+	for _, line := range f.Header {
+		if strings.HasPrefix(line, "diff --git ") {
+			gitPaths = strings.TrimPrefix(line, "diff --git ")
+		}
+	}
+	if isCreation {
+		oldPath = strings.TrimSuffix(gitPaths, " "+newPath)
+	} else {
+		newPath = strings.TrimPrefix(gitPaths, oldPath+" ")
+	}
+
+	header := []string{}
+	for _, line := range f.Header {
+		switch {
+		case strings.HasPrefix(line, "new file mode "), strings.HasPrefix(line, "deleted file mode "):
+			continue
+		case strings.HasPrefix(line, "--- "):
+			line = "--- " + oldPath
+		case strings.HasPrefix(line, "+++ "):
+			line = "+++ " + newPath
+		}
+		header = append(header, line)
+	}
+	f.Header = header
+}
+
+func (f *FileDiff) hasHeaderLine(prefix string) bool {
+	for _, line := range f.Header {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// SplitHunk breaks up the hunk that the line belongs to into smaller hunks, one
+// per run of changes, like the split option in `git add --patch`. The context
+// lines between two runs are shared by both of the resulting hunks. Returns
+// false if the hunk can't be split
+func (p *Patch) SplitHunk(lineNumber int) bool {
+	fileDiff, hunk := p.hunkAt(lineNumber)
+	if hunk == nil {
+		return false
+	}
+
+	splitHunks := hunk.split()
+	if len(splitHunks) < 2 {
+		return false
+	}
+
+	hunks := []*Hunk{}
+	for _, existingHunk := range fileDiff.Hunks {
+		if existingHunk == hunk {
+			hunks = append(hunks, splitHunks...)
+		} else {
+			hunks = append(hunks, existingHunk)
+		}
+	}
+	fileDiff.Hunks = hunks
+	return true
+}
+
+// split returns the smaller hunks that the hunk can be split into
+func (h *Hunk) split() []*Hunk {
+	// find the start and end of each run of changes in the hunk
+	runStarts := []int{}
+	runEnds := []int{}
+	for index := 0; index < len(h.Lines); {
+		if !isChange(h.Lines[index]) {
+			index++
+			continue
+		}
+		runStarts = append(runStarts, index)
+		for index < len(h.Lines) && !strings.HasPrefix(h.Lines[index], " ") {
+			index++
+		}
+		runEnds = append(runEnds, index)
+	}
+
+	hunks := []*Hunk{}
+	for runIndex := range runStarts {
+		subStart := 0
+		if runIndex > 0 {
+			subStart = runEnds[runIndex-1]
+		}
+		subEnd := len(h.Lines)
+		if runIndex < len(runStarts)-1 {
+			subEnd = runStarts[runIndex+1]
+		}
+
+		oldOffset, newOffset := countHunkLines(h.Lines[:subStart])
+		hunks = append(hunks, &Hunk{
+			OldStart: h.OldStart + oldOffset,
+			NewStart: h.NewStart + newOffset,
+			Heading:  h.Heading,
+			Lines:    append([]string{}, h.Lines[subStart:subEnd]...),
+		})
+	}
+	return hunks
+}
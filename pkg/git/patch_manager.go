@@ -0,0 +1,166 @@
+package git
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/jesseduffield/lazygit/pkg/i18n"
+	"github.com/sirupsen/logrus"
+)
+
+// PatchManager builds up a custom patch out of lines selected from the diffs
+// of any number of files, so that the patch can be applied in one go later on.
+// The lines come either from the working tree or from the diff of a commit,
+// the latter letting us split the commit in two
+type PatchManager struct {
+	Log *logrus.Entry
+	Tr  *i18n.Localizer
+
+	// commitSha is the commit whose diff the lines are selected from, or empty
+	// if they're selected from the working tree
+	commitSha string
+	// fileDiffs holds the diff of each file that we've selected lines from,
+	// which may have had its hunks split
+	fileDiffs map[string]string
+	// fileSources holds the diff of each file as git gave it to us, so that we
+	// can tell when it's changed
+	fileSources map[string]string
+	// fileLines holds the selected line numbers within each file's diff
+	fileLines map[string][]int
+}
+
+// NewPatchManager builds a new patch manager
+func NewPatchManager(log *logrus.Entry, tr *i18n.Localizer) (*PatchManager, error) {
+	p := &PatchManager{
+		Log: log,
+		Tr:  tr,
+	}
+	p.Reset()
+	return p, nil
+}
+
+// Reset deselects all lines
+func (p *PatchManager) Reset() {
+	p.fileDiffs = map[string]string{}
+	p.fileSources = map[string]string{}
+	p.fileLines = map[string][]int{}
+}
+
+// Start starts a new patch made from the diff of the given commit, or from the
+// working tree if commitSha is empty. We can't mix lines from different places
+// in one patch, so any lines selected from somewhere else are deselected
+func (p *PatchManager) Start(commitSha string) {
+	if p.commitSha == commitSha {
+		return
+	}
+	p.Reset()
+	p.commitSha = commitSha
+}
+
+// CommitSha returns the commit that the patch is being built from, or an empty
+// string if it's being built from the working tree
+func (p *PatchManager) CommitSha() string {
+	return p.commitSha
+}
+
+// IsEmpty returns true if no lines have been selected
+func (p *PatchManager) IsEmpty() bool {
+	return len(p.fileLines) == 0
+}
+
+// GetSelectedLines returns the selected line numbers within the file's diff.
+// If the diff has changed since the lines were selected, the line numbers no
+// longer mean anything so we return nothing
+func (p *PatchManager) GetSelectedLines(filename string, diff string) []int {
+	if p.fileDiffs[filename] != diff {
+		return []int{}
+	}
+	return p.fileLines[filename]
+}
+
+// AddLines selects the given lines of the file's diff. If the diff has changed
+// since we last selected lines from it, the previous selection is dropped
+func (p *PatchManager) AddLines(filename string, diffState *DiffState, lineNumbers []int) {
+	if diff := diffState.Diff(); p.fileDiffs[filename] != diff {
+		p.fileDiffs[filename] = diff
+		p.fileSources[filename] = diffState.Source()
+		p.fileLines[filename] = []int{}
+	}
+
+	selected := map[int]bool{}
+	for _, lineNumber := range p.fileLines[filename] {
+		selected[lineNumber] = true
+	}
+	for _, lineNumber := range lineNumbers {
+		if !selected[lineNumber] {
+			selected[lineNumber] = true
+			p.fileLines[filename] = append(p.fileLines[filename], lineNumber)
+		}
+	}
+	sort.Ints(p.fileLines[filename])
+}
+
+// RemoveLines deselects the given lines of the file's diff
+func (p *PatchManager) RemoveLines(filename string, lineNumbers []int) {
+	removed := map[int]bool{}
+	for _, lineNumber := range lineNumbers {
+		removed[lineNumber] = true
+	}
+
+	remaining := []int{}
+	for _, lineNumber := range p.fileLines[filename] {
+		if !removed[lineNumber] {
+			remaining = append(remaining, lineNumber)
+		}
+	}
+
+	if len(remaining) == 0 {
+		p.removeFile(filename)
+		return
+	}
+	p.fileLines[filename] = remaining
+}
+
+func (p *PatchManager) removeFile(filename string) {
+	delete(p.fileDiffs, filename)
+	delete(p.fileSources, filename)
+	delete(p.fileLines, filename)
+}
+
+// DropChangedFiles deselects the lines of any file whose diff has changed
+// since we selected them, given getDiff returns a file's diff as it is now.
+// The line numbers only mean something in the diff they were selected from, so
+// a patch made from them could fail to apply or change the wrong lines. It
+// returns true if any lines were deselected
+func (p *PatchManager) DropChangedFiles(getDiff func(filename string) string) bool {
+	dropped := false
+	for filename, source := range p.fileSources {
+		if getDiff(filename) != source {
+			p.removeFile(filename)
+			dropped = true
+		}
+	}
+	return dropped
+}
+
+// RenderPatch returns the custom patch made up of the selected lines of every
+// file. If reverse is true, it's built to be applied with `git apply --reverse`
+func (p *PatchManager) RenderPatch(reverse bool) (string, error) {
+	// sorting the filenames so that we always render the same patch
+	filenames := make([]string, 0, len(p.fileLines))
+	for filename := range p.fileLines {
+		filenames = append(filenames, filename)
+	}
+	sort.Strings(filenames)
+
+	patches := make([]string, len(filenames))
+	for i, filename := range filenames {
+		var err error
+		patches[i], err = NewDiffState(p.Log, p.Tr, p.fileDiffs[filename]).PatchForLines(p.fileLines[filename], reverse)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return strings.Join(patches, ""), nil
+}
@@ -0,0 +1,130 @@
+package git
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/jesseduffield/lazygit/pkg/commands"
+	"github.com/jesseduffield/lazygit/pkg/i18n"
+	"github.com/stretchr/testify/assert"
+)
+
+// NewDummyPatchManager constructs a new dummy patch manager for testing
+func NewDummyPatchManager() *PatchManager {
+	p, _ := NewPatchManager(commands.NewDummyLog(), i18n.NewLocalizer(commands.NewDummyLog()))
+	return p
+}
+
+func readTestFile(filename string) string {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		panic("Cannot open file at " + filename)
+	}
+	return string(content)
+}
+
+func TestPatchManager(t *testing.T) {
+	type scenario struct {
+		testName      string
+		setup         func(*PatchManager)
+		expectedPatch string
+	}
+
+	before := readTestFile("testdata/testPatchBefore.diff")
+	before2 := readTestFile("testdata/testPatchBefore2.diff")
+
+	scenarios := []scenario{
+		{
+			"No lines selected",
+			func(p *PatchManager) {},
+			"",
+		},
+		{
+			"Lines selected from two files",
+			func(p *PatchManager) {
+				p.AddLines("b", NewDummyDiffState(before2), []int{20})
+				p.AddLines("a", NewDummyDiffState(before), []int{8})
+			},
+			readTestFile("testdata/testPatchAfter1.diff") + readTestFile("testdata/testPatchAfter3.diff"),
+		},
+		{
+			"All lines of a file removed",
+			func(p *PatchManager) {
+				p.AddLines("a", NewDummyDiffState(before), []int{8, 10})
+				p.AddLines("b", NewDummyDiffState(before2), []int{20})
+				p.RemoveLines("a", []int{8, 10})
+			},
+			readTestFile("testdata/testPatchAfter3.diff"),
+		},
+		{
+			"Diff changed since lines were selected",
+			func(p *PatchManager) {
+				p.AddLines("a", NewDummyDiffState(before), []int{8})
+				p.AddLines("a", NewDummyDiffState(before2), []int{20})
+			},
+			readTestFile("testdata/testPatchAfter3.diff"),
+		},
+		{
+			"Lines of a file whose diff has changed since dropped",
+			func(p *PatchManager) {
+				p.AddLines("a", NewDummyDiffState(before), []int{8})
+				p.AddLines("b", NewDummyDiffState(before2), []int{20})
+				p.DropChangedFiles(func(filename string) string {
+					return map[string]string{"a": before2, "b": before2}[filename]
+				})
+			},
+			readTestFile("testdata/testPatchAfter3.diff"),
+		},
+		{
+			"Lines selected from a commit dropped when starting a patch from the working tree",
+			func(p *PatchManager) {
+				p.Start("abc123")
+				p.AddLines("a", NewDummyDiffState(before), []int{8})
+				p.Start("")
+				p.AddLines("b", NewDummyDiffState(before2), []int{20})
+			},
+			readTestFile("testdata/testPatchAfter3.diff"),
+		},
+		{
+			"Lines kept when starting a patch from the same commit again",
+			func(p *PatchManager) {
+				p.Start("abc123")
+				p.AddLines("b", NewDummyDiffState(before2), []int{20})
+				p.Start("abc123")
+			},
+			readTestFile("testdata/testPatchAfter3.diff"),
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			p := NewDummyPatchManager()
+			s.setup(p)
+			assert.EqualValues(t, s.expectedPatch == "", p.IsEmpty())
+			patch, err := p.RenderPatch(false)
+			assert.NoError(t, err)
+			assert.EqualValues(t, s.expectedPatch, patch)
+		})
+	}
+}
+
+// TestPatchManagerDropChangedFiles is a function.
+func TestPatchManagerDropChangedFiles(t *testing.T) {
+	diff := readTestFile("testdata/testPatchBefore4.diff")
+	getDiff := func(string) string {
+		return diff
+	}
+
+	// splitting a hunk changes the diff we select lines from, but not the one
+	// git gives us
+	p := NewDummyPatchManager()
+	d := NewDummyDiffState(diff)
+	assert.NoError(t, d.SplitHunk(8))
+	p.AddLines("a", d, d.StageableLines()[:1])
+	assert.False(t, p.DropChangedFiles(getDiff))
+	assert.False(t, p.IsEmpty())
+
+	diff = readTestFile("testdata/testPatchBefore2.diff")
+	assert.True(t, p.DropChangedFiles(getDiff))
+	assert.True(t, p.IsEmpty())
+}
@@ -0,0 +1,309 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPatchRender is a function.
+func TestPatchRender(t *testing.T) {
+	filenames := []string{
+		"testdata/testPatchBefore.diff",
+		"testdata/testPatchBefore2.diff",
+		"testdata/testPatchBefore4.diff",
+		"testdata/addedFile.diff",
+		"testdata/testPatchMultiFile.diff",
+		"testdata/testPatchBefore7.diff",
+		"testdata/testPatchBefore8.diff",
+		"testdata/testPatchBefore9.diff",
+		"testdata/testPatchBefore10.diff",
+		"testdata/testPatchCached.diff",
+	}
+
+	for _, filename := range filenames {
+		t.Run(filename, func(t *testing.T) {
+			diff := readTestFile(filename)
+			assert.EqualValues(t, diff, NewPatch(diff).Render())
+		})
+	}
+}
+
+// TestPatchLineNumbers is a function.
+func TestPatchLineNumbers(t *testing.T) {
+	patch := NewPatch(readTestFile("testdata/testPatchMultiFile.diff"))
+
+	// the `--- a/two.txt` header and the `+-- y` addition shouldn't be mistaken
+	// for one another
+	assert.EqualValues(t, []int{4, 14}, patch.HunkStarts())
+	assert.EqualValues(t, []int{6, 7, 9, 16, 17}, patch.StageableLines())
+
+	oldLineNumbers, newLineNumbers := patch.LineNumbers()
+	assert.EqualValues(t, []int{0, 0, 0, 0, 0, 1, 2, 0, 3, 0, 0, 0, 0, 0, 0, 1, 2, 0}, oldLineNumbers)
+	assert.EqualValues(t, []int{0, 0, 0, 0, 0, 1, 0, 2, 3, 4, 0, 0, 0, 0, 0, 1, 0, 2}, newLineNumbers)
+}
+
+// TestPatchFilterLines is a function.
+func TestPatchFilterLines(t *testing.T) {
+	type scenario struct {
+		testName              string
+		patchFilename         string
+		lineNumbers           []int
+		reverse               bool
+		expectedPatchFilename string
+	}
+
+	scenarios := []scenario{
+		{
+			"Lines selected from multiple files",
+			"testdata/testPatchMultiFile.diff",
+			[]int{7, 17},
+			false,
+			"testdata/testPatchAfter8.diff",
+		},
+		{
+			"Staged lines selected in reverse",
+			"testdata/testPatchBefore3.diff",
+			[]int{9, 10},
+			true,
+			"testdata/testPatchAfter6.diff",
+		},
+		{
+			"Some lines of a new file unstaged",
+			"testdata/testPatchBefore5.diff",
+			[]int{7},
+			true,
+			"testdata/testPatchAfter9.diff",
+		},
+		{
+			"Some lines of a deleted file staged",
+			"testdata/testPatchBefore6.diff",
+			[]int{7},
+			false,
+			"testdata/testPatchAfter10.diff",
+		},
+		{
+			"Some lines of a new file staged",
+			"testdata/testPatchBefore5.diff",
+			[]int{6, 8},
+			false,
+			"testdata/testPatchAfter11.diff",
+		},
+		{
+			"Line staged in a renamed file",
+			"testdata/testPatchBefore7.diff",
+			[]int{10},
+			false,
+			"testdata/testPatchAfter12.diff",
+		},
+		{
+			"Line unstaged in a renamed file",
+			"testdata/testPatchBefore7.diff",
+			[]int{11},
+			true,
+			"testdata/testPatchAfter13.diff",
+		},
+		{
+			"Line unstaged in a file with a mode change",
+			"testdata/testPatchBefore8.diff",
+			[]int{8},
+			true,
+			"testdata/testPatchBefore8.diff",
+		},
+		{
+			"Line added after a last line with no newline",
+			"testdata/testPatchBefore9.diff",
+			[]int{8},
+			false,
+			"testdata/testPatchAfter14.diff",
+		},
+		{
+			"Last line with no newline removed",
+			"testdata/testPatchBefore9.diff",
+			[]int{6},
+			false,
+			"testdata/testPatchAfter15.diff",
+		},
+		{
+			"Last line with no newline unstaged",
+			"testdata/testPatchBefore9.diff",
+			[]int{9},
+			true,
+			"testdata/testPatchAfter16.diff",
+		},
+		{
+			"Last line with no newline staged",
+			"testdata/testPatchBefore9.diff",
+			[]int{9},
+			false,
+			"testdata/testPatchAfter17.diff",
+		},
+		{
+			"CRLF line endings and trailing whitespace kept when staging a line",
+			"testdata/testPatchBefore10.diff",
+			[]int{7},
+			false,
+			"testdata/testPatchAfter20.diff",
+		},
+		{
+			"CRLF line endings kept when staging the last line",
+			"testdata/testPatchBefore10.diff",
+			[]int{9},
+			false,
+			"testdata/testPatchAfter21.diff",
+		},
+		{
+			"Staged lines unstaged from a diff with mnemonic prefixes",
+			"testdata/testPatchCached.diff",
+			[]int{6, 11},
+			true,
+			"testdata/testPatchAfter23.diff",
+		},
+		{
+			"Line of a staged new file unstaged from a diff with mnemonic prefixes",
+			"testdata/testPatchCached.diff",
+			[]int{18},
+			true,
+			"testdata/testPatchAfter22.diff",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			patch := NewPatch(readTestFile(s.patchFilename))
+			assert.EqualValues(t, readTestFile(s.expectedPatchFilename), patch.FilterLines(s.lineNumbers, s.reverse).Render())
+		})
+	}
+}
+
+// TestPatchHunkHeaders is a function.
+func TestPatchHunkHeaders(t *testing.T) {
+	type scenario struct {
+		testName       string
+		hunkHeader     string
+		hunkBody       string
+		expectedHeader string
+	}
+
+	// staging the `+y` line turns `-x` into context, adding a line to the new side
+	scenarios := []scenario{
+		{
+			"Lengths of 1 left out",
+			"@@ -1 +1 @@",
+			"-x\n+y\n",
+			"@@ -1 +1,2 @@",
+		},
+		{
+			"Heading kept",
+			"@@ -1,2 +1,2 @@ func foo()",
+			"-x\n+y\n z\n",
+			"@@ -1,2 +1,3 @@ func foo()",
+		},
+		{
+			"Heading containing @@ kept",
+			"@@ -1,2 +1,2 @@ foo @@ bar",
+			"-x\n+y\n z\n",
+			"@@ -1,2 +1,3 @@ foo @@ bar",
+		},
+		{
+			"Heading ending in a carriage return kept",
+			"@@ -1,2 +1,2 @@ func foo()\r",
+			"-x\n+y\n z\n",
+			"@@ -1,2 +1,3 @@ func foo()\r",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			patch := NewPatch("diff --git a/a b/a\n--- a/a\n+++ b/a\n" + s.hunkHeader + "\n" + s.hunkBody)
+			assert.EqualValues(t, s.hunkHeader, patch.FileDiffs[0].Hunks[0].header())
+			assert.EqualValues(t, s.expectedHeader, patch.FilterLines([]int{5}, false).FileDiffs[0].Hunks[0].header())
+		})
+	}
+}
+
+// TestPatchPatchForHunk is a function.
+func TestPatchPatchForHunk(t *testing.T) {
+	patch := NewPatch(readTestFile("testdata/testPatchMultiFile.diff"))
+	expected := `diff --git a/two.txt b/two.txt
+index b77b4eb..adaaafc 100644
+--- a/two.txt
++++ b/two.txt
+@@ -1,2 +1,2 @@
+ x
+-y
++-- y
+`
+	assert.EqualValues(t, expected, patch.PatchForHunk(16).Render())
+}
+
+// TestPatchIsBinary is a function.
+func TestPatchIsBinary(t *testing.T) {
+	type scenario struct {
+		testName      string
+		patchFilename string
+		expected      bool
+	}
+
+	scenarios := []scenario{
+		{
+			"Binary file",
+			"testdata/binaryFile.diff",
+			true,
+		},
+		{
+			"Text file",
+			"testdata/testPatchBefore.diff",
+			false,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			assert.EqualValues(t, s.expected, NewPatch(readTestFile(s.patchFilename)).IsBinary())
+		})
+	}
+}
+
+// TestPatchFirstChangedLine is a function.
+func TestPatchFirstChangedLine(t *testing.T) {
+	type scenario struct {
+		testName string
+		diff     string
+		expected int
+	}
+
+	scenarios := []scenario{
+		{
+			"No changes",
+			"",
+			0,
+		},
+		{
+			"Added line after context",
+			"diff --git a/a b/a\n--- a/a\n+++ b/a\n@@ -10,3 +10,4 @@\n a\n b\n+c\n d\n",
+			12,
+		},
+		{
+			"Removed line",
+			"diff --git a/a b/a\n--- a/a\n+++ b/a\n@@ -10,3 +10,2 @@\n a\n-b\n c\n",
+			11,
+		},
+		{
+			"New file",
+			"diff --git a/a b/a\nnew file mode 100644\n--- /dev/null\n+++ b/a\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+			1,
+		},
+		{
+			"Deleted file",
+			"diff --git a/a b/a\ndeleted file mode 100644\n--- a/a\n+++ /dev/null\n@@ -1,2 +0,0 @@\n-a\n-b\n",
+			0,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			assert.EqualValues(t, s.expected, NewPatch(s.diff).FirstChangedLine())
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package gui
+
+import (
+	"github.com/jesseduffield/gocui"
+)
+
+// handleAbsorbStagedChanges puts each staged hunk into the commit that last
+// changed its lines, by making fixup commits of the hunks and squashing them
+// in. Hunks we can't tell the commit of are left uncommitted
+func (gui *Gui) handleAbsorbStagedChanges(g *gocui.Gui, v *gocui.View) error {
+	if filtered, err := gui.handleRebaseNotAllowed(); filtered {
+		return err
+	}
+	if gui.operationInProgress() {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("CantAbsorbMidOperation"))
+	}
+	if len(gui.stagedFiles()) == 0 {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("NoStagedFilesToCommit"))
+	}
+
+	return gui.createConfirmationPanel(g, v, gui.Tr.SLocalize("AbsorbChanges"), gui.Tr.SLocalize("SureAbsorbChanges"), func(g *gocui.Gui, v *gocui.View) error {
+		return gui.WithWaitingStatus(gui.Tr.SLocalize("AbsorbingStatus"), func() error {
+			targets, err := gui.GitCommand.AbsorbStagedChanges(gui.State.Commits)
+			if err != nil {
+				return gui.handleGenericMergeCommandResult(err)
+			}
+			if len(targets) == 0 {
+				return gui.createErrorPanel(gui.g, gui.Tr.SLocalize("NothingToAbsorb"))
+			}
+			// the commits are in the order of the log, so the last is the oldest
+			err = gui.GitCommand.SquashAllAboveFixupCommits(targets[len(targets)-1].Sha)
+			return gui.handleGenericMergeCommandResult(err)
+		})
+	}, nil)
+}
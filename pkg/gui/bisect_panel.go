@@ -0,0 +1,105 @@
+package gui
+
+import (
+	"github.com/jesseduffield/gocui"
+)
+
+type bisectOption struct {
+	description string
+	onPress     func() error
+}
+
+// GetDisplayStrings is a function.
+func (o *bisectOption) GetDisplayStrings(isFocused bool) []string {
+	return []string{o.description}
+}
+
+// bisectProgress says how far the bisect in progress has got and which commit
+// is checked out for testing, for the commits panel's title
+func (gui *Gui) bisectProgress() string {
+	bisect := gui.State.Bisect
+	switch {
+	case bisect.Remaining < 0:
+		return gui.Tr.SLocalize("BisectWaiting")
+	case bisect.Remaining == 0:
+		return gui.Tr.TemplateLocalize("BisectDone", Teml{"sha": shortSha(bisect.Bad)})
+	default:
+		return gui.Tr.TemplateLocalize("BisectProgress", Teml{"remaining": bisect.Remaining, "sha": shortSha(bisect.Current)})
+	}
+}
+
+func shortSha(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// handleCreateBisectMenu offers to mark the selected commit as bad or good,
+// which starts a bisect if there isn't one yet, and once there is, to skip the
+// commit or to end the bisect
+func (gui *Gui) handleCreateBisectMenu(g *gocui.Gui, v *gocui.View) error {
+	if readOnly, err := gui.handleCommitsReadOnly(); readOnly {
+		return err
+	}
+	commit := gui.getSelectedCommit(g)
+	if commit == nil {
+		return nil
+	}
+
+	mark := func(term string) func() error {
+		return func() error { return gui.markBisect(v, term, commit.Sha) }
+	}
+	options := []*bisectOption{
+		{description: gui.Tr.TemplateLocalize("bisectMarkBad", Teml{"sha": commit.Sha}), onPress: mark("bad")},
+		{description: gui.Tr.TemplateLocalize("bisectMarkGood", Teml{"sha": commit.Sha}), onPress: mark("good")},
+	}
+	if gui.State.Bisect != nil {
+		options = append(options,
+			&bisectOption{description: gui.Tr.TemplateLocalize("bisectSkip", Teml{"sha": commit.Sha}), onPress: mark("skip")},
+			&bisectOption{description: gui.Tr.SLocalize("bisectReset"), onPress: gui.resetBisect},
+		)
+	}
+
+	handleMenuPress := func(index int) error {
+		return options[index].onPress()
+	}
+
+	return gui.createMenu(gui.Tr.SLocalize("BisectTitle"), options, len(options), handleMenuPress)
+}
+
+// markBisect marks the commit, starting a bisect first if need be. When that
+// finds the first bad commit we offer to end the bisect
+func (gui *Gui) markBisect(v *gocui.View, term string, sha string) error {
+	if gui.State.Bisect == nil {
+		if err := gui.GitCommand.StartBisect(); err != nil {
+			return gui.createErrorPanel(gui.g, err.Error())
+		}
+	}
+
+	firstBad, err := gui.GitCommand.MarkBisect(term, sha)
+	if err := gui.refreshSidePanels(gui.g); err != nil {
+		return err
+	}
+	if err != nil {
+		return gui.createErrorPanel(gui.g, err.Error())
+	}
+
+	if firstBad == "" {
+		return nil
+	}
+	prompt := gui.Tr.TemplateLocalize("BisectFoundPrompt", Teml{"sha": shortSha(firstBad)})
+	return gui.createConfirmationPanel(gui.g, v, gui.Tr.SLocalize("BisectFoundTitle"), prompt, func(g *gocui.Gui, v *gocui.View) error {
+		return gui.resetBisect()
+	}, nil)
+}
+
+// resetBisect ends the bisect, which checks out whatever was checked out
+// before it started
+func (gui *Gui) resetBisect() error {
+	if err := gui.GitCommand.ResetBisect(); err != nil {
+		return gui.createErrorPanel(gui.g, err.Error())
+	}
+	gui.State.Panels.Commits.SelectedLine = 0
+	return gui.refreshSidePanels(gui.g)
+}
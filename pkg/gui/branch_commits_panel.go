@@ -0,0 +1,48 @@
+package gui
+
+import (
+	"github.com/jesseduffield/gocui"
+)
+
+// setBrowsingBranch shows the branch's commits in the commits panel without
+// checking it out, or the checked out branch's again if the name is empty
+func (gui *Gui) setBrowsingBranch(name string) error {
+	gui.State.BrowsingBranch = name
+	gui.State.Panels.Commits.SelectedLine = 0
+	gui.State.Panels.Commits.LimitCommits = commitsPageSize
+	if err := gui.refreshCommits(gui.g); err != nil {
+		return err
+	}
+	commitsView := gui.getCommitsView()
+	gui.resetOrigin(commitsView)
+	return gui.switchFocus(gui.g, gui.g.CurrentView(), commitsView)
+}
+
+// handleBrowseBranchCommits shows the selected branch's commits in the commits
+// panel, where you can look through them and copy them to cherry-pick. When
+// comparing branches we list the files it has changed instead
+func (gui *Gui) handleBrowseBranchCommits(g *gocui.Gui, v *gocui.View) error {
+	branch := gui.getSelectedBranch()
+	if branch == nil {
+		return nil
+	}
+	if gui.comparingBranch(branch) {
+		return gui.showCompareFiles(branch)
+	}
+	// the first branch is the checked out one, whose commits we show anyway
+	if gui.State.Panels.Branches.SelectedLine == 0 || branch.Detached {
+		return gui.setBrowsingBranch("")
+	}
+	return gui.setBrowsingBranch(branch.Name)
+}
+
+// handleCommitsReadOnly refuses to change the checked out branch from the
+// commits panel while it shows another branch's commits, as it would be
+// unclear which branch we'd change. It returns true if it did
+func (gui *Gui) handleCommitsReadOnly() (bool, error) {
+	if gui.State.BrowsingBranch != "" {
+		message := gui.Tr.TemplateLocalize("CantChangeWhileBrowsingBranch", Teml{"branch": gui.State.BrowsingBranch})
+		return true, gui.createErrorPanel(gui.g, message)
+	}
+	return false, nil
+}
@@ -0,0 +1,56 @@
+package gui
+
+import (
+	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazygit/pkg/commands"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+)
+
+// filterBranches leaves out the branches whose names don't fuzzy match the
+// branches filter. The checked out branch is kept regardless, at the top
+// where the branch actions expect it, so that you can still merge into it or
+// rebase it onto the branches you've filtered for
+func (gui *Gui) filterBranches(branches []*commands.Branch) []*commands.Branch {
+	filter := gui.State.Panels.Branches.Filter
+	if filter == "" || len(branches) == 0 {
+		return branches
+	}
+	filtered := []*commands.Branch{branches[0]}
+	for _, branch := range branches[1:] {
+		if utils.FuzzyMatch(filter, branch.Name) {
+			filtered = append(filtered, branch)
+		}
+	}
+	return filtered
+}
+
+// setBranchesFilter shows only the branches matching the filter in the
+// branches panel, or all of them again if the filter is empty
+func (gui *Gui) setBranchesFilter(filter string) error {
+	gui.State.Panels.Branches.Filter = filter
+	gui.State.Panels.Branches.SelectedLine = 0
+	branchesView := gui.getBranchesView()
+	gui.setBranchesTabs()
+	gui.resetOrigin(branchesView)
+	if err := gui.refreshBranches(gui.g); err != nil {
+		return err
+	}
+	gui.g.Update(func(g *gocui.Gui) error {
+		return gui.handleBranchSelect(g, branchesView)
+	})
+	return nil
+}
+
+// handleFilterBranches asks for the text to fuzzy filter the branches by,
+// starting from the current filter so that it's easy to refine
+func (gui *Gui) handleFilterBranches(g *gocui.Gui, v *gocui.View) error {
+	if err := gui.createPromptPanel(g, v, gui.Tr.SLocalize("FilterBranchesTitle"), func(g *gocui.Gui, promptView *gocui.View) error {
+		return gui.setBranchesFilter(gui.trimmedContent(promptView))
+	}); err != nil {
+		return err
+	}
+
+	promptView, _ := gui.g.View("confirmation")
+	gui.setPromptContent(promptView, gui.State.Panels.Branches.Filter)
+	return nil
+}
@@ -2,11 +2,14 @@ package gui
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
+	"github.com/fatih/color"
 	"github.com/jesseduffield/gocui"
 	"github.com/jesseduffield/lazygit/pkg/commands"
 	"github.com/jesseduffield/lazygit/pkg/git"
+	"github.com/jesseduffield/lazygit/pkg/utils"
 )
 
 // list panel functions
@@ -37,9 +40,12 @@ func (gui *Gui) handleBranchSelect(g *gocui.Gui, v *gocui.View) error {
 	if err := gui.focusPoint(0, gui.State.Panels.Branches.SelectedLine, len(gui.State.Branches), v); err != nil {
 		return err
 	}
-	go func() {
-		_ = gui.RenderSelectedBranchUpstreamDifferences()
-	}()
+	if err := gui.RenderSelectedBranchUpstreamDifferences(); err != nil {
+		return err
+	}
+	if gui.comparingBranch(branch) {
+		return gui.renderComparison(branch)
+	}
 	go func() {
 		graph, err := gui.GitCommand.GetBranchGraph(branch.Name)
 		if err != nil && strings.HasPrefix(graph, "fatal: ambiguous argument") {
@@ -58,8 +64,6 @@ func (gui *Gui) RenderSelectedBranchUpstreamDifferences() error {
 		branch.Selected = i == gui.State.Panels.Branches.SelectedLine
 	}
 
-	branch := gui.getSelectedBranch()
-	branch.Pushables, branch.Pullables = gui.GitCommand.GetBranchUpstreamDifferenceCount(branch.Name)
 	return gui.renderListPanel(gui.getBranchesView(), gui.State.Branches)
 }
 
@@ -67,22 +71,89 @@ func (gui *Gui) RenderSelectedBranchUpstreamDifferences() error {
 // be sure there is a state.Branches array to pick the current branch from
 func (gui *Gui) refreshBranches(g *gocui.Gui) error {
 	g.Update(func(g *gocui.Gui) error {
-		builder, err := git.NewBranchListBuilder(gui.Log, gui.GitCommand)
+		builder, err := git.NewBranchListBuilder(gui.Log, gui.GitCommand, gui.State.Panels.Branches.SortOrder)
 		if err != nil {
 			return err
 		}
-		gui.State.Branches = builder.Build()
+		branches := gui.filterBranches(builder.Build())
+		// until we've got the new counts, the old ones are closer than nothing
+		gui.carryOverAheadBehindCounts(gui.State.Branches, branches)
+		gui.State.Branches = branches
 
 		gui.refreshSelectedLine(&gui.State.Panels.Branches.SelectedLine, len(gui.State.Branches))
 		if err := gui.RenderSelectedBranchUpstreamDifferences(); err != nil {
 			return err
 		}
 
+		go gui.loadAheadBehindCounts(branches)
+
 		return gui.refreshStatus(g)
 	})
 	return nil
 }
 
+// loadAheadBehindCounts gets how far each branch is ahead of and behind its
+// upstream and shows it, unless the branches have been refreshed since, in
+// which case it's up to the newer refresh
+func (gui *Gui) loadAheadBehindCounts(branches []*commands.Branch) {
+	counts, err := gui.GitCommand.GetBranchesAheadBehindCounts()
+	if err != nil {
+		gui.Log.Error(err)
+		return
+	}
+	gui.g.Update(func(g *gocui.Gui) error {
+		if len(branches) == 0 || len(gui.State.Branches) != len(branches) || gui.State.Branches[0] != branches[0] {
+			return nil
+		}
+		for _, branch := range branches {
+			if branchCounts, ok := counts[branch.Name]; ok && !branch.Detached {
+				branch.Pushables, branch.Pullables = branchCounts.Pushables, branchCounts.Pullables
+			}
+		}
+		return gui.renderListPanel(gui.getBranchesView(), gui.State.Branches)
+	})
+}
+
+// carryOverAheadBehindCounts gives the new branches the counts the old
+// branches of the same name had
+func (gui *Gui) carryOverAheadBehindCounts(oldBranches []*commands.Branch, newBranches []*commands.Branch) {
+	oldBranchesByName := map[string]*commands.Branch{}
+	for _, branch := range oldBranches {
+		oldBranchesByName[branch.Name] = branch
+	}
+	for _, branch := range newBranches {
+		if oldBranch, ok := oldBranchesByName[branch.Name]; ok && oldBranch.Detached == branch.Detached {
+			branch.Pushables, branch.Pullables = oldBranch.Pushables, oldBranch.Pullables
+		}
+	}
+}
+
+// branchesTitle says if the branches panel is comparing branches or only
+// showing some of them
+func (gui *Gui) branchesTitle() string {
+	title := gui.Tr.SLocalize("BranchesTitle")
+	if gui.State.CompareBase != "" {
+		title = gui.Tr.TemplateLocalize("BranchesComparingTitle", Teml{"base": gui.State.CompareBase})
+	}
+	if gui.State.Panels.Branches.Filter != "" {
+		title += " " + gui.Tr.TemplateLocalize("BranchesFilteredSuffix", Teml{"filter": gui.State.Panels.Branches.Filter})
+	}
+	return title
+}
+
+// handleBranchesEscape stops filtering the branches if they're filtered, then
+// stops comparing branches if we are, and otherwise quits like escape does
+// everywhere else
+func (gui *Gui) handleBranchesEscape(g *gocui.Gui, v *gocui.View) error {
+	if gui.State.Panels.Branches.Filter != "" {
+		return gui.setBranchesFilter("")
+	}
+	if gui.State.CompareBase != "" {
+		return gui.setCompareBase("")
+	}
+	return gui.quit(g, v)
+}
+
 func (gui *Gui) handleBranchesNextLine(g *gocui.Gui, v *gocui.View) error {
 	if gui.popupPanelFocused() {
 		return nil
@@ -117,17 +188,75 @@ func (gui *Gui) handleBranchPress(g *gocui.Gui, v *gocui.View) error {
 	if gui.State.Panels.Branches.SelectedLine == -1 {
 		return nil
 	}
+	branch := gui.getSelectedBranch()
 	if gui.State.Panels.Branches.SelectedLine == 0 {
+		if branch.Detached {
+			return gui.handleCreateDetachedHeadMenu(g, v)
+		}
 		return gui.createErrorPanel(g, gui.Tr.SLocalize("AlreadyCheckedOutBranch"))
 	}
-	branch := gui.getSelectedBranch()
 	return gui.handleCheckoutBranch(branch.Name)
 }
 
+type detachedHeadOption struct {
+	description string
+	onPress     func() error
+}
+
+// GetDisplayStrings is a function.
+func (o *detachedHeadOption) GetDisplayStrings(isFocused bool) []string {
+	return []string{o.description}
+}
+
+// handleCreateDetachedHeadMenu offers what you'd do next while HEAD is
+// detached: keep what you've got by making a branch there, or go back to the
+// branch you were on before
+func (gui *Gui) handleCreateDetachedHeadMenu(g *gocui.Gui, v *gocui.View) error {
+	head := gui.State.Branches[0]
+	options := []*detachedHeadOption{
+		{
+			description: gui.Tr.SLocalize("createBranchHere"),
+			onPress: func() error {
+				// the menu has to close before we can open the prompt
+				gui.g.Update(func(g *gocui.Gui) error {
+					return gui.handleNewBranch(g, v)
+				})
+				return nil
+			},
+		},
+	}
+
+	previousBranch, err := gui.GitCommand.GetPreviousBranch()
+	if err != nil {
+		return gui.createErrorPanel(g, err.Error())
+	}
+	if previousBranch != "" {
+		options = append(options, &detachedHeadOption{
+			description: gui.Tr.TemplateLocalize("returnToBranch", Teml{"branchName": previousBranch}),
+			onPress: func() error {
+				return gui.handleCheckoutBranch(previousBranch)
+			},
+		})
+	}
+
+	handleMenuPress := func(index int) error {
+		return options[index].onPress()
+	}
+
+	title := gui.Tr.TemplateLocalize("DetachedHeadMenuTitle", Teml{"sha": head.Name})
+	return gui.createMenu(title, options, len(options), handleMenuPress)
+}
+
 func (gui *Gui) handleCreatePullRequestPress(g *gocui.Gui, v *gocui.View) error {
 	pullRequest := commands.NewPullRequest(gui.GitCommand)
 
 	branch := gui.getSelectedBranch()
+	if branch == nil {
+		return nil
+	}
+	if branch.Detached {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("CantDoOnDetachedHead"))
+	}
 	if err := pullRequest.Create(branch); err != nil {
 		return gui.createErrorPanel(g, err.Error())
 	}
@@ -159,46 +288,176 @@ func (gui *Gui) handleForceCheckout(g *gocui.Gui, v *gocui.View) error {
 }
 
 func (gui *Gui) handleCheckoutBranch(branchName string) error {
-	if err := gui.GitCommand.Checkout(branchName, false); err != nil {
+	return gui.confirmLeavingDetachedHead(gui.getBranchesView(), func() error {
+		return gui.checkoutRef(gui.getBranchesView(), branchName, nil)
+	})
+}
+
+// checkoutRef checks out the branch or commit, offering to stash your changes
+// if they'd be overwritten. onSuccess, if given, is called once it's checked
+// out
+func (gui *Gui) checkoutRef(v *gocui.View, ref string, onSuccess func() error) error {
+	done := func(g *gocui.Gui) error {
+		// checkout successful so we select the new branch
+		gui.State.Panels.Branches.SelectedLine = 0
+		if err := gui.refreshSidePanels(g); err != nil {
+			return err
+		}
+		if onSuccess != nil {
+			return onSuccess()
+		}
+		return nil
+	}
+
+	if err := gui.GitCommand.Checkout(ref, false); err != nil {
 		// note, this will only work for english-language git commands. If we force git to use english, and the error isn't this one, then the user will receive an english command they may not understand. I'm not sure what the best solution to this is. Running the command once in english and a second time in the native language is one option
 
 		if strings.Contains(err.Error(), "Please commit your changes or stash them before you switch branch") {
 			// offer to autostash changes
-			return gui.createConfirmationPanel(gui.g, gui.getBranchesView(), gui.Tr.SLocalize("AutoStashTitle"), gui.Tr.SLocalize("AutoStashPrompt"), func(g *gocui.Gui, v *gocui.View) error {
-				if err := gui.GitCommand.StashSave(gui.Tr.SLocalize("StashPrefix") + branchName); err != nil {
+			return gui.createConfirmationPanel(gui.g, v, gui.Tr.SLocalize("AutoStashTitle"), gui.Tr.SLocalize("AutoStashPrompt"), func(g *gocui.Gui, v *gocui.View) error {
+				if err := gui.GitCommand.StashSave(gui.Tr.SLocalize("StashPrefix") + ref); err != nil {
 					return gui.createErrorPanel(g, err.Error())
 				}
-				if err := gui.GitCommand.Checkout(branchName, false); err != nil {
+				if err := gui.GitCommand.Checkout(ref, false); err != nil {
 					return gui.createErrorPanel(g, err.Error())
 				}
 
-				// checkout successful so we select the new branch
-				gui.State.Panels.Branches.SelectedLine = 0
-
 				if err := gui.GitCommand.StashDo(0, "pop"); err != nil {
+					gui.State.Panels.Branches.SelectedLine = 0
 					if err := gui.refreshSidePanels(g); err != nil {
 						return err
 					}
 					return gui.createErrorPanel(g, err.Error())
 				}
-				return gui.refreshSidePanels(g)
+				return done(g)
 			}, nil)
 		}
 
 		if err := gui.createErrorPanel(gui.g, err.Error()); err != nil {
 			return err
 		}
+		gui.State.Panels.Branches.SelectedLine = 0
+		return gui.refreshSidePanels(gui.g)
 	}
 
-	gui.State.Panels.Branches.SelectedLine = 0
-	return gui.refreshSidePanels(gui.g)
+	return done(gui.g)
+}
+
+// confirmLeavingDetachedHead asks before checking out something else while
+// HEAD is detached at a commit that no branch contains, as the commits made
+// there would then only be found in the reflog
+func (gui *Gui) confirmLeavingDetachedHead(v *gocui.View, onConfirm func() error) error {
+	if !gui.GitCommand.IsHeadDetached() || gui.GitCommand.IsHeadOnABranch() {
+		return onConfirm()
+	}
+	return gui.createConfirmationPanel(gui.g, v, gui.Tr.SLocalize("LeaveDetachedHeadTitle"), gui.Tr.SLocalize("LeaveDetachedHeadPrompt"), func(g *gocui.Gui, v *gocui.View) error {
+		return onConfirm()
+	}, nil)
 }
 
+// getCompletableRefNames gets the names tab completes a ref to in a prompt:
+// the local and remote branches, the tags and the commits we've loaded
+func (gui *Gui) getCompletableRefNames() ([]string, error) {
+	names := []string{}
+	for _, getNames := range []func() ([]string, error){
+		gui.GitCommand.GetLocalBranchNames,
+		gui.GitCommand.GetRemoteBranchNames,
+		gui.GitCommand.GetTagNames,
+	} {
+		refNames, err := getNames()
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, refNames...)
+	}
+	for _, commit := range gui.State.Commits {
+		names = append(names, commit.Sha)
+	}
+	return names, nil
+}
+
+// promptForRef asks for a branch, tag or commit, which tab completes
+func (gui *Gui) promptForRef(v *gocui.View, title string, handleRef func(ref string) error) error {
+	names, err := gui.getCompletableRefNames()
+	if err != nil {
+		return gui.createErrorPanel(gui.g, err.Error())
+	}
+
+	if err := gui.createPromptPanel(gui.g, v, title, func(g *gocui.Gui, promptView *gocui.View) error {
+		ref := gui.trimmedContent(promptView)
+		if ref == "" {
+			return nil
+		}
+		return handleRef(ref)
+	}); err != nil {
+		return err
+	}
+
+	return gui.g.SetKeybinding("confirmation", gocui.KeyTab, gocui.ModNone, func(g *gocui.Gui, v *gocui.View) error {
+		gui.setPromptContent(v, utils.CompleteName(gui.trimmedContent(v), names))
+		return nil
+	})
+}
+
+// handleCheckoutByName checks out the branch, tag or commit you type in
 func (gui *Gui) handleCheckoutByName(g *gocui.Gui, v *gocui.View) error {
-	gui.createPromptPanel(g, v, gui.Tr.SLocalize("BranchName")+":", func(g *gocui.Gui, v *gocui.View) error {
-		return gui.handleCheckoutBranch(gui.trimmedContent(v))
+	return gui.promptForRef(v, gui.Tr.SLocalize("BranchName")+":", func(ref string) error {
+		remoteBranchNames, err := gui.GitCommand.GetRemoteBranchNames()
+		if err != nil {
+			return gui.createErrorPanel(gui.g, err.Error())
+		}
+		if utils.IncludesString(remoteBranchNames, ref) {
+			return gui.checkoutRemoteBranch(ref)
+		}
+		return gui.handleCheckoutBranch(ref)
+	})
+}
+
+// checkoutRemoteBranch checks out a local branch of the remote branch's name,
+// e.g. feature for origin/feature, making one that tracks the remote branch
+// if there isn't one yet. Checking out the remote branch itself would only
+// detach HEAD at it
+func (gui *Gui) checkoutRemoteBranch(remoteBranchName string) error {
+	localBranchNames, err := gui.GitCommand.GetLocalBranchNames()
+	if err != nil {
+		return gui.createErrorPanel(gui.g, err.Error())
+	}
+	localName := remoteBranchName[strings.Index(remoteBranchName, "/")+1:]
+	if utils.IncludesString(localBranchNames, localName) {
+		return gui.handleCheckoutBranch(localName)
+	}
+	return gui.newBranchAt(gui.getBranchesView(), localName, remoteBranchName)
+}
+
+// handleNewBranchFromRef makes a new branch off the branch, tag or commit you
+// type in
+func (gui *Gui) handleNewBranchFromRef(g *gocui.Gui, v *gocui.View) error {
+	return gui.promptForRef(v, gui.Tr.SLocalize("NewBranchBaseTitle"), func(ref string) error {
+		// the prompt has to close before we can open the next one
+		gui.g.Update(func(g *gocui.Gui) error {
+			return gui.promptNewBranchAt(v, ref)
+		})
+		return nil
+	})
+}
+
+// promptNewBranchAt asks for the name of a new branch to make at the ref
+func (gui *Gui) promptNewBranchAt(v *gocui.View, ref string) error {
+	message := gui.Tr.TemplateLocalize("NewBranchNameBranchOff", Teml{"branchName": ref})
+	return gui.createPromptPanel(gui.g, v, message, func(g *gocui.Gui, promptView *gocui.View) error {
+		return gui.newBranchAt(v, gui.trimmedContent(promptView), ref)
+	})
+}
+
+// newBranchAt makes a branch at the ref and checks it out
+func (gui *Gui) newBranchAt(v *gocui.View, name string, ref string) error {
+	return gui.confirmLeavingDetachedHead(v, func() error {
+		if err := gui.GitCommand.NewBranchAt(name, ref); err != nil {
+			return gui.createErrorPanel(gui.g, err.Error())
+		}
+		gui.State.Panels.Branches.SelectedLine = 0
+		return gui.refreshSidePanels(gui.g)
 	})
-	return nil
 }
 
 func (gui *Gui) handleNewBranch(g *gocui.Gui, v *gocui.View) error {
@@ -227,23 +486,91 @@ func (gui *Gui) handleForceDeleteBranch(g *gocui.Gui, v *gocui.View) error {
 	return gui.deleteBranch(g, v, true)
 }
 
+type deleteBranchOption struct {
+	description string
+	onPress     func() error
+}
+
+// GetDisplayStrings is a function.
+func (o *deleteBranchOption) GetDisplayStrings(isFocused bool) []string {
+	return []string{o.description}
+}
+
 func (gui *Gui) deleteBranch(g *gocui.Gui, v *gocui.View, force bool) error {
 	selectedBranch := gui.getSelectedBranch()
 	if selectedBranch == nil {
 		return nil
 	}
-	checkedOutBranch := gui.State.Branches[0]
-	if checkedOutBranch.Name == selectedBranch.Name {
-		return gui.createErrorPanel(g, gui.Tr.SLocalize("CantDeleteCheckOutBranch"))
+	isCheckedOut := gui.State.Branches[0].Name == selectedBranch.Name
+	remoteName, remoteBranchName := "", ""
+	if !force && !selectedBranch.Detached {
+		remoteName, remoteBranchName = gui.GitCommand.GetBranchUpstream(selectedBranch.Name)
+	}
+	if remoteName == "" {
+		if isCheckedOut {
+			return gui.createErrorPanel(g, gui.Tr.SLocalize("CantDeleteCheckOutBranch"))
+		}
+		return gui.deleteNamedBranch(g, v, selectedBranch, force, nil)
+	}
+
+	// a branch that tracks a remote one can be deleted here, there, or both
+	remoteBranch := remoteName + "/" + remoteBranchName
+	deleteRemote := func() error {
+		return gui.deleteRemoteBranch(v, remoteName, remoteBranchName)
+	}
+	confirmDeleteRemote := func() error {
+		message := gui.Tr.TemplateLocalize("DeleteRemoteBranchMessage", Teml{"remoteBranch": remoteBranch})
+		return gui.createConfirmationPanel(g, v, gui.Tr.SLocalize("DeleteBranch"), message, func(*gocui.Gui, *gocui.View) error {
+			return deleteRemote()
+		}, nil)
+	}
+	if isCheckedOut {
+		return confirmDeleteRemote()
+	}
+
+	options := []*deleteBranchOption{
+		{
+			description: gui.Tr.TemplateLocalize("deleteLocalBranch", Teml{"branch": selectedBranch.Name}),
+			onPress: func() error {
+				return gui.deleteNamedBranch(g, v, selectedBranch, false, nil)
+			},
+		},
+		{
+			description: gui.Tr.TemplateLocalize("deleteRemoteBranch", Teml{"remoteBranch": remoteBranch}),
+			onPress:     confirmDeleteRemote,
+		},
+		{
+			description: gui.Tr.SLocalize("deleteLocalAndRemoteBranch"),
+			onPress: func() error {
+				message := gui.Tr.TemplateLocalize("DeleteLocalAndRemoteBranchMessage", Teml{"branch": selectedBranch.Name, "remoteBranch": remoteBranch})
+				return gui.createConfirmationPanel(g, v, gui.Tr.SLocalize("DeleteBranch"), message, func(g *gocui.Gui, _ *gocui.View) error {
+					return gui.deleteLocalBranch(g, v, selectedBranch, false, deleteRemote)
+				}, nil)
+			},
+		},
+	}
+
+	handleMenuPress := func(index int) error {
+		return options[index].onPress()
 	}
-	return gui.deleteNamedBranch(g, v, selectedBranch, force)
+
+	return gui.createMenu(gui.Tr.SLocalize("DeleteBranch"), options, len(options), handleMenuPress)
 }
 
-func (gui *Gui) deleteNamedBranch(g *gocui.Gui, v *gocui.View, selectedBranch *commands.Branch, force bool) error {
+// deleteNamedBranch asks whether to delete the branch, and if so deletes it
+// and then does whatever's next, if anything
+func (gui *Gui) deleteNamedBranch(g *gocui.Gui, v *gocui.View, selectedBranch *commands.Branch, force bool, then func() error) error {
 	title := gui.Tr.SLocalize("DeleteBranch")
 	var messageID string
+	count := 0
 	if force {
 		messageID = "ForceDeleteBranchMessage"
+		// say how much would be lost, unless the branch's commits are all on
+		// other refs, e.g. its upstream
+		if unreachable, err := gui.GitCommand.CountCommitsOnlyOnBranch(selectedBranch.Name); err == nil && unreachable > 0 {
+			messageID = "ForceDeleteBranchUnreachableMessage"
+			count = unreachable
+		}
 	} else {
 		messageID = "DeleteBranchMessage"
 	}
@@ -251,44 +578,305 @@ func (gui *Gui) deleteNamedBranch(g *gocui.Gui, v *gocui.View, selectedBranch *c
 		messageID,
 		Teml{
 			"selectedBranchName": selectedBranch.Name,
+			"count":              count,
 		},
 	)
-	return gui.createConfirmationPanel(g, v, title, message, func(g *gocui.Gui, v *gocui.View) error {
-		if err := gui.GitCommand.DeleteBranch(selectedBranch.Name, force); err != nil {
-			errMessage := err.Error()
-			if !force && strings.Contains(errMessage, "is not fully merged") {
-				return gui.deleteNamedBranch(g, v, selectedBranch, true)
-			}
-			return gui.createErrorPanel(g, errMessage)
+	return gui.createConfirmationPanel(g, v, title, message, func(g *gocui.Gui, _ *gocui.View) error {
+		return gui.deleteLocalBranch(g, v, selectedBranch, force, then)
+	}, nil)
+}
+
+// deleteLocalBranch deletes the branch, asking whether to force it if it
+// isn't merged, and then does whatever's next, if anything
+func (gui *Gui) deleteLocalBranch(g *gocui.Gui, v *gocui.View, selectedBranch *commands.Branch, force bool, then func() error) error {
+	if err := gui.GitCommand.DeleteBranch(selectedBranch.Name, force); err != nil {
+		errMessage := err.Error()
+		if !force && strings.Contains(errMessage, "is not fully merged") {
+			return gui.deleteNamedBranch(g, v, selectedBranch, true, then)
+		}
+		return gui.createErrorPanel(g, errMessage)
+	}
+	if then != nil {
+		return then()
+	}
+	return gui.refreshSidePanels(g)
+}
+
+// deleteRemoteBranch deletes the branch from the remote in the background, as
+// that can take a while and may ask for credentials. It's called from
+// confirmation panels, so its loader waits for them to close
+func (gui *Gui) deleteRemoteBranch(v *gocui.View, remoteName string, branchName string) error {
+	gui.g.Update(func(g *gocui.Gui) error {
+		if err := gui.createLoaderPanel(g, v, gui.Tr.SLocalize("DeletingRemoteBranchStatus")); err != nil {
+			return err
+		}
+		go func() {
+			unamePassOpened := false
+			err := gui.GitCommand.DeleteRemoteBranch(remoteName, branchName, func(passOrUname string) string {
+				unamePassOpened = true
+				return gui.waitForPassUname(g, v, passOrUname)
+			})
+			gui.HandleCredentialsPopup(g, unamePassOpened, err)
+		}()
+		return nil
+	})
+	return nil
+}
+
+// handleRenameBranch asks for the branch's new name, starting from its current
+// one. If the branch tracks a remote branch we then offer to rename that too
+func (gui *Gui) handleRenameBranch(g *gocui.Gui, v *gocui.View) error {
+	branch := gui.getSelectedBranch()
+	if branch == nil || branch.Detached {
+		return nil
+	}
+
+	title := gui.Tr.TemplateLocalize("RenameBranchTitle", Teml{"branch": branch.Name})
+	if err := gui.createPromptPanel(g, v, title, func(g *gocui.Gui, promptView *gocui.View) error {
+		newName := gui.trimmedContent(promptView)
+		if newName == "" || newName == branch.Name {
+			return nil
+		}
+		remoteName, remoteBranchName := gui.GitCommand.GetBranchUpstream(branch.Name)
+		if err := gui.GitCommand.RenameBranch(branch.Name, newName); err != nil {
+			return gui.createErrorPanel(g, err.Error())
+		}
+		if remoteName == "" {
+			return gui.refreshSidePanels(g)
+		}
+
+		prompt := gui.Tr.TemplateLocalize("RenameRemoteBranchPrompt", Teml{
+			"branch":       newName,
+			"remote":       remoteName,
+			"remoteBranch": remoteName + "/" + remoteBranchName,
+		})
+		return gui.createConfirmationPanel(g, v, gui.Tr.SLocalize("RenameRemoteBranchTitle"), prompt, func(*gocui.Gui, *gocui.View) error {
+			return gui.renameRemoteBranch(v, remoteName, remoteBranchName, newName)
+		}, func(g *gocui.Gui, _ *gocui.View) error {
+			return gui.refreshSidePanels(g)
+		})
+	}); err != nil {
+		return err
+	}
+
+	promptView, _ := gui.g.View("confirmation")
+	gui.setPromptContent(promptView, branch.Name)
+	return nil
+}
+
+// renameRemoteBranch renames the remote branch in the background, like
+// deleteRemoteBranch
+func (gui *Gui) renameRemoteBranch(v *gocui.View, remoteName string, remoteBranchName string, branchName string) error {
+	gui.g.Update(func(g *gocui.Gui) error {
+		if err := gui.createLoaderPanel(g, v, gui.Tr.SLocalize("RenamingRemoteBranchStatus")); err != nil {
+			return err
+		}
+		go func() {
+			unamePassOpened := false
+			err := gui.GitCommand.RenameRemoteBranch(remoteName, remoteBranchName, branchName, func(passOrUname string) string {
+				unamePassOpened = true
+				return gui.waitForPassUname(g, v, passOrUname)
+			})
+			gui.HandleCredentialsPopup(g, unamePassOpened, err)
+		}()
+		return nil
+	})
+	return nil
+}
+
+type upstreamOption struct {
+	description string
+	onPress     func() error
+}
+
+// GetDisplayStrings is a function.
+func (o *upstreamOption) GetDisplayStrings(isFocused bool) []string {
+	return []string{o.description}
+}
+
+// handleCreateUpstreamMenu lets you choose which remote branch the selected
+// branch tracks, which is what its ahead/behind counts are against, or stop it
+// tracking one
+func (gui *Gui) handleCreateUpstreamMenu(g *gocui.Gui, v *gocui.View) error {
+	branch := gui.getSelectedBranch()
+	if branch == nil || branch.Detached {
+		return nil
+	}
+	remoteBranchNames, err := gui.GitCommand.GetRemoteBranchNames()
+	if err != nil {
+		return gui.createErrorPanel(g, err.Error())
+	}
+	upstream := ""
+	if remoteName, remoteBranchName := gui.GitCommand.GetBranchUpstream(branch.Name); remoteName != "" {
+		upstream = remoteName + "/" + remoteBranchName
+	}
+	if len(remoteBranchNames) == 0 && upstream == "" {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("NoRemoteBranches"))
+	}
+
+	setUpstream := func(change func() error) error {
+		if err := change(); err != nil {
+			return gui.createErrorPanel(g, err.Error())
 		}
 		return gui.refreshSidePanels(g)
-	}, nil)
+	}
+
+	options := []*upstreamOption{}
+	if upstream != "" {
+		options = append(options, &upstreamOption{
+			description: gui.Tr.TemplateLocalize("unsetUpstream", Teml{"upstream": upstream}),
+			onPress: func() error {
+				return setUpstream(func() error { return gui.GitCommand.UnsetBranchUpstream(branch.Name) })
+			},
+		})
+	}
+	for _, name := range remoteBranchNames {
+		name := name
+		description := name
+		if name == upstream {
+			description += " " + utils.ColoredString("✓", color.FgGreen)
+		}
+		options = append(options, &upstreamOption{
+			description: description,
+			onPress: func() error {
+				return setUpstream(func() error { return gui.GitCommand.SetBranchUpstream(branch.Name, name) })
+			},
+		})
+	}
+
+	handleMenuPress := func(index int) error {
+		return options[index].onPress()
+	}
+
+	title := gui.Tr.TemplateLocalize("SetUpstreamTitle", Teml{"branch": branch.Name})
+	return gui.createMenu(title, options, len(options), handleMenuPress)
+}
+
+type branchSortOption struct {
+	order       string
+	description string
 }
 
+// GetDisplayStrings is a function.
+func (o *branchSortOption) GetDisplayStrings(isFocused bool) []string {
+	return []string{o.description}
+}
+
+// handleCreateBranchSortMenu lets you choose the order of the branches panel.
+// The choice is saved to the user config so it sticks
+func (gui *Gui) handleCreateBranchSortMenu(g *gocui.Gui, v *gocui.View) error {
+	descriptions := map[string]string{
+		git.SortBranchesByRecency: gui.Tr.SLocalize("sortBranchesByRecency"),
+		git.SortBranchesByDate:    gui.Tr.SLocalize("sortBranchesByDate"),
+		git.SortBranchesByName:    gui.Tr.SLocalize("sortBranchesByName"),
+	}
+	options := make([]*branchSortOption, len(git.BranchSortOrders))
+	for i, order := range git.BranchSortOrders {
+		description := descriptions[order]
+		if order == gui.State.Panels.Branches.SortOrder {
+			description += " " + utils.ColoredString("✓", color.FgGreen)
+		}
+		options[i] = &branchSortOption{order: order, description: description}
+	}
+
+	handleMenuPress := func(index int) error {
+		order := options[index].order
+		gui.State.Panels.Branches.SortOrder = order
+		if err := gui.Config.WriteToUserConfig("gui.branchSortOrder", order); err != nil {
+			return gui.createErrorPanel(g, err.Error())
+		}
+		return gui.refreshBranches(g)
+	}
+
+	return gui.createMenu(gui.Tr.SLocalize("SortBranchesTitle"), options, len(options), handleMenuPress)
+}
+
+type mergeOption struct {
+	flag     string
+	lastUsed bool
+}
+
+// GetDisplayStrings is a function.
+func (o *mergeOption) GetDisplayStrings(isFocused bool) []string {
+	flag := o.flag
+	if flag == "" {
+		flag = "merge"
+	}
+	if o.lastUsed {
+		flag += " " + utils.ColoredString("✓", color.FgGreen)
+	}
+	return []string{flag}
+}
+
+// handleMerge offers the ways of merging the selected branch into the checked
+// out one. Whichever you pick is remembered for the repo and listed first next
+// time, so merging the same way again is just a matter of pressing enter
 func (gui *Gui) handleMerge(g *gocui.Gui, v *gocui.View) error {
+	branch := gui.getSelectedBranch()
+	if branch == nil {
+		return nil
+	}
 	checkedOutBranch := gui.State.Branches[0].Name
-	selectedBranch := gui.getSelectedBranch().Name
+	selectedBranch := branch.Name
 	if checkedOutBranch == selectedBranch {
 		return gui.createErrorPanel(g, gui.Tr.SLocalize("CantMergeBranchIntoItself"))
 	}
-	prompt := gui.Tr.TemplateLocalize(
-		"ConfirmMerge",
-		Teml{
-			"checkedOutBranch": checkedOutBranch,
-			"selectedBranch":   selectedBranch,
-		},
-	)
-	return gui.createConfirmationPanel(g, v, gui.Tr.SLocalize("MergingTitle"), prompt,
-		func(g *gocui.Gui, v *gocui.View) error {
 
-			err := gui.GitCommand.Merge(selectedBranch)
-			return gui.handleGenericMergeCommandResult(err)
-		}, nil)
+	currentRepo, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	appState := gui.Config.GetAppState()
+	lastFlag, remembered := appState.MergeFlags[currentRepo]
+
+	options := []*mergeOption{}
+	for _, flag := range append([]string{""}, commands.MergeFlags...) {
+		option := &mergeOption{flag: flag, lastUsed: remembered && flag == lastFlag}
+		if option.lastUsed {
+			options = append([]*mergeOption{option}, options...)
+		} else {
+			options = append(options, option)
+		}
+	}
+
+	handleMenuPress := func(index int) error {
+		flag := options[index].flag
+		if appState.MergeFlags == nil {
+			appState.MergeFlags = map[string]string{}
+		}
+		appState.MergeFlags[currentRepo] = flag
+		if err := gui.Config.SaveAppState(); err != nil {
+			return err
+		}
+
+		err := gui.GitCommand.Merge(selectedBranch, flag)
+		if err == nil && flag == "--squash" {
+			// a squash merge only stages the changes, leaving the commit to you
+			if err := gui.refreshSidePanels(g); err != nil {
+				return err
+			}
+			return gui.createMessagePanel(g, v, gui.Tr.SLocalize("MergingTitle"), gui.Tr.SLocalize("SquashMergeStaged"))
+		}
+		return gui.handleGenericMergeCommandResult(err)
+	}
+
+	title := gui.Tr.TemplateLocalize("MergeOptionsMenuTitle", Teml{
+		"checkedOutBranch": checkedOutBranch,
+		"selectedBranch":   selectedBranch,
+	})
+	return gui.createMenu(title, options, len(options), handleMenuPress)
 }
 
+// handleRebase rebases the checked out branch onto the selected one. If it
+// stops at a conflict we go through the usual conflict handling, and the
+// rebase options menu can continue, skip or abort it from there
 func (gui *Gui) handleRebase(g *gocui.Gui, v *gocui.View) error {
+	branch := gui.getSelectedBranch()
+	if branch == nil {
+		return nil
+	}
 	checkedOutBranch := gui.State.Branches[0].Name
-	selectedBranch := gui.getSelectedBranch().Name
+	selectedBranch := branch.Name
 	if selectedBranch == checkedOutBranch {
 		return gui.createErrorPanel(g, gui.Tr.SLocalize("CantRebaseOntoSelf"))
 	}
@@ -301,9 +889,10 @@ func (gui *Gui) handleRebase(g *gocui.Gui, v *gocui.View) error {
 	)
 	return gui.createConfirmationPanel(g, v, gui.Tr.SLocalize("RebasingTitle"), prompt,
 		func(g *gocui.Gui, v *gocui.View) error {
-
-			err := gui.GitCommand.RebaseBranch(selectedBranch)
-			return gui.handleGenericMergeCommandResult(err)
+			return gui.WithWaitingStatus(gui.Tr.SLocalize("RebasingStatus"), func() error {
+				err := gui.GitCommand.RebaseBranch(selectedBranch)
+				return gui.handleGenericMergeCommandResult(err)
+			})
 		}, nil)
 }
 
@@ -335,7 +924,7 @@ func (gui *Gui) handleFastForward(g *gocui.Gui, v *gocui.View) error {
 			_ = gui.createErrorPanel(gui.g, err.Error())
 		} else {
 			_ = gui.closeConfirmationPrompt(gui.g)
-			_ = gui.RenderSelectedBranchUpstreamDifferences()
+			_ = gui.refreshBranches(gui.g)
 		}
 	}()
 	return nil
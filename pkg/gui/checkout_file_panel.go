@@ -0,0 +1,100 @@
+package gui
+
+import (
+	"github.com/fatih/color"
+	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+)
+
+type refOption struct {
+	ref         string
+	description string
+	onPress     func() error
+}
+
+// GetDisplayStrings is a function.
+func (o *refOption) GetDisplayStrings(isFocused bool) []string {
+	return []string{o.ref, o.description}
+}
+
+// createCheckoutFileFromRefMenu offers the branches and commits we know of, or
+// any other ref you type in, to restore the file to its content at
+func (gui *Gui) createCheckoutFileFromRefMenu(v *gocui.View, fileName string) error {
+	options := []*refOption{{
+		ref:         "...",
+		description: gui.Tr.SLocalize("EnterRef"),
+		onPress: func() error {
+			return gui.createPromptPanel(gui.g, v, gui.Tr.SLocalize("EnterRef"), func(g *gocui.Gui, promptView *gocui.View) error {
+				return gui.checkoutFileFromRef(v, fileName, gui.trimmedContent(promptView))
+			})
+		},
+	}}
+	for _, branch := range gui.State.Branches {
+		name := branch.Name
+		options = append(options, &refOption{
+			ref:     utils.ColoredString(name, color.FgGreen),
+			onPress: func() error { return gui.checkoutFileFromRef(v, fileName, name) },
+		})
+	}
+	for _, commit := range gui.State.Commits {
+		// commits still to be picked in a rebase aren't part of the history yet
+		if commit.Status == "rebasing" {
+			continue
+		}
+		sha := commit.Sha
+		options = append(options, &refOption{
+			ref:         utils.ColoredString(sha, color.FgYellow),
+			description: commit.Name,
+			onPress:     func() error { return gui.checkoutFileFromRef(v, fileName, sha) },
+		})
+	}
+
+	handleMenuPress := func(index int) error {
+		return options[index].onPress()
+	}
+
+	title := gui.Tr.TemplateLocalize("CheckoutFileFromRefTitle", Teml{"path": fileName})
+	return gui.createMenu(title, options, len(options), handleMenuPress)
+}
+
+// checkoutFileFromRef restores the file to its content at the ref, first
+// asking if that would overwrite changes you've made to it
+func (gui *Gui) checkoutFileFromRef(v *gocui.View, fileName string, ref string) error {
+	if ref == "" {
+		return nil
+	}
+	checkout := func(g *gocui.Gui, v *gocui.View) error {
+		if err := gui.GitCommand.CheckoutFile(ref, fileName); err != nil {
+			return gui.createErrorPanel(gui.g, err.Error())
+		}
+		return gui.refreshFiles()
+	}
+
+	for _, file := range gui.State.Files {
+		if file.CurrentPath() == fileName && (file.HasStagedChanges || file.HasUnstagedChanges) {
+			title := gui.Tr.SLocalize("CheckoutFileFromRef")
+			prompt := gui.Tr.TemplateLocalize("SureToCheckoutFileFromRef", Teml{"path": fileName, "ref": ref})
+			return gui.createConfirmationPanel(gui.g, v, title, prompt, checkout, nil)
+		}
+	}
+	return checkout(gui.g, v)
+}
+
+func (gui *Gui) handleCheckoutFileFromRef(g *gocui.Gui, v *gocui.View) error {
+	file, err := gui.getSelectedFile(g)
+	if err != nil {
+		if err != gui.Errors.ErrNoFiles {
+			return err
+		}
+		return nil
+	}
+	return gui.createCheckoutFileFromRefMenu(v, file.CurrentPath())
+}
+
+func (gui *Gui) handleCheckoutCommitFileFromRef(g *gocui.Gui, v *gocui.View) error {
+	if len(gui.State.CommitFiles) == 0 {
+		return nil
+	}
+	file := gui.State.CommitFiles[gui.State.Panels.CommitFiles.SelectedLine]
+	return gui.createCheckoutFileFromRefMenu(v, file.Name)
+}
@@ -0,0 +1,96 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+)
+
+// maxCleanPathsListed is how many of the paths about to be deleted we list
+// when asking for confirmation, so that the prompt fits on the screen
+const maxCleanPathsListed = 15
+
+type cleanOption struct {
+	path     string
+	selected bool
+}
+
+// GetDisplayStrings is a function.
+func (o *cleanOption) GetDisplayStrings(isFocused bool) []string {
+	if o.selected {
+		return []string{utils.ColoredString("[x]", color.FgRed), utils.ColoredString(o.path, color.FgRed)}
+	}
+	return []string{"[ ]", o.path}
+}
+
+// handleCreateCleanMenu lists the untracked files, and with includeIgnored the
+// ignored ones too, for you to choose which to delete. Space selects a file
+// without closing the menu, and enter deletes the selected files, or the one
+// under the cursor if none are selected
+func (gui *Gui) handleCreateCleanMenu(includeIgnored bool) error {
+	paths, err := gui.GitCommand.GetCleanablePaths(includeIgnored)
+	if err != nil {
+		return gui.createErrorPanel(gui.g, err.Error())
+	}
+	if len(paths) == 0 {
+		return gui.createErrorPanel(gui.g, gui.Tr.SLocalize("NothingToClean"))
+	}
+
+	options := []*cleanOption{}
+	for _, path := range paths {
+		options = append(options, &cleanOption{path: path})
+	}
+
+	handleMenuPress := func(index int) error {
+		selectedPaths := []string{}
+		for _, option := range options {
+			if option.selected {
+				selectedPaths = append(selectedPaths, option.path)
+			}
+		}
+		if len(selectedPaths) == 0 {
+			selectedPaths = []string{options[index].path}
+		}
+		return gui.confirmCleanPaths(selectedPaths, includeIgnored)
+	}
+
+	if err := gui.createMenu(gui.Tr.SLocalize("CleanTitle"), options, len(options), handleMenuPress); err != nil {
+		return err
+	}
+
+	// createMenu has space run the option like enter does, but here it selects
+	handleToggle := func(g *gocui.Gui, v *gocui.View) error {
+		option := options[gui.State.Panels.Menu.SelectedLine]
+		option.selected = !option.selected
+		list, err := utils.RenderList(options, true)
+		if err != nil {
+			return err
+		}
+		v.Clear()
+		fmt.Fprint(v, list)
+		return gui.handleMenuNextLine(g, v)
+	}
+	if err := gui.g.DeleteKeybinding("menu", gocui.KeySpace, gocui.ModNone); err != nil {
+		return err
+	}
+	return gui.g.SetKeybinding("menu", gocui.KeySpace, gocui.ModNone, handleToggle)
+}
+
+// confirmCleanPaths deletes the paths once you've confirmed it, listing them in
+// the prompt because there's no getting them back
+func (gui *Gui) confirmCleanPaths(paths []string, includeIgnored bool) error {
+	listed := paths
+	if len(listed) > maxCleanPathsListed {
+		listed = append(listed[:maxCleanPathsListed:maxCleanPathsListed], "...")
+	}
+	prompt := gui.Tr.TemplateLocalize("CleanPrompt", Teml{"count": len(paths)}) + "\n\n" + strings.Join(listed, "\n")
+	return gui.createConfirmationPanel(gui.g, gui.getFilesView(), gui.Tr.SLocalize("CleanConfirmTitle"), prompt, func(g *gocui.Gui, v *gocui.View) error {
+		if err := gui.GitCommand.CleanPaths(paths, includeIgnored); err != nil {
+			return gui.createErrorPanel(g, err.Error())
+		}
+		return gui.refreshFiles()
+	}, nil)
+}
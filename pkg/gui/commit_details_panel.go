@@ -0,0 +1,103 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/jesseduffield/lazygit/pkg/commands"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+)
+
+// maxDetailsBranches is how many of the branches a commit is part of we list
+// before saying how many more there are
+const maxDetailsBranches = 5
+
+// renderCommitDetails lays out the commit's details, one to a line with a
+// label in front, for showing above its diff
+func (gui *Gui) renderCommitDetails(details *commands.CommitDetails) string {
+	type detail struct {
+		label string
+		value string
+	}
+	person := func(name string, email string, date string) string {
+		return fmt.Sprintf("%s <%s> %s", name, email, utils.ColoredString(date, color.FgBlue))
+	}
+	rows := []detail{
+		{gui.Tr.SLocalize("CommitAuthor"), person(details.AuthorName, details.AuthorEmail, details.AuthorDate)},
+		{gui.Tr.SLocalize("CommitCommitter"), person(details.CommitterName, details.CommitterEmail, details.CommitterDate)},
+	}
+	if len(details.Refs) > 0 {
+		rows = append(rows, detail{gui.Tr.SLocalize("CommitRefs"), utils.ColoredString(strings.Join(details.Refs, ", "), color.FgYellow)})
+	}
+	if len(details.ContainingBranches) > 0 {
+		branches := details.ContainingBranches
+		more := ""
+		if len(branches) > maxDetailsBranches {
+			more = " " + gui.Tr.TemplateLocalize("AndMore", Teml{"count": len(branches) - maxDetailsBranches})
+			branches = branches[:maxDetailsBranches]
+		}
+		rows = append(rows, detail{gui.Tr.SLocalize("CommitContainedIn"), utils.ColoredString(strings.Join(branches, ", "), color.FgGreen) + more})
+	}
+	rows = append(rows, detail{gui.Tr.SLocalize("CommitSignature"), gui.describeSignature(details)})
+	if details.Verification != "" {
+		for _, line := range strings.Split(details.Verification, "\n") {
+			rows = append(rows, detail{"", utils.ColoredString(line, color.FgBlue)})
+		}
+	}
+	for i, trailer := range details.Trailers {
+		label := ""
+		if i == 0 {
+			label = gui.Tr.SLocalize("CommitTrailers")
+		}
+		rows = append(rows, detail{label, trailer})
+	}
+
+	labelWidth := 0
+	for _, row := range rows {
+		if len(row.label)+1 > labelWidth {
+			labelWidth = len(row.label) + 1
+		}
+	}
+	lines := []string{}
+	for _, row := range rows {
+		label := row.label
+		if label != "" {
+			label += ":"
+		}
+		lines = append(lines, utils.ColoredString(utils.WithPadding(label, labelWidth), color.FgCyan)+" "+row.value)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// describeSignature says whether the commit is signed, and if so whether the
+// signature is good and who made it
+func (gui *Gui) describeSignature(details *commands.CommitDetails) string {
+	if !details.IsSigned() {
+		return gui.Tr.SLocalize("NotSigned")
+	}
+	descriptions := map[string]string{
+		"G": "SignatureGood",
+		"B": "SignatureBad",
+		"U": "SignatureUnknownValidity",
+		"X": "SignatureExpired",
+		"Y": "SignatureExpired",
+		"R": "SignatureRevoked",
+	}
+	id, ok := descriptions[details.Signature]
+	if !ok {
+		id = "SignatureUncheckable"
+	}
+	colour := color.FgYellow
+	switch details.Signature {
+	case "G":
+		colour = color.FgGreen
+	case "B":
+		colour = color.FgRed
+	}
+	description := utils.ColoredString(gui.Tr.SLocalize(id), colour)
+	if details.Signer != "" {
+		description += " " + gui.Tr.TemplateLocalize("SignedBy", Teml{"signer": details.Signer})
+	}
+	return description
+}
@@ -23,7 +23,13 @@ func (gui *Gui) handleCommitFileSelect(g *gocui.Gui, v *gocui.View) error {
 	if err := gui.focusPoint(0, gui.State.Panels.CommitFiles.SelectedLine, len(gui.State.CommitFiles), v); err != nil {
 		return err
 	}
-	commitText, err := gui.GitCommand.ShowCommitFile(commitFile.Sha, commitFile.Name)
+	var commitText string
+	var err error
+	if commitFile.Base != "" {
+		commitText, err = gui.GitCommand.CompareRefsFile(commitFile.Base, commitFile.Sha, commitFile.Name)
+	} else {
+		commitText, err = gui.GitCommand.ShowCommitFile(commitFile.Sha, commitFile.Name)
+	}
 	if err != nil {
 		return err
 	}
@@ -44,7 +50,12 @@ func (gui *Gui) handleCommitFilesPrevLine(g *gocui.Gui, v *gocui.View) error {
 	return gui.handleCommitFileSelect(gui.g, v)
 }
 
+// handleSwitchToCommitsPanel goes back to the panel we came from, which is the
+// branches panel if we're listing the files of a comparison
 func (gui *Gui) handleSwitchToCommitsPanel(g *gocui.Gui, v *gocui.View) error {
+	if gui.State.Panels.CommitFiles.ComparedRef != "" {
+		return gui.switchFocus(g, v, gui.getBranchesView())
+	}
 	commitsView, err := g.View("commits")
 	if err != nil {
 		return err
@@ -63,6 +74,13 @@ func (gui *Gui) handleCheckoutCommitFile(g *gocui.Gui, v *gocui.View) error {
 }
 
 func (gui *Gui) handleDiscardOldFileChange(g *gocui.Gui, v *gocui.View) error {
+	if readOnly, err := gui.handleCompareFilesReadOnly(); readOnly {
+		return err
+	}
+	if filtered, err := gui.handleRebaseNotAllowed(); filtered {
+		return err
+	}
+
 	fileName := gui.State.CommitFiles[gui.State.Panels.CommitFiles.SelectedLine].Name
 
 	return gui.createConfirmationPanel(gui.g, v, gui.Tr.SLocalize("DiscardFileChangesTitle"), gui.Tr.SLocalize("DiscardFileChangesPrompt"), func(g *gocui.Gui, v *gocui.View) error {
@@ -79,12 +97,20 @@ func (gui *Gui) handleDiscardOldFileChange(g *gocui.Gui, v *gocui.View) error {
 }
 
 func (gui *Gui) refreshCommitFilesView() error {
-	commit := gui.getSelectedCommit(gui.g)
-	if commit == nil {
-		return nil
+	commitFilesView := gui.getCommitFilesView()
+	var files []*commands.CommitFile
+	var err error
+	if comparedRef := gui.State.Panels.CommitFiles.ComparedRef; comparedRef != "" {
+		commitFilesView.Title = gui.Tr.TemplateLocalize("CompareFilesTitle", Teml{"base": gui.State.CompareBase, "ref": comparedRef})
+		files, err = gui.GitCommand.GetCompareFiles(gui.State.CompareBase, comparedRef)
+	} else {
+		commit := gui.getSelectedCommit(gui.g)
+		if commit == nil {
+			return nil
+		}
+		commitFilesView.Title = gui.Tr.SLocalize("CommitFiles")
+		files, err = gui.GitCommand.GetCommitFiles(commit.Sha)
 	}
-
-	files, err := gui.GitCommand.GetCommitFiles(commit.Sha)
 	if err != nil {
 		return gui.createErrorPanel(gui.g, err.Error())
 	}
@@ -93,11 +119,21 @@ func (gui *Gui) refreshCommitFilesView() error {
 
 	gui.refreshSelectedLine(&gui.State.Panels.CommitFiles.SelectedLine, len(gui.State.CommitFiles))
 
-	if err := gui.renderListPanel(gui.getCommitFilesView(), gui.State.CommitFiles); err != nil {
+	if err := gui.renderListPanel(commitFilesView, gui.State.CommitFiles); err != nil {
 		return err
 	}
 
-	return gui.handleCommitFileSelect(gui.g, gui.getCommitFilesView())
+	return gui.handleCommitFileSelect(gui.g, commitFilesView)
+}
+
+func (gui *Gui) handleCopyCommitFilePath(absolute bool) func(g *gocui.Gui, v *gocui.View) error {
+	return func(g *gocui.Gui, v *gocui.View) error {
+		file := gui.getSelectedCommitFile(g)
+		if file == nil {
+			return nil
+		}
+		return gui.copyPathToClipboard(file.Name, absolute)
+	}
 }
 
 func (gui *Gui) handleOpenOldCommitFile(g *gocui.Gui, v *gocui.View) error {
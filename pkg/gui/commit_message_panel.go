@@ -5,7 +5,10 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/fatih/color"
 	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazygit/pkg/commands"
+	"github.com/jesseduffield/lazygit/pkg/utils"
 )
 
 // runSyncOrAsyncCommand takes the output of a command that may have returned
@@ -30,11 +33,36 @@ func (gui *Gui) handleCommitConfirm(g *gocui.Gui, v *gocui.View) error {
 	if message == "" {
 		return gui.createErrorPanel(g, gui.Tr.SLocalize("CommitWithoutMessageErr"))
 	}
+	if sha := gui.State.RewordingSha; sha != "" {
+		return gui.withCommitLint(v, message, func() error {
+			if err := gui.handleCommitClose(g, v); err != nil {
+				return err
+			}
+			return gui.rewordCommit(sha, message)
+		})
+	}
 	flags := ""
 	skipHookPrefix := gui.Config.GetUserConfig().GetString("git.skipHookPrefix")
 	if skipHookPrefix != "" && strings.HasPrefix(message, skipHookPrefix) {
 		flags = "--no-verify"
 	}
+	template, err := gui.GitCommand.CommitTemplate()
+	if err != nil {
+		return gui.createErrorPanel(g, err.Error())
+	}
+	if template != "" {
+		if message == strings.TrimSpace(template) {
+			return gui.createErrorPanel(g, gui.Tr.SLocalize("CommitWithoutMessageErr"))
+		}
+		// like git does when you commit with a template, leave out the comments
+		flags += " --cleanup=strip"
+	}
+	return gui.withCommitLint(v, message, func() error {
+		return gui.commitWithMessage(g, v, message, flags)
+	})
+}
+
+func (gui *Gui) commitWithMessage(g *gocui.Gui, v *gocui.View, message string, flags string) error {
 	ok, err := gui.runSyncOrAsyncCommand(gui.GitCommand.Commit(message, flags))
 	if err != nil {
 		return err
@@ -48,11 +76,55 @@ func (gui *Gui) handleCommitConfirm(g *gocui.Gui, v *gocui.View) error {
 	_ = v.SetOrigin(0, 0)
 	_, _ = g.SetViewOnBottom("commitMessage")
 	_ = gui.switchFocus(g, v, gui.getFilesView())
+	if gui.State.SplittingSha != "" {
+		return gui.finishSplittingCommit()
+	}
 	return gui.refreshSidePanels(g)
 }
 
+// withCommitLint runs git.commitLintCommand, if it's set, on the message
+// before committing with it. If the command fails or has anything to say, we
+// show what it said and ask whether to commit anyway. Like hooks, it's skipped
+// for messages starting with git.skipHookPrefix
+func (gui *Gui) withCommitLint(v *gocui.View, message string, commit func() error) error {
+	userConfig := gui.Config.GetUserConfig()
+	command := userConfig.GetString("git.commitLintCommand")
+	skipHookPrefix := userConfig.GetString("git.skipHookPrefix")
+	if command == "" || (skipHookPrefix != "" && strings.HasPrefix(message, skipHookPrefix)) {
+		return commit()
+	}
+
+	output, err := gui.OSCommand.RunCommandWithInput(command, message)
+	output = strings.TrimSpace(output)
+	if err == nil && output == "" {
+		return commit()
+	}
+	if output == "" {
+		output = err.Error()
+	}
+	prompt := gui.Tr.TemplateLocalize("CommitLintPrompt", Teml{"output": output})
+	return gui.createConfirmationPanel(gui.g, v, gui.Tr.SLocalize("CommitLintTitle"), prompt, func(g *gocui.Gui, _ *gocui.View) error {
+		// committing moves focus on from the commit message panel, which it can
+		// only do once this panel has closed and given focus back to it
+		g.Update(func(*gocui.Gui) error {
+			return commit()
+		})
+		return nil
+	}, nil)
+}
+
 func (gui *Gui) handleCommitClose(g *gocui.Gui, v *gocui.View) error {
 	g.SetViewOnBottom("commitMessage")
+	if gui.State.RewordingSha != "" {
+		// the panel goes back to making commits, without the message we were
+		// rewording
+		gui.State.RewordingSha = ""
+		v.Title = gui.Tr.SLocalize("CommitMessage")
+		v.Clear()
+		_ = v.SetCursor(0, 0)
+		_ = v.SetOrigin(0, 0)
+		return gui.switchFocus(g, v, gui.getCommitsView())
+	}
 	return gui.switchFocus(g, v, gui.getFilesView())
 }
 
@@ -61,25 +133,139 @@ func (gui *Gui) handleCommitFocused(g *gocui.Gui, v *gocui.View) error {
 		return err
 	}
 
+	return gui.renderCommitMessageOptions()
+}
+
+// renderCommitMessageOptions shows the commit message panel's keybindings,
+// followed by any warnings about the message
+func (gui *Gui) renderCommitMessageOptions() error {
 	message := gui.Tr.TemplateLocalize(
 		"CloseConfirm",
 		Teml{
 			"keyBindClose":   "esc",
 			"keyBindConfirm": "enter",
 		},
-	)
-	return gui.renderString(g, "options", message)
+	) + ", ctrl+o: " + gui.Tr.SLocalize("addCoAuthor")
+	if warnings := gui.commitMessageWarnings(gui.getCommitMessageView()); warnings != "" {
+		message += "  " + warnings
+	}
+	return gui.renderString(gui.g, "options", message)
+}
+
+// commitMessageWarnings describes the lines of the message that are too long
+// or end in whitespace. A subject over gui.commitLength.subjectLimit is red,
+// and everything else yellow
+func (gui *Gui) commitMessageWarnings(v *gocui.View) string {
+	userConfig := gui.Config.GetUserConfig()
+	limits := commands.CommitMessageLimits{
+		SubjectWarning: userConfig.GetInt("gui.commitLength.subjectWarning"),
+		SubjectLimit:   userConfig.GetInt("gui.commitLength.subjectLimit"),
+		BodyLimit:      userConfig.GetInt("gui.commitLength.bodyLimit"),
+	}
+	_, cursorY := v.Cursor()
+	_, originY := v.Origin()
+
+	warnings := []string{}
+	for _, problem := range commands.LintCommitMessage(v.Buffer(), limits) {
+		colour := color.FgYellow
+		id := ""
+		limit := 0
+		switch problem.Kind {
+		case commands.CommitSubjectTooLong:
+			colour = color.FgRed
+			id, limit = "CommitSubjectTooLong", limits.SubjectLimit
+		case commands.CommitSubjectLong:
+			id, limit = "CommitSubjectTooLong", limits.SubjectWarning
+		case commands.CommitBodyLineTooLong:
+			id, limit = "CommitLineTooLong", limits.BodyLimit
+		case commands.CommitTrailingWhitespace:
+			// you're most likely still typing the line you're on
+			if problem.Line == originY+cursorY+1 {
+				continue
+			}
+			id = "CommitLineTrailingWhitespace"
+		}
+		warning := gui.Tr.TemplateLocalize(id, Teml{"line": problem.Line, "length": problem.Length, "limit": limit})
+		warnings = append(warnings, utils.ColoredString(warning, colour))
+	}
+	return strings.Join(warnings, ", ")
+}
+
+// commitMessageEditor edits the message like gocui's default editor, keeping
+// the length and warnings up to date as you type
+func (gui *Gui) commitMessageEditor(v *gocui.View, key gocui.Key, ch rune, mod gocui.Modifier) {
+	gocui.DefaultEditor.Edit(v, key, ch, mod)
+	gui.RenderCommitLength()
 }
 
 func (gui *Gui) getBufferLength(view *gocui.View) string {
 	return " " + strconv.Itoa(strings.Count(view.Buffer(), "")-1) + " "
 }
 
-// RenderCommitLength is a function.
+// RenderCommitLength shows the length of the commit message, if configured
+// to, along with any warnings about it while you're writing it
 func (gui *Gui) RenderCommitLength() {
-	if !gui.Config.GetUserConfig().GetBool("gui.commitLength.show") {
-		return
-	}
 	v := gui.getCommitMessageView()
-	v.Subtitle = gui.getBufferLength(v)
+	if gui.Config.GetUserConfig().GetBool("gui.commitLength.show") {
+		v.Subtitle = gui.getBufferLength(v)
+	}
+	if gui.g.CurrentView() == v {
+		_ = gui.renderCommitMessageOptions()
+	}
+}
+
+type coAuthorOption struct {
+	author string
+}
+
+// GetDisplayStrings is a function.
+func (o *coAuthorOption) GetDisplayStrings(isFocused bool) []string {
+	return []string{o.author}
+}
+
+// handleAddCoAuthor offers the authors of recent commits, and adds a
+// Co-authored-by trailer for the one you pick to the commit message
+func (gui *Gui) handleAddCoAuthor(g *gocui.Gui, v *gocui.View) error {
+	authors, err := gui.GitCommand.GetRecentAuthors()
+	if err != nil {
+		return gui.createErrorPanel(g, err.Error())
+	}
+	if len(authors) == 0 {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("NoRecentAuthors"))
+	}
+
+	options := make([]*coAuthorOption, len(authors))
+	for i, author := range authors {
+		options[i] = &coAuthorOption{author: author}
+	}
+
+	handleMenuPress := func(index int) error {
+		if err := gui.setViewContent(g, v, addCoAuthorTrailer(v.Buffer(), options[index].author)); err != nil {
+			return err
+		}
+		// the menu returns focus to the panel behind the commit message panel, as
+		// popups don't stack, so once it has we come back to the message
+		g.Update(func(g *gocui.Gui) error {
+			if _, err := g.SetViewOnTop("commitMessage"); err != nil {
+				return err
+			}
+			gui.RenderCommitLength()
+			return gui.switchFocus(g, nil, v)
+		})
+		return nil
+	}
+
+	return gui.createMenu(gui.Tr.SLocalize("AddCoAuthorTitle"), options, len(options), handleMenuPress)
+}
+
+// addCoAuthorTrailer adds the trailer on its own line after the message, which
+// is after a blank line unless the message already ends in trailers
+func addCoAuthorTrailer(message string, author string) string {
+	message = strings.TrimRight(message, "\n")
+	trailer := "Co-authored-by: " + author
+	lines := strings.Split(message, "\n")
+	if len(lines) > 1 && strings.HasPrefix(lines[len(lines)-1], "Co-authored-by: ") {
+		return message + "\n" + trailer
+	}
+	return message + "\n\n" + trailer
 }
@@ -0,0 +1,102 @@
+package gui
+
+import (
+	"github.com/jesseduffield/gocui"
+)
+
+type filterOption struct {
+	description string
+	onPress     func() error
+}
+
+// GetDisplayStrings is a function.
+func (o *filterOption) GetDisplayStrings(isFocused bool) []string {
+	return []string{o.description}
+}
+
+// setCommitsFilter shows only the commits that changed the path in the commits
+// panel, or all of them again if the path is empty
+func (gui *Gui) setCommitsFilter(path string) error {
+	gui.State.FilterPath = path
+	gui.State.Panels.Commits.SelectedLine = 0
+	if err := gui.refreshCommits(gui.g); err != nil {
+		return err
+	}
+	commitsView := gui.getCommitsView()
+	gui.resetOrigin(commitsView)
+	return gui.switchFocus(gui.g, gui.g.CurrentView(), commitsView)
+}
+
+// handleFilterCommitsByFilePath shows the commits that changed the selected
+// file or directory
+func (gui *Gui) handleFilterCommitsByFilePath(g *gocui.Gui, v *gocui.View) error {
+	node := gui.getSelectedFileNode()
+	if node == nil {
+		return nil
+	}
+	return gui.setCommitsFilter(node.Path)
+}
+
+// handleCreateCommitsFilterMenu offers to filter the commits by a path you
+// type in, or to stop filtering them
+func (gui *Gui) handleCreateCommitsFilterMenu(g *gocui.Gui, v *gocui.View) error {
+	options := []*filterOption{{
+		description: gui.Tr.SLocalize("filterCommitsByPath"),
+		onPress: func() error {
+			return gui.createPromptPanel(g, v, gui.Tr.SLocalize("FilterCommitsByPathTitle"), func(g *gocui.Gui, promptView *gocui.View) error {
+				path := gui.trimmedContent(promptView)
+				if path == "" {
+					return nil
+				}
+				return gui.setCommitsFilter(path)
+			})
+		},
+	}}
+	if gui.State.FilterPath != "" {
+		options = append(options, &filterOption{
+			description: gui.Tr.SLocalize("stopFilteringCommits"),
+			onPress:     func() error { return gui.setCommitsFilter("") },
+		})
+	}
+
+	handleMenuPress := func(index int) error {
+		return options[index].onPress()
+	}
+
+	return gui.createMenu(gui.Tr.SLocalize("FilterCommitsTitle"), options, len(options), handleMenuPress)
+}
+
+// handleCommitsEscape unmarks any marked commits, then goes back to the
+// checked out branch's commits if we're showing another branch's, then stops
+// filtering the commits if they're filtered, and otherwise quits like escape
+// does everywhere else
+func (gui *Gui) handleCommitsEscape(g *gocui.Gui, v *gocui.View) error {
+	if len(gui.State.Panels.Commits.MarkedShas) > 0 {
+		gui.State.Panels.Commits.MarkedShas = map[string]bool{}
+		return gui.refreshCommits(g)
+	}
+	if gui.State.BrowsingBranch != "" {
+		return gui.setBrowsingBranch("")
+	}
+	if gui.State.FilterPath != "" {
+		return gui.setCommitsFilter("")
+	}
+	return gui.quit(g, v)
+}
+
+// handleRebaseNotAllowed refuses to rebase while the commits are filtered,
+// as the todo we'd make from them would leave out the commits in between, or
+// while bisecting, when the commits above HEAD are shown too. Nor can we
+// rebase another branch's commits. It returns true if it did
+func (gui *Gui) handleRebaseNotAllowed() (bool, error) {
+	if readOnly, err := gui.handleCommitsReadOnly(); readOnly {
+		return true, err
+	}
+	if gui.State.FilterPath != "" {
+		return true, gui.createErrorPanel(gui.g, gui.Tr.SLocalize("CantRebaseWhileFiltering"))
+	}
+	if gui.State.Bisect != nil {
+		return true, gui.createErrorPanel(gui.g, gui.Tr.SLocalize("CantRebaseWhileBisecting"))
+	}
+	return false, nil
+}
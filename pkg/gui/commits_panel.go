@@ -3,6 +3,7 @@ package gui
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/fatih/color"
 	"github.com/go-errors/errors"
@@ -24,6 +25,10 @@ func (gui *Gui) getSelectedCommit(g *gocui.Gui) *commands.Commit {
 	return gui.State.Commits[selectedLine]
 }
 
+// commitsPageSize is how many more commits we load each time you scroll to the
+// bottom of the commits panel
+const commitsPageSize = 300
+
 func (gui *Gui) handleCommitSelect(g *gocui.Gui, v *gocui.View) error {
 	if gui.popupPanelFocused() {
 		return nil
@@ -40,22 +45,139 @@ func (gui *Gui) handleCommitSelect(g *gocui.Gui, v *gocui.View) error {
 	if err := gui.focusPoint(0, gui.State.Panels.Commits.SelectedLine, len(gui.State.Commits), v); err != nil {
 		return err
 	}
+	if err := gui.loadMoreCommitsIfNeeded(); err != nil {
+		return err
+	}
 
 	// if specific diff mode is on, don't show diff
 	if gui.State.Panels.Commits.SpecificDiffMode {
 		return nil
 	}
 
-	commitText, err := gui.GitCommand.Show(commit.Sha)
+	// getting the details means a couple of git commands on top of the diff, so
+	// we do it all in the background to keep scrolling through commits snappy
+	parent := gui.State.Panels.Commits.MergeDiffParent
+	syntaxHighlighting := gui.State.SyntaxHighlighting
+	go func() {
+		commitText, heading, err := gui.showCommit(commit.Sha, parent)
+		if err != nil {
+			gui.Log.Error(err)
+			return
+		}
+		if syntaxHighlighting {
+			commitText = gui.renderDiff(utils.Decolorise(commitText))
+		}
+		if heading != "" {
+			commitText = heading + "\n\n" + commitText
+		}
+		// the diff is still worth showing without the details
+		details, err := gui.GitCommand.GetCommitDetails(commit.Sha)
+		if err != nil {
+			gui.Log.Error(err)
+		} else {
+			commitText = gui.renderCommitDetails(details) + "\n\n" + commitText
+		}
+		g.Update(func(g *gocui.Gui) error {
+			// we may have moved on to another commit or panel, or switched which
+			// parent to diff against, while this one was loading
+			if g.CurrentView() != v || gui.State.Panels.Commits.MergeDiffParent != parent {
+				return nil
+			}
+			if selected := gui.getSelectedCommit(g); selected == nil || selected.Sha != commit.Sha {
+				return nil
+			}
+			return gui.renderString(g, "main", commitText)
+		})
+	}()
+	return nil
+}
+
+// showCommit gets the commit's diff, which for a merge commit is either the
+// combined diff or its diff against one of its parents, along with a heading
+// saying which parent that is
+func (gui *Gui) showCommit(sha string, parent int) (string, string, error) {
+	if parent == 0 {
+		commitText, err := gui.GitCommand.Show(sha)
+		return commitText, "", err
+	}
+	parents, err := gui.GitCommand.GetCommitParents(sha)
 	if err != nil {
-		return err
+		return "", "", err
+	}
+	if len(parents) < parent {
+		commitText, err := gui.GitCommand.Show(sha)
+		return commitText, "", err
+	}
+	commitText, err := gui.GitCommand.ShowAgainstParent(sha, parent)
+	if err != nil {
+		return "", "", err
+	}
+	heading := gui.Tr.TemplateLocalize("MergeDiffAgainstParent", Teml{
+		"number": parent,
+		"sha":    parents[parent-1].Sha,
+		"name":   parents[parent-1].Name,
+	})
+	return commitText, utils.ColoredString(heading, color.FgCyan), nil
+}
+
+// handleCycleMergeDiff switches a merge commit's diff between the combined
+// diff and the diff against each of its first two parents. The choice sticks
+// for the other merge commits we select
+func (gui *Gui) handleCycleMergeDiff(g *gocui.Gui, v *gocui.View) error {
+	commit := gui.getSelectedCommit(g)
+	if commit == nil {
+		return nil
+	}
+	parents, err := gui.GitCommand.GetCommitParents(commit.Sha)
+	if err != nil {
+		return gui.createErrorPanel(g, err.Error())
+	}
+	if len(parents) < 2 {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("NotAMergeCommit"))
+	}
+	panelState := gui.State.Panels.Commits
+	panelState.MergeDiffParent = (panelState.MergeDiffParent + 1) % 3
+	return gui.handleCommitSelect(g, v)
+}
+
+// loadMoreCommitsIfNeeded loads another page of commits once the last one
+// we've loaded is selected, unless we've already loaded all of them
+func (gui *Gui) loadMoreCommitsIfNeeded() error {
+	panelState := gui.State.Panels.Commits
+	if panelState.SelectedLine < len(gui.State.Commits)-1 {
+		return nil
+	}
+	if !gui.moreCommitsToLoad() {
+		return nil
 	}
-	return gui.renderString(g, "main", commitText)
+	panelState.LimitCommits += commitsPageSize
+	return gui.refreshCommits(gui.g)
+}
+
+// moreCommitsToLoad says whether we loaded as many commits as we asked for,
+// in which case there may be more
+func (gui *Gui) moreCommitsToLoad() bool {
+	loaded := 0
+	for _, commit := range gui.State.Commits {
+		// commits still to be picked in a rebase don't come from the log
+		if commit.Status != "rebasing" {
+			loaded++
+		}
+	}
+	return loaded >= gui.State.Panels.Commits.LimitCommits
 }
 
 func (gui *Gui) refreshCommits(g *gocui.Gui) error {
 	g.Update(func(*gocui.Gui) error {
-		builder, err := git.NewCommitListBuilder(gui.Log, gui.GitCommand, gui.OSCommand, gui.Tr, gui.State.CherryPickedCommits, gui.State.DiffEntries)
+		bisectInfo, err := gui.GitCommand.GetBisectInfo()
+		if err != nil {
+			return err
+		}
+		// when git checks out another commit to test we select it
+		bisectMoved := bisectInfo != nil && (gui.State.Bisect == nil || gui.State.Bisect.Current != bisectInfo.Current)
+		gui.State.Bisect = bisectInfo
+
+		builder, err := git.NewCommitListBuilder(gui.Log, gui.GitCommand, gui.OSCommand, gui.Tr, gui.State.CherryPickedCommits, gui.State.DiffEntries, gui.State.FilterPath, gui.State.Bisect, gui.State.Panels.Commits.LimitCommits, gui.State.BrowsingBranch)
 		if err != nil {
 			return err
 		}
@@ -64,7 +186,18 @@ func (gui *Gui) refreshCommits(g *gocui.Gui) error {
 			return err
 		}
 		gui.State.Commits = commits
-
+		gui.setMarkedCommits()
+
+		if bisectMoved {
+			for i, commit := range commits {
+				if commit.Bisect == "current" {
+					gui.State.Panels.Commits.SelectedLine = i
+				}
+			}
+		}
+		if gui.State.Panels.Commits.GotoSha != "" && gui.selectGotoCommit() {
+			return nil
+		}
 		gui.refreshSelectedLine(&gui.State.Panels.Commits.SelectedLine, len(gui.State.Commits))
 
 		isFocused := gui.g.CurrentView().Name() == "commits"
@@ -74,6 +207,7 @@ func (gui *Gui) refreshCommits(g *gocui.Gui) error {
 		}
 
 		v := gui.getCommitsView()
+		gui.setCommitsTabs()
 		v.Clear()
 		fmt.Fprint(v, list)
 
@@ -142,6 +276,9 @@ func (gui *Gui) handleResetToCommit(g *gocui.Gui, commitView *gocui.View) error
 }
 
 func (gui *Gui) handleCommitSquashDown(g *gocui.Gui, v *gocui.View) error {
+	if len(gui.State.Panels.Commits.MarkedShas) > 0 {
+		return gui.handleRebaseMarkedCommits(v, "squash")
+	}
 	if len(gui.State.Commits) <= 1 {
 		return gui.createErrorPanel(g, gui.Tr.SLocalize("YouNoCommitsToSquash"))
 	}
@@ -153,6 +290,9 @@ func (gui *Gui) handleCommitSquashDown(g *gocui.Gui, v *gocui.View) error {
 	if applied {
 		return nil
 	}
+	if filtered, err := gui.handleRebaseNotAllowed(); filtered {
+		return err
+	}
 
 	gui.createConfirmationPanel(g, v, gui.Tr.SLocalize("Squash"), gui.Tr.SLocalize("SureSquashThisCommit"), func(g *gocui.Gui, v *gocui.View) error {
 		return gui.WithWaitingStatus(gui.Tr.SLocalize("SquashingStatus"), func() error {
@@ -174,6 +314,9 @@ func (gui *Gui) anyUnStagedChanges(files []*commands.File) bool {
 }
 
 func (gui *Gui) handleCommitFixup(g *gocui.Gui, v *gocui.View) error {
+	if len(gui.State.Panels.Commits.MarkedShas) > 0 {
+		return gui.handleRebaseMarkedCommits(v, "fixup")
+	}
 	if len(gui.State.Commits) <= 1 {
 		return gui.createErrorPanel(g, gui.Tr.SLocalize("YouNoCommitsToSquash"))
 	}
@@ -185,6 +328,9 @@ func (gui *Gui) handleCommitFixup(g *gocui.Gui, v *gocui.View) error {
 	if applied {
 		return nil
 	}
+	if filtered, err := gui.handleRebaseNotAllowed(); filtered {
+		return err
+	}
 
 	gui.createConfirmationPanel(g, v, gui.Tr.SLocalize("Fixup"), gui.Tr.SLocalize("SureFixupThisCommit"), func(g *gocui.Gui, v *gocui.View) error {
 		return gui.WithWaitingStatus(gui.Tr.SLocalize("FixingStatus"), func() error {
@@ -203,19 +349,49 @@ func (gui *Gui) handleRenameCommit(g *gocui.Gui, v *gocui.View) error {
 	if applied {
 		return nil
 	}
+	if filtered, err := gui.handleRebaseNotAllowed(); filtered {
+		return err
+	}
 
-	if gui.State.Panels.Commits.SelectedLine != 0 {
-		return gui.createErrorPanel(g, gui.Tr.SLocalize("OnlyRenameTopCommit"))
+	commit := gui.State.Commits[gui.State.Panels.Commits.SelectedLine]
+	message, err := gui.GitCommand.GetCommitMessage(commit.Sha)
+	if err != nil {
+		return gui.createErrorPanel(g, err.Error())
 	}
-	return gui.createPromptPanel(g, v, gui.Tr.SLocalize("renameCommit"), func(g *gocui.Gui, v *gocui.View) error {
-		if err := gui.GitCommand.RenameCommit(v.Buffer()); err != nil {
-			return gui.createErrorPanel(g, err.Error())
+
+	// the commit message panel confirms by rewording this commit until it's
+	// closed again
+	gui.State.RewordingSha = commit.Sha
+	commitMessageView := gui.getCommitMessageView()
+	commitMessageView.Title = gui.Tr.TemplateLocalize("RewordCommitTitle", Teml{"sha": commit.Sha})
+	g.Update(func(g *gocui.Gui) error {
+		if err := gui.setViewContent(g, commitMessageView, message); err != nil {
+			return err
 		}
-		if err := gui.refreshCommits(g); err != nil {
-			panic(err)
+		lines := strings.Split(message, "\n")
+		if err := commitMessageView.SetCursor(len(lines[len(lines)-1]), len(lines)-1); err != nil {
+			return err
 		}
-		return gui.handleCommitSelect(g, v)
+		g.SetViewOnTop("commitMessage")
+		gui.switchFocus(g, v, commitMessageView)
+		gui.RenderCommitLength()
+		return nil
 	})
+	return nil
+}
+
+// rewordCommit gives the commit the message from the commit message panel
+func (gui *Gui) rewordCommit(sha string, message string) error {
+	for index, commit := range gui.State.Commits {
+		if commit.Sha == sha {
+			return gui.WithWaitingStatus(gui.Tr.SLocalize("RewordingStatus"), func() error {
+				err := gui.GitCommand.RewordCommitWithMessage(gui.State.Commits, index, message)
+				return gui.handleGenericMergeCommandResult(err)
+			})
+		}
+	}
+	// the commits have changed under us since we started
+	return gui.createErrorPanel(gui.g, gui.Tr.SLocalize("RewordCommitGone"))
 }
 
 func (gui *Gui) handleRenameCommitEditor(g *gocui.Gui, v *gocui.View) error {
@@ -226,6 +402,9 @@ func (gui *Gui) handleRenameCommitEditor(g *gocui.Gui, v *gocui.View) error {
 	if applied {
 		return nil
 	}
+	if filtered, err := gui.handleRebaseNotAllowed(); filtered {
+		return err
+	}
 
 	subProcess, err := gui.GitCommand.RewordCommit(gui.State.Commits, gui.State.Panels.Commits.SelectedLine)
 	if err != nil {
@@ -278,6 +457,10 @@ func (gui *Gui) handleMoveTodoDown(index int) (bool, error) {
 }
 
 func (gui *Gui) handleCommitDelete(g *gocui.Gui, v *gocui.View) error {
+	if len(gui.State.Panels.Commits.MarkedShas) > 0 {
+		return gui.handleRebaseMarkedCommits(v, "drop")
+	}
+
 	applied, err := gui.handleMidRebaseCommand("drop")
 	if err != nil {
 		return err
@@ -285,6 +468,9 @@ func (gui *Gui) handleCommitDelete(g *gocui.Gui, v *gocui.View) error {
 	if applied {
 		return nil
 	}
+	if filtered, err := gui.handleRebaseNotAllowed(); filtered {
+		return err
+	}
 
 	return gui.createConfirmationPanel(gui.g, v, gui.Tr.SLocalize("DeleteCommitTitle"), gui.Tr.SLocalize("DeleteCommitPrompt"), func(*gocui.Gui, *gocui.View) error {
 		return gui.WithWaitingStatus(gui.Tr.SLocalize("DeletingStatus"), func() error {
@@ -307,6 +493,9 @@ func (gui *Gui) handleCommitMoveDown(g *gocui.Gui, v *gocui.View) error {
 		gui.State.Panels.Commits.SelectedLine++
 		return gui.refreshCommits(gui.g)
 	}
+	if filtered, err := gui.handleRebaseNotAllowed(); filtered {
+		return err
+	}
 
 	return gui.WithWaitingStatus(gui.Tr.SLocalize("MovingStatus"), func() error {
 		err := gui.GitCommand.MoveCommitDown(gui.State.Commits, index)
@@ -330,6 +519,9 @@ func (gui *Gui) handleCommitMoveUp(g *gocui.Gui, v *gocui.View) error {
 		gui.State.Panels.Commits.SelectedLine--
 		return gui.refreshCommits(gui.g)
 	}
+	if filtered, err := gui.handleRebaseNotAllowed(); filtered {
+		return err
+	}
 
 	return gui.WithWaitingStatus(gui.Tr.SLocalize("MovingStatus"), func() error {
 		err := gui.GitCommand.MoveCommitDown(gui.State.Commits, index-1)
@@ -348,6 +540,9 @@ func (gui *Gui) handleCommitEdit(g *gocui.Gui, v *gocui.View) error {
 	if applied {
 		return nil
 	}
+	if filtered, err := gui.handleRebaseNotAllowed(); filtered {
+		return err
+	}
 
 	return gui.WithWaitingStatus(gui.Tr.SLocalize("RebasingStatus"), func() error {
 		err = gui.GitCommand.InteractiveRebase(gui.State.Commits, gui.State.Panels.Commits.SelectedLine, "edit")
@@ -379,11 +574,98 @@ func (gui *Gui) handleCommitPick(g *gocui.Gui, v *gocui.View) error {
 }
 
 func (gui *Gui) handleCommitRevert(g *gocui.Gui, v *gocui.View) error {
-	if err := gui.GitCommand.Revert(gui.State.Commits[gui.State.Panels.Commits.SelectedLine].Sha); err != nil {
+	if readOnly, err := gui.handleCommitsReadOnly(); readOnly {
+		return err
+	}
+	commit := gui.State.Commits[gui.State.Panels.Commits.SelectedLine]
+	parents, err := gui.GitCommand.GetCommitParents(commit.Sha)
+	if err != nil {
 		return gui.createErrorPanel(gui.g, err.Error())
 	}
-	gui.State.Panels.Commits.SelectedLine++
-	return gui.refreshCommits(gui.g)
+	if len(parents) > 1 {
+		return gui.createRevertMergeMenu(commit, parents)
+	}
+	return gui.revertCommit(commit.Sha, 0)
+}
+
+type parentOption struct {
+	number int
+	commit *commands.Commit
+}
+
+// GetDisplayStrings is a function.
+func (o *parentOption) GetDisplayStrings(isFocused bool) []string {
+	return []string{strconv.Itoa(o.number), utils.ColoredString(o.commit.Sha, color.FgYellow), o.commit.Name}
+}
+
+// createRevertMergeMenu asks which parent to revert the merge commit against,
+// i.e. which side of the merge to keep
+func (gui *Gui) createRevertMergeMenu(commit *commands.Commit, parents []*commands.Commit) error {
+	options := make([]*parentOption, len(parents))
+	for i, parent := range parents {
+		options[i] = &parentOption{number: i + 1, commit: parent}
+	}
+
+	handleMenuPress := func(index int) error {
+		return gui.revertCommit(commit.Sha, options[index].number)
+	}
+
+	title := gui.Tr.TemplateLocalize("RevertMergeTitle", Teml{"sha": commit.Sha})
+	return gui.createMenu(title, options, len(options), handleMenuPress)
+}
+
+// revertCommit reverts the commit, against the given parent if it's a merge.
+// Conflicts are handled like they are in a rebase or merge
+func (gui *Gui) revertCommit(sha string, mainline int) error {
+	return gui.WithWaitingStatus(gui.Tr.SLocalize("RevertingStatus"), func() error {
+		err := gui.GitCommand.Revert(sha, mainline)
+		if err == nil {
+			// the revert commit goes on top, pushing the one we reverted down
+			gui.State.Panels.Commits.SelectedLine++
+		}
+		return gui.handleGenericMergeCommandResult(err)
+	})
+}
+
+type copyOption struct {
+	description string
+	getText     func() (string, error)
+}
+
+// GetDisplayStrings is a function.
+func (o *copyOption) GetDisplayStrings(isFocused bool) []string {
+	return []string{o.description}
+}
+
+// handleCreateCopyCommitMenu offers to copy the selected commit's sha, short or
+// in full, its subject or its whole message to the clipboard
+func (gui *Gui) handleCreateCopyCommitMenu(g *gocui.Gui, v *gocui.View) error {
+	commit := gui.getSelectedCommit(g)
+	if commit == nil {
+		return nil
+	}
+
+	getSubject := func() (string, error) {
+		message, err := gui.GitCommand.GetCommitMessage(commit.Sha)
+		return strings.Split(message, "\n")[0], err
+	}
+	options := []*copyOption{
+		{description: gui.Tr.SLocalize("copyCommitShortSha"), getText: func() (string, error) { return commit.Sha, nil }},
+		{description: gui.Tr.SLocalize("copyCommitFullSha"), getText: func() (string, error) { return gui.GitCommand.GetFullSha(commit.Sha) }},
+		{description: gui.Tr.SLocalize("copyCommitSubject"), getText: getSubject},
+		{description: gui.Tr.SLocalize("copyCommitMessage"), getText: func() (string, error) { return gui.GitCommand.GetCommitMessage(commit.Sha) }},
+	}
+
+	handleMenuPress := func(index int) error {
+		text, err := options[index].getText()
+		if err != nil {
+			return gui.createErrorPanel(gui.g, err.Error())
+		}
+		return gui.copyToClipboard(text)
+	}
+
+	title := gui.Tr.TemplateLocalize("CopyCommitTitle", Teml{"sha": commit.Sha})
+	return gui.createMenu(title, options, len(options), handleMenuPress)
 }
 
 func (gui *Gui) handleCopyCommit(g *gocui.Gui, v *gocui.View) error {
@@ -439,8 +721,14 @@ func (gui *Gui) handleCopyCommitRange(g *gocui.Gui, v *gocui.View) error {
 	return gui.refreshCommits(gui.g)
 }
 
-// HandlePasteCommits begins a cherry-pick rebase with the commits the user has copied
+// HandlePasteCommits cherry-picks the commits the user has copied onto HEAD
 func (gui *Gui) HandlePasteCommits(g *gocui.Gui, v *gocui.View) error {
+	if readOnly, err := gui.handleCommitsReadOnly(); readOnly {
+		return err
+	}
+	if len(gui.State.CherryPickedCommits) == 0 {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("NoCopiedCommits"))
+	}
 	return gui.createConfirmationPanel(g, v, gui.Tr.SLocalize("CherryPick"), gui.Tr.SLocalize("SureCherryPick"), func(g *gocui.Gui, v *gocui.View) error {
 		return gui.WithWaitingStatus(gui.Tr.SLocalize("CherryPickingStatus"), func() error {
 			err := gui.GitCommand.CherryPickCommits(gui.State.CherryPickedCommits)
@@ -450,6 +738,7 @@ func (gui *Gui) HandlePasteCommits(g *gocui.Gui, v *gocui.View) error {
 }
 
 func (gui *Gui) handleSwitchToCommitFilesPanel(g *gocui.Gui, v *gocui.View) error {
+	gui.State.Panels.CommitFiles.ComparedRef = ""
 	if err := gui.refreshCommitFilesView(); err != nil {
 		return err
 	}
@@ -493,18 +782,29 @@ func (gui *Gui) handleToggleDiffCommit(g *gocui.Gui, v *gocui.View) error {
 }
 
 func (gui *Gui) setDiffMode() {
-	v := gui.getCommitsView()
-	if len(gui.State.DiffEntries) != 0 {
-		gui.State.Panels.Commits.SpecificDiffMode = true
-		v.Title = gui.Tr.SLocalize("CommitsDiffTitle")
-	} else {
-		gui.State.Panels.Commits.SpecificDiffMode = false
-		v.Title = gui.Tr.SLocalize("CommitsTitle")
-	}
+	gui.State.Panels.Commits.SpecificDiffMode = len(gui.State.DiffEntries) != 0
 
 	gui.refreshCommits(gui.g)
 }
 
+// commitsTitle says if the commits panel is diffing commits or only showing
+// some of them
+func (gui *Gui) commitsTitle() string {
+	if gui.State.Panels.Commits.SpecificDiffMode {
+		return gui.Tr.SLocalize("CommitsDiffTitle")
+	}
+	if gui.State.BrowsingBranch != "" {
+		return gui.Tr.TemplateLocalize("CommitsOfBranchTitle", Teml{"branch": gui.State.BrowsingBranch})
+	}
+	if gui.State.FilterPath != "" {
+		return gui.Tr.TemplateLocalize("CommitsFilteredTitle", Teml{"path": gui.State.FilterPath})
+	}
+	if gui.State.Bisect != nil {
+		return gui.Tr.SLocalize("CommitsTitle") + " " + gui.bisectProgress()
+	}
+	return gui.Tr.SLocalize("CommitsTitle")
+}
+
 func (gui *Gui) hasCommit(commits []*commands.Commit, target string) (int, bool) {
 	for idx, commit := range commits {
 		if commit.Sha == target {
@@ -518,27 +818,60 @@ func (gui *Gui) unchooseCommit(commits []*commands.Commit, i int) []*commands.Co
 	return append(commits[:i], commits[i+1:]...)
 }
 
+type fixupOption struct {
+	subject     string
+	description string
+	create      func(sha string) error
+}
+
+// GetDisplayStrings is a function.
+func (o *fixupOption) GetDisplayStrings(isFocused bool) []string {
+	return []string{o.subject, o.description}
+}
+
+// handleCreateFixupCommit commits what's staged as a fixup! or squash! commit
+// for the selected commit, for squashing into it later with
+// handleSquashAllAboveFixupCommits
 func (gui *Gui) handleCreateFixupCommit(g *gocui.Gui, v *gocui.View) error {
+	if readOnly, err := gui.handleCommitsReadOnly(); readOnly {
+		return err
+	}
 	commit := gui.getSelectedCommit(g)
 	if commit == nil {
 		return nil
 	}
+	if len(gui.stagedFiles()) == 0 {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("NoStagedFilesToCommit"))
+	}
 
-	return gui.createConfirmationPanel(g, v, gui.Tr.SLocalize("CreateFixupCommit"), gui.Tr.TemplateLocalize(
-		"SureCreateFixupCommit",
-		Teml{
-			"commit": commit.Sha,
+	options := []*fixupOption{
+		{
+			subject:     "fixup! " + commit.Name,
+			description: gui.Tr.SLocalize("fixupCommitDescription"),
+			create:      gui.GitCommand.CreateFixupCommit,
 		},
-	), func(g *gocui.Gui, v *gocui.View) error {
-		if err := gui.GitCommand.CreateFixupCommit(commit.Sha); err != nil {
+		{
+			subject:     "squash! " + commit.Name,
+			description: gui.Tr.SLocalize("squashCommitDescription"),
+			create:      gui.GitCommand.CreateSquashCommit,
+		},
+	}
+
+	handleMenuPress := func(index int) error {
+		if err := options[index].create(commit.Sha); err != nil {
 			return gui.createErrorPanel(g, err.Error())
 		}
-
 		return gui.refreshSidePanels(gui.g)
-	}, nil)
+	}
+
+	title := gui.Tr.TemplateLocalize("CreateFixupCommitTitle", Teml{"commit": commit.Sha})
+	return gui.createMenu(title, options, len(options), handleMenuPress)
 }
 
 func (gui *Gui) handleSquashAllAboveFixupCommits(g *gocui.Gui, v *gocui.View) error {
+	if filtered, err := gui.handleRebaseNotAllowed(); filtered {
+		return err
+	}
 	commit := gui.getSelectedCommit(g)
 	if commit == nil {
 		return nil
@@ -568,6 +901,9 @@ func (r *resetOption) GetDisplayStrings(isFocused bool) []string {
 }
 
 func (gui *Gui) handleCreateCommitResetMenu(g *gocui.Gui, v *gocui.View) error {
+	if readOnly, err := gui.handleCommitsReadOnly(); readOnly {
+		return err
+	}
 	commit := gui.getSelectedCommit(g)
 	if commit == nil {
 		return gui.createErrorPanel(gui.g, gui.Tr.SLocalize("NoCommitsThisBranch"))
@@ -583,23 +919,186 @@ func (gui *Gui) handleCreateCommitResetMenu(g *gocui.Gui, v *gocui.View) error {
 	}
 
 	handleMenuPress := func(index int) error {
-		if err := gui.GitCommand.ResetToCommit(commit.Sha, strengths[index]); err != nil {
-			return err
-		}
+		strength := strengths[index]
+		title := strings.Title(gui.Tr.TemplateLocalize("ResetTitle", Teml{"strength": strength}))
+		prompt := gui.Tr.TemplateLocalize("Sure"+strings.Title(strength)+"Reset", Teml{"commit": commit.Sha})
+		return gui.createConfirmationPanel(g, v, title, prompt, func(g *gocui.Gui, _ *gocui.View) error {
+			if err := gui.GitCommand.ResetToCommit(commit.Sha, strength); err != nil {
+				return gui.createErrorPanel(g, err.Error())
+			}
+
+			if err := gui.refreshCommits(g); err != nil {
+				return err
+			}
+			if err := gui.refreshFiles(); err != nil {
+				return err
+			}
+			if err := gui.resetOrigin(gui.getCommitsView()); err != nil {
+				return err
+			}
+
+			gui.State.Panels.Commits.SelectedLine = 0
+			return gui.handleCommitSelect(g, gui.getCommitsView())
+		}, nil)
+	}
 
-		if err := gui.refreshCommits(g); err != nil {
-			return err
+	return gui.createMenu(fmt.Sprintf("%s %s", gui.Tr.SLocalize("resetTo"), commit.Sha), options, len(options), handleMenuPress)
+}
+
+// handleTagCommit asks for a tag name and then for a message to annotate the
+// tag with, which can be left empty for a lightweight tag, and tags the
+// selected commit. Then it offers to push the tag
+func (gui *Gui) handleTagCommit(g *gocui.Gui, v *gocui.View) error {
+	commit := gui.getSelectedCommit(g)
+	if commit == nil {
+		return nil
+	}
+
+	return gui.createPromptPanel(g, v, gui.Tr.SLocalize("TagNameTitle"), func(g *gocui.Gui, promptView *gocui.View) error {
+		tagName := gui.trimmedContent(promptView)
+		if tagName == "" {
+			return nil
 		}
-		if err := gui.refreshFiles(); err != nil {
-			return err
+		// the name prompt has to close before we can open the message prompt
+		g.Update(func(g *gocui.Gui) error {
+			return gui.createPromptPanel(g, v, gui.Tr.SLocalize("TagMessageTitle"), func(g *gocui.Gui, promptView *gocui.View) error {
+				if err := gui.GitCommand.CreateTag(tagName, commit.Sha, gui.trimmedContent(promptView)); err != nil {
+					return gui.createErrorPanel(g, err.Error())
+				}
+				if err := gui.refreshCommits(g); err != nil {
+					return err
+				}
+				prompt := gui.Tr.TemplateLocalize("PushTagPrompt", Teml{"tagName": tagName})
+				return gui.createConfirmationPanel(g, v, gui.Tr.SLocalize("PushTagTitle"), prompt, func(g *gocui.Gui, v *gocui.View) error {
+					return gui.pushTag(v, tagName)
+				}, nil)
+			})
+		})
+		return nil
+	})
+}
+
+func (gui *Gui) pushTag(v *gocui.View, tagName string) error {
+	if err := gui.createLoaderPanel(gui.g, v, gui.Tr.SLocalize("PushWait")); err != nil {
+		return err
+	}
+	go func() {
+		unamePassOpened := false
+		err := gui.GitCommand.PushTag(tagName, func(passOrUname string) string {
+			unamePassOpened = true
+			return gui.waitForPassUname(gui.g, v, passOrUname)
+		})
+		gui.HandleCredentialsPopup(gui.g, unamePassOpened, err)
+	}()
+	return nil
+}
+
+// handleCheckoutCommit checks out the selected commit, which leaves HEAD
+// detached, and then offers to create a branch there so that the commits you
+// make on top of it aren't lost when you check out something else
+func (gui *Gui) handleCheckoutCommit(g *gocui.Gui, v *gocui.View) error {
+	commit := gui.getSelectedCommit(g)
+	if commit == nil {
+		return nil
+	}
+
+	prompt := gui.Tr.TemplateLocalize("SureCheckoutCommit", Teml{"sha": commit.Sha})
+	return gui.createConfirmationPanel(g, v, gui.Tr.SLocalize("CheckoutCommit"), prompt, func(g *gocui.Gui, _ *gocui.View) error {
+		return gui.confirmLeavingDetachedHead(v, func() error {
+			return gui.checkoutRef(v, commit.Sha, func() error {
+				gui.State.Panels.Commits.SelectedLine = 0
+				if err := gui.refreshCommits(gui.g); err != nil {
+					return err
+				}
+				return gui.offerBranchAtDetachedHead(v, commit.Sha)
+			})
+		})
+	}, nil)
+}
+
+// handleNewBranchAtCommit makes a new branch off the selected commit
+func (gui *Gui) handleNewBranchAtCommit(g *gocui.Gui, v *gocui.View) error {
+	commit := gui.getSelectedCommit(g)
+	if commit == nil || commit.Status == "rebasing" {
+		return nil
+	}
+	return gui.promptNewBranchAt(v, commit.Sha)
+}
+
+func (gui *Gui) offerBranchAtDetachedHead(v *gocui.View, sha string) error {
+	prompt := gui.Tr.TemplateLocalize("DetachedHeadPrompt", Teml{"sha": sha})
+	return gui.createConfirmationPanel(gui.g, v, gui.Tr.SLocalize("DetachedHeadTitle"), prompt, func(g *gocui.Gui, _ *gocui.View) error {
+		// the confirmation has to close before we can open the prompt
+		g.Update(func(g *gocui.Gui) error {
+			message := gui.Tr.TemplateLocalize("NewBranchNameBranchOff", Teml{"branchName": sha})
+			return gui.createPromptPanel(g, v, message, func(g *gocui.Gui, promptView *gocui.View) error {
+				if err := gui.GitCommand.NewBranch(gui.trimmedContent(promptView)); err != nil {
+					return gui.createErrorPanel(g, err.Error())
+				}
+				return gui.refreshSidePanels(g)
+			})
+		})
+		return nil
+	}, nil)
+}
+
+type commitDateOption struct {
+	description string
+	onPress     func() error
+}
+
+// GetDisplayStrings is a function.
+func (o *commitDateOption) GetDisplayStrings(isFocused bool) []string {
+	return []string{o.description}
+}
+
+// handleCreateCommitDateMenu lets you choose how each commit's date is shown
+// in the commits panel, if at all, and whether it's the author or committer
+// date. The choice is saved to the user config so it sticks
+func (gui *Gui) handleCreateCommitDateMenu(g *gocui.Gui, v *gocui.View) error {
+	userConfig := gui.Config.GetUserConfig()
+	setOption := func(key string, value string) error {
+		userConfig.Set(key, value)
+		if err := gui.Config.WriteToUserConfig(key, value); err != nil {
+			return gui.createErrorPanel(g, err.Error())
 		}
-		if err := gui.resetOrigin(gui.getCommitsView()); err != nil {
-			return err
+		return gui.refreshCommits(g)
+	}
+
+	descriptions := map[string]string{
+		commands.CommitDateNone:     gui.Tr.SLocalize("hideCommitDates"),
+		commands.CommitDateRelative: gui.Tr.SLocalize("showRelativeCommitDates"),
+		commands.CommitDateShort:    gui.Tr.SLocalize("showShortCommitDates"),
+		commands.CommitDateFull:     gui.Tr.SLocalize("showFullCommitDates"),
+	}
+	currentFormat := userConfig.GetString("gui.commitDate.format")
+	options := []*commitDateOption{}
+	for _, format := range commands.CommitDateFormats {
+		format := format
+		description := descriptions[format]
+		if format == currentFormat {
+			description += " " + utils.ColoredString("✓", color.FgGreen)
 		}
+		options = append(options, &commitDateOption{
+			description: description,
+			onPress:     func() error { return setOption("gui.commitDate.format", format) },
+		})
+	}
 
-		gui.State.Panels.Commits.SelectedLine = 0
-		return gui.handleCommitSelect(g, gui.getCommitsView())
+	source := commands.CommitDateSourceCommitter
+	sourceDescription := gui.Tr.SLocalize("useCommitterDates")
+	if userConfig.GetString("gui.commitDate.source") == commands.CommitDateSourceCommitter {
+		source = commands.CommitDateSourceAuthor
+		sourceDescription = gui.Tr.SLocalize("useAuthorDates")
 	}
+	options = append(options, &commitDateOption{
+		description: sourceDescription,
+		onPress:     func() error { return setOption("gui.commitDate.source", source) },
+	})
 
-	return gui.createMenu(fmt.Sprintf("%s %s", gui.Tr.SLocalize("resetTo"), commit.Sha), options, len(options), handleMenuPress)
+	handleMenuPress := func(index int) error {
+		return options[index].onPress()
+	}
+
+	return gui.createMenu(gui.Tr.SLocalize("CommitDateTitle"), options, len(options), handleMenuPress)
 }
@@ -0,0 +1,72 @@
+package gui
+
+import (
+	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazygit/pkg/commands"
+)
+
+// a branch can be marked as the base that the other branches are compared
+// against, showing what each would bring in if merged into it, like a pull
+// request would
+
+// handleToggleCompareBase marks the selected branch as the base to compare
+// the other branches against, or unmarks it if it already is
+func (gui *Gui) handleToggleCompareBase(g *gocui.Gui, v *gocui.View) error {
+	branch := gui.getSelectedBranch()
+	if branch == nil || branch.Detached {
+		return nil
+	}
+	if gui.State.CompareBase == branch.Name {
+		return gui.setCompareBase("")
+	}
+	return gui.setCompareBase(branch.Name)
+}
+
+func (gui *Gui) setCompareBase(name string) error {
+	gui.State.CompareBase = name
+	branchesView := gui.getBranchesView()
+	gui.setBranchesTabs()
+	return gui.handleBranchSelect(gui.g, branchesView)
+}
+
+// comparingBranch tells us whether the branch is being compared against the
+// compare base, which is every branch but the base itself once there is one
+func (gui *Gui) comparingBranch(branch *commands.Branch) bool {
+	return gui.State.CompareBase != "" && gui.State.CompareBase != branch.Name && !branch.Detached
+}
+
+// renderComparison shows the commits the branch has that the compare base
+// doesn't, followed by their diff
+func (gui *Gui) renderComparison(branch *commands.Branch) error {
+	base := gui.State.CompareBase
+	go func() {
+		comparison, err := gui.GitCommand.CompareRefs(base, branch.Name)
+		if err != nil {
+			comparison = err.Error()
+		}
+		_ = gui.renderString(gui.g, "main", comparison)
+	}()
+	return nil
+}
+
+// showCompareFiles lists the files the branch has changed since it branched
+// off the compare base in the commit files panel, so that we can go through
+// their diffs one at a time
+func (gui *Gui) showCompareFiles(branch *commands.Branch) error {
+	gui.State.Panels.CommitFiles.ComparedRef = branch.Name
+	gui.State.Panels.CommitFiles.SelectedLine = 0
+	if err := gui.refreshCommitFilesView(); err != nil {
+		return err
+	}
+	return gui.switchFocus(gui.g, gui.g.CurrentView(), gui.getCommitFilesView())
+}
+
+// handleCompareFilesReadOnly refuses to change the files listed in the commit
+// files panel when they're from comparing two branches, as they don't belong
+// to a single commit. It returns true if it did
+func (gui *Gui) handleCompareFilesReadOnly() (bool, error) {
+	if gui.State.Panels.CommitFiles.ComparedRef != "" {
+		return true, gui.createErrorPanel(gui.g, gui.Tr.SLocalize("CantChangeComparedFiles"))
+	}
+	return false, nil
+}
@@ -0,0 +1,63 @@
+package gui
+
+import (
+	"github.com/fatih/color"
+	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+)
+
+type containingRefOption struct {
+	kind string
+	name string
+}
+
+// GetDisplayStrings is a function.
+func (o *containingRefOption) GetDisplayStrings(isFocused bool) []string {
+	kindColor := color.FgGreen
+	if o.kind == "tag" {
+		kindColor = color.FgYellow
+	}
+	return []string{utils.ColoredString(o.kind, kindColor), o.name}
+}
+
+// handleShowContainingRefs lists the branches and tags the selected commit is
+// part of, telling you whether it has been merged or released yet. Choosing
+// one shows its commits
+func (gui *Gui) handleShowContainingRefs(g *gocui.Gui, v *gocui.View) error {
+	commit := gui.getSelectedCommit(g)
+	if commit == nil || commit.Status == "rebasing" {
+		return nil
+	}
+
+	branches, err := gui.GitCommand.GetBranchesContaining(commit.Sha)
+	if err != nil {
+		return gui.createErrorPanel(g, err.Error())
+	}
+	tags, err := gui.GitCommand.GetTagsContaining(commit.Sha)
+	if err != nil {
+		return gui.createErrorPanel(g, err.Error())
+	}
+	if len(branches)+len(tags) == 0 {
+		return gui.createErrorPanel(g, gui.Tr.TemplateLocalize("NoRefsContainCommit", Teml{"sha": commit.Sha}))
+	}
+
+	options := []*containingRefOption{}
+	for _, branch := range branches {
+		options = append(options, &containingRefOption{kind: "branch", name: branch})
+	}
+	for _, tag := range tags {
+		options = append(options, &containingRefOption{kind: "tag", name: tag})
+	}
+
+	handleMenuPress := func(index int) error {
+		name := options[index].name
+		// the checked out branch's commits are the ones we show anyway
+		if len(gui.State.Branches) > 0 && name == gui.State.Branches[0].Name {
+			name = ""
+		}
+		return gui.setBrowsingBranch(name)
+	}
+
+	title := gui.Tr.TemplateLocalize("ContainingRefsTitle", Teml{"sha": commit.Sha})
+	return gui.createMenu(title, options, len(options), handleMenuPress)
+}
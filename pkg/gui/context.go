@@ -2,20 +2,23 @@ package gui
 
 func (gui *Gui) titleMap() map[string]string {
 	return map[string]string{
-		"commits":  gui.Tr.SLocalize("DiffTitle"),
-		"branches": gui.Tr.SLocalize("LogTitle"),
-		"files":    gui.Tr.SLocalize("DiffTitle"),
-		"status":   "",
-		"stash":    gui.Tr.SLocalize("DiffTitle"),
+		"commits":        gui.Tr.SLocalize("DiffTitle"),
+		"branches":       gui.Tr.SLocalize("LogTitle"),
+		"files":          gui.Tr.SLocalize("DiffTitle"),
+		"status":         "",
+		"stash":          gui.Tr.SLocalize("DiffTitle"),
+		"reflog":         gui.Tr.SLocalize("DiffTitle"),
+		"remoteBranches": gui.Tr.SLocalize("LogTitle"),
 	}
 }
 
 func (gui *Gui) contextTitleMap() map[string]map[string]string {
 	return map[string]map[string]string{
 		"main": {
-			"staging": gui.Tr.SLocalize("StagingMainTitle"),
-			"merging": gui.Tr.SLocalize("MergingMainTitle"),
-			"normal":  "",
+			"staging":       gui.Tr.SLocalize("StagingMainTitle"),
+			"merging":       gui.Tr.SLocalize("MergingMainTitle"),
+			"patchBuilding": gui.Tr.SLocalize("PatchBuildingMainTitle"),
+			"normal":        "",
 		},
 	}
 }
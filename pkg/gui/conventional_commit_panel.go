@@ -0,0 +1,104 @@
+package gui
+
+import (
+	"fmt"
+
+	"github.com/jesseduffield/gocui"
+)
+
+type conventionalCommitOption struct {
+	name    string
+	onPress func() error
+}
+
+// GetDisplayStrings is a function.
+func (o *conventionalCommitOption) GetDisplayStrings(isFocused bool) []string {
+	return []string{o.name}
+}
+
+// handleConventionalCommitPress starts a conventional commit, whose message is
+// like "type(scope): subject". You pick the type and then the scope from menus,
+// and then write the rest of the message in the commit message panel
+func (gui *Gui) handleConventionalCommitPress(g *gocui.Gui, filesView *gocui.View) error {
+	if len(gui.stagedFiles()) == 0 && !gui.operationInProgress() {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("NoStagedFilesToCommit"))
+	}
+
+	options := []*conventionalCommitOption{}
+	for _, commitType := range gui.GitCommand.ConventionalCommitTypes() {
+		commitType := commitType
+		options = append(options, &conventionalCommitOption{
+			name: commitType,
+			onPress: func() error {
+				// the type menu has to close before we can open the scope menu
+				g.Update(func(g *gocui.Gui) error {
+					return gui.createConventionalCommitScopeMenu(filesView, commitType)
+				})
+				return nil
+			},
+		})
+	}
+
+	handleMenuPress := func(index int) error {
+		return options[index].onPress()
+	}
+
+	return gui.createMenu(gui.Tr.SLocalize("ConventionalCommitTypeTitle"), options, len(options), handleMenuPress)
+}
+
+// createConventionalCommitScopeMenu offers no scope, the scopes that recent
+// commits have used, or any other scope you type in
+func (gui *Gui) createConventionalCommitScopeMenu(filesView *gocui.View, commitType string) error {
+	scopes, err := gui.GitCommand.GetConventionalCommitScopes()
+	if err != nil {
+		return gui.createErrorPanel(gui.g, err.Error())
+	}
+
+	options := []*conventionalCommitOption{
+		{
+			name:    gui.Tr.SLocalize("noScope"),
+			onPress: func() error { return gui.startConventionalCommit(filesView, commitType+": ") },
+		},
+		{
+			name: gui.Tr.SLocalize("enterScope"),
+			onPress: func() error {
+				return gui.createPromptPanel(gui.g, filesView, gui.Tr.SLocalize("ConventionalCommitScopeTitle"), func(g *gocui.Gui, promptView *gocui.View) error {
+					return gui.startConventionalCommit(filesView, fmt.Sprintf("%s(%s): ", commitType, gui.trimmedContent(promptView)))
+				})
+			},
+		},
+	}
+	for _, scope := range scopes {
+		scope := scope
+		options = append(options, &conventionalCommitOption{
+			name: scope,
+			onPress: func() error {
+				return gui.startConventionalCommit(filesView, fmt.Sprintf("%s(%s): ", commitType, scope))
+			},
+		})
+	}
+
+	handleMenuPress := func(index int) error {
+		return options[index].onPress()
+	}
+
+	title := gui.Tr.TemplateLocalize("ConventionalCommitScopeMenuTitle", Teml{"type": commitType})
+	return gui.createMenu(title, options, len(options), handleMenuPress)
+}
+
+// startConventionalCommit opens the commit message panel with the type and
+// scope already written, for you to write the subject after them
+func (gui *Gui) startConventionalCommit(filesView *gocui.View, prefix string) error {
+	// whatever we're picking the scope from has to close first
+	gui.g.Update(func(g *gocui.Gui) error {
+		commitMessageView := gui.getCommitMessageView()
+		if err := gui.setViewContent(g, commitMessageView, prefix); err != nil {
+			return err
+		}
+		if err := commitMessageView.SetCursor(len(prefix), 0); err != nil {
+			return err
+		}
+		return gui.openCommitMessagePanel(g, filesView, "")
+	})
+	return nil
+}
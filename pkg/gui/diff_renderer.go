@@ -0,0 +1,153 @@
+package gui
+
+import (
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+)
+
+// renderDiff colors a plain diff the way git would, but additionally
+// highlights the characters that changed between a removed line and the
+// added line that replaced it, in the style of git's diff-highlight script.
+// If syntax highlighting is enabled, context lines are coloured according to
+// the language of the file they belong to.
+// Carriage returns are dropped because the view would treat them as an
+// instruction to clear the line, leaving files with CRLF endings blank.
+// Combined diffs of merge commits, which have a column of +/- for each parent,
+// are coloured line by line
+func (gui *Gui) renderDiff(diff string) string {
+	lines := strings.Split(strings.Replace(diff, "\r\n", "\n", -1), "\n")
+	output := make([]string, 0, len(lines))
+
+	var syntax *syntaxDefinition
+	// the number of parents of the combined diff we're in, if we're in one
+	combinedParents := 0
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		if strings.HasPrefix(line, "+++ ") {
+			syntax = syntaxForFile(strings.TrimPrefix(line, "+++ "))
+		}
+		if strings.HasPrefix(line, "diff ") {
+			combinedParents = 0
+		} else if strings.HasPrefix(line, "@@@") {
+			combinedParents = len(line) - len(strings.TrimLeft(line, "@")) - 1
+		}
+		if combinedParents > 0 {
+			output = append(output, renderCombinedDiffLine(line, combinedParents))
+			i++
+			continue
+		}
+		if gui.State.SyntaxHighlighting && syntax != nil && strings.HasPrefix(line, " ") {
+			output = append(output, " "+syntax.highlight(line[1:]))
+			i++
+			continue
+		}
+		if !strings.HasPrefix(line, "-") || strings.HasPrefix(line, "---") {
+			output = append(output, renderDiffLine(line))
+			i++
+			continue
+		}
+
+		// find the block of removals and the block of additions that follows it
+		removedStart := i
+		for i < len(lines) && strings.HasPrefix(lines[i], "-") {
+			i++
+		}
+		addedStart := i
+		for i < len(lines) && strings.HasPrefix(lines[i], "+") {
+			i++
+		}
+		removed := lines[removedStart:addedStart]
+		added := lines[addedStart:i]
+
+		// like diff-highlight, we only pair up lines when the blocks are the same
+		// size, otherwise we can't know which line replaced which
+		if len(removed) != len(added) {
+			for _, blockLine := range lines[removedStart:i] {
+				output = append(output, renderDiffLine(blockLine))
+			}
+			continue
+		}
+
+		renderedAdded := make([]string, len(added))
+		for j := range removed {
+			var renderedRemoved string
+			renderedRemoved, renderedAdded[j] = renderChangedLinePair(removed[j], added[j])
+			output = append(output, renderedRemoved)
+		}
+		output = append(output, renderedAdded...)
+	}
+
+	return strings.Join(output, "\n")
+}
+
+// renderDiffLine colors a single line of a diff with no intra-line highlighting
+func renderDiffLine(line string) string {
+	switch {
+	case strings.HasPrefix(line, "diff --git"), strings.HasPrefix(line, "index "),
+		strings.HasPrefix(line, "---"), strings.HasPrefix(line, "+++"):
+		return utils.ColoredString(line, color.Bold)
+	case strings.HasPrefix(line, "@@"):
+		return utils.ColoredString(line, color.FgCyan)
+	case strings.HasPrefix(line, "-"):
+		return utils.ColoredString(line, color.FgRed)
+	case strings.HasPrefix(line, "+"):
+		return utils.ColoredString(line, color.FgGreen)
+	default:
+		return line
+	}
+}
+
+// renderCombinedDiffLine colors a line of a combined diff, which starts with a
+// column for each parent saying whether the line was added or removed relative
+// to that parent
+func renderCombinedDiffLine(line string, parents int) string {
+	if strings.HasPrefix(line, "@@@") || len(line) < parents {
+		return renderDiffLine(line)
+	}
+	columns := line[:parents]
+	switch {
+	case strings.Contains(columns, "-"):
+		return utils.ColoredString(line, color.FgRed)
+	case strings.Contains(columns, "+"):
+		return utils.ColoredString(line, color.FgGreen)
+	default:
+		return line
+	}
+}
+
+// renderChangedLinePair colors a removed line and the added line that replaced
+// it, highlighting the part of each line that differs from the other
+func renderChangedLinePair(removed string, added string) (string, string) {
+	// skip the leading '-' and '+'
+	oldRunes := []rune(removed[1:])
+	newRunes := []rune(added[1:])
+
+	prefixLength := 0
+	for prefixLength < len(oldRunes) && prefixLength < len(newRunes) && oldRunes[prefixLength] == newRunes[prefixLength] {
+		prefixLength++
+	}
+
+	suffixLength := 0
+	for suffixLength < len(oldRunes)-prefixLength && suffixLength < len(newRunes)-prefixLength &&
+		oldRunes[len(oldRunes)-1-suffixLength] == newRunes[len(newRunes)-1-suffixLength] {
+		suffixLength++
+	}
+
+	// if the lines have nothing in common, highlighting the whole line tells us
+	// nothing we didn't already know
+	if prefixLength == 0 && suffixLength == 0 {
+		return renderDiffLine(removed), renderDiffLine(added)
+	}
+
+	return renderHighlightedLine("-", oldRunes, prefixLength, suffixLength, color.FgRed),
+		renderHighlightedLine("+", newRunes, prefixLength, suffixLength, color.FgGreen)
+}
+
+func renderHighlightedLine(prefix string, runes []rune, prefixLength int, suffixLength int, colorAttribute color.Attribute) string {
+	changedEnd := len(runes) - suffixLength
+	return utils.ColoredString(prefix+string(runes[:prefixLength]), colorAttribute) +
+		utils.ColoredStringDirect(string(runes[prefixLength:changedEnd]), color.New(colorAttribute, color.ReverseVideo)) +
+		utils.ColoredString(string(runes[changedEnd:]), colorAttribute)
+}
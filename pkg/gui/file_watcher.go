@@ -0,0 +1,169 @@
+package gui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// fileWatcherDebounce is how long the repo has to go without changing before
+// we refresh, so that something like a checkout touching many files only
+// causes one refresh
+const fileWatcherDebounce = 200 * time.Millisecond
+
+// maxIgnoreCheckPaths is how many changed paths we'll ask git about before
+// giving up and assuming that some of them matter
+const maxIgnoreCheckPaths = 100
+
+// startFileWatcher watches the working tree and the .git directory, and
+// refreshes the panels when something changes them outside of lazygit.
+// Directories that git ignores aren't watched. It returns an error if the repo
+// can't be watched, e.g. because it has more directories than the OS lets us
+// watch, in which case the caller should fall back to polling
+func (gui *Gui) startFileWatcher() (*fsnotify.Watcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	// keyed with forward slashes, so that they match the walked paths on
+	// Windows too
+	ignoredDirectories := map[string]bool{}
+	for _, directory := range gui.GitCommand.GetIgnoredDirectories() {
+		ignoredDirectories[filepath.ToSlash(filepath.Clean(directory))] = true
+	}
+	if err := gui.watchDirectory(watcher, ".", ignoredDirectories); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+	// the .git directory itself tells us about the index, HEAD and any merge or
+	// rebase in progress, and refs/ about branches, tags and the stash. We leave
+	// out objects/ and logs/, which change along with those
+	dotGitDir := filepath.Clean(gui.GitCommand.DotGitDir)
+	if err := watcher.Add(dotGitDir); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+	if err := gui.watchDirectory(watcher, filepath.Join(dotGitDir, "refs"), nil); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+
+	go gui.handleFileWatcherEvents(watcher, dotGitDir)
+	return watcher, nil
+}
+
+// watchDirectory watches the directory and every directory inside it, except
+// for .git directories and those in ignoredDirectories
+func (gui *Gui) watchDirectory(watcher *fsnotify.Watcher, root string, ignoredDirectories map[string]bool) error {
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			// the directory may have been removed since we listed it
+			return nil
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		path = filepath.Clean(path)
+		if path != root && (info.Name() == ".git" || ignoredDirectories[filepath.ToSlash(path)]) {
+			return filepath.SkipDir
+		}
+		return watcher.Add(path)
+	})
+}
+
+func (gui *Gui) handleFileWatcherEvents(watcher *fsnotify.Watcher, dotGitDir string) {
+	changedPaths := map[string]bool{}
+	indexChanged := false
+	refsChanged := false
+	var debounce <-chan time.Time
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			path := filepath.Clean(event.Name)
+			// git creates lock files alongside whatever it's about to change, and
+			// we'll hear about the change itself soon enough
+			if strings.HasSuffix(path, ".lock") {
+				continue
+			}
+			if path == dotGitDir || strings.HasPrefix(path, dotGitDir+string(filepath.Separator)) {
+				if filepath.Base(path) == "index" {
+					indexChanged = true
+				} else {
+					refsChanged = true
+				}
+			} else {
+				changedPaths[path] = true
+				if event.Op&fsnotify.Create != 0 {
+					gui.watchNewDirectory(watcher, path)
+				}
+			}
+			debounce = time.After(fileWatcherDebounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			gui.Log.Error(err)
+		case <-debounce:
+			debounce = nil
+			if err := gui.refreshForFileChanges(changedPaths, indexChanged, refsChanged); err != nil {
+				gui.Log.Error(err)
+			}
+			changedPaths = map[string]bool{}
+			indexChanged = false
+			refsChanged = false
+		}
+	}
+}
+
+// watchNewDirectory starts watching a directory that's been created in one
+// we're watching, unless git ignores it
+func (gui *Gui) watchNewDirectory(watcher *fsnotify.Watcher, path string) {
+	info, err := os.Stat(path)
+	if err != nil || !info.IsDir() || info.Name() == ".git" {
+		return
+	}
+	if len(gui.GitCommand.GetIgnoredPaths([]string{path})) > 0 {
+		return
+	}
+	if err := gui.watchDirectory(watcher, path, nil); err != nil {
+		gui.Log.Error(err)
+	}
+}
+
+// refreshForFileChanges refreshes whichever panels the changes affect. A change
+// to refs or HEAD can affect any of them, while the index and the working tree
+// only show up in the files panel
+func (gui *Gui) refreshForFileChanges(changedPaths map[string]bool, indexChanged bool, refsChanged bool) error {
+	if refsChanged {
+		return gui.refreshSidePanels(gui.g)
+	}
+	if indexChanged || gui.anyPathNotIgnored(changedPaths) {
+		return gui.refreshFiles()
+	}
+	return nil
+}
+
+// anyPathNotIgnored says whether any of the paths is one git doesn't ignore.
+// Ignored files in directories we watch, like build output next to the
+// source, would otherwise refresh the files panel for nothing
+func (gui *Gui) anyPathNotIgnored(paths map[string]bool) bool {
+	if len(paths) == 0 {
+		return false
+	}
+	if len(paths) > maxIgnoreCheckPaths {
+		return true
+	}
+	pathList := []string{}
+	for path := range paths {
+		pathList = append(pathList, path)
+	}
+	return len(gui.GitCommand.GetIgnoredPaths(pathList)) < len(pathList)
+}
@@ -8,23 +8,39 @@ import (
 	// "strings"
 
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/fatih/color"
 	"github.com/jesseduffield/gocui"
 	"github.com/jesseduffield/lazygit/pkg/commands"
+	"github.com/jesseduffield/lazygit/pkg/git"
 	"github.com/jesseduffield/lazygit/pkg/utils"
 )
 
 // list panel functions
 
-func (gui *Gui) getSelectedFile(g *gocui.Gui) (*commands.File, error) {
+// getSelectedFileNode returns the selected line of the files panel, which may
+// be a directory, or nil if there are no files
+func (gui *Gui) getSelectedFileNode() *commands.FileNode {
 	selectedLine := gui.State.Panels.Files.SelectedLine
 	if selectedLine == -1 {
+		return nil
+	}
+
+	return gui.State.FileNodes[selectedLine]
+}
+
+// getSelectedFile returns the selected file. If a directory is selected there's
+// no file to return, so we return ErrNoFiles as if there were no files at all
+func (gui *Gui) getSelectedFile(g *gocui.Gui) (*commands.File, error) {
+	node := gui.getSelectedFileNode()
+	if node == nil || node.IsDirectory() {
 		return &commands.File{}, gui.Errors.ErrNoFiles
 	}
 
-	return gui.State.Files[selectedLine], nil
+	return node.File, nil
 }
 
 func (gui *Gui) handleFilesFocus(g *gocui.Gui, v *gocui.View) error {
@@ -38,7 +54,7 @@ func (gui *Gui) handleFilesFocus(g *gocui.Gui, v *gocui.View) error {
 	prevSelectedLine := gui.State.Panels.Files.SelectedLine
 	newSelectedLine := cy - oy
 
-	if newSelectedLine > len(gui.State.Files)-1 || len(utils.Decolorise(gui.State.Files[newSelectedLine].DisplayString)) < cx {
+	if newSelectedLine > len(gui.State.FileNodes)-1 || len(utils.Decolorise(gui.State.FileNodes[newSelectedLine].GetDisplayStrings(true)[0])) < cx {
 		return gui.handleFileSelect(gui.g, v, false)
 	}
 
@@ -56,23 +72,35 @@ func (gui *Gui) handleFileSelect(g *gocui.Gui, v *gocui.View, alreadySelected bo
 		return err
 	}
 
-	file, err := gui.getSelectedFile(g)
-	if err != nil {
-		if err != gui.Errors.ErrNoFiles {
-			return err
-		}
+	node := gui.getSelectedFileNode()
+	if node == nil {
 		return gui.renderString(g, "main", gui.Tr.SLocalize("NoChangedFiles"))
 	}
 
-	if err := gui.focusPoint(0, gui.State.Panels.Files.SelectedLine, len(gui.State.Files), v); err != nil {
+	if err := gui.focusPoint(0, gui.State.Panels.Files.SelectedLine, len(gui.State.FileNodes), v); err != nil {
 		return err
 	}
 
-	if file.HasInlineMergeConflicts {
-		return gui.refreshMergePanel()
+	var content string
+	if node.IsDirectory() {
+		content = gui.directoryDiff(node)
+	} else {
+		if node.File.HasInlineMergeConflicts {
+			return gui.refreshMergePanel()
+		}
+		if node.File.SkipWorktree || node.File.AssumeUnchanged {
+			content = gui.Tr.SLocalize("ChangesIgnored")
+		} else if node.File.HasMergeConflicts {
+			content = gui.GitCommand.DescribeMergeConflict(node.File) + "\n\n" + gui.Tr.SLocalize("PressEnterToResolveConflict")
+		} else if node.File.IsSubmodule {
+			content = gui.describeSubmodule(node.File)
+		} else {
+			content = gui.GitCommand.Diff(node.File, false, false, gui.State.IgnoreWhitespace, gui.State.DiffContextSize)
+			if node.File.IsLFS {
+				content = gui.describeLFSDiff(content)
+			}
+		}
 	}
-
-	content := gui.GitCommand.Diff(file, false)
 	if alreadySelected {
 		g.Update(func(*gocui.Gui) error {
 			return gui.setViewContent(gui.g, gui.getMainView(), content)
@@ -82,6 +110,17 @@ func (gui *Gui) handleFileSelect(g *gocui.Gui, v *gocui.View, alreadySelected bo
 	return gui.renderString(g, "main", content)
 }
 
+// handleToggleIgnoreWhitespace toggles whether changes in whitespace are shown
+// in the diff of a file, both here and in the staging panel
+func (gui *Gui) handleToggleIgnoreWhitespace(g *gocui.Gui, v *gocui.View) error {
+	gui.State.IgnoreWhitespace = !gui.State.IgnoreWhitespace
+
+	if gui.State.Panels.Staging != nil {
+		return gui.refreshStagingPanel()
+	}
+	return gui.handleFileSelect(g, v, false)
+}
+
 func (gui *Gui) refreshFiles() error {
 	selectedFile, _ := gui.getSelectedFile(gui.g)
 
@@ -95,10 +134,11 @@ func (gui *Gui) refreshFiles() error {
 	}
 
 	gui.g.Update(func(g *gocui.Gui) error {
+		filesView.Title = gui.filesTitle()
 
 		filesView.Clear()
 		isFocused := gui.g.CurrentView().Name() == "files"
-		list, err := utils.RenderList(gui.State.Files, isFocused)
+		list, err := utils.RenderList(gui.State.FileNodes, isFocused)
 		if err != nil {
 			return err
 		}
@@ -115,13 +155,28 @@ func (gui *Gui) refreshFiles() error {
 	return nil
 }
 
+// filesTitle is the files panel's title, with how many lines have changed in
+// all the files
+func (gui *Gui) filesTitle() string {
+	additions, deletions := 0, 0
+	for _, file := range gui.State.Files {
+		additions += file.Additions
+		deletions += file.Deletions
+	}
+	title := gui.Tr.SLocalize("FilesTitle")
+	if additions == 0 && deletions == 0 {
+		return title
+	}
+	return fmt.Sprintf("%s +%d -%d", title, additions, deletions)
+}
+
 func (gui *Gui) handleFilesNextLine(g *gocui.Gui, v *gocui.View) error {
 	if gui.popupPanelFocused() {
 		return nil
 	}
 
 	panelState := gui.State.Panels.Files
-	gui.changeSelectedLine(&panelState.SelectedLine, len(gui.State.Files), false)
+	gui.changeSelectedLine(&panelState.SelectedLine, len(gui.State.FileNodes), false)
 
 	return gui.handleFileSelect(gui.g, v, false)
 }
@@ -132,7 +187,7 @@ func (gui *Gui) handleFilesPrevLine(g *gocui.Gui, v *gocui.View) error {
 	}
 
 	panelState := gui.State.Panels.Files
-	gui.changeSelectedLine(&panelState.SelectedLine, len(gui.State.Files), true)
+	gui.changeSelectedLine(&panelState.SelectedLine, len(gui.State.FileNodes), true)
 
 	return gui.handleFileSelect(gui.g, v, false)
 }
@@ -170,6 +225,10 @@ func (gui *Gui) stageSelectedFile(g *gocui.Gui) error {
 }
 
 func (gui *Gui) handleEnterFile(g *gocui.Gui, v *gocui.View) error {
+	if node := gui.getSelectedFileNode(); node != nil && node.IsDirectory() {
+		return gui.handleToggleDirectoryCollapsed(node)
+	}
+
 	file, err := gui.getSelectedFile(g)
 	if err != nil {
 		if err != gui.Errors.ErrNoFiles {
@@ -180,7 +239,13 @@ func (gui *Gui) handleEnterFile(g *gocui.Gui, v *gocui.View) error {
 	if file.HasInlineMergeConflicts {
 		return gui.handleSwitchToMerge(g, v)
 	}
-	if !file.HasUnstagedChanges || file.HasMergeConflicts {
+	if file.HasMergeConflicts {
+		return gui.handleCreateConflictMenu(file)
+	}
+	if file.IsSubmodule {
+		return gui.handleCreateSubmoduleMenu(file)
+	}
+	if (!file.HasUnstagedChanges && !file.HasStagedChanges) || file.HasMergeConflicts {
 		return gui.createErrorPanel(g, gui.Tr.SLocalize("FileStagingRequirements"))
 	}
 	if err := gui.changeContext("main", "staging"); err != nil {
@@ -193,6 +258,13 @@ func (gui *Gui) handleEnterFile(g *gocui.Gui, v *gocui.View) error {
 }
 
 func (gui *Gui) handleFilePress(g *gocui.Gui, v *gocui.View) error {
+	if len(gui.State.Panels.Files.MarkedFileNames) > 0 {
+		return gui.handleMarkedFilesPress()
+	}
+	if node := gui.getSelectedFileNode(); node != nil && node.IsDirectory() {
+		return gui.handleDirectoryPress(node)
+	}
+
 	file, err := gui.getSelectedFile(g)
 	if err != nil {
 		if err == gui.Errors.ErrNoFiles {
@@ -215,9 +287,63 @@ func (gui *Gui) handleFilePress(g *gocui.Gui, v *gocui.View) error {
 		return err
 	}
 
+	// staging the last file with conflicts marks them all resolved, so the
+	// operation that stopped at them can carry on
+	if file.HasMergeConflicts && gui.operationInProgress() && !gui.anyFilesWithMergeConflicts() {
+		return gui.promptToContinue()
+	}
+
 	return gui.handleFileSelect(g, v, true)
 }
 
+// handleDirectoryPress stages everything in the directory, or unstages it all
+// if there's nothing left to stage. We don't want to mark conflicts as
+// resolved by accident, so we won't touch a directory with merge conflicts
+func (gui *Gui) handleDirectoryPress(node *commands.FileNode) error {
+	return gui.stageOrUnstage(node, []string{node.Path}, gui.Tr.SLocalize("DirectoryHasMergeConflicts"))
+}
+
+// handleMarkedFilesPress stages or unstages the marked files together, as if
+// they were the files of a directory
+func (gui *Gui) handleMarkedFilesPress() error {
+	node := gui.markedFilesNode()
+	fileNames := make([]string, len(node.Files))
+	for i, file := range node.Files {
+		fileNames[i] = file.Name
+	}
+	return gui.stageOrUnstage(node, fileNames, gui.Tr.SLocalize("MarkedFilesHaveMergeConflicts"))
+}
+
+// stageOrUnstage stages the given paths if any of the node's files has
+// unstaged changes, and otherwise unstages each of its files. Files with merge
+// conflicts need resolving one at a time, so if there are any we just show
+// mergeConflictsMessage
+func (gui *Gui) stageOrUnstage(node *commands.FileNode, paths []string, mergeConflictsMessage string) error {
+	if node.HasMergeConflicts() {
+		return gui.createErrorPanel(gui.g, mergeConflictsMessage)
+	}
+
+	var err error
+	if node.HasUnstagedChanges() {
+		for _, path := range paths {
+			if err = gui.GitCommand.StageFile(path); err != nil {
+				break
+			}
+		}
+	} else {
+		for _, file := range node.Files {
+			if err = gui.GitCommand.UnStageFile(file.Name, file.Tracked); err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		_ = gui.createErrorPanel(gui.g, err.Error())
+	}
+
+	return gui.refreshFiles()
+}
+
 func (gui *Gui) allFilesStaged() bool {
 	for _, file := range gui.State.Files {
 		if file.HasUnstagedChanges {
@@ -245,6 +371,20 @@ func (gui *Gui) handleStageAll(g *gocui.Gui, v *gocui.View) error {
 	return gui.handleFileSelect(g, v, false)
 }
 
+// handleStageTracked stages the changes to tracked files, which unlike
+// handleStageAll won't sweep untracked junk into the next commit
+func (gui *Gui) handleStageTracked(g *gocui.Gui, v *gocui.View) error {
+	if err := gui.GitCommand.StageTracked(); err != nil {
+		_ = gui.createErrorPanel(g, err.Error())
+	}
+
+	if err := gui.refreshFiles(); err != nil {
+		return err
+	}
+
+	return gui.handleFileSelect(g, v, false)
+}
+
 func (gui *Gui) handleAddPatch(g *gocui.Gui, v *gocui.View) error {
 	file, err := gui.getSelectedFile(g)
 	if err != nil {
@@ -264,16 +404,130 @@ func (gui *Gui) handleAddPatch(g *gocui.Gui, v *gocui.View) error {
 	return gui.Errors.ErrSubProcess
 }
 
+type ignoreOption struct {
+	description string
+	onPress     func() error
+}
+
+// GetDisplayStrings is a function.
+func (o *ignoreOption) GetDisplayStrings(isFocused bool) []string {
+	return []string{o.description}
+}
+
+// handleIgnoreFile offers to add the selected file or directory, or a pattern
+// that you type in, to either the .gitignore or the repo's exclude file
 func (gui *Gui) handleIgnoreFile(g *gocui.Gui, v *gocui.View) error {
+	node := gui.getSelectedFileNode()
+	if node == nil {
+		return gui.createErrorPanel(g, gui.Errors.ErrNoFiles.Error())
+	}
+	path := node.Path
+	if node.IsDirectory() {
+		path += "/"
+	}
+
+	ignorePath := func(ignore func(string) error) func() error {
+		return func() error {
+			for _, file := range node.GetFiles() {
+				if file.Tracked {
+					return gui.createErrorPanel(g, gui.Tr.SLocalize("CantIgnoreTrackFiles"))
+				}
+			}
+			return gui.ignore(ignore, path)
+		}
+	}
+	ignorePattern := func(ignore func(string) error) func() error {
+		return func() error {
+			return gui.createPromptPanel(g, v, gui.Tr.SLocalize("IgnorePatternTitle"), func(g *gocui.Gui, v *gocui.View) error {
+				pattern := gui.trimmedContent(v)
+				if pattern == "" {
+					return nil
+				}
+				return gui.ignore(ignore, pattern)
+			})
+		}
+	}
+
+	options := []*ignoreOption{
+		{description: gui.Tr.SLocalize("ignoreFile"), onPress: ignorePath(gui.GitCommand.Ignore)},
+		{description: gui.Tr.SLocalize("excludeFile"), onPress: ignorePath(gui.GitCommand.Exclude)},
+		{description: gui.Tr.SLocalize("ignorePattern"), onPress: ignorePattern(gui.GitCommand.Ignore)},
+		{description: gui.Tr.SLocalize("excludePattern"), onPress: ignorePattern(gui.GitCommand.Exclude)},
+	}
+
+	handleMenuPress := func(index int) error {
+		return options[index].onPress()
+	}
+
+	return gui.createMenu(path, options, len(options), handleMenuPress)
+}
+
+// handleEditIgnoreFiles offers to open the .gitignore or the repo's exclude
+// file in the editor. The files panel is refreshed when we come back, so
+// anything newly ignored disappears from it
+func (gui *Gui) handleEditIgnoreFiles(g *gocui.Gui, v *gocui.View) error {
+	editExcludeFile := func() error {
+		excludeFile, err := gui.GitCommand.ExcludeFilePath()
+		if err != nil {
+			return gui.createErrorPanel(g, err.Error())
+		}
+		return gui.editFile(excludeFile)
+	}
+
+	options := []*ignoreOption{
+		{description: gui.Tr.SLocalize("editGitignore"), onPress: func() error { return gui.editFile(".gitignore") }},
+		{description: gui.Tr.SLocalize("editExcludeFile"), onPress: editExcludeFile},
+	}
+
+	handleMenuPress := func(index int) error {
+		return options[index].onPress()
+	}
+
+	return gui.createMenu(gui.Tr.SLocalize("EditIgnoreFilesTitle"), options, len(options), handleMenuPress)
+}
+
+// handleIgnoreChanges offers to tell git to ignore changes to the selected
+// file, with either skip-worktree or assume-unchanged, or to stop doing so
+func (gui *Gui) handleIgnoreChanges(g *gocui.Gui, v *gocui.View) error {
 	file, err := gui.getSelectedFile(g)
 	if err != nil {
-		return gui.createErrorPanel(g, err.Error())
+		if err != gui.Errors.ErrNoFiles {
+			return err
+		}
+		return nil
 	}
-	if file.Tracked {
-		return gui.createErrorPanel(g, gui.Tr.SLocalize("CantIgnoreTrackFiles"))
+
+	setFlag := func(set func(string, bool) error, value bool) func() error {
+		return func() error {
+			if err := set(file.CurrentPath(), value); err != nil {
+				return gui.createErrorPanel(g, err.Error())
+			}
+			return gui.refreshFiles()
+		}
 	}
-	if err := gui.GitCommand.Ignore(file.Name); err != nil {
-		return gui.createErrorPanel(g, err.Error())
+
+	skipWorktree := &ignoreOption{description: gui.Tr.SLocalize("skipWorktree"), onPress: setFlag(gui.GitCommand.SetSkipWorktree, true)}
+	if file.SkipWorktree {
+		skipWorktree = &ignoreOption{description: gui.Tr.SLocalize("noSkipWorktree"), onPress: setFlag(gui.GitCommand.SetSkipWorktree, false)}
+	}
+	assumeUnchanged := &ignoreOption{description: gui.Tr.SLocalize("assumeUnchanged"), onPress: setFlag(gui.GitCommand.SetAssumeUnchanged, true)}
+	if file.AssumeUnchanged {
+		assumeUnchanged = &ignoreOption{description: gui.Tr.SLocalize("noAssumeUnchanged"), onPress: setFlag(gui.GitCommand.SetAssumeUnchanged, false)}
+	}
+	options := []*ignoreOption{skipWorktree, assumeUnchanged}
+
+	handleMenuPress := func(index int) error {
+		return options[index].onPress()
+	}
+
+	return gui.createMenu(file.CurrentPath(), options, len(options), handleMenuPress)
+}
+
+// ignore adds the pattern using the given function, which writes it to either
+// the .gitignore or the exclude file, and then refreshes the files
+func (gui *Gui) ignore(ignore func(string) error, pattern string) error {
+	if err := ignore(pattern); err != nil {
+		return gui.createErrorPanel(gui.g, err.Error())
 	}
 	return gui.refreshFiles()
 }
@@ -291,15 +545,39 @@ func (gui *Gui) handleWIPCommitPress(g *gocui.Gui, filesView *gocui.View) error
 		return err
 	}
 
-	return gui.handleCommitPress(g, filesView)
+	return gui.openCommitMessagePanel(g, filesView, "")
 }
 
 func (gui *Gui) handleCommitPress(g *gocui.Gui, filesView *gocui.View) error {
-	if len(gui.stagedFiles()) == 0 && gui.State.WorkingTreeState == "normal" {
+	template, err := gui.GitCommand.CommitTemplate()
+	if err != nil {
+		return gui.createErrorPanel(g, err.Error())
+	}
+	splitMessage, err := gui.splitCommitMessage()
+	if err != nil {
+		return gui.createErrorPanel(g, err.Error())
+	}
+	if splitMessage != "" {
+		template = splitMessage
+	}
+	return gui.openCommitMessagePanel(g, filesView, template)
+}
+
+// openCommitMessagePanel opens the panel for writing the commit message,
+// starting it with the template unless there's already a message there
+func (gui *Gui) openCommitMessagePanel(g *gocui.Gui, filesView *gocui.View, template string) error {
+	if len(gui.stagedFiles()) == 0 && !gui.operationInProgress() {
 		return gui.createErrorPanel(g, gui.Tr.SLocalize("NoStagedFilesToCommit"))
 	}
 	commitMessageView := gui.getCommitMessageView()
 	g.Update(func(g *gocui.Gui) error {
+		if template != "" && gui.trimmedContent(commitMessageView) == "" {
+			if err := gui.setViewContent(g, commitMessageView, template); err != nil {
+				return err
+			}
+			_ = commitMessageView.SetCursor(0, 0)
+			_ = commitMessageView.SetOrigin(0, 0)
+		}
 		g.SetViewOnTop("commitMessage")
 		gui.switchFocus(g, filesView, commitMessageView)
 		gui.RenderCommitLength()
@@ -309,7 +587,7 @@ func (gui *Gui) handleCommitPress(g *gocui.Gui, filesView *gocui.View) error {
 }
 
 func (gui *Gui) handleAmendCommitPress(g *gocui.Gui, filesView *gocui.View) error {
-	if len(gui.stagedFiles()) == 0 && gui.State.WorkingTreeState == "normal" {
+	if len(gui.stagedFiles()) == 0 && !gui.operationInProgress() {
 		return gui.createErrorPanel(g, gui.Tr.SLocalize("NoStagedFilesToCommit"))
 	}
 	if len(gui.State.Commits) == 0 {
@@ -332,10 +610,56 @@ func (gui *Gui) handleAmendCommitPress(g *gocui.Gui, filesView *gocui.View) erro
 	}, nil)
 }
 
+// handleAmendFilePress amends the last commit with the selected file's changes,
+// or those of every file in the selected directory, without touching anything
+// else that's staged
+func (gui *Gui) handleAmendFilePress(g *gocui.Gui, filesView *gocui.View) error {
+	node := gui.getSelectedFileNode()
+	if node == nil {
+		return nil
+	}
+	if len(gui.State.Commits) == 0 {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("NoCommitToAmend"))
+	}
+	if gui.operationInProgress() {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("CantAmendFileMidOperation"))
+	}
+	for _, file := range node.GetFiles() {
+		if file.HasMergeConflicts {
+			return gui.createErrorPanel(g, gui.Tr.SLocalize("CantAmendFileWithMergeConflicts"))
+		}
+	}
+
+	path := node.Path
+	if !node.IsDirectory() {
+		path = node.File.Name
+	}
+	title := strings.Title(gui.Tr.SLocalize("amendFile"))
+	question := gui.Tr.TemplateLocalize("SureToAmendFile", Teml{"path": path})
+
+	return gui.createConfirmationPanel(g, filesView, title, question, func(g *gocui.Gui, v *gocui.View) error {
+		sub, err := gui.GitCommand.AmendFileIntoHead(path)
+		if sub != nil {
+			gui.SubProcessCallback = func() error {
+				return gui.GitCommand.FinishAmendingFile(path)
+			}
+		}
+		ok, err := gui.runSyncOrAsyncCommand(sub, err)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+
+		return gui.refreshSidePanels(g)
+	}, nil)
+}
+
 // handleCommitEditorPress - handle when the user wants to commit changes via
 // their editor rather than via the popup panel
 func (gui *Gui) handleCommitEditorPress(g *gocui.Gui, filesView *gocui.View) error {
-	if len(gui.stagedFiles()) == 0 && gui.State.WorkingTreeState == "normal" {
+	if len(gui.stagedFiles()) == 0 && !gui.operationInProgress() {
 		return gui.createErrorPanel(g, gui.Tr.SLocalize("NoStagedFilesToCommit"))
 	}
 	gui.PrepareSubProcess(g, "git", "commit")
@@ -355,13 +679,22 @@ func (gui *Gui) editFile(filename string) error {
 	return err
 }
 
+// handleFileEdit opens the file in the editor with the cursor on the first line
+// that has changed, if there is one
 func (gui *Gui) handleFileEdit(g *gocui.Gui, v *gocui.View) error {
 	file, err := gui.getSelectedFile(g)
 	if err != nil {
 		return gui.createErrorPanel(gui.g, err.Error())
 	}
 
-	return gui.editFile(file.Name)
+	diff := gui.GitCommand.Diff(file, true, false, false, gui.State.DiffContextSize)
+	lineNumber := git.NewDiffState(gui.Log, gui.Tr, diff).FirstChangedLine()
+	if lineNumber == 0 {
+		return gui.editFile(file.Name)
+	}
+
+	_, err = gui.runSyncOrAsyncCommand(gui.OSCommand.EditFileAtLine(file.CurrentPath(), lineNumber))
+	return err
 }
 
 func (gui *Gui) handleFileOpen(g *gocui.Gui, v *gocui.View) error {
@@ -372,6 +705,38 @@ func (gui *Gui) handleFileOpen(g *gocui.Gui, v *gocui.View) error {
 	return gui.openFile(file.Name)
 }
 
+// handleMoveFile asks where to move the selected file to, starting from where
+// it is now. Tab completes the name of a directory
+func (gui *Gui) handleMoveFile(g *gocui.Gui, v *gocui.View) error {
+	file, err := gui.getSelectedFile(g)
+	if err != nil {
+		if err != gui.Errors.ErrNoFiles {
+			return err
+		}
+		return nil
+	}
+
+	if err := gui.createPromptPanel(g, v, gui.Tr.SLocalize("MoveFileTitle"), func(g *gocui.Gui, v *gocui.View) error {
+		newPath := gui.trimmedContent(v)
+		if newPath == "" || newPath == file.CurrentPath() {
+			return nil
+		}
+		if err := gui.GitCommand.MoveFile(file, newPath); err != nil {
+			return gui.createErrorPanel(g, err.Error())
+		}
+		return gui.refreshFiles()
+	}); err != nil {
+		return err
+	}
+
+	promptView, _ := g.View("confirmation")
+	gui.setPromptContent(promptView, file.CurrentPath())
+	return g.SetKeybinding("confirmation", gocui.KeyTab, gocui.ModNone, func(g *gocui.Gui, v *gocui.View) error {
+		gui.setPromptContent(v, utils.CompleteDirectory(gui.trimmedContent(v)))
+		return nil
+	})
+}
+
 func (gui *Gui) handleRefreshFiles(g *gocui.Gui, v *gocui.View) error {
 	return gui.refreshFiles()
 }
@@ -380,8 +745,168 @@ func (gui *Gui) refreshStateFiles() error {
 	// get files to stage
 	files := gui.GitCommand.GetStatusFiles()
 	gui.State.Files = gui.GitCommand.MergeStatusFiles(gui.State.Files, files)
-	gui.refreshSelectedLine(&gui.State.Panels.Files.SelectedLine, len(gui.State.Files))
-	return gui.updateWorkTreeState()
+	gui.refreshFileNodes()
+	if err := gui.updateWorkTreeState(); err != nil {
+		return err
+	}
+	// a commit can only be split while the rebase stopped at it is going
+	if gui.State.WorkingTreeState != "rebasing" {
+		gui.State.SplittingSha = ""
+	}
+	return nil
+}
+
+// refreshFileNodes rebuilds the lines of the files panel from the files,
+// keeping the same file or directory selected if it's still there
+func (gui *Gui) refreshFileNodes() {
+	panelState := gui.State.Panels.Files
+	selectedPath := ""
+	if node := gui.getSelectedFileNode(); node != nil {
+		selectedPath = node.Path
+	}
+
+	files := commands.SortFiles(gui.State.Files, panelState.SortOrder)
+	if panelState.ShowTree {
+		gui.State.FileNodes = commands.BuildFileTree(files, panelState.CollapsedPaths)
+	} else {
+		gui.State.FileNodes = commands.BuildFlatFileList(files)
+	}
+
+	// files that are gone, e.g. because they've been discarded, can't stay marked
+	markedFileNames := map[string]bool{}
+	for _, file := range gui.State.Files {
+		if panelState.MarkedFileNames[file.Name] {
+			markedFileNames[file.Name] = true
+		}
+	}
+	panelState.MarkedFileNames = markedFileNames
+	for _, node := range gui.State.FileNodes {
+		node.Marked = len(markedFileNames) > 0
+		for _, file := range node.GetFiles() {
+			if !markedFileNames[file.Name] {
+				node.Marked = false
+				break
+			}
+		}
+	}
+
+	for i, node := range gui.State.FileNodes {
+		if node.Path == selectedPath {
+			panelState.SelectedLine = i
+			break
+		}
+	}
+	gui.refreshSelectedLine(&panelState.SelectedLine, len(gui.State.FileNodes))
+}
+
+// directoryDiff returns the diffs of every file in the directory
+// markedFilesNode returns a node holding the marked files, so that they can be
+// staged or discarded the same way as the files of a directory
+func (gui *Gui) markedFilesNode() *commands.FileNode {
+	files := []*commands.File{}
+	for _, file := range gui.State.Files {
+		if gui.State.Panels.Files.MarkedFileNames[file.Name] {
+			files = append(files, file)
+		}
+	}
+	return &commands.FileNode{Files: files, Marked: true}
+}
+
+// handleToggleFileMarked marks the selected file, or every file in the
+// selected directory, or unmarks them if they're already marked. We then move
+// on to the next line so that several files can be marked in a row
+func (gui *Gui) handleToggleFileMarked(g *gocui.Gui, v *gocui.View) error {
+	node := gui.getSelectedFileNode()
+	if node == nil {
+		return nil
+	}
+
+	markedFileNames := gui.State.Panels.Files.MarkedFileNames
+	for _, file := range node.GetFiles() {
+		if node.Marked {
+			delete(markedFileNames, file.Name)
+		} else {
+			markedFileNames[file.Name] = true
+		}
+	}
+
+	if err := gui.handleFilesNextLine(g, v); err != nil {
+		return err
+	}
+	return gui.refreshFiles()
+}
+
+func (gui *Gui) handleClearMarkedFiles(g *gocui.Gui, v *gocui.View) error {
+	gui.State.Panels.Files.MarkedFileNames = map[string]bool{}
+	return gui.refreshFiles()
+}
+
+func (gui *Gui) directoryDiff(node *commands.FileNode) string {
+	diffs := make([]string, len(node.Files))
+	for i, file := range node.Files {
+		diffs[i] = gui.GitCommand.Diff(file, false, false, gui.State.IgnoreWhitespace, gui.State.DiffContextSize)
+	}
+	return strings.Join(diffs, "")
+}
+
+// handleToggleDirectoryCollapsed hides the contents of the directory in the
+// tree, or shows them again if they're hidden
+func (gui *Gui) handleToggleDirectoryCollapsed(node *commands.FileNode) error {
+	collapsedPaths := gui.State.Panels.Files.CollapsedPaths
+	if node.Collapsed {
+		delete(collapsedPaths, node.Path)
+	} else {
+		collapsedPaths[node.Path] = true
+	}
+
+	return gui.refreshFiles()
+}
+
+// handleToggleFileTree switches between showing the files grouped into their
+// directories and showing them as a flat list
+func (gui *Gui) handleToggleFileTree(g *gocui.Gui, v *gocui.View) error {
+	gui.State.Panels.Files.ShowTree = !gui.State.Panels.Files.ShowTree
+
+	return gui.refreshFiles()
+}
+
+type fileSortOption struct {
+	order       string
+	description string
+}
+
+// GetDisplayStrings is a function.
+func (o *fileSortOption) GetDisplayStrings(isFocused bool) []string {
+	return []string{o.description}
+}
+
+// handleCreateFileSortMenu lets you choose the order of the files panel. The
+// choice is saved to the user config so it sticks
+func (gui *Gui) handleCreateFileSortMenu(g *gocui.Gui, v *gocui.View) error {
+	descriptions := map[string]string{
+		commands.SortFilesByPath:     gui.Tr.SLocalize("sortFilesByPath"),
+		commands.SortFilesByStatus:   gui.Tr.SLocalize("sortFilesByStatus"),
+		commands.SortFilesByModified: gui.Tr.SLocalize("sortFilesByModified"),
+	}
+	options := make([]*fileSortOption, len(commands.FileSortOrders))
+	for i, order := range commands.FileSortOrders {
+		description := descriptions[order]
+		if order == gui.State.Panels.Files.SortOrder {
+			description += " " + utils.ColoredString("✓", color.FgGreen)
+		}
+		options[i] = &fileSortOption{order: order, description: description}
+	}
+
+	handleMenuPress := func(index int) error {
+		order := options[index].order
+		gui.State.Panels.Files.SortOrder = order
+		if err := gui.Config.WriteToUserConfig("gui.fileSortOrder", order); err != nil {
+			return gui.createErrorPanel(g, err.Error())
+		}
+		return gui.refreshFiles()
+	}
+
+	return gui.createMenu(gui.Tr.SLocalize("SortFilesTitle"), options, len(options), handleMenuPress)
 }
 
 func (gui *Gui) catSelectedFile(g *gocui.Gui) (string, error) {
@@ -403,14 +928,45 @@ func (gui *Gui) catSelectedFile(g *gocui.Gui) (string, error) {
 	return cat, nil
 }
 
+type pullOption struct {
+	mode string
+}
+
+// GetDisplayStrings is a function.
+func (o *pullOption) GetDisplayStrings(isFocused bool) []string {
+	return []string{o.mode}
+}
+
+// pullFiles pulls with the mode set in the git.pull.mode config, or if that's
+// to prompt, asks whether to merge, rebase or only fast-forward
 func (gui *Gui) pullFiles(g *gocui.Gui, v *gocui.View) error {
+	if gui.headIsDetached() {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("CantDoOnDetachedHead"))
+	}
+
+	mode := gui.Config.GetUserConfig().GetString("git.pull.mode")
+	if mode != commands.PullModePrompt {
+		return gui.pullWithMode(g, v, mode)
+	}
+
+	options := []*pullOption{}
+	for _, mode := range commands.PullModes {
+		options = append(options, &pullOption{mode: mode})
+	}
+	handleMenuPress := func(index int) error {
+		return gui.pullWithMode(g, v, options[index].mode)
+	}
+	return gui.createMenu(gui.Tr.SLocalize("PullOptionsMenuTitle"), options, len(options), handleMenuPress)
+}
+
+func (gui *Gui) pullWithMode(g *gocui.Gui, v *gocui.View, mode string) error {
 	if err := gui.createLoaderPanel(gui.g, v, gui.Tr.SLocalize("PullWait")); err != nil {
 		return err
 	}
 
 	go func() {
 		unamePassOpend := false
-		err := gui.GitCommand.Pull(func(passOrUname string) string {
+		err := gui.GitCommand.Pull(mode, func(passOrUname string) string {
 			unamePassOpend = true
 			return gui.waitForPassUname(g, v, passOrUname)
 		})
@@ -436,6 +992,9 @@ func (gui *Gui) pushWithForceFlag(g *gocui.Gui, v *gocui.View, force bool) error
 }
 
 func (gui *Gui) pushFiles(g *gocui.Gui, v *gocui.View) error {
+	if gui.headIsDetached() {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("CantDoOnDetachedHead"))
+	}
 	// if we have pullables we'll ask if the user wants to force push
 	_, pullables := gui.GitCommand.GetCurrentBranchUpstreamDifferenceCount()
 	if pullables == "?" || pullables == "0" {
@@ -447,6 +1006,130 @@ func (gui *Gui) pushFiles(g *gocui.Gui, v *gocui.View) error {
 	return err
 }
 
+// headIsDetached says whether HEAD is a commit rather than a branch, in which
+// case there's no branch to push or pull
+func (gui *Gui) headIsDetached() bool {
+	return len(gui.State.Branches) > 0 && gui.State.Branches[0].Detached
+}
+
+type conflictOption struct {
+	description string
+	handler     func(fileName string) error
+}
+
+// GetDisplayStrings is a function.
+func (o *conflictOption) GetDisplayStrings(isFocused bool) []string {
+	return []string{o.description}
+}
+
+// handleCreateConflictMenu offers the ways to resolve a merge conflict that
+// isn't inside the file, i.e. one where a side added or deleted the whole file.
+// Git has left whichever version of the file there is in the working tree
+func (gui *Gui) handleCreateConflictMenu(file *commands.File) error {
+	options := []*conflictOption{}
+	if file.ShortStatus != "DD" {
+		options = append(options, &conflictOption{
+			description: gui.Tr.SLocalize("keepConflictedFile"),
+			handler:     gui.GitCommand.StageFile,
+		})
+	}
+	options = append(options, &conflictOption{
+		description: gui.Tr.SLocalize("removeConflictedFile"),
+		handler:     gui.GitCommand.RemoveConflictedFile,
+	})
+
+	handleMenuPress := func(index int) error {
+		if err := options[index].handler(file.Name); err != nil {
+			return gui.createErrorPanel(gui.g, err.Error())
+		}
+		return gui.refreshFiles()
+	}
+
+	return gui.createMenu(gui.GitCommand.DescribeMergeConflict(file), options, len(options), handleMenuPress)
+}
+
+// describeSubmodule says which commit the submodule has checked out compared
+// with the one recorded for it, which is what its diff amounts to
+func (gui *Gui) describeSubmodule(file *commands.File) string {
+	status := gui.GitCommand.GetSubmoduleStatus(file.Name)
+	none := gui.Tr.SLocalize("SubmoduleNoCommit")
+	recordedSha, checkedOutSha := none, none
+	if status.RecordedSha != "" {
+		recordedSha = status.RecordedSha
+	}
+	if status.CheckedOutSha != "" {
+		checkedOutSha = status.CheckedOutSha
+	}
+	lines := []string{
+		utils.ColoredString(gui.Tr.TemplateLocalize("SubmoduleTitle", Teml{"path": file.Name}), color.FgMagenta),
+		"",
+		gui.Tr.SLocalize("SubmoduleRecordedCommit") + " " + recordedSha,
+		gui.Tr.SLocalize("SubmoduleCheckedOutCommit") + " " + checkedOutSha,
+	}
+	if status.Dirty {
+		lines = append(lines, "", utils.ColoredString(gui.Tr.SLocalize("SubmoduleHasChanges"), color.FgRed))
+	}
+	lines = append(lines, "", gui.Tr.SLocalize("PressEnterForSubmoduleOptions"))
+	return strings.Join(lines, "\n")
+}
+
+type submoduleOption struct {
+	description string
+	handler     func(path string) error
+}
+
+// GetDisplayStrings is a function.
+func (o *submoduleOption) GetDisplayStrings(isFocused bool) []string {
+	return []string{o.description}
+}
+
+// handleCreateSubmoduleMenu offers to check out the commit recorded for the
+// submodule, or to open the submodule in lazygit to work on it there
+func (gui *Gui) handleCreateSubmoduleMenu(file *commands.File) error {
+	options := []*submoduleOption{
+		{
+			description: gui.Tr.SLocalize("updateSubmodule"),
+			handler:     gui.GitCommand.UpdateSubmodule,
+		},
+		{
+			description: gui.Tr.SLocalize("stashAndUpdateSubmodule"),
+			handler: func(path string) error {
+				if err := gui.GitCommand.StashSubmoduleChanges(path); err != nil {
+					return err
+				}
+				return gui.GitCommand.UpdateSubmodule(path)
+			},
+		},
+		{
+			description: gui.Tr.SLocalize("openSubmodule"),
+			handler:     gui.openSubmodule,
+		},
+	}
+
+	handleMenuPress := func(index int) error {
+		if err := options[index].handler(file.Name); err != nil {
+			if err == gui.Errors.ErrSubProcess {
+				return err
+			}
+			return gui.createErrorPanel(gui.g, err.Error())
+		}
+		return gui.refreshFiles()
+	}
+
+	return gui.createMenu(gui.Tr.TemplateLocalize("SubmoduleTitle", Teml{"path": file.Name}), options, len(options), handleMenuPress)
+}
+
+// openSubmodule runs another lazygit in the submodule, and we carry on where
+// we were once it's quit
+func (gui *Gui) openSubmodule(path string) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	gui.SubProcess = gui.OSCommand.PrepareSubProcess(executable, "--path", path)
+	return gui.Errors.ErrSubProcess
+}
+
 func (gui *Gui) handleSwitchToMerge(g *gocui.Gui, v *gocui.View) error {
 	file, err := gui.getSelectedFile(g)
 	if err != nil {
@@ -483,6 +1166,46 @@ func (gui *Gui) openFile(filename string) error {
 	return nil
 }
 
+// copyPathToClipboard copies the repo-relative path, or if absolute is true
+// the absolute path, of a file to the clipboard. We're always run from the root
+// of the repo so a relative path is relative to it
+func (gui *Gui) copyPathToClipboard(path string, absolute bool) error {
+	if absolute {
+		absolutePath, err := filepath.Abs(path)
+		if err != nil {
+			return gui.createErrorPanel(gui.g, err.Error())
+		}
+		path = absolutePath
+	}
+	return gui.copyToClipboard(path)
+}
+
+// copyToClipboard puts the text on the clipboard, saying so if there's no
+// clipboard program to do that with
+func (gui *Gui) copyToClipboard(text string) error {
+	if err := utils.CopyToClipboard(text); err != nil {
+		if err == utils.ErrNoClipboard {
+			return gui.createErrorPanel(gui.g, gui.Tr.SLocalize("NoClipboard"))
+		}
+		return gui.createErrorPanel(gui.g, err.Error())
+	}
+	return nil
+}
+
+func (gui *Gui) handleCopyFilePath(absolute bool) func(g *gocui.Gui, v *gocui.View) error {
+	return func(g *gocui.Gui, v *gocui.View) error {
+		node := gui.getSelectedFileNode()
+		if node == nil {
+			return nil
+		}
+		path := node.Path
+		if !node.IsDirectory() {
+			path = node.File.CurrentPath()
+		}
+		return gui.copyPathToClipboard(path, absolute)
+	}
+}
+
 func (gui *Gui) anyFilesWithMergeConflicts() bool {
 	for _, file := range gui.State.Files {
 		if file.HasMergeConflicts {
@@ -514,6 +1237,13 @@ func (r *discardAllOption) GetDisplayStrings(isFocused bool) []string {
 }
 
 func (gui *Gui) handleCreateDiscardMenu(g *gocui.Gui, v *gocui.View) error {
+	if len(gui.State.Panels.Files.MarkedFileNames) > 0 {
+		return gui.handleDiscardFiles(gui.markedFilesNode().Files, gui.Tr.SLocalize("DiscardMarkedFilesTitle"), gui.Tr.SLocalize("DiscardMarkedFilesPrompt"))
+	}
+	if node := gui.getSelectedFileNode(); node != nil && node.IsDirectory() {
+		return gui.handleDiscardDirectory(node)
+	}
+
 	file, err := gui.getSelectedFile(g)
 	if err != nil {
 		if err != gui.Errors.ErrNoFiles {
@@ -564,6 +1294,31 @@ func (gui *Gui) handleCreateDiscardMenu(g *gocui.Gui, v *gocui.View) error {
 	return gui.createMenu(file.Name, options, len(options), handleMenuPress)
 }
 
+// handleDiscardDirectory discards all changes to the files in the directory,
+// staged or not, once you've confirmed it. Untracked files are deleted
+func (gui *Gui) handleDiscardDirectory(node *commands.FileNode) error {
+	prompt := gui.Tr.TemplateLocalize(
+		"DiscardDirectoryPrompt",
+		Teml{
+			"path": node.Path,
+		},
+	)
+	return gui.handleDiscardFiles(node.Files, gui.Tr.SLocalize("DiscardDirectoryTitle"), prompt)
+}
+
+// handleDiscardFiles discards all changes to the files once the user has
+// confirmed it
+func (gui *Gui) handleDiscardFiles(files []*commands.File, title, prompt string) error {
+	return gui.createConfirmationPanel(gui.g, gui.getFilesView(), title, prompt, func(g *gocui.Gui, v *gocui.View) error {
+		for _, file := range files {
+			if err := gui.GitCommand.DiscardAllFileChanges(file); err != nil {
+				return gui.createErrorPanel(g, err.Error())
+			}
+		}
+		return gui.refreshFiles()
+	}, nil)
+}
+
 func (gui *Gui) handleCreateResetMenu(g *gocui.Gui, v *gocui.View) error {
 	options := []*discardAllOption{
 		{
@@ -587,6 +1342,20 @@ func (gui *Gui) handleCreateResetMenu(g *gocui.Gui, v *gocui.View) error {
 				return gui.GitCommand.RemoveUntrackedFiles()
 			},
 		},
+		{
+			description: gui.Tr.SLocalize("chooseUntrackedFilesToDelete"),
+			command:     "git clean -fd -- <paths>",
+			handler: func() error {
+				return gui.handleCreateCleanMenu(false)
+			},
+		},
+		{
+			description: gui.Tr.SLocalize("chooseIgnoredFilesToDelete"),
+			command:     "git clean -fdx -- <paths>",
+			handler: func() error {
+				return gui.handleCreateCleanMenu(true)
+			},
+		},
 		{
 			description: gui.Tr.SLocalize("softReset"),
 			command:     "git reset --soft HEAD",
@@ -652,13 +1421,36 @@ func (gui *Gui) handleCreateStashMenu(g *gocui.Gui, v *gocui.View) error {
 				return gui.handleStashSave(gui.GitCommand.StashSaveStagedChanges)
 			},
 		},
-		{
-			description: gui.Tr.SLocalize("cancel"),
+	}
+	if len(gui.State.Panels.Files.MarkedFileNames) > 0 {
+		fileNames := []string{}
+		for _, file := range gui.markedFilesNode().Files {
+			fileNames = append(fileNames, file.Name)
+		}
+		options = append(options, &stashOption{
+			description: gui.Tr.SLocalize("stashMarkedFiles"),
 			handler: func() error {
-				return nil
+				return gui.handleStashSave(func(message string) error {
+					return gui.GitCommand.StashSaveFiles(fileNames, message)
+				})
 			},
-		},
+		})
+	} else if node := gui.getSelectedFileNode(); node != nil {
+		options = append(options, &stashOption{
+			description: gui.Tr.TemplateLocalize("stashFile", Teml{"path": node.Path}),
+			handler: func() error {
+				return gui.handleStashSave(func(message string) error {
+					return gui.GitCommand.StashSaveFiles([]string{node.Path}, message)
+				})
+			},
+		})
 	}
+	options = append(options, &stashOption{
+		description: gui.Tr.SLocalize("cancel"),
+		handler: func() error {
+			return nil
+		},
+	})
 
 	handleMenuPress := func(index int) error {
 		return options[index].handler()
@@ -0,0 +1,66 @@
+package gui
+
+import (
+	"strings"
+
+	"github.com/jesseduffield/gocui"
+)
+
+// handleGotoCommit asks for a sha, tag, or expression like HEAD~20 and selects
+// the commit it points to, loading more commits if it's further back than
+// we've loaded so far
+func (gui *Gui) handleGotoCommit(g *gocui.Gui, v *gocui.View) error {
+	return gui.createPromptPanel(g, v, gui.Tr.SLocalize("GotoCommitTitle"), func(g *gocui.Gui, promptView *gocui.View) error {
+		ref := gui.trimmedContent(promptView)
+		if ref == "" {
+			return nil
+		}
+		sha, err := gui.GitCommand.ResolveCommit(ref)
+		if err != nil {
+			return gui.createErrorPanel(g, gui.Tr.TemplateLocalize("NoSuchCommit", Teml{"ref": ref}))
+		}
+		branch := gui.State.BrowsingBranch
+		if branch == "" {
+			branch = "HEAD"
+		}
+		if !gui.GitCommand.IsAncestor(sha, branch) {
+			return gui.createErrorPanel(g, gui.Tr.TemplateLocalize("CommitNotInHistory", Teml{"ref": ref}))
+		}
+		after, err := gui.GitCommand.CountCommitsAfter(sha, branch)
+		if err != nil {
+			return gui.createErrorPanel(g, err.Error())
+		}
+
+		// load whole pages, so scrolling on loads more in the usual way
+		panelState := gui.State.Panels.Commits
+		for panelState.LimitCommits <= after {
+			panelState.LimitCommits += commitsPageSize
+		}
+		panelState.GotoSha = sha
+		return gui.refreshCommits(g)
+	})
+}
+
+// selectGotoCommit selects the commit we were asked to go to, now the commits
+// have been loaded. Commits aren't always in the order of their history, so if
+// it isn't among them we load another page and return true, unless there are
+// no more to load
+func (gui *Gui) selectGotoCommit() bool {
+	panelState := gui.State.Panels.Commits
+	for i, commit := range gui.State.Commits {
+		if strings.HasPrefix(panelState.GotoSha, commit.Sha) {
+			panelState.SelectedLine = i
+			panelState.GotoSha = ""
+			return false
+		}
+	}
+	if gui.moreCommitsToLoad() {
+		panelState.LimitCommits += commitsPageSize
+		_ = gui.refreshCommits(gui.g)
+		return true
+	}
+	// e.g. we're only showing the commits that touched a path
+	panelState.GotoSha = ""
+	_ = gui.createErrorPanel(gui.g, gui.Tr.SLocalize("CommitNotInList"))
+	return false
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/jesseduffield/gocui"
 	"github.com/jesseduffield/lazygit/pkg/commands"
 	"github.com/jesseduffield/lazygit/pkg/config"
+	"github.com/jesseduffield/lazygit/pkg/git"
 	"github.com/jesseduffield/lazygit/pkg/i18n"
 	"github.com/jesseduffield/lazygit/pkg/updates"
 	"github.com/jesseduffield/lazygit/pkg/utils"
@@ -63,29 +64,81 @@ type Teml i18n.Teml
 
 // Gui wraps the gocui Gui object which handles rendering and events
 type Gui struct {
-	g             *gocui.Gui
-	Log           *logrus.Entry
-	GitCommand    *commands.GitCommand
-	OSCommand     *commands.OSCommand
-	SubProcess    *exec.Cmd
-	State         guiState
-	Config        config.AppConfigurer
-	Tr            *i18n.Localizer
-	Errors        SentinelErrors
-	Updater       *updates.Updater
-	statusManager *statusManager
-	credentials   credentials
-	waitForIntro  sync.WaitGroup
+	g                  *gocui.Gui
+	Log                *logrus.Entry
+	GitCommand         *commands.GitCommand
+	OSCommand          *commands.OSCommand
+	SubProcess         *exec.Cmd
+	SubProcessCallback func() error
+	State              guiState
+	Config             config.AppConfigurer
+	Tr                 *i18n.Localizer
+	Errors             SentinelErrors
+	Updater            *updates.Updater
+	statusManager      *statusManager
+	credentials        credentials
+	waitForIntro       sync.WaitGroup
 }
 
 // for now the staging panel state, unlike the other panel states, is going to be
 // non-mutative, so that we don't accidentally end up
 // with mismatches of data. We might change this in the future
 type stagingPanelState struct {
-	SelectedLine   int
-	StageableLines []int
-	HunkStarts     []int
-	Diff           string
+	SelectedLine int
+	// DiffState is the parsed diff, which we hold onto across refreshes for as
+	// long as the diff stays the same. See guiState.StagingDiffs
+	DiffState      *git.DiffState
+	ColorDiff      string
+	SelectingRange bool
+	RangeStart     int
+	ShowingStaged  bool
+	PatchHistory   *stack.Stack
+	FileName       string
+	LastClickTime  time.Time
+	SearchString   string
+	SearchMatches  []int
+	SearchIndex    int
+	Refining       bool
+	RefinedLines   map[int]bool
+	// CommitSha is set when we're selecting lines from the diff of a file in a
+	// commit rather than staging lines from the working tree
+	CommitSha string
+}
+
+// stagingDiffKey says which of a file's diffs we're staging lines from
+type stagingDiffKey struct {
+	FileName string
+	Staged   bool
+}
+
+// stagingDiff is a file's diff, parsed and coloured for the staging panel
+type stagingDiff struct {
+	DiffState *git.DiffState
+	ColorDiff string
+}
+
+// appliedPatch is a patch that was applied from the staging panel, kept around
+// so that it can be undone
+type appliedPatch struct {
+	patch            string
+	cached           bool
+	reverse          bool
+	ignoreWhitespace bool
+}
+
+// flags returns the flags to pass to `git apply` for this patch
+func (p *appliedPatch) flags() []string {
+	flags := []string{}
+	if p.cached {
+		flags = append(flags, "cached")
+	}
+	if p.reverse {
+		flags = append(flags, "reverse")
+	}
+	if p.ignoreWhitespace {
+		flags = append(flags, "ignore-whitespace")
+	}
+	return flags
 }
 
 type mergingPanelState struct {
@@ -97,15 +150,41 @@ type mergingPanelState struct {
 
 type filePanelState struct {
 	SelectedLine int
+	// ShowTree is true if the files are grouped into their directories, rather
+	// than being shown as a flat list
+	ShowTree bool
+	// CollapsedPaths holds the paths of the directories collapsed in the tree
+	CollapsedPaths map[string]bool
+	// MarkedFileNames holds the names of the files marked for staging,
+	// discarding or stashing together
+	MarkedFileNames map[string]bool
+	// SortOrder is one of commands.FileSortOrders
+	SortOrder string
 }
 
 type branchPanelState struct {
 	SelectedLine int
+	// SortOrder is one of git.BranchSortOrders
+	SortOrder string
+	// Filter is what the branch names must fuzzy match to be listed, or empty
+	Filter string
 }
 
 type commitPanelState struct {
 	SelectedLine     int
 	SpecificDiffMode bool
+	// MergeDiffParent is which parent we show a merge commit's diff against,
+	// counting from 1, or 0 for the combined diff
+	MergeDiffParent int
+	// LimitCommits is how many commits we load, which grows a page at a time as
+	// you scroll to the bottom
+	LimitCommits int
+	// GotoSha is the commit we've been asked to go to, which we select once
+	// it's loaded
+	GotoSha string
+	// MarkedShas holds the shas of the commits marked for squashing, fixing up
+	// or dropping together
+	MarkedShas map[string]bool
 }
 
 type stashPanelState struct {
@@ -118,6 +197,17 @@ type menuPanelState struct {
 
 type commitFilesPanelState struct {
 	SelectedLine int
+	// ComparedRef is the ref whose changes since the compare base the panel
+	// lists, or empty if it lists the selected commit's files
+	ComparedRef string
+}
+
+type reflogPanelState struct {
+	SelectedLine int
+}
+
+type remoteBranchesPanelState struct {
+	SelectedLine int
 }
 
 type panelStates struct {
@@ -129,13 +219,19 @@ type panelStates struct {
 	Staging     *stagingPanelState
 	Merging     *mergingPanelState
 	CommitFiles *commitFilesPanelState
+	Reflog      *reflogPanelState
+	// RemoteBranches is the remote branches' tab in the branches panel
+	RemoteBranches *remoteBranchesPanelState
 }
 
 type guiState struct {
 	Files               []*commands.File
+	FileNodes           []*commands.FileNode
 	Branches            []*commands.Branch
 	Commits             []*commands.Commit
 	StashEntries        []*commands.StashEntry
+	ReflogEntries       []*commands.ReflogEntry
+	RemoteBranches      []*commands.RemoteBranch
 	CommitFiles         []*commands.CommitFile
 	DiffEntries         []*commands.Commit
 	MenuItemCount       int // can't store the actual list because it's of interface{} type
@@ -143,29 +239,73 @@ type guiState struct {
 	Platform            commands.Platform
 	Updating            bool
 	Panels              *panelStates
-	WorkingTreeState    string // one of "merging", "rebasing", "normal"
+	WorkingTreeState    string // one of "merging", "rebasing", "cherry-picking", "reverting", "conflicted", "normal"
 	Contexts            map[string]string
 	CherryPickedCommits []*commands.Commit
+	SyntaxHighlighting  bool
+	IgnoreWhitespace    bool
+	DiffContextSize     int
+	FullscreenMain      bool
+	PatchManager        *git.PatchManager
+	// StagingDiffs are the diffs of each file we've staged lines of, so that
+	// only the files whose diffs have changed are parsed and coloured again
+	StagingDiffs map[stagingDiffKey]*stagingDiff
+	// RewordingSha is the commit whose message the commit message panel is
+	// editing, or empty if the panel is for making a new commit
+	RewordingSha string
+	// SplittingSha is the commit we've stopped a rebase at to split in two, or
+	// empty if we're not splitting one
+	SplittingSha string
+	// FilterPath limits the commits panel to the commits that changed this path
+	FilterPath string
+	// BrowsingBranch is the branch whose commits the commits panel shows in
+	// place of the checked out branch's, or empty
+	BrowsingBranch string
+	// Bisect is the bisect in progress, or nil if there isn't one
+	Bisect *commands.BisectInfo
+	// ShowingReflog says whether the reflog's tab is the one shown in the
+	// commits panel's place
+	ShowingReflog bool
+	// ShowingRemoteBranches says whether the remote branches' tab is the one
+	// shown in the branches panel's place
+	ShowingRemoteBranches bool
+	// CompareBase is the branch the other branches are compared against, or
+	// empty if we're not comparing branches
+	CompareBase string
 }
 
 // NewGui builds a new gui handler
 func NewGui(log *logrus.Entry, gitCommand *commands.GitCommand, oSCommand *commands.OSCommand, tr *i18n.Localizer, config config.AppConfigurer, updater *updates.Updater) (*Gui, error) {
 
+	patchManager, err := git.NewPatchManager(log, tr)
+	if err != nil {
+		return nil, err
+	}
+
 	initialState := guiState{
 		Files:               make([]*commands.File, 0),
+		FileNodes:           make([]*commands.FileNode, 0),
 		PreviousView:        "files",
 		Commits:             make([]*commands.Commit, 0),
 		CherryPickedCommits: make([]*commands.Commit, 0),
 		StashEntries:        make([]*commands.StashEntry, 0),
+		ReflogEntries:       make([]*commands.ReflogEntry, 0),
+		RemoteBranches:      make([]*commands.RemoteBranch, 0),
 		DiffEntries:         make([]*commands.Commit, 0),
 		Platform:            *oSCommand.Platform,
+		SyntaxHighlighting:  config.GetUserConfig().GetBool("gui.syntaxHighlighting"),
+		DiffContextSize:     3,
+		PatchManager:        patchManager,
+		StagingDiffs:        map[stagingDiffKey]*stagingDiff{},
 		Panels: &panelStates{
-			Files:       &filePanelState{SelectedLine: -1},
-			Branches:    &branchPanelState{SelectedLine: 0},
-			Commits:     &commitPanelState{SelectedLine: -1},
-			CommitFiles: &commitFilesPanelState{SelectedLine: -1},
-			Stash:       &stashPanelState{SelectedLine: -1},
-			Menu:        &menuPanelState{SelectedLine: 0},
+			Files:          &filePanelState{SelectedLine: -1, ShowTree: config.GetUserConfig().GetBool("gui.showFileTree"), CollapsedPaths: map[string]bool{}, MarkedFileNames: map[string]bool{}, SortOrder: config.GetUserConfig().GetString("gui.fileSortOrder")},
+			Branches:       &branchPanelState{SelectedLine: 0, SortOrder: config.GetUserConfig().GetString("gui.branchSortOrder")},
+			Commits:        &commitPanelState{SelectedLine: -1, LimitCommits: commitsPageSize, MarkedShas: map[string]bool{}},
+			CommitFiles:    &commitFilesPanelState{SelectedLine: -1},
+			Stash:          &stashPanelState{SelectedLine: -1},
+			Reflog:         &reflogPanelState{SelectedLine: -1},
+			RemoteBranches: &remoteBranchesPanelState{SelectedLine: -1},
+			Menu:           &menuPanelState{SelectedLine: 0},
 			Merging: &mergingPanelState{
 				ConflictIndex: 0,
 				ConflictTop:   true,
@@ -212,6 +352,19 @@ func (gui *Gui) scrollDownMain(g *gocui.Gui, v *gocui.View) error {
 	return nil
 }
 
+// handleToggleFullscreenMain stretches the main view across the whole screen,
+// covering the side panels, so that wide diffs are readable. Doing it again
+// puts things back the way they were
+func (gui *Gui) handleToggleFullscreenMain(g *gocui.Gui, v *gocui.View) error {
+	gui.State.FullscreenMain = !gui.State.FullscreenMain
+	if gui.State.FullscreenMain {
+		if _, err := g.SetViewOnTop("main"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (gui *Gui) handleRefresh(g *gocui.Gui, v *gocui.View) error {
 	return gui.refreshSidePanels(g)
 }
@@ -282,6 +435,14 @@ func (gui *Gui) onFocus(v *gocui.View) error {
 	if v == nil {
 		return nil
 	}
+	// whichever of the commits and reflog you last went to is the tab we show
+	if v.Name() == "commits" || v.Name() == "reflog" {
+		gui.State.ShowingReflog = v.Name() == "reflog"
+	}
+	// and likewise for the branches and remote branches
+	if v.Name() == "branches" || v.Name() == "remoteBranches" {
+		gui.State.ShowingRemoteBranches = v.Name() == "remoteBranches"
+	}
 	gui.Log.Info(v.Name() + " focus gained")
 	return nil
 }
@@ -316,6 +477,14 @@ func (gui *Gui) layout(g *gocui.Gui) error {
 	currentCyclebleView := gui.State.PreviousView
 	if currView != nil {
 		viewName := currView.Name()
+		// the reflog takes the commits panel's place
+		if viewName == "reflog" {
+			viewName = "commits"
+		}
+		// and the remote branches take the branches panel's
+		if viewName == "remoteBranches" {
+			viewName = "branches"
+		}
 		usePreviouseView := true
 		for _, view := range cyclableViews {
 			if view == viewName {
@@ -374,7 +543,11 @@ func (gui *Gui) layout(g *gocui.Gui) error {
 	_, _ = g.SetViewOnBottom("limit")
 	g.DeleteView("limit")
 
-	v, err := g.SetView("main", leftSideWidth+panelSpacing, 0, width-1, height-2, gocui.LEFT)
+	mainViewLeft := leftSideWidth + panelSpacing
+	if gui.State.FullscreenMain {
+		mainViewLeft = 0
+	}
+	v, err := g.SetView("main", mainViewLeft, 0, width-1, height-2, gocui.LEFT)
 	if err != nil {
 		if err.Error() != "unknown view" {
 			return err
@@ -402,13 +575,20 @@ func (gui *Gui) layout(g *gocui.Gui) error {
 		v.FgColor = gocui.ColorWhite
 	}
 
+	if v, err := g.SetViewBeneath("remoteBranches", "files", vHeights["branches"]); err != nil {
+		if err.Error() != "unknown view" {
+			return err
+		}
+		v.FgColor = gocui.ColorWhite
+	}
+
 	branchesView, err := g.SetViewBeneath("branches", "files", vHeights["branches"])
 	if err != nil {
 		if err.Error() != "unknown view" {
 			return err
 		}
-		branchesView.Title = gui.Tr.SLocalize("BranchesTitle")
 		branchesView.FgColor = gocui.ColorWhite
+		gui.setBranchesTabs()
 	}
 
 	if v, err := g.SetViewBeneath("commitFiles", "branches", vHeights["commits"]); err != nil {
@@ -419,13 +599,20 @@ func (gui *Gui) layout(g *gocui.Gui) error {
 		v.FgColor = gocui.ColorWhite
 	}
 
+	if v, err := g.SetViewBeneath("reflog", "branches", vHeights["commits"]); err != nil {
+		if err.Error() != "unknown view" {
+			return err
+		}
+		v.FgColor = gocui.ColorWhite
+	}
+
 	commitsView, err := g.SetViewBeneath("commits", "branches", vHeights["commits"])
 	if err != nil {
 		if err.Error() != "unknown view" {
 			return err
 		}
-		commitsView.Title = gui.Tr.SLocalize("CommitsTitle")
 		commitsView.FgColor = gocui.ColorWhite
+		gui.setCommitsTabs()
 	}
 
 	stashView, err := g.SetViewBeneath("stash", "commits", vHeights["stash"])
@@ -457,6 +644,7 @@ func (gui *Gui) layout(g *gocui.Gui) error {
 			commitMessageView.Title = gui.Tr.SLocalize("CommitMessage")
 			commitMessageView.FgColor = gocui.ColorWhite
 			commitMessageView.Editable = true
+			commitMessageView.Editor = gocui.EditorFunc(gui.commitMessageEditor)
 		}
 	}
 
@@ -521,7 +709,7 @@ func (gui *Gui) layout(g *gocui.Gui) error {
 	}
 
 	listViews := map[*gocui.View]listViewState{
-		filesView:    {selectedLine: gui.State.Panels.Files.SelectedLine, lineCount: len(gui.State.Files)},
+		filesView:    {selectedLine: gui.State.Panels.Files.SelectedLine, lineCount: len(gui.State.FileNodes)},
 		branchesView: {selectedLine: gui.State.Panels.Branches.SelectedLine, lineCount: len(gui.State.Branches)},
 		commitsView:  {selectedLine: gui.State.Panels.Commits.SelectedLine, lineCount: len(gui.State.Commits)},
 		stashView:    {selectedLine: gui.State.Panels.Stash.SelectedLine, lineCount: len(gui.State.StashEntries)},
@@ -663,7 +851,18 @@ func (gui *Gui) Run() error {
 	if gui.Config.GetUserConfig().GetBool("git.autoFetch") {
 		go gui.startBackgroundFetch()
 	}
-	gui.goEvery(time.Second*10, gui.refreshFiles)
+	if gui.Config.GetUserConfig().GetBool("git.autoRefresh") {
+		watcher, err := gui.startFileWatcher()
+		if err != nil {
+			// most likely the repo has more directories than we're allowed to watch
+			gui.Log.Error(err)
+			gui.goEvery(time.Second*10, gui.refreshFiles)
+		} else {
+			defer watcher.Close()
+		}
+	} else {
+		gui.goEvery(time.Second*10, gui.refreshFiles)
+	}
 	gui.goEvery(time.Millisecond*50, gui.renderAppStatus)
 
 	g.SetManager(gocui.ManagerFunc(gui.layout), gocui.ManagerFunc(gui.getFocusLayout()))
@@ -711,6 +910,14 @@ func (gui *Gui) runCommand() error {
 		gui.Log.Error(err)
 	}
 
+	if gui.SubProcessCallback != nil {
+		if err := gui.SubProcessCallback(); err != nil {
+			gui.Log.Error(err)
+			fmt.Fprintf(os.Stdout, "\n%s\n", utils.ColoredString(err.Error(), color.FgRed))
+		}
+		gui.SubProcessCallback = nil
+	}
+
 	gui.SubProcess.Stdout = ioutil.Discard
 	gui.SubProcess.Stderr = ioutil.Discard
 	gui.SubProcess.Stdin = nil
@@ -723,6 +930,11 @@ func (gui *Gui) runCommand() error {
 }
 
 func (gui *Gui) quit(g *gocui.Gui, v *gocui.View) error {
+	// if the main view is fullscreen, the side panels are hidden and it's easy
+	// to forget that they're still there, so we just put them back
+	if gui.State.FullscreenMain {
+		return gui.handleToggleFullscreenMain(g, v)
+	}
 	if gui.State.Updating {
 		return gui.createUpdateQuitConfirmation(g, v)
 	}
@@ -14,6 +14,9 @@ type Binding struct {
 	Modifier    gocui.Modifier
 	Description string
 	Alternative string
+	// Available says whether the binding applies in the current state; bindings
+	// that don't are left out of the options menu. Nil means always
+	Available func() bool
 }
 
 // GetDisplayStrings returns the display string of a file
@@ -60,7 +63,7 @@ func (b *Binding) GetKey() string {
 		return "PgDn"
 	}
 
-	return string(key)
+	return string(rune(key))
 }
 
 // GetInitialKeybindings is a function.
@@ -119,6 +122,7 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCreateRebaseOptionsMenu,
 			Description: gui.Tr.SLocalize("ViewMergeRebaseOptions"),
+			Available:   gui.operationInProgress,
 		}, {
 			ViewName:    "",
 			Key:         'P',
@@ -137,6 +141,12 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleRefresh,
 			Description: gui.Tr.SLocalize("refresh"),
+		}, {
+			ViewName:    "",
+			Key:         '+',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleToggleFullscreenMain,
+			Description: gui.Tr.SLocalize("ToggleFullscreenMain"),
 		}, {
 			ViewName: "",
 			Key:      'x',
@@ -186,6 +196,36 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleAmendCommitPress,
 			Description: gui.Tr.SLocalize("AmendLastCommit"),
+		}, {
+			ViewName:    "files",
+			Key:         'F',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleAmendFilePress,
+			Description: gui.Tr.SLocalize("amendFile"),
+		}, {
+			ViewName:    "files",
+			Key:         'B',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleAbsorbStagedChanges,
+			Description: gui.Tr.SLocalize("absorbStagedChanges"),
+		}, {
+			ViewName:    "files",
+			Key:         'E',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleApplyPatchFile,
+			Description: gui.Tr.SLocalize("applyPatchFile"),
+		}, {
+			ViewName:    "files",
+			Key:         'T',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleConventionalCommitPress,
+			Description: gui.Tr.SLocalize("conventionalCommit"),
+		}, {
+			ViewName:    "files",
+			Key:         'b',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCheckoutFileFromRef,
+			Description: gui.Tr.SLocalize("checkoutFileFromRef"),
 		}, {
 			ViewName:    "files",
 			Key:         'C',
@@ -210,6 +250,12 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleFileEdit,
 			Description: gui.Tr.SLocalize("editFile"),
+		}, {
+			ViewName:    "files",
+			Key:         'W',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleToggleIgnoreWhitespace,
+			Description: gui.Tr.SLocalize("ToggleIgnoreWhitespace"),
 		}, {
 			ViewName:    "files",
 			Key:         'o',
@@ -221,13 +267,67 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 			Key:         'i',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleIgnoreFile,
-			Description: gui.Tr.SLocalize("ignoreFile"),
+			Description: gui.Tr.SLocalize("viewIgnoreOptions"),
+		}, {
+			ViewName:    "files",
+			Key:         'I',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleEditIgnoreFiles,
+			Description: gui.Tr.SLocalize("editIgnoreFiles"),
+		}, {
+			ViewName:    "files",
+			Key:         gocui.KeyCtrlS,
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleFilterCommitsByFilePath,
+			Description: gui.Tr.SLocalize("filterCommitsByThisPath"),
 		}, {
 			ViewName:    "files",
 			Key:         'r',
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleRefreshFiles,
 			Description: gui.Tr.SLocalize("refreshFiles"),
+		}, {
+			ViewName:    "files",
+			Key:         'M',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleMoveFile,
+			Description: gui.Tr.SLocalize("moveFile"),
+		}, {
+			ViewName:    "files",
+			Key:         'O',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCreateFileSortMenu,
+			Description: gui.Tr.SLocalize("sortFiles"),
+		}, {
+			ViewName:    "files",
+			Key:         'u',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleIgnoreChanges,
+			Description: gui.Tr.SLocalize("viewIgnoreChangesOptions"),
+		}, {
+			ViewName:    "files",
+			Key:         'v',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleToggleFileMarked,
+			Description: gui.Tr.SLocalize("toggleFileMarked"),
+		}, {
+			ViewName:    "files",
+			Key:         'V',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleClearMarkedFiles,
+			Description: gui.Tr.SLocalize("clearMarkedFiles"),
+		}, {
+			ViewName:    "files",
+			Key:         'y',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCopyFilePath(false),
+			Description: gui.Tr.SLocalize("copyFilePath"),
+		}, {
+			ViewName:    "files",
+			Key:         'Y',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCopyFilePath(true),
+			Description: gui.Tr.SLocalize("copyAbsoluteFilePath"),
 		}, {
 			ViewName:    "files",
 			Key:         's',
@@ -246,6 +346,18 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleStageAll,
 			Description: gui.Tr.SLocalize("toggleStagedAll"),
+		}, {
+			ViewName:    "files",
+			Key:         'U',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleStageTracked,
+			Description: gui.Tr.SLocalize("stageTracked"),
+		}, {
+			ViewName:    "files",
+			Key:         'L',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCreateLFSMenu,
+			Description: gui.Tr.SLocalize("LFSOptions"),
 		}, {
 			ViewName:    "files",
 			Key:         't',
@@ -263,7 +375,13 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 			Key:         gocui.KeyEnter,
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleEnterFile,
-			Description: gui.Tr.SLocalize("StageLines"),
+			Description: gui.Tr.SLocalize("StageLinesOrToggleDirectory"),
+		}, {
+			ViewName:    "files",
+			Key:         '`',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleToggleFileTree,
+			Description: gui.Tr.SLocalize("ToggleFileTree"),
 		}, {
 			ViewName:    "files",
 			Key:         'f',
@@ -312,6 +430,47 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleDeleteBranch,
 			Description: gui.Tr.SLocalize("deleteBranch"),
+		}, {
+			ViewName:    "branches",
+			Key:         'R',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleRenameBranch,
+			Description: gui.Tr.SLocalize("renameBranch"),
+		}, {
+			ViewName:    "branches",
+			Key:         'u',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCreateUpstreamMenu,
+			Description: gui.Tr.SLocalize("setUpstream"),
+		}, {
+			ViewName:    "branches",
+			Key:         'W',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleToggleCompareBase,
+			Description: gui.Tr.SLocalize("toggleCompareBase"),
+		}, {
+			ViewName:    "branches",
+			Key:         '/',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleFilterBranches,
+			Description: gui.Tr.SLocalize("filterBranches"),
+		}, {
+			ViewName:    "branches",
+			Key:         'O',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCreateBranchSortMenu,
+			Description: gui.Tr.SLocalize("sortBranches"),
+		}, {
+			ViewName:    "branches",
+			Key:         'N',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleNewBranchFromRef,
+			Description: gui.Tr.SLocalize("newBranchFromRef"),
+		}, {
+			ViewName: "branches",
+			Key:      gocui.KeyEsc,
+			Modifier: gocui.ModNone,
+			Handler:  gui.handleBranchesEscape,
 		}, {
 			ViewName:    "branches",
 			Key:         'r',
@@ -330,6 +489,60 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleFastForward,
 			Description: gui.Tr.SLocalize("FastForward"),
+		}, {
+			ViewName:    "branches",
+			Key:         gocui.KeyEnter,
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleBrowseBranchCommits,
+			Description: gui.Tr.SLocalize("browseBranchCommits"),
+		}, {
+			ViewName:    "branches",
+			Key:         ']',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleSwitchBranchesTab,
+			Description: gui.Tr.SLocalize("nextTab"),
+		}, {
+			ViewName:    "branches",
+			Key:         '[',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleSwitchBranchesTab,
+			Description: gui.Tr.SLocalize("prevTab"),
+		}, {
+			ViewName:    "remoteBranches",
+			Key:         ']',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleSwitchBranchesTab,
+			Description: gui.Tr.SLocalize("nextTab"),
+		}, {
+			ViewName:    "remoteBranches",
+			Key:         '[',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleSwitchBranchesTab,
+			Description: gui.Tr.SLocalize("prevTab"),
+		}, {
+			ViewName:    "remoteBranches",
+			Key:         gocui.KeySpace,
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCheckoutRemoteBranchPress,
+			Description: gui.Tr.SLocalize("checkoutRemoteBranch"),
+		}, {
+			ViewName:    "remoteBranches",
+			Key:         'd',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleDeleteRemoteBranchPress,
+			Description: gui.Tr.SLocalize("deleteFromRemote"),
+		}, {
+			ViewName:    "remoteBranches",
+			Key:         gocui.KeyEnter,
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleBrowseRemoteBranchCommits,
+			Description: gui.Tr.SLocalize("browseBranchCommits"),
+		}, {
+			ViewName:    "remoteBranches",
+			Key:         gocui.KeyEsc,
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleSwitchBranchesTab,
+			Description: gui.Tr.SLocalize("backToBranches"),
 		}, {
 			ViewName:    "commits",
 			Key:         's',
@@ -396,6 +609,18 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCommitEdit,
 			Description: gui.Tr.SLocalize("editCommit"),
+		}, {
+			ViewName:    "commits",
+			Key:         'X',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleSplitCommit,
+			Description: gui.Tr.SLocalize("splitCommit"),
+		}, {
+			ViewName:    "commits",
+			Key:         'E',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCreatePatchFilesMenu,
+			Description: gui.Tr.SLocalize("viewPatchFileOptions"),
 		}, {
 			ViewName:    "commits",
 			Key:         'A',
@@ -426,6 +651,36 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCopyCommitRange,
 			Description: gui.Tr.SLocalize("cherryPickCopyRange"),
+		}, {
+			ViewName:    "commits",
+			Key:         'y',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCreateCopyCommitMenu,
+			Description: gui.Tr.SLocalize("copyCommitToClipboard"),
+		}, {
+			ViewName:    "commits",
+			Key:         'T',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleTagCommit,
+			Description: gui.Tr.SLocalize("tagCommit"),
+		}, {
+			ViewName:    "commits",
+			Key:         'O',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCheckoutCommit,
+			Description: gui.Tr.SLocalize("checkoutCommit"),
+		}, {
+			ViewName:    "commits",
+			Key:         'n',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleNewBranchAtCommit,
+			Description: gui.Tr.SLocalize("newBranchAtCommit"),
+		}, {
+			ViewName:    "commits",
+			Key:         'b',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCreateBisectMenu,
+			Description: gui.Tr.SLocalize("bisectOptions"),
 		}, {
 			ViewName:    "commits",
 			Key:         'v',
@@ -444,6 +699,113 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleToggleDiffCommit,
 			Description: gui.Tr.SLocalize("CommitsDiff"),
+		}, {
+			ViewName:    "commits",
+			Key:         'M',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCycleMergeDiff,
+			Description: gui.Tr.SLocalize("cycleMergeDiff"),
+		}, {
+			ViewName:    "commits",
+			Key:         'H',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleToggleSyntaxHighlighting,
+			Description: gui.Tr.SLocalize("ToggleSyntaxHighlighting"),
+		}, {
+			ViewName:    "commits",
+			Key:         gocui.KeyCtrlP,
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCreatePatchOptionsMenu,
+			Description: gui.Tr.SLocalize("ViewPatchOptions"),
+		}, {
+			ViewName:    "commits",
+			Key:         gocui.KeyCtrlS,
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCreateCommitsFilterMenu,
+			Description: gui.Tr.SLocalize("filterCommits"),
+		}, {
+			ViewName:    "commits",
+			Key:         gocui.KeyCtrlG,
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleGotoCommit,
+			Description: gui.Tr.SLocalize("gotoCommit"),
+		}, {
+			ViewName:    "commits",
+			Key:         'D',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCreateCommitDateMenu,
+			Description: gui.Tr.SLocalize("commitDateOptions"),
+		}, {
+			ViewName:    "commits",
+			Key:         'a',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleToggleCommitMarked,
+			Description: gui.Tr.SLocalize("toggleCommitMarked"),
+		}, {
+			ViewName:    "commits",
+			Key:         'w',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleShowContainingRefs,
+			Description: gui.Tr.SLocalize("showContainingRefs"),
+		}, {
+			ViewName: "commits",
+			Key:      gocui.KeyEsc,
+			Modifier: gocui.ModNone,
+			Handler:  gui.handleCommitsEscape,
+		}, {
+			ViewName:    "commits",
+			Key:         ']',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleSwitchCommitsTab,
+			Description: gui.Tr.SLocalize("nextTab"),
+		}, {
+			ViewName:    "commits",
+			Key:         '[',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleSwitchCommitsTab,
+			Description: gui.Tr.SLocalize("prevTab"),
+		}, {
+			ViewName:    "reflog",
+			Key:         ']',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleSwitchCommitsTab,
+			Description: gui.Tr.SLocalize("nextTab"),
+		}, {
+			ViewName:    "reflog",
+			Key:         '[',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleSwitchCommitsTab,
+			Description: gui.Tr.SLocalize("prevTab"),
+		}, {
+			ViewName:    "reflog",
+			Key:         gocui.KeySpace,
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCheckoutReflogEntry,
+			Description: gui.Tr.SLocalize("checkoutReflogEntry"),
+		}, {
+			ViewName:    "reflog",
+			Key:         'n',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleNewBranchAtReflogEntry,
+			Description: gui.Tr.SLocalize("newBranchAtReflogEntry"),
+		}, {
+			ViewName:    "reflog",
+			Key:         'c',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCherryPickReflogEntry,
+			Description: gui.Tr.SLocalize("cherryPickReflogEntry"),
+		}, {
+			ViewName:    "reflog",
+			Key:         'g',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleHardResetToReflogEntry,
+			Description: gui.Tr.SLocalize("hardResetToReflogEntry"),
+		}, {
+			ViewName:    "reflog",
+			Key:         gocui.KeyEsc,
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleSwitchCommitsTab,
+			Description: gui.Tr.SLocalize("backToCommits"),
 		}, {
 			ViewName:    "stash",
 			Key:         gocui.KeySpace,
@@ -462,6 +824,12 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleStashDrop,
 			Description: gui.Tr.SLocalize("drop"),
+		}, {
+			ViewName:    "stash",
+			Key:         'n',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleStashBranch,
+			Description: gui.Tr.SLocalize("newBranchFromStash"),
 		}, {
 			ViewName: "commitMessage",
 			Key:      gocui.KeyEnter,
@@ -472,6 +840,12 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 			Key:      gocui.KeyEsc,
 			Modifier: gocui.ModNone,
 			Handler:  gui.handleCommitClose,
+		}, {
+			ViewName:    "commitMessage",
+			Key:         gocui.KeyCtrlO,
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleAddCoAuthor,
+			Description: gui.Tr.SLocalize("addCoAuthor"),
 		}, {
 			ViewName: "credentials",
 			Key:      gocui.KeyEnter,
@@ -509,6 +883,12 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 			Modifier:    gocui.ModNone,
 			Handler:     gui.handleCheckoutCommitFile,
 			Description: gui.Tr.SLocalize("checkoutCommitFile"),
+		}, {
+			ViewName:    "commitFiles",
+			Key:         'b',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCheckoutCommitFileFromRef,
+			Description: gui.Tr.SLocalize("checkoutFileFromRef"),
 		}, {
 			ViewName:    "commitFiles",
 			Key:         'd',
@@ -523,9 +903,30 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 			Handler:     gui.handleOpenOldCommitFile,
 			Description: gui.Tr.SLocalize("openFile"),
 		},
+		{
+			ViewName:    "commitFiles",
+			Key:         'y',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCopyCommitFilePath(false),
+			Description: gui.Tr.SLocalize("copyFilePath"),
+		},
+		{
+			ViewName:    "commitFiles",
+			Key:         'Y',
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleCopyCommitFilePath(true),
+			Description: gui.Tr.SLocalize("copyAbsoluteFilePath"),
+		},
+		{
+			ViewName:    "commitFiles",
+			Key:         gocui.KeyEnter,
+			Modifier:    gocui.ModNone,
+			Handler:     gui.handleEnterCommitFile,
+			Description: gui.Tr.SLocalize("SelectLinesForPatch"),
+		},
 	}
 
-	for _, viewName := range []string{"status", "branches", "files", "commits", "commitFiles", "stash", "menu"} {
+	for _, viewName := range []string{"status", "branches", "remoteBranches", "files", "commits", "commitFiles", "stash", "menu"} {
 		bindings = append(bindings, []*Binding{
 			{ViewName: viewName, Key: gocui.KeyTab, Modifier: gocui.ModNone, Handler: gui.nextView},
 			{ViewName: viewName, Key: gocui.KeyArrowLeft, Modifier: gocui.ModNone, Handler: gui.previousView},
@@ -540,13 +941,15 @@ func (gui *Gui) GetInitialKeybindings() []*Binding {
 		nextLine func(*gocui.Gui, *gocui.View) error
 		focus    func(*gocui.Gui, *gocui.View) error
 	}{
-		"menu":        {prevLine: gui.handleMenuPrevLine, nextLine: gui.handleMenuNextLine, focus: gui.handleMenuSelect},
-		"files":       {prevLine: gui.handleFilesPrevLine, nextLine: gui.handleFilesNextLine, focus: gui.handleFilesFocus},
-		"branches":    {prevLine: gui.handleBranchesPrevLine, nextLine: gui.handleBranchesNextLine, focus: gui.handleBranchSelect},
-		"commits":     {prevLine: gui.handleCommitsPrevLine, nextLine: gui.handleCommitsNextLine, focus: gui.handleCommitSelect},
-		"stash":       {prevLine: gui.handleStashPrevLine, nextLine: gui.handleStashNextLine, focus: gui.handleStashEntrySelect},
-		"status":      {focus: gui.handleStatusSelect},
-		"commitFiles": {prevLine: gui.handleCommitFilesPrevLine, nextLine: gui.handleCommitFilesNextLine, focus: gui.handleCommitFileSelect},
+		"menu":           {prevLine: gui.handleMenuPrevLine, nextLine: gui.handleMenuNextLine, focus: gui.handleMenuSelect},
+		"files":          {prevLine: gui.handleFilesPrevLine, nextLine: gui.handleFilesNextLine, focus: gui.handleFilesFocus},
+		"branches":       {prevLine: gui.handleBranchesPrevLine, nextLine: gui.handleBranchesNextLine, focus: gui.handleBranchSelect},
+		"commits":        {prevLine: gui.handleCommitsPrevLine, nextLine: gui.handleCommitsNextLine, focus: gui.handleCommitSelect},
+		"stash":          {prevLine: gui.handleStashPrevLine, nextLine: gui.handleStashNextLine, focus: gui.handleStashEntrySelect},
+		"status":         {focus: gui.handleStatusSelect},
+		"commitFiles":    {prevLine: gui.handleCommitFilesPrevLine, nextLine: gui.handleCommitFilesNextLine, focus: gui.handleCommitFileSelect},
+		"reflog":         {prevLine: gui.handleReflogPrevLine, nextLine: gui.handleReflogNextLine, focus: gui.handleReflogEntrySelect},
+		"remoteBranches": {prevLine: gui.handleRemoteBranchesPrevLine, nextLine: gui.handleRemoteBranchesNextLine, focus: gui.handleRemoteBranchSelect},
 	}
 
 	for viewName, functions := range listPanelMap {
@@ -582,6 +985,16 @@ func (gui *Gui) keybindings(g *gocui.Gui) error {
 			return err
 		}
 	}
+	for _, viewName := range []string{"commits", "reflog"} {
+		if err := g.SetTabClickBinding(viewName, gui.switchToCommitsTab); err != nil {
+			return err
+		}
+	}
+	for _, viewName := range []string{"branches", "remoteBranches"} {
+		if err := g.SetTabClickBinding(viewName, gui.switchToBranchesTab); err != nil {
+			return err
+		}
+	}
 	if err := gui.setInitialContexts(); err != nil {
 		return err
 	}
@@ -647,6 +1060,11 @@ func (gui *Gui) GetContextMap() map[string]map[string][]*Binding {
 					Key:      gocui.MouseWheelDown,
 					Modifier: gocui.ModNone,
 					Handler:  gui.handleStagingNextLine,
+				}, {
+					ViewName: "main",
+					Key:      gocui.MouseLeft,
+					Modifier: gocui.ModNone,
+					Handler:  gui.handleStagingClick,
 				}, {
 					ViewName:    "main",
 					Key:         gocui.KeyArrowLeft,
@@ -681,6 +1099,242 @@ func (gui *Gui) GetContextMap() map[string]map[string][]*Binding {
 					Modifier:    gocui.ModNone,
 					Handler:     gui.handleStageHunk,
 					Description: gui.Tr.SLocalize("StageHunk"),
+				}, {
+					ViewName:    "main",
+					Key:         'A',
+					Modifier:    gocui.ModNone,
+					Handler:     gui.handleStageFile,
+					Description: gui.Tr.SLocalize("StageFile"),
+				}, {
+					ViewName:    "main",
+					Key:         'S',
+					Modifier:    gocui.ModNone,
+					Handler:     gui.handleStartRefining,
+					Description: gui.Tr.SLocalize("RefineHunk"),
+				}, {
+					ViewName:    "main",
+					Key:         gocui.KeyEnter,
+					Modifier:    gocui.ModNone,
+					Handler:     gui.handleApplyRefinedLines,
+					Description: gui.Tr.SLocalize("ApplyRefinedLines"),
+				}, {
+					ViewName:    "main",
+					Key:         'v',
+					Modifier:    gocui.ModNone,
+					Handler:     gui.handleToggleSelectRange,
+					Description: gui.Tr.SLocalize("ToggleSelectRange"),
+				}, {
+					ViewName:    "main",
+					Key:         gocui.KeyTab,
+					Modifier:    gocui.ModNone,
+					Handler:     gui.handleToggleStagedSide,
+					Description: gui.Tr.SLocalize("ToggleStagedSide"),
+				}, {
+					ViewName:    "main",
+					Key:         'e',
+					Modifier:    gocui.ModNone,
+					Handler:     gui.handleEditHunk,
+					Description: gui.Tr.SLocalize("EditHunk"),
+				}, {
+					ViewName:    "main",
+					Key:         'd',
+					Modifier:    gocui.ModNone,
+					Handler:     gui.handleDiscardLine,
+					Description: gui.Tr.SLocalize("DiscardLine"),
+				}, {
+					ViewName:    "main",
+					Key:         'D',
+					Modifier:    gocui.ModNone,
+					Handler:     gui.handleDiscardHunk,
+					Description: gui.Tr.SLocalize("DiscardHunk"),
+				}, {
+					ViewName:    "main",
+					Key:         '[',
+					Modifier:    gocui.ModNone,
+					Handler:     gui.handleStagingPrevFile,
+					Description: gui.Tr.SLocalize("PrevFile"),
+				}, {
+					ViewName:    "main",
+					Key:         ']',
+					Modifier:    gocui.ModNone,
+					Handler:     gui.handleStagingNextFile,
+					Description: gui.Tr.SLocalize("NextFile"),
+				}, {
+					ViewName:    "main",
+					Key:         'u',
+					Modifier:    gocui.ModNone,
+					Handler:     gui.handleStagingUndo,
+					Description: gui.Tr.SLocalize("Undo"),
+				}, {
+					ViewName:    "main",
+					Key:         'H',
+					Modifier:    gocui.ModNone,
+					Handler:     gui.handleToggleSyntaxHighlighting,
+					Description: gui.Tr.SLocalize("ToggleSyntaxHighlighting"),
+				}, {
+					ViewName:    "main",
+					Key:         'W',
+					Modifier:    gocui.ModNone,
+					Handler:     gui.handleToggleIgnoreWhitespace,
+					Description: gui.Tr.SLocalize("ToggleIgnoreWhitespace"),
+				}, {
+					ViewName:    "main",
+					Key:         's',
+					Modifier:    gocui.ModNone,
+					Handler:     gui.handleSplitHunk,
+					Description: gui.Tr.SLocalize("SplitHunk"),
+				}, {
+					ViewName:    "main",
+					Key:         '{',
+					Modifier:    gocui.ModNone,
+					Handler:     gui.handleDecreaseContextSize,
+					Description: gui.Tr.SLocalize("DecreaseContextSize"),
+				}, {
+					ViewName:    "main",
+					Key:         '}',
+					Modifier:    gocui.ModNone,
+					Handler:     gui.handleIncreaseContextSize,
+					Description: gui.Tr.SLocalize("IncreaseContextSize"),
+				}, {
+					ViewName:    "main",
+					Key:         'p',
+					Modifier:    gocui.ModNone,
+					Handler:     gui.handleToggleLinesInPatch,
+					Description: gui.Tr.SLocalize("ToggleLinesInPatch"),
+				}, {
+					ViewName:    "main",
+					Key:         gocui.KeyCtrlP,
+					Modifier:    gocui.ModNone,
+					Handler:     gui.handleCreatePatchOptionsMenu,
+					Description: gui.Tr.SLocalize("ViewPatchOptions"),
+				}, {
+					ViewName:    "main",
+					Key:         '/',
+					Modifier:    gocui.ModNone,
+					Handler:     gui.handleStagingSearch,
+					Description: gui.Tr.SLocalize("StagingSearch"),
+				}, {
+					ViewName:    "main",
+					Key:         'n',
+					Modifier:    gocui.ModNone,
+					Handler:     gui.handleStagingNextSearchMatch,
+					Description: gui.Tr.SLocalize("NextSearchMatch"),
+				}, {
+					ViewName:    "main",
+					Key:         'N',
+					Modifier:    gocui.ModNone,
+					Handler:     gui.handleStagingPrevSearchMatch,
+					Description: gui.Tr.SLocalize("PrevSearchMatch"),
+				},
+			},
+			"patchBuilding": {
+				{
+					ViewName:    "main",
+					Key:         gocui.KeyEsc,
+					Modifier:    gocui.ModNone,
+					Handler:     gui.handlePatchBuildingEscape,
+					Description: gui.Tr.SLocalize("ReturnToCommitFiles"),
+				}, {
+					ViewName:    "main",
+					Key:         gocui.KeyArrowUp,
+					Modifier:    gocui.ModNone,
+					Handler:     gui.handleStagingPrevLine,
+					Description: gui.Tr.SLocalize("PrevLine"),
+				}, {
+					ViewName:    "main",
+					Key:         gocui.KeyArrowDown,
+					Modifier:    gocui.ModNone,
+					Handler:     gui.handleStagingNextLine,
+					Description: gui.Tr.SLocalize("NextLine"),
+				}, {
+					ViewName: "main",
+					Key:      'k',
+					Modifier: gocui.ModNone,
+					Handler:  gui.handleStagingPrevLine,
+				}, {
+					ViewName: "main",
+					Key:      'j',
+					Modifier: gocui.ModNone,
+					Handler:  gui.handleStagingNextLine,
+				}, {
+					ViewName: "main",
+					Key:      gocui.MouseWheelUp,
+					Modifier: gocui.ModNone,
+					Handler:  gui.handleStagingPrevLine,
+				}, {
+					ViewName: "main",
+					Key:      gocui.MouseWheelDown,
+					Modifier: gocui.ModNone,
+					Handler:  gui.handleStagingNextLine,
+				}, {
+					ViewName: "main",
+					Key:      gocui.MouseLeft,
+					Modifier: gocui.ModNone,
+					Handler:  gui.handleStagingClick,
+				}, {
+					ViewName:    "main",
+					Key:         gocui.KeyArrowLeft,
+					Modifier:    gocui.ModNone,
+					Handler:     gui.handleStagingPrevHunk,
+					Description: gui.Tr.SLocalize("PrevHunk"),
+				}, {
+					ViewName:    "main",
+					Key:         gocui.KeyArrowRight,
+					Modifier:    gocui.ModNone,
+					Handler:     gui.handleStagingNextHunk,
+					Description: gui.Tr.SLocalize("NextHunk"),
+				}, {
+					ViewName: "main",
+					Key:      'h',
+					Modifier: gocui.ModNone,
+					Handler:  gui.handleStagingPrevHunk,
+				}, {
+					ViewName: "main",
+					Key:      'l',
+					Modifier: gocui.ModNone,
+					Handler:  gui.handleStagingNextHunk,
+				}, {
+					ViewName:    "main",
+					Key:         gocui.KeySpace,
+					Modifier:    gocui.ModNone,
+					Handler:     gui.handleToggleLinesInPatch,
+					Description: gui.Tr.SLocalize("ToggleLinesInPatch"),
+				}, {
+					ViewName:    "main",
+					Key:         'a',
+					Modifier:    gocui.ModNone,
+					Handler:     gui.handleToggleHunkInPatch,
+					Description: gui.Tr.SLocalize("ToggleHunkInPatch"),
+				}, {
+					ViewName:    "main",
+					Key:         'v',
+					Modifier:    gocui.ModNone,
+					Handler:     gui.handleToggleSelectRange,
+					Description: gui.Tr.SLocalize("ToggleSelectRange"),
+				}, {
+					ViewName:    "main",
+					Key:         gocui.KeyCtrlP,
+					Modifier:    gocui.ModNone,
+					Handler:     gui.handleCreatePatchOptionsMenu,
+					Description: gui.Tr.SLocalize("ViewPatchOptions"),
+				}, {
+					ViewName:    "main",
+					Key:         '/',
+					Modifier:    gocui.ModNone,
+					Handler:     gui.handleStagingSearch,
+					Description: gui.Tr.SLocalize("StagingSearch"),
+				}, {
+					ViewName:    "main",
+					Key:         'n',
+					Modifier:    gocui.ModNone,
+					Handler:     gui.handleStagingNextSearchMatch,
+					Description: gui.Tr.SLocalize("NextSearchMatch"),
+				}, {
+					ViewName:    "main",
+					Key:         'N',
+					Modifier:    gocui.ModNone,
+					Handler:     gui.handleStagingPrevSearchMatch,
+					Description: gui.Tr.SLocalize("PrevSearchMatch"),
 				},
 			},
 			"merging": {
@@ -0,0 +1,77 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazygit/pkg/commands"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+)
+
+// describeLFSDiff shows which object each side of an LFS file's diff points
+// at, as the pointer text says little on its own. If the diff isn't of
+// pointers, e.g. because git LFS shows the real content, we leave it be
+func (gui *Gui) describeLFSDiff(diff string) string {
+	oldPointer, newPointer, ok := commands.ParseLFSPointerDiff(utils.Decolorise(diff))
+	if !ok {
+		return diff
+	}
+	lines := []string{
+		utils.ColoredString(gui.Tr.SLocalize("LFSFileTitle"), color.FgCyan),
+		"",
+		gui.Tr.SLocalize("LFSOldContent") + " " + gui.describeLFSPointer(oldPointer),
+		gui.Tr.SLocalize("LFSNewContent") + " " + gui.describeLFSPointer(newPointer),
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (gui *Gui) describeLFSPointer(pointer *commands.LFSPointer) string {
+	if pointer == nil {
+		return gui.Tr.SLocalize("LFSNoContent")
+	}
+	return fmt.Sprintf("%s (%s)", pointer.Oid, utils.FormatBytes(pointer.Size))
+}
+
+type lfsOption struct {
+	description string
+	handler     func(fileName string) error
+}
+
+// GetDisplayStrings is a function.
+func (o *lfsOption) GetDisplayStrings(isFocused bool) []string {
+	return []string{o.description}
+}
+
+// handleCreateLFSMenu offers to lock or unlock the selected LFS file, for
+// repos where binary files are locked while someone works on them
+func (gui *Gui) handleCreateLFSMenu(g *gocui.Gui, v *gocui.View) error {
+	file, err := gui.getSelectedFile(g)
+	if err != nil {
+		if err != gui.Errors.ErrNoFiles {
+			return err
+		}
+		return nil
+	}
+	if !file.IsLFS {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("NotAnLFSFile"))
+	}
+
+	options := []*lfsOption{
+		{description: gui.Tr.SLocalize("lockLFSFile"), handler: gui.GitCommand.LockLFSFile},
+		{description: gui.Tr.SLocalize("unlockLFSFile"), handler: gui.GitCommand.UnlockLFSFile},
+	}
+
+	handleMenuPress := func(index int) error {
+		return gui.WithWaitingStatus(gui.Tr.SLocalize("LFSStatus"), func() error {
+			if err := options[index].handler(file.CurrentPath()); err != nil {
+				return gui.createErrorPanel(gui.g, err.Error())
+			}
+			return gui.refreshFiles()
+		})
+	}
+
+	title := gui.Tr.TemplateLocalize("LFSOptionsTitle", Teml{"path": file.CurrentPath()})
+	return gui.createMenu(title, options, len(options), handleMenuPress)
+}
@@ -0,0 +1,79 @@
+package gui
+
+import (
+	"github.com/jesseduffield/gocui"
+)
+
+// commits can be marked so that squashing, fixing up or dropping acts on all
+// of them in one rebase, rather than in a rebase for each
+
+// setMarkedCommits marks the commits whose shas are marked, forgetting the
+// shas of commits that are no longer listed, e.g. because a rebase has
+// rewritten them
+func (gui *Gui) setMarkedCommits() {
+	panelState := gui.State.Panels.Commits
+	markedShas := map[string]bool{}
+	for _, commit := range gui.State.Commits {
+		commit.Marked = panelState.MarkedShas[commit.Sha]
+		if commit.Marked {
+			markedShas[commit.Sha] = true
+		}
+	}
+	panelState.MarkedShas = markedShas
+}
+
+// handleToggleCommitMarked marks the selected commit, or unmarks it if it's
+// already marked. We then move on to the next line so that several commits
+// can be marked in a row
+func (gui *Gui) handleToggleCommitMarked(g *gocui.Gui, v *gocui.View) error {
+	commit := gui.getSelectedCommit(g)
+	if commit == nil {
+		return nil
+	}
+	if commit.Status == "rebasing" {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("CantMarkRebasingCommit"))
+	}
+
+	markedShas := gui.State.Panels.Commits.MarkedShas
+	if markedShas[commit.Sha] {
+		delete(markedShas, commit.Sha)
+	} else {
+		markedShas[commit.Sha] = true
+	}
+
+	if err := gui.handleCommitsNextLine(g, v); err != nil {
+		return err
+	}
+	return gui.refreshCommits(g)
+}
+
+// handleRebaseMarkedCommits squashes, fixes up or drops every marked commit
+// in a single rebase
+func (gui *Gui) handleRebaseMarkedCommits(v *gocui.View, action string) error {
+	if filtered, err := gui.handleRebaseNotAllowed(); filtered {
+		return err
+	}
+
+	actions := map[string]string{}
+	for _, commit := range gui.State.Commits {
+		if commit.Marked {
+			actions[commit.Sha] = action
+		}
+	}
+
+	ids := map[string]struct{ title, prompt, status string }{
+		"squash": {"Squash", "SureSquashMarkedCommits", "SquashingStatus"},
+		"fixup":  {"Fixup", "SureFixupMarkedCommits", "FixingStatus"},
+		"drop":   {"DeleteCommitTitle", "SureDropMarkedCommits", "DeletingStatus"},
+	}[action]
+	prompt := gui.Tr.TemplateLocalize(ids.prompt, Teml{"count": len(actions)})
+	return gui.createConfirmationPanel(gui.g, v, gui.Tr.SLocalize(ids.title), prompt, func(g *gocui.Gui, v *gocui.View) error {
+		return gui.WithWaitingStatus(gui.Tr.SLocalize(ids.status), func() error {
+			err := gui.GitCommand.InteractiveRebaseCommits(gui.State.Commits, actions)
+			if err == nil {
+				gui.State.Panels.Commits.MarkedShas = map[string]bool{}
+			}
+			return gui.handleGenericMergeCommandResult(err)
+		})
+	}, nil)
+}
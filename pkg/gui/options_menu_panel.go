@@ -16,6 +16,9 @@ func (gui *Gui) getBindings(v *gocui.View) []*Binding {
 	bindings := gui.GetCurrentKeybindings()
 
 	for _, binding := range bindings {
+		if binding.Available != nil && !binding.Available() {
+			continue
+		}
 		if binding.GetKey() != "" && binding.Description != "" {
 			switch binding.ViewName {
 			case "":
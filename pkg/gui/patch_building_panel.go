@@ -0,0 +1,80 @@
+package gui
+
+import (
+	"github.com/golang-collections/collections/stack"
+	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazygit/pkg/git"
+)
+
+// the patch building panel is the staging panel pointed at the diff of a file
+// in a commit. Rather than staging lines, you add them to the custom patch, so
+// that they can then be split out of the commit
+
+func (gui *Gui) handleEnterCommitFile(g *gocui.Gui, v *gocui.View) error {
+	if gui.getSelectedCommitFile(g) == nil {
+		return nil
+	}
+	if readOnly, err := gui.handleCompareFilesReadOnly(); readOnly {
+		return err
+	}
+	if err := gui.changeContext("main", "patchBuilding"); err != nil {
+		return err
+	}
+	if err := gui.switchFocus(g, v, gui.getMainView()); err != nil {
+		return err
+	}
+	return gui.refreshPatchBuildingPanel()
+}
+
+func (gui *Gui) refreshPatchBuildingPanel() error {
+	file := gui.getSelectedCommitFile(gui.g)
+	if file == nil {
+		return gui.handlePatchBuildingEscape(gui.g, nil)
+	}
+
+	diff, err := gui.GitCommand.CommitFileDiff(file.Sha, file.Name, gui.State.DiffContextSize)
+	if err != nil {
+		return gui.createErrorPanel(gui.g, err.Error())
+	}
+
+	diffState := git.NewDiffState(gui.Log, gui.Tr, diff)
+	if diffState.IsBinary() || len(diffState.StageableLines()) == 0 {
+		if err := gui.handlePatchBuildingEscape(gui.g, nil); err != nil {
+			return err
+		}
+		return gui.createErrorPanel(gui.g, "No lines to select")
+	}
+
+	gui.State.Panels.Staging = &stagingPanelState{
+		DiffState:    diffState,
+		ColorDiff:    gui.renderDiff(diff),
+		PatchHistory: stack.New(),
+		FileName:     file.Name,
+		CommitSha:    file.Sha,
+	}
+
+	if err := gui.focusLineAndHunk(); err != nil {
+		return err
+	}
+
+	mainView := gui.getMainView()
+	mainView.Highlight = true
+	mainView.Wrap = false
+	mainView.Title = gui.Tr.SLocalize("PatchBuildingMainTitle")
+
+	return gui.renderStagingDiff()
+}
+
+// handleToggleHunkInPatch adds the selected hunk to the custom patch, or
+// removes it if it's already in there
+func (gui *Gui) handleToggleHunkInPatch(g *gocui.Gui, v *gocui.View) error {
+	state := gui.State.Panels.Staging
+	state.SelectingRange = false
+	return gui.toggleLinesInPatch(gui.currentHunkStageableLines())
+}
+
+func (gui *Gui) handlePatchBuildingEscape(g *gocui.Gui, v *gocui.View) error {
+	gui.State.Panels.Staging = nil
+
+	return gui.switchFocus(gui.g, nil, gui.getCommitFilesView())
+}
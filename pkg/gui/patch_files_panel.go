@@ -0,0 +1,103 @@
+package gui
+
+import (
+	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+)
+
+type patchFileOption struct {
+	description string
+	onPress     func() error
+}
+
+// GetDisplayStrings is a function.
+func (o *patchFileOption) GetDisplayStrings(isFocused bool) []string {
+	return []string{o.description}
+}
+
+// handleCreatePatchFilesMenu offers to export the selected commit, or it and
+// the commits after it, as patch files, or to make commits from a patch file
+func (gui *Gui) handleCreatePatchFilesMenu(g *gocui.Gui, v *gocui.View) error {
+	options := []*patchFileOption{}
+
+	commit := gui.getSelectedCommit(g)
+	// commits still to be picked in a rebase aren't part of the history yet
+	if commit != nil && commit.Status != "rebasing" {
+		options = append(options, &patchFileOption{
+			description: gui.Tr.TemplateLocalize("exportCommitAsPatch", Teml{"sha": commit.Sha}),
+			onPress:     func() error { return gui.promptForPatchDirectory(v, commit.Sha, "") },
+		})
+		if gui.State.Panels.Commits.SelectedLine > 0 {
+			upTo := gui.State.BrowsingBranch
+			if upTo == "" {
+				upTo = "HEAD"
+			}
+			options = append(options, &patchFileOption{
+				description: gui.Tr.TemplateLocalize("exportCommitsAsPatches", Teml{"sha": commit.Sha}),
+				onPress:     func() error { return gui.promptForPatchDirectory(v, commit.Sha, upTo) },
+			})
+		}
+	}
+	options = append(options, &patchFileOption{
+		description: gui.Tr.SLocalize("applyPatchFileAsCommits"),
+		onPress: func() error {
+			if readOnly, err := gui.handleCommitsReadOnly(); readOnly {
+				return err
+			}
+			return gui.promptForPatchFile(v, gui.GitCommand.ApplyPatchFileAsCommits)
+		},
+	})
+
+	handleMenuPress := func(index int) error {
+		return options[index].onPress()
+	}
+
+	return gui.createMenu(gui.Tr.SLocalize("PatchFilesTitle"), options, len(options), handleMenuPress)
+}
+
+// promptForPatchDirectory asks which directory to write the patch files of the
+// commits to, completing directory names when you press tab
+func (gui *Gui) promptForPatchDirectory(v *gocui.View, sha string, upTo string) error {
+	if err := gui.createPromptPanel(gui.g, v, gui.Tr.SLocalize("PatchDirectoryTitle"), func(g *gocui.Gui, promptView *gocui.View) error {
+		directory := gui.trimmedContent(promptView)
+		if directory == "" {
+			return nil
+		}
+		paths, err := gui.GitCommand.ExportPatches(sha, upTo, directory)
+		if err != nil {
+			return gui.createErrorPanel(g, err.Error())
+		}
+		message := gui.Tr.TemplateLocalize("ExportedPatches", Teml{"count": len(paths), "directory": directory})
+		return gui.createMessagePanel(g, v, "", message)
+	}); err != nil {
+		return err
+	}
+
+	promptView, _ := gui.g.View("confirmation")
+	gui.setPromptContent(promptView, "./")
+	return gui.g.SetKeybinding("confirmation", gocui.KeyTab, gocui.ModNone, func(g *gocui.Gui, v *gocui.View) error {
+		gui.setPromptContent(v, utils.CompleteDirectory(gui.trimmedContent(v)))
+		return nil
+	})
+}
+
+// promptForPatchFile asks for the path of a patch file and applies it
+func (gui *Gui) promptForPatchFile(v *gocui.View, apply func(path string) error) error {
+	return gui.createPromptPanel(gui.g, v, gui.Tr.SLocalize("PatchFileTitle"), func(g *gocui.Gui, promptView *gocui.View) error {
+		path := gui.trimmedContent(promptView)
+		if path == "" {
+			return nil
+		}
+		return gui.WithWaitingStatus(gui.Tr.SLocalize("ApplyingPatchStatus"), func() error {
+			if err := apply(path); err != nil {
+				_ = gui.createErrorPanel(gui.g, err.Error())
+			}
+			return gui.refreshSidePanels(gui.g)
+		})
+	})
+}
+
+// handleApplyPatchFile applies a patch file to the working tree
+func (gui *Gui) handleApplyPatchFile(g *gocui.Gui, v *gocui.View) error {
+	return gui.promptForPatchFile(v, gui.GitCommand.ApplyPatchFile)
+}
@@ -0,0 +1,241 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jesseduffield/gocui"
+)
+
+type patchOption struct {
+	description string
+	onPress     func() error
+}
+
+// GetDisplayStrings is a function.
+func (o *patchOption) GetDisplayStrings(isFocused bool) []string {
+	return []string{o.description}
+}
+
+func (gui *Gui) handleCreatePatchOptionsMenu(g *gocui.Gui, v *gocui.View) error {
+	if gui.State.PatchManager.IsEmpty() {
+		return gui.createErrorPanel(gui.g, gui.Tr.SLocalize("NoPatchError"))
+	}
+	if gui.dropChangedPatchFiles() {
+		if gui.State.Panels.Staging != nil {
+			if err := gui.renderStagingDiff(); err != nil {
+				return err
+			}
+		}
+		return gui.createErrorPanel(gui.g, gui.Tr.SLocalize("PatchFilesChanged"))
+	}
+
+	commit := gui.getSelectedCommit(g)
+	var options []*patchOption
+	// a patch built from a commit's diff can be split out of that commit or
+	// moved into another one
+	if commitSha := gui.State.PatchManager.CommitSha(); commitSha != "" {
+		options = []*patchOption{
+			{description: gui.Tr.SLocalize("SplitPatchIntoNewCommit"), onPress: gui.handleSplitPatchIntoNewCommit},
+			{description: gui.Tr.SLocalize("ResetPatch"), onPress: gui.handleResetPatch},
+		}
+		if commit != nil && commit.Sha != commitSha {
+			options = append([]*patchOption{{
+				description: fmt.Sprintf("%s %s", gui.Tr.SLocalize("ApplyPatchToCommit"), commit.Sha),
+				onPress:     gui.handleMovePatchToCommit,
+			}}, options...)
+		}
+	} else {
+		options = []*patchOption{
+			{description: gui.Tr.SLocalize("ApplyPatchToIndex"), onPress: func() error { return gui.applyCustomPatch("cached") }},
+			{description: gui.Tr.SLocalize("RemovePatchFromWorkingTree"), onPress: func() error { return gui.applyCustomPatch("reverse") }},
+			{description: gui.Tr.SLocalize("ResetPatch"), onPress: gui.handleResetPatch},
+		}
+		if commit != nil {
+			options = append([]*patchOption{{
+				description: fmt.Sprintf("%s %s", gui.Tr.SLocalize("ApplyPatchToCommit"), commit.Sha),
+				onPress:     gui.handleApplyPatchToCommit,
+			}}, options...)
+		}
+	}
+
+	handleMenuPress := func(index int) error {
+		return options[index].onPress()
+	}
+
+	return gui.createMenu(gui.Tr.SLocalize("PatchOptionsTitle"), options, len(options), handleMenuPress)
+}
+
+// applyCustomPatch applies the custom patch, passing the flags through to
+// `git apply`, and then starts a new patch
+func (gui *Gui) applyCustomPatch(flags ...string) error {
+	patch, err := gui.State.PatchManager.RenderPatch(false)
+	if err != nil {
+		return err
+	}
+	if err := gui.GitCommand.CheckPatch(patch, flags...); err != nil {
+		return gui.createErrorPanel(gui.g, err.Error())
+	}
+	if _, err := gui.GitCommand.ApplyPatch(patch, flags...); err != nil {
+		return gui.createErrorPanel(gui.g, err.Error())
+	}
+	gui.State.PatchManager.Reset()
+
+	return gui.refreshAfterPatch()
+}
+
+func (gui *Gui) handleApplyPatchToCommit() error {
+	if filtered, err := gui.handleRebaseNotAllowed(); filtered {
+		return err
+	}
+
+	patch, err := gui.State.PatchManager.RenderPatch(false)
+	if err != nil {
+		return err
+	}
+
+	return gui.WithWaitingStatus(gui.Tr.SLocalize("RebasingStatus"), func() error {
+		err := gui.GitCommand.ApplyPatchToCommit(gui.State.Commits, gui.State.Panels.Commits.SelectedLine, patch)
+		if err == nil {
+			gui.State.PatchManager.Reset()
+		}
+		if err := gui.handleGenericMergeCommandResult(err); err != nil {
+			return err
+		}
+		return gui.refreshAfterPatch()
+	})
+}
+
+// handleSplitPatchIntoNewCommit takes the lines of the custom patch out of the
+// commit they were selected from and puts them in a new commit straight after
+// it, asking for the new commit's message first
+func (gui *Gui) handleSplitPatchIntoNewCommit() error {
+	if filtered, err := gui.handleRebaseNotAllowed(); filtered {
+		return err
+	}
+
+	commitIndex := gui.patchCommitIndex()
+	if commitIndex == -1 {
+		return gui.createErrorPanel(gui.g, gui.Tr.SLocalize("PatchCommitNotFound"))
+	}
+
+	patch, err := gui.State.PatchManager.RenderPatch(true)
+	if err != nil {
+		return err
+	}
+
+	return gui.createPromptPanel(gui.g, gui.g.CurrentView(), gui.Tr.SLocalize("NewCommitMessageTitle"), func(g *gocui.Gui, v *gocui.View) error {
+		message := strings.TrimSpace(v.Buffer())
+		if message == "" {
+			return nil
+		}
+
+		return gui.WithWaitingStatus(gui.Tr.SLocalize("RebasingStatus"), func() error {
+			err := gui.GitCommand.SplitCommit(gui.State.Commits, commitIndex, patch, message)
+			if err == nil {
+				gui.State.PatchManager.Reset()
+			}
+			if err := gui.handleGenericMergeCommandResult(err); err != nil {
+				return err
+			}
+			// the commit we were selecting lines from has been rewritten
+			state := gui.State.Panels.Staging
+			if state != nil && state.CommitSha != "" {
+				gui.g.Update(func(g *gocui.Gui) error {
+					gui.State.Panels.Staging = nil
+					return gui.switchFocus(g, nil, gui.getCommitsView())
+				})
+			}
+			return gui.refreshSidePanels(gui.g)
+		})
+	})
+}
+
+// handleMovePatchToCommit moves the lines of the custom patch out of the commit
+// they were selected from and into the selected commit
+func (gui *Gui) handleMovePatchToCommit() error {
+	if filtered, err := gui.handleRebaseNotAllowed(); filtered {
+		return err
+	}
+
+	sourceIndex := gui.patchCommitIndex()
+	if sourceIndex == -1 {
+		return gui.createErrorPanel(gui.g, gui.Tr.SLocalize("PatchCommitNotFound"))
+	}
+
+	patch, err := gui.State.PatchManager.RenderPatch(false)
+	if err != nil {
+		return err
+	}
+	reversePatch, err := gui.State.PatchManager.RenderPatch(true)
+	if err != nil {
+		return err
+	}
+
+	return gui.WithWaitingStatus(gui.Tr.SLocalize("RebasingStatus"), func() error {
+		err := gui.GitCommand.MovePatchToCommit(gui.State.Commits, sourceIndex, gui.State.Panels.Commits.SelectedLine, patch, reversePatch)
+		if err == nil {
+			gui.State.PatchManager.Reset()
+		}
+		if err := gui.handleGenericMergeCommandResult(err); err != nil {
+			return err
+		}
+		return gui.refreshSidePanels(gui.g)
+	})
+}
+
+// dropChangedPatchFiles deselects the custom patch's lines from any file whose
+// diff has changed since they were selected, e.g. because the file's been
+// edited, returning true if there were any
+func (gui *Gui) dropChangedPatchFiles() bool {
+	patchManager := gui.State.PatchManager
+	commitSha := patchManager.CommitSha()
+	return patchManager.DropChangedFiles(func(filename string) string {
+		if commitSha != "" {
+			diff, err := gui.GitCommand.CommitFileDiff(commitSha, filename, gui.State.DiffContextSize)
+			if err != nil {
+				return ""
+			}
+			return diff
+		}
+		// lines can only be added to the patch from the unstaged side
+		for _, file := range gui.State.Files {
+			if file.Name == filename {
+				return gui.GitCommand.Diff(file, true, false, gui.State.IgnoreWhitespace, gui.State.DiffContextSize)
+			}
+		}
+		return ""
+	})
+}
+
+// patchCommitIndex returns the index of the commit that the custom patch was
+// built from, or -1 if it's no longer in the log
+func (gui *Gui) patchCommitIndex() int {
+	commitSha := gui.State.PatchManager.CommitSha()
+	for i, commit := range gui.State.Commits {
+		if commit.Sha == commitSha {
+			return i
+		}
+	}
+	return -1
+}
+
+func (gui *Gui) handleResetPatch() error {
+	gui.State.PatchManager.Reset()
+	if gui.State.Panels.Staging != nil {
+		return gui.renderStagingDiff()
+	}
+	return nil
+}
+
+// refreshAfterPatch refreshes everything the patch may have changed, including
+// the staging panel if we're in it
+func (gui *Gui) refreshAfterPatch() error {
+	if err := gui.refreshSidePanels(gui.g); err != nil {
+		return err
+	}
+	if gui.State.Panels.Staging != nil {
+		return gui.refreshStagingPanel()
+	}
+	return nil
+}
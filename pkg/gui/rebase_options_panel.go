@@ -16,13 +16,29 @@ func (r *option) GetDisplayStrings(isFocused bool) []string {
 	return []string{r.value}
 }
 
+// operationCommands maps each working tree state to the git command that can
+// continue or abort the operation in progress
+var operationCommands = map[string]string{
+	"merging":        "merge",
+	"rebasing":       "rebase",
+	"cherry-picking": "cherry-pick",
+	"reverting":      "revert",
+}
+
 func (gui *Gui) handleCreateRebaseOptionsMenu(g *gocui.Gui, v *gocui.View) error {
+	if gui.State.WorkingTreeState == "normal" {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("NotMergingOrRebasing"))
+	}
+	if gui.State.WorkingTreeState == "conflicted" {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("ConflictsNotFromOperation"))
+	}
+
 	options := []*option{
 		{value: "continue"},
 		{value: "abort"},
 	}
 
-	if gui.State.WorkingTreeState == "rebasing" {
+	if gui.State.WorkingTreeState != "merging" {
 		options = append(options, &option{value: "skip"})
 	}
 
@@ -32,9 +48,14 @@ func (gui *Gui) handleCreateRebaseOptionsMenu(g *gocui.Gui, v *gocui.View) error
 	}
 
 	var title string
-	if gui.State.WorkingTreeState == "merging" {
+	switch gui.State.WorkingTreeState {
+	case "merging":
 		title = gui.Tr.SLocalize("MergeOptionsTitle")
-	} else {
+	case "cherry-picking":
+		title = gui.Tr.SLocalize("CherryPickOptionsTitle")
+	case "reverting":
+		title = gui.Tr.SLocalize("RevertOptionsTitle")
+	default:
 		title = gui.Tr.SLocalize("RebaseOptionsTitle")
 	}
 
@@ -44,11 +65,11 @@ func (gui *Gui) handleCreateRebaseOptionsMenu(g *gocui.Gui, v *gocui.View) error
 func (gui *Gui) genericMergeCommand(command string) error {
 	status := gui.State.WorkingTreeState
 
-	if status != "merging" && status != "rebasing" {
+	commandType, ok := operationCommands[status]
+	if !ok {
 		return gui.createErrorPanel(gui.g, gui.Tr.SLocalize("NotMergingOrRebasing"))
 	}
 
-	commandType := strings.Replace(status, "ing", "e", 1)
 	// we should end up with a command like 'git merge --continue'
 
 	// it's impossible for a rebase to require a commit so we'll use a subprocess only if it's a merge
@@ -75,12 +96,12 @@ func (gui *Gui) handleGenericMergeCommandResult(result error) error {
 		return nil
 	} else if result == gui.Errors.ErrSubProcess {
 		return result
-	} else if strings.Contains(result.Error(), "No changes - did you forget to use") {
+	} else if strings.Contains(result.Error(), "No changes - did you forget to use") || strings.Contains(result.Error(), "The previous cherry-pick is now empty") {
 		return gui.genericMergeCommand("skip")
-	} else if strings.Contains(result.Error(), "When you have resolved this problem") || strings.Contains(result.Error(), "fix conflicts") || strings.Contains(result.Error(), "Resolve all conflicts manually") {
-		return gui.createConfirmationPanel(gui.g, gui.getFilesView(), gui.Tr.SLocalize("FoundConflictsTitle"), gui.Tr.SLocalize("FoundConflicts"),
+	} else if strings.Contains(result.Error(), "When you have resolved this problem") || strings.Contains(result.Error(), "fix conflicts") || strings.Contains(result.Error(), "Resolve all conflicts manually") || strings.Contains(result.Error(), "could not apply") || strings.Contains(result.Error(), "could not revert") {
+		return gui.createConfirmationPanel(gui.g, gui.getFilesView(), gui.Tr.SLocalize("FoundConflictsTitle"), gui.foundConflictsMessage(),
 			func(g *gocui.Gui, v *gocui.View) error {
-				return nil
+				return gui.focusFirstConflict()
 			}, func(g *gocui.Gui, v *gocui.View) error {
 				return gui.genericMergeCommand("abort")
 			},
@@ -89,3 +110,44 @@ func (gui *Gui) handleGenericMergeCommandResult(result error) error {
 		return gui.createErrorPanel(gui.g, result.Error())
 	}
 }
+
+// foundConflictsMessage says what to do about the conflicts the operation
+// stopped at, naming the commit if it was being cherry-picked or reverted
+func (gui *Gui) foundConflictsMessage() string {
+	heads := []struct {
+		ref       string
+		messageID string
+	}{
+		{ref: "CHERRY_PICK_HEAD", messageID: "FoundCherryPickConflicts"},
+		{ref: "REVERT_HEAD", messageID: "FoundRevertConflicts"},
+	}
+	for _, head := range heads {
+		sha, err := gui.GitCommand.ResolveCommit(head.ref)
+		if err != nil || sha == "" {
+			continue
+		}
+		message, _ := gui.GitCommand.GetCommitMessage(sha)
+		return gui.Tr.TemplateLocalize(head.messageID, Teml{
+			"sha":  shortSha(sha),
+			"name": strings.SplitN(message, "\n", 2)[0],
+		})
+	}
+	return gui.Tr.SLocalize("FoundConflicts")
+}
+
+// focusFirstConflict takes you to the files panel with the first file that has
+// conflicts selected, so you can start resolving them
+func (gui *Gui) focusFirstConflict() error {
+	// the files may still be refreshing, and the confirmation panel has to
+	// close before we can move the focus
+	gui.g.Update(func(g *gocui.Gui) error {
+		for i, node := range gui.State.FileNodes {
+			if node.HasMergeConflicts() {
+				gui.State.Panels.Files.SelectedLine = i
+				break
+			}
+		}
+		return gui.switchFocus(g, g.CurrentView(), gui.getFilesView())
+	})
+	return nil
+}
@@ -0,0 +1,204 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazygit/pkg/commands"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+)
+
+// the reflog shares the commits panel's place, as a second tab, so that you
+// can get back to commits that a rebase or reset has left behind
+
+// list panel functions
+
+func (gui *Gui) getSelectedReflogEntry() *commands.ReflogEntry {
+	selectedLine := gui.State.Panels.Reflog.SelectedLine
+	if selectedLine == -1 {
+		return nil
+	}
+
+	return gui.State.ReflogEntries[selectedLine]
+}
+
+func (gui *Gui) handleReflogEntrySelect(g *gocui.Gui, v *gocui.View) error {
+	if gui.popupPanelFocused() {
+		return nil
+	}
+
+	if _, err := gui.g.SetCurrentView(v.Name()); err != nil {
+		return err
+	}
+	entry := gui.getSelectedReflogEntry()
+	if entry == nil {
+		return gui.renderString(g, "main", gui.Tr.SLocalize("NoReflogEntries"))
+	}
+	if err := gui.focusPoint(0, gui.State.Panels.Reflog.SelectedLine, len(gui.State.ReflogEntries), v); err != nil {
+		return err
+	}
+	go func() {
+		// doing this asynchronously cos it can take time
+		commitText, _ := gui.GitCommand.Show(entry.Sha)
+		_ = gui.renderString(g, "main", commitText)
+	}()
+	return nil
+}
+
+func (gui *Gui) refreshReflog(g *gocui.Gui) error {
+	g.Update(func(g *gocui.Gui) error {
+		entries, err := gui.GitCommand.GetReflogEntries()
+		if err != nil {
+			return err
+		}
+		gui.State.ReflogEntries = entries
+
+		gui.refreshSelectedLine(&gui.State.Panels.Reflog.SelectedLine, len(gui.State.ReflogEntries))
+
+		v := gui.getReflogView()
+		isFocused := gui.g.CurrentView() == v
+		list, err := utils.RenderList(gui.State.ReflogEntries, isFocused)
+		if err != nil {
+			return err
+		}
+		v.Clear()
+		fmt.Fprint(v, list)
+
+		if isFocused {
+			return gui.handleReflogEntrySelect(g, v)
+		}
+		return nil
+	})
+	return nil
+}
+
+func (gui *Gui) handleReflogNextLine(g *gocui.Gui, v *gocui.View) error {
+	if gui.popupPanelFocused() {
+		return nil
+	}
+
+	panelState := gui.State.Panels.Reflog
+	gui.changeSelectedLine(&panelState.SelectedLine, len(gui.State.ReflogEntries), false)
+
+	if err := gui.resetOrigin(gui.getMainView()); err != nil {
+		return err
+	}
+	return gui.handleReflogEntrySelect(gui.g, v)
+}
+
+func (gui *Gui) handleReflogPrevLine(g *gocui.Gui, v *gocui.View) error {
+	if gui.popupPanelFocused() {
+		return nil
+	}
+
+	panelState := gui.State.Panels.Reflog
+	gui.changeSelectedLine(&panelState.SelectedLine, len(gui.State.ReflogEntries), true)
+
+	if err := gui.resetOrigin(gui.getMainView()); err != nil {
+		return err
+	}
+	return gui.handleReflogEntrySelect(gui.g, v)
+}
+
+// tabs
+
+// setCommitsTabs titles the commits and reflog views with a tab for each,
+// highlighting their own
+func (gui *Gui) setCommitsTabs() {
+	tabs := []string{gui.commitsTitle(), gui.Tr.SLocalize("ReflogTitle")}
+	for i, v := range []*gocui.View{gui.getCommitsView(), gui.getReflogView()} {
+		if v != nil {
+			v.Tabs = tabs
+			v.TabIndex = i
+		}
+	}
+}
+
+// commitsSlotView is the view shown in the commits panel's place, which is
+// the reflog if you've switched to its tab
+func (gui *Gui) commitsSlotView() *gocui.View {
+	if gui.State.ShowingReflog {
+		return gui.getReflogView()
+	}
+	return gui.getCommitsView()
+}
+
+// handleSwitchCommitsTab swaps between the commits and reflog tabs. There are
+// only two, so going to the next tab or the previous one is the same
+func (gui *Gui) handleSwitchCommitsTab(g *gocui.Gui, v *gocui.View) error {
+	tabIndex := 1
+	if gui.State.ShowingReflog {
+		tabIndex = 0
+	}
+	return gui.switchToCommitsTab(tabIndex)
+}
+
+func (gui *Gui) switchToCommitsTab(tabIndex int) error {
+	gui.State.ShowingReflog = tabIndex == 1
+	if gui.State.ShowingReflog {
+		if err := gui.refreshReflog(gui.g); err != nil {
+			return err
+		}
+	}
+	return gui.switchFocus(gui.g, gui.g.CurrentView(), gui.commitsSlotView())
+}
+
+// actions
+
+func (gui *Gui) handleCheckoutReflogEntry(g *gocui.Gui, v *gocui.View) error {
+	entry := gui.getSelectedReflogEntry()
+	if entry == nil {
+		return nil
+	}
+
+	prompt := gui.Tr.TemplateLocalize("SureCheckoutCommit", Teml{"sha": entry.Sha})
+	return gui.createConfirmationPanel(g, v, gui.Tr.SLocalize("CheckoutCommit"), prompt, func(g *gocui.Gui, _ *gocui.View) error {
+		return gui.confirmLeavingDetachedHead(v, func() error {
+			return gui.checkoutRef(v, entry.Sha, func() error {
+				return gui.offerBranchAtDetachedHead(v, entry.Sha)
+			})
+		})
+	}, nil)
+}
+
+func (gui *Gui) handleNewBranchAtReflogEntry(g *gocui.Gui, v *gocui.View) error {
+	entry := gui.getSelectedReflogEntry()
+	if entry == nil {
+		return nil
+	}
+
+	return gui.promptNewBranchAt(v, entry.Sha)
+}
+
+func (gui *Gui) handleCherryPickReflogEntry(g *gocui.Gui, v *gocui.View) error {
+	entry := gui.getSelectedReflogEntry()
+	if entry == nil {
+		return nil
+	}
+
+	prompt := gui.Tr.TemplateLocalize("SureCherryPickReflogEntry", Teml{"sha": entry.Sha})
+	return gui.createConfirmationPanel(g, v, gui.Tr.SLocalize("CherryPick"), prompt, func(g *gocui.Gui, v *gocui.View) error {
+		return gui.WithWaitingStatus(gui.Tr.SLocalize("CherryPickingStatus"), func() error {
+			err := gui.GitCommand.CherryPickCommits([]*commands.Commit{{Sha: entry.Sha}})
+			return gui.handleGenericMergeCommandResult(err)
+		})
+	}, nil)
+}
+
+func (gui *Gui) handleHardResetToReflogEntry(g *gocui.Gui, v *gocui.View) error {
+	entry := gui.getSelectedReflogEntry()
+	if entry == nil {
+		return nil
+	}
+
+	prompt := gui.Tr.TemplateLocalize("SureHardReset", Teml{"commit": entry.Sha})
+	title := strings.Title(gui.Tr.TemplateLocalize("ResetTitle", Teml{"strength": "hard"}))
+	return gui.createConfirmationPanel(g, v, title, prompt, func(g *gocui.Gui, _ *gocui.View) error {
+		if err := gui.GitCommand.ResetToCommit(entry.Sha, "hard"); err != nil {
+			return gui.createErrorPanel(g, err.Error())
+		}
+		gui.State.Panels.Commits.SelectedLine = 0
+		return gui.refreshSidePanels(g)
+	}, nil)
+}
@@ -0,0 +1,178 @@
+package gui
+
+import (
+	"fmt"
+
+	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazygit/pkg/commands"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+)
+
+// the remote branches share the branches panel's place, as a second tab, so
+// that you can see what's on each remote without a local branch for it
+
+// list panel functions
+
+func (gui *Gui) getSelectedRemoteBranch() *commands.RemoteBranch {
+	selectedLine := gui.State.Panels.RemoteBranches.SelectedLine
+	if selectedLine == -1 {
+		return nil
+	}
+
+	return gui.State.RemoteBranches[selectedLine]
+}
+
+func (gui *Gui) handleRemoteBranchSelect(g *gocui.Gui, v *gocui.View) error {
+	if gui.popupPanelFocused() {
+		return nil
+	}
+
+	if _, err := gui.g.SetCurrentView(v.Name()); err != nil {
+		return err
+	}
+	branch := gui.getSelectedRemoteBranch()
+	if branch == nil {
+		return gui.renderString(g, "main", gui.Tr.SLocalize("NoRemoteTrackingBranches"))
+	}
+	if err := gui.focusPoint(0, gui.State.Panels.RemoteBranches.SelectedLine, len(gui.State.RemoteBranches), v); err != nil {
+		return err
+	}
+	go func() {
+		graph, _ := gui.GitCommand.GetBranchGraph(branch.FullName())
+		_ = gui.renderString(g, "main", graph)
+	}()
+	return nil
+}
+
+func (gui *Gui) refreshRemoteBranches(g *gocui.Gui) error {
+	g.Update(func(g *gocui.Gui) error {
+		branches, err := gui.GitCommand.GetRemoteBranches()
+		if err != nil {
+			return err
+		}
+		gui.State.RemoteBranches = branches
+
+		gui.refreshSelectedLine(&gui.State.Panels.RemoteBranches.SelectedLine, len(gui.State.RemoteBranches))
+
+		v := gui.getRemoteBranchesView()
+		isFocused := gui.g.CurrentView() == v
+		list, err := utils.RenderList(gui.State.RemoteBranches, isFocused)
+		if err != nil {
+			return err
+		}
+		v.Clear()
+		fmt.Fprint(v, list)
+
+		if isFocused {
+			return gui.handleRemoteBranchSelect(g, v)
+		}
+		return nil
+	})
+	return nil
+}
+
+func (gui *Gui) handleRemoteBranchesNextLine(g *gocui.Gui, v *gocui.View) error {
+	if gui.popupPanelFocused() {
+		return nil
+	}
+
+	panelState := gui.State.Panels.RemoteBranches
+	gui.changeSelectedLine(&panelState.SelectedLine, len(gui.State.RemoteBranches), false)
+
+	if err := gui.resetOrigin(gui.getMainView()); err != nil {
+		return err
+	}
+	return gui.handleRemoteBranchSelect(gui.g, v)
+}
+
+func (gui *Gui) handleRemoteBranchesPrevLine(g *gocui.Gui, v *gocui.View) error {
+	if gui.popupPanelFocused() {
+		return nil
+	}
+
+	panelState := gui.State.Panels.RemoteBranches
+	gui.changeSelectedLine(&panelState.SelectedLine, len(gui.State.RemoteBranches), true)
+
+	if err := gui.resetOrigin(gui.getMainView()); err != nil {
+		return err
+	}
+	return gui.handleRemoteBranchSelect(gui.g, v)
+}
+
+// tabs
+
+// setBranchesTabs titles the branches and remote branches views with a tab for
+// each, highlighting their own
+func (gui *Gui) setBranchesTabs() {
+	tabs := []string{gui.branchesTitle(), gui.Tr.SLocalize("RemoteBranchesTitle")}
+	for i, v := range []*gocui.View{gui.getBranchesView(), gui.getRemoteBranchesView()} {
+		if v != nil {
+			v.Tabs = tabs
+			v.TabIndex = i
+		}
+	}
+}
+
+// branchesSlotView is the view shown in the branches panel's place, which is
+// the remote branches if you've switched to their tab
+func (gui *Gui) branchesSlotView() *gocui.View {
+	if gui.State.ShowingRemoteBranches {
+		return gui.getRemoteBranchesView()
+	}
+	return gui.getBranchesView()
+}
+
+// handleSwitchBranchesTab swaps between the branches and remote branches tabs
+func (gui *Gui) handleSwitchBranchesTab(g *gocui.Gui, v *gocui.View) error {
+	tabIndex := 1
+	if gui.State.ShowingRemoteBranches {
+		tabIndex = 0
+	}
+	return gui.switchToBranchesTab(tabIndex)
+}
+
+func (gui *Gui) switchToBranchesTab(tabIndex int) error {
+	gui.State.ShowingRemoteBranches = tabIndex == 1
+	if gui.State.ShowingRemoteBranches {
+		if err := gui.refreshRemoteBranches(gui.g); err != nil {
+			return err
+		}
+	}
+	return gui.switchFocus(gui.g, gui.g.CurrentView(), gui.branchesSlotView())
+}
+
+// actions
+
+// handleCheckoutRemoteBranchPress checks out the local branch of the same
+// name, making one that tracks the remote branch if there isn't one yet
+func (gui *Gui) handleCheckoutRemoteBranchPress(g *gocui.Gui, v *gocui.View) error {
+	branch := gui.getSelectedRemoteBranch()
+	if branch == nil {
+		return nil
+	}
+
+	return gui.checkoutRemoteBranch(branch.FullName())
+}
+
+func (gui *Gui) handleDeleteRemoteBranchPress(g *gocui.Gui, v *gocui.View) error {
+	branch := gui.getSelectedRemoteBranch()
+	if branch == nil {
+		return nil
+	}
+
+	message := gui.Tr.TemplateLocalize("DeleteRemoteBranchMessage", Teml{"remoteBranch": branch.FullName()})
+	return gui.createConfirmationPanel(g, v, gui.Tr.SLocalize("DeleteBranch"), message, func(*gocui.Gui, *gocui.View) error {
+		return gui.deleteRemoteBranch(v, branch.RemoteName, branch.Name)
+	}, nil)
+}
+
+// handleBrowseRemoteBranchCommits shows the remote branch's commits in the
+// commits panel
+func (gui *Gui) handleBrowseRemoteBranchCommits(g *gocui.Gui, v *gocui.View) error {
+	branch := gui.getSelectedRemoteBranch()
+	if branch == nil {
+		return nil
+	}
+
+	return gui.setBrowsingBranch(branch.FullName())
+}
@@ -0,0 +1,67 @@
+package gui
+
+import (
+	"github.com/jesseduffield/gocui"
+)
+
+// handleSplitCommit stops a rebase at the selected commit and undoes it, then
+// takes you to the staging panel to stage the changes of the first of the two
+// commits it's split into. Once you've committed those, the rest are committed
+// as the second one
+func (gui *Gui) handleSplitCommit(g *gocui.Gui, v *gocui.View) error {
+	if filtered, err := gui.handleRebaseNotAllowed(); filtered {
+		return err
+	}
+	if gui.operationInProgress() {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("CantSplitCommitMidOperation"))
+	}
+	commit := gui.getSelectedCommit(g)
+	if commit == nil {
+		return nil
+	}
+
+	return gui.WithWaitingStatus(gui.Tr.SLocalize("RebasingStatus"), func() error {
+		err := gui.GitCommand.StartSplittingCommit(gui.State.Commits, gui.State.Panels.Commits.SelectedLine)
+		if err != nil {
+			return gui.handleGenericMergeCommandResult(err)
+		}
+		if err := gui.refreshSidePanels(gui.g); err != nil {
+			return err
+		}
+		gui.State.SplittingSha = commit.Sha
+
+		gui.g.Update(func(g *gocui.Gui) error {
+			filesView := gui.getFilesView()
+			for i, node := range gui.State.FileNodes {
+				if !node.IsDirectory() {
+					gui.State.Panels.Files.SelectedLine = i
+					break
+				}
+			}
+			if err := gui.switchFocus(g, nil, filesView); err != nil {
+				return err
+			}
+			return gui.handleEnterFile(g, filesView)
+		})
+		return nil
+	})
+}
+
+// splitCommitMessage is the message of the commit being split, which the
+// first of the two commits starts with, or empty if we're not splitting one
+func (gui *Gui) splitCommitMessage() (string, error) {
+	if gui.State.SplittingSha == "" {
+		return "", nil
+	}
+	return gui.GitCommand.GetCommitMessage(gui.State.SplittingSha)
+}
+
+// finishSplittingCommit commits the rest of the changes of the commit being
+// split, now that the first of the two commits has been made
+func (gui *Gui) finishSplittingCommit() error {
+	sha := gui.State.SplittingSha
+	gui.State.SplittingSha = ""
+	return gui.WithWaitingStatus(gui.Tr.SLocalize("RebasingStatus"), func() error {
+		return gui.handleGenericMergeCommandResult(gui.GitCommand.FinishSplittingCommit(sha))
+	})
+}
@@ -1,11 +1,38 @@
 package gui
 
 import (
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/golang-collections/collections/stack"
 	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazygit/pkg/commands"
 	"github.com/jesseduffield/lazygit/pkg/git"
 	"github.com/jesseduffield/lazygit/pkg/utils"
 )
 
+// doubleClickInterval is how soon a second click on a line has to come after
+// the first for us to treat it as a double click
+const doubleClickInterval = 500 * time.Millisecond
+
+// forgetUnchangedFilesDiffs drops the diffs we've kept of files that no longer
+// have any changes
+func (gui *Gui) forgetUnchangedFilesDiffs() {
+	fileNames := map[string]bool{}
+	for _, file := range gui.State.Files {
+		fileNames[file.Name] = true
+	}
+	for key := range gui.State.StagingDiffs {
+		if !fileNames[key.FileName] {
+			delete(gui.State.StagingDiffs, key)
+		}
+	}
+}
+
 func (gui *Gui) refreshStagingPanel() error {
 	file, err := gui.getSelectedFile(gui.g)
 	if err != nil {
@@ -15,28 +42,51 @@ func (gui *Gui) refreshStagingPanel() error {
 		return gui.handleStagingEscape(gui.g, nil)
 	}
 
-	if !file.HasUnstagedChanges {
+	// if we're not already in the staging panel we start on the unstaged side
+	// unless there's nothing there
+	showingStaged := !file.HasUnstagedChanges
+	patchHistory := stack.New()
+	searchString := ""
+	if gui.State.Panels.Staging != nil {
+		showingStaged = gui.State.Panels.Staging.ShowingStaged
+		patchHistory = gui.State.Panels.Staging.PatchHistory
+		searchString = gui.State.Panels.Staging.SearchString
+	}
+
+	if (showingStaged && !file.HasStagedChanges) || (!showingStaged && !file.HasUnstagedChanges) {
 		return gui.handleStagingEscape(gui.g, nil)
 	}
 
 	// note for custom diffs, we'll need to send a flag here saying not to use the custom diff
-	diff := gui.GitCommand.Diff(file, true)
-	colorDiff := gui.GitCommand.Diff(file, false)
+	diff := gui.GitCommand.Diff(file, true, showingStaged, gui.State.IgnoreWhitespace, gui.State.DiffContextSize)
 
 	if len(diff) < 2 {
 		return gui.handleStagingEscape(gui.g, nil)
 	}
 
-	// parse the diff and store the line numbers of hunks and stageable lines
-	// TODO: maybe instantiate this at application start
-	p, err := git.NewPatchParser(gui.Log)
-	if err != nil {
-		return nil
+	// if the file's diff hasn't changed (e.g. we're refreshing because some
+	// other file changed) there's no need to parse it and render it all over
+	// again
+	gui.forgetUnchangedFilesDiffs()
+	key := stagingDiffKey{FileName: file.Name, Staged: showingStaged}
+	cached, ok := gui.State.StagingDiffs[key]
+	if !ok || cached.DiffState.Diff() != diff {
+		cached = &stagingDiff{
+			DiffState: git.NewDiffState(gui.Log, gui.Tr, diff),
+			// we render the colors ourselves so that we can highlight intra-line changes
+			ColorDiff: gui.renderDiff(diff),
+		}
+		gui.State.StagingDiffs[key] = cached
 	}
-	hunkStarts, stageableLines, err := p.ParsePatch(diff)
-	if err != nil {
-		return nil
+	diffState, colorDiff := cached.DiffState, cached.ColorDiff
+
+	if diffState.IsBinary() {
+		if err := gui.handleStagingEscape(gui.g, nil); err != nil {
+			return err
+		}
+		return gui.createBinaryFileMenu(showingStaged)
 	}
+	stageableLines := diffState.StageableLines()
 
 	var selectedLine int
 	if gui.State.Panels.Staging != nil {
@@ -50,12 +100,18 @@ func (gui *Gui) refreshStagingPanel() error {
 		selectedLine = 0
 	}
 
+	// any range selection is dropped on refresh because the line numbers it
+	// refers to are no longer valid once the patch has been applied
 	gui.State.Panels.Staging = &stagingPanelState{
-		StageableLines: stageableLines,
-		HunkStarts:     hunkStarts,
-		SelectedLine:   selectedLine,
-		Diff:           diff,
+		SelectedLine:  selectedLine,
+		DiffState:     diffState,
+		ColorDiff:     colorDiff,
+		ShowingStaged: showingStaged,
+		PatchHistory:  patchHistory,
+		FileName:      file.Name,
+		SearchString:  searchString,
 	}
+	gui.updateStagingSearchMatches()
 
 	if len(stageableLines) == 0 {
 		return gui.createErrorPanel(gui.g, "No lines to stage")
@@ -68,20 +124,439 @@ func (gui *Gui) refreshStagingPanel() error {
 	mainView := gui.getMainView()
 	mainView.Highlight = true
 	mainView.Wrap = false
+	if showingStaged {
+		mainView.Title = gui.Tr.SLocalize("UnstagingMainTitle")
+	} else {
+		mainView.Title = gui.Tr.SLocalize("StagingMainTitle")
+	}
+
+	return gui.renderStagingDiff()
+}
+
+type binaryFileOption struct {
+	description string
+	handler     func(file *commands.File) error
+}
+
+// GetDisplayStrings is a function.
+func (o *binaryFileOption) GetDisplayStrings(isFocused bool) []string {
+	return []string{o.description}
+}
+
+// createBinaryFileMenu offers to stage or discard the whole of a binary file,
+// given that there are no lines to stage individually
+func (gui *Gui) createBinaryFileMenu(showingStaged bool) error {
+	options := []*binaryFileOption{
+		{
+			description: gui.Tr.SLocalize("StageBinaryFile"),
+			handler: func(file *commands.File) error {
+				return gui.GitCommand.StageFile(file.Name)
+			},
+		},
+		{
+			description: gui.Tr.SLocalize("discardUnstagedChanges"),
+			handler: func(file *commands.File) error {
+				if file.HasStagedChanges {
+					return gui.GitCommand.DiscardUnstagedFileChanges(file)
+				}
+				return gui.GitCommand.DiscardAllFileChanges(file)
+			},
+		},
+	}
+	if showingStaged {
+		options = []*binaryFileOption{
+			{
+				description: gui.Tr.SLocalize("UnstageBinaryFile"),
+				handler: func(file *commands.File) error {
+					return gui.GitCommand.UnStageFile(file.Name, file.Tracked)
+				},
+			},
+		}
+	}
+	options = append(options, &binaryFileOption{
+		description: gui.Tr.SLocalize("cancel"),
+		handler: func(file *commands.File) error {
+			return nil
+		},
+	})
+
+	handleMenuPress := func(index int) error {
+		file, err := gui.getSelectedFile(gui.g)
+		if err != nil {
+			return err
+		}
+		if err := options[index].handler(file); err != nil {
+			return gui.createErrorPanel(gui.g, err.Error())
+		}
+		return gui.refreshFiles()
+	}
+
+	return gui.createMenu(gui.Tr.SLocalize("BinaryFileTitle"), options, len(options), handleMenuPress)
+}
+
+// renderStagingDiff writes the diff to the main view, highlighting search
+// matches and the lines that are in the custom patch, the refined selection,
+// and the current range selection
+func (gui *Gui) renderStagingDiff() error {
+	state := gui.State.Panels.Staging
+	lines := strings.Split(state.ColorDiff, "\n")
+	gui.highlightSearchMatches(lines)
+	if gui.State.PatchManager.CommitSha() == state.CommitSha {
+		for _, lineNumber := range gui.State.PatchManager.GetSelectedLines(state.FileName, state.DiffState.Diff()) {
+			if lineNumber < len(lines) {
+				lines[lineNumber] = utils.ColoredStringDirect(utils.Decolorise(lines[lineNumber]), color.New(color.BgMagenta))
+			}
+		}
+	}
+	for lineNumber := range state.RefinedLines {
+		if lineNumber < len(lines) {
+			lines[lineNumber] = utils.ColoredStringDirect(utils.Decolorise(lines[lineNumber]), color.New(color.BgGreen))
+		}
+	}
+	if state.SelectingRange {
+		firstLine, lastLine := gui.stagingRange()
+		for i := firstLine; i <= lastLine && i < len(lines); i++ {
+			lines[i] = utils.ColoredStringDirect(utils.Decolorise(lines[i]), color.New(color.BgBlue))
+		}
+	}
+	gui.addLineNumberGutter(lines)
+	content := strings.Join(lines, "\n")
 
 	gui.g.Update(func(*gocui.Gui) error {
-		return gui.setViewContent(gui.g, gui.getMainView(), colorDiff)
+		return gui.setViewContent(gui.g, gui.getMainView(), content)
 	})
 
 	return nil
 }
 
+// addLineNumberGutter prefixes each line of the diff with its line number in
+// the old and new versions of the file, so that it's easy to find the change
+// in your editor. Lines outside of hunks get an empty gutter
+func (gui *Gui) addLineNumberGutter(lines []string) {
+	oldLineNumbers, newLineNumbers := gui.State.Panels.Staging.DiffState.LineNumbers()
+
+	maxLineNumber := 0
+	for i := range oldLineNumbers {
+		if oldLineNumbers[i] > maxLineNumber {
+			maxLineNumber = oldLineNumbers[i]
+		}
+		if newLineNumbers[i] > maxLineNumber {
+			maxLineNumber = newLineNumbers[i]
+		}
+	}
+	width := len(strconv.Itoa(maxLineNumber))
+
+	formatLineNumber := func(lineNumber int) string {
+		if lineNumber == 0 {
+			return strings.Repeat(" ", width)
+		}
+		return fmt.Sprintf("%*d", width, lineNumber)
+	}
+
+	for i := range lines {
+		if i >= len(oldLineNumbers) {
+			break
+		}
+		gutter := formatLineNumber(oldLineNumbers[i]) + " " + formatLineNumber(newLineNumbers[i]) + " "
+		lines[i] = utils.ColoredString(gutter, color.FgHiBlack) + lines[i]
+	}
+}
+
+// stagingRange returns the patch line numbers of the first and last lines in
+// the current range selection
+func (gui *Gui) stagingRange() (int, int) {
+	state := gui.State.Panels.Staging
+	first, last := state.RangeStart, state.SelectedLine
+	if first > last {
+		first, last = last, first
+	}
+	stageableLines := state.DiffState.StageableLines()
+	return stageableLines[first], stageableLines[last]
+}
+
+func (gui *Gui) handleToggleSelectRange(g *gocui.Gui, v *gocui.View) error {
+	state := gui.State.Panels.Staging
+	state.SelectingRange = !state.SelectingRange
+	state.RangeStart = state.SelectedLine
+
+	return gui.renderStagingDiff()
+}
+
+// selectedStageableLines returns the patch line numbers of the selected line,
+// or of the stageable lines in the range if we're selecting a range
+func (gui *Gui) selectedStageableLines() []int {
+	state := gui.State.Panels.Staging
+	if !state.SelectingRange {
+		return []int{state.DiffState.StageableLines()[state.SelectedLine]}
+	}
+
+	firstLine, lastLine := gui.stagingRange()
+	lineNumbers := []int{}
+	for _, lineNumber := range state.DiffState.StageableLines() {
+		if lineNumber >= firstLine && lineNumber <= lastLine {
+			lineNumbers = append(lineNumbers, lineNumber)
+		}
+	}
+	return lineNumbers
+}
+
+// handleStartRefining selects every line of the current hunk so that you can
+// then toggle individual lines out of (or back into) the selection with space,
+// and stage whatever's left in one go with enter
+func (gui *Gui) handleStartRefining(g *gocui.Gui, v *gocui.View) error {
+	state := gui.State.Panels.Staging
+	state.Refining = true
+	state.RefinedLines = map[int]bool{}
+	for _, lineNumber := range gui.currentHunkStageableLines() {
+		state.RefinedLines[lineNumber] = true
+	}
+	state.SelectingRange = false
+
+	return gui.renderStagingDiff()
+}
+
+// currentHunkStageableLines returns the patch line numbers of the stageable
+// lines in the hunk containing the selected line
+func (gui *Gui) currentHunkStageableLines() []int {
+	state := gui.State.Panels.Staging
+	stageableLines := state.DiffState.StageableLines()
+	hunkStarts := state.DiffState.HunkStarts()
+	currentLine := stageableLines[state.SelectedLine]
+	hunkStartIndex := utils.PrevIndex(hunkStarts, currentLine)
+	hunkStart := hunkStarts[hunkStartIndex]
+	hunkEnd := len(strings.Split(state.DiffState.Diff(), "\n"))
+	if hunkStartIndex < len(hunkStarts)-1 {
+		hunkEnd = hunkStarts[hunkStartIndex+1]
+	}
+
+	lineNumbers := []int{}
+	for _, lineNumber := range stageableLines {
+		if lineNumber > hunkStart && lineNumber < hunkEnd {
+			lineNumbers = append(lineNumbers, lineNumber)
+		}
+	}
+	return lineNumbers
+}
+
+// handleToggleRefinedLines adds the selected lines to the refined selection,
+// or removes them if they're all already in it
+func (gui *Gui) handleToggleRefinedLines() error {
+	state := gui.State.Panels.Staging
+	lineNumbers := gui.selectedStageableLines()
+
+	allSelected := true
+	for _, lineNumber := range lineNumbers {
+		if !state.RefinedLines[lineNumber] {
+			allSelected = false
+			break
+		}
+	}
+	for _, lineNumber := range lineNumbers {
+		if allSelected {
+			delete(state.RefinedLines, lineNumber)
+		} else {
+			state.RefinedLines[lineNumber] = true
+		}
+	}
+	state.SelectingRange = false
+
+	return gui.renderStagingDiff()
+}
+
+// handleApplyRefinedLines stages (or unstages) the refined selection as a
+// single patch
+func (gui *Gui) handleApplyRefinedLines(g *gocui.Gui, v *gocui.View) error {
+	state := gui.State.Panels.Staging
+	if !state.Refining {
+		return nil
+	}
+	if len(state.RefinedLines) == 0 {
+		return gui.handleStopRefining()
+	}
+	return gui.handleStageLineOrHunk(false)
+}
+
+func (gui *Gui) handleStopRefining() error {
+	state := gui.State.Panels.Staging
+	state.Refining = false
+	state.RefinedLines = nil
+	return gui.renderStagingDiff()
+}
+
+// handleToggleLinesInPatch adds the selected line, or lines if we're selecting
+// a range, to the custom patch, or removes them if they're already in it
+func (gui *Gui) handleToggleLinesInPatch(g *gocui.Gui, v *gocui.View) error {
+	state := gui.State.Panels.Staging
+	if state.ShowingStaged {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("CustomPatchUnstagedOnly"))
+	}
+
+	return gui.toggleLinesInPatch(gui.selectedStageableLines())
+}
+
+// toggleLinesInPatch adds the lines to the custom patch, unless they're all in
+// there already in which case they're removed. Adding lines from somewhere
+// other than where the patch was being built from starts a new patch
+func (gui *Gui) toggleLinesInPatch(lineNumbers []int) error {
+	state := gui.State.Panels.Staging
+	patchManager := gui.State.PatchManager
+	patchManager.Start(state.CommitSha)
+	inPatch := map[int]bool{}
+	for _, lineNumber := range patchManager.GetSelectedLines(state.FileName, state.DiffState.Diff()) {
+		inPatch[lineNumber] = true
+	}
+	allInPatch := true
+	for _, lineNumber := range lineNumbers {
+		if !inPatch[lineNumber] {
+			allInPatch = false
+			break
+		}
+	}
+
+	if allInPatch {
+		patchManager.RemoveLines(state.FileName, lineNumbers)
+	} else {
+		patchManager.AddLines(state.FileName, state.DiffState, lineNumbers)
+	}
+
+	return gui.renderStagingDiff()
+}
+
 func (gui *Gui) handleStagingEscape(g *gocui.Gui, v *gocui.View) error {
+	if gui.State.Panels.Staging != nil && gui.State.Panels.Staging.Refining {
+		return gui.handleStopRefining()
+	}
+
 	gui.State.Panels.Staging = nil
 
 	return gui.switchFocus(gui.g, nil, gui.getFilesView())
 }
 
+// handleToggleStagedSide switches between staging unstaged changes and
+// unstaging staged changes for the selected file
+func (gui *Gui) handleToggleStagedSide(g *gocui.Gui, v *gocui.View) error {
+	file, err := gui.getSelectedFile(g)
+	if err != nil {
+		if err != gui.Errors.ErrNoFiles {
+			return err
+		}
+		return nil
+	}
+
+	state := gui.State.Panels.Staging
+	if (state.ShowingStaged && !file.HasUnstagedChanges) || (!state.ShowingStaged && !file.HasStagedChanges) {
+		return gui.createErrorPanel(g, gui.Tr.SLocalize("NoChangesOnOtherSide"))
+	}
+
+	state.ShowingStaged = !state.ShowingStaged
+	state.SelectedLine = 0
+
+	return gui.refreshStagingPanel()
+}
+
+// handleSplitHunk breaks the selected hunk up into smaller hunks so that they
+// can be staged separately. The split only lasts until the panel is next
+// refreshed from git
+func (gui *Gui) handleSplitHunk(g *gocui.Gui, v *gocui.View) error {
+	state := gui.State.Panels.Staging
+	currentLine := state.DiffState.StageableLines()[state.SelectedLine]
+	// the first of the new hunks starts where the old hunk did
+	hunkStarts := state.DiffState.HunkStarts()
+	hunkStart := hunkStarts[utils.PrevIndex(hunkStarts, currentLine)]
+	if err := state.DiffState.SplitHunk(currentLine); err != nil {
+		return gui.createErrorPanel(g, err.Error())
+	}
+
+	state.ColorDiff = gui.renderDiff(state.DiffState.Diff())
+	state.SelectedLine = utils.NextIndex(state.DiffState.StageableLines(), hunkStart)
+	state.SelectingRange = false
+	gui.updateStagingSearchMatches()
+
+	if err := gui.focusLineAndHunk(); err != nil {
+		return err
+	}
+	return gui.renderStagingDiff()
+}
+
+func (gui *Gui) handleDecreaseContextSize(g *gocui.Gui, v *gocui.View) error {
+	// with no context at all, git won't apply our patches
+	if gui.State.DiffContextSize <= 1 {
+		return nil
+	}
+	gui.State.DiffContextSize--
+	return gui.refreshStagingPanel()
+}
+
+func (gui *Gui) handleIncreaseContextSize(g *gocui.Gui, v *gocui.View) error {
+	gui.State.DiffContextSize++
+	return gui.refreshStagingPanel()
+}
+
+func (gui *Gui) handleStagingPrevFile(g *gocui.Gui, v *gocui.View) error {
+	return gui.handleCycleFile(true)
+}
+
+func (gui *Gui) handleStagingNextFile(g *gocui.Gui, v *gocui.View) error {
+	return gui.handleCycleFile(false)
+}
+
+// handleCycleFile selects the previous/next file that has changes on the side
+// of the staging panel we're looking at, and shows it in the staging panel
+func (gui *Gui) handleCycleFile(prev bool) error {
+	state := gui.State.Panels.Staging
+	fileCount := len(gui.State.FileNodes)
+	currentIndex := gui.State.Panels.Files.SelectedLine
+
+	for offset := 1; offset < fileCount; offset++ {
+		index := (currentIndex + offset) % fileCount
+		if prev {
+			index = (currentIndex - offset + fileCount) % fileCount
+		}
+
+		// we skip over directories, and files in collapsed directories are hidden
+		// so they're skipped too
+		file := gui.State.FileNodes[index].File
+		if file == nil || file.HasMergeConflicts {
+			continue
+		}
+		if (state.ShowingStaged && file.HasStagedChanges) || (!state.ShowingStaged && file.HasUnstagedChanges) {
+			gui.State.Panels.Files.SelectedLine = index
+			if err := gui.focusPoint(0, index, fileCount, gui.getFilesView()); err != nil {
+				return err
+			}
+			state.SelectedLine = 0
+			return gui.refreshStagingPanel()
+		}
+	}
+
+	// there's no other file to go to, but the current file may have changed
+	return gui.refreshStagingPanel()
+}
+
+// handleStageFile stages all of the file's remaining changes (or unstages them
+// all if we're on the staged side) and moves on to the next file, so that you
+// can quickly work through a bunch of files
+func (gui *Gui) handleStageFile(g *gocui.Gui, v *gocui.View) error {
+	file, err := gui.getSelectedFile(g)
+	if err != nil {
+		return err
+	}
+
+	if gui.State.Panels.Staging.ShowingStaged {
+		err = gui.GitCommand.UnStageFile(file.Name, file.Tracked)
+	} else {
+		err = gui.GitCommand.StageFile(file.Name)
+	}
+	if err != nil {
+		return gui.createErrorPanel(g, err.Error())
+	}
+
+	if err := gui.refreshFiles(); err != nil {
+		return err
+	}
+	return gui.handleCycleFile(false)
+}
+
 func (gui *Gui) handleStagingPrevLine(g *gocui.Gui, v *gocui.View) error {
 	return gui.handleCycleLine(true)
 }
@@ -90,6 +565,29 @@ func (gui *Gui) handleStagingNextLine(g *gocui.Gui, v *gocui.View) error {
 	return gui.handleCycleLine(false)
 }
 
+// handleStagingClick selects the stageable line that was clicked on, or the
+// next one if the line can't be staged. Clicking the selected line again in
+// quick succession stages it
+func (gui *Gui) handleStagingClick(g *gocui.Gui, v *gocui.View) error {
+	state := gui.State.Panels.Staging
+	_, cy := v.Cursor()
+	_, oy := v.Origin()
+	clickedLine := cy + oy
+
+	newIndex := utils.NextIndex(state.DiffState.StageableLines(), clickedLine-1)
+	isDoubleClick := newIndex == state.SelectedLine && time.Since(state.LastClickTime) < doubleClickInterval
+	state.LastClickTime = time.Now()
+	if isDoubleClick {
+		if state.CommitSha != "" {
+			return gui.handleToggleLinesInPatch(g, v)
+		}
+		return gui.handleStageLine(g, v)
+	}
+
+	state.SelectedLine = newIndex
+	return gui.focusLineAndHunkAndRender()
+}
+
 func (gui *Gui) handleStagingPrevHunk(g *gocui.Gui, v *gocui.View) error {
 	return gui.handleCycleHunk(true)
 }
@@ -100,32 +598,33 @@ func (gui *Gui) handleStagingNextHunk(g *gocui.Gui, v *gocui.View) error {
 
 func (gui *Gui) handleCycleHunk(prev bool) error {
 	state := gui.State.Panels.Staging
-	lineNumbers := state.StageableLines
+	lineNumbers := state.DiffState.StageableLines()
+	hunkStarts := state.DiffState.HunkStarts()
 	currentLine := lineNumbers[state.SelectedLine]
-	currentHunkIndex := utils.PrevIndex(state.HunkStarts, currentLine)
+	currentHunkIndex := utils.PrevIndex(hunkStarts, currentLine)
 	var newHunkIndex int
 	if prev {
 		if currentHunkIndex == 0 {
-			newHunkIndex = len(state.HunkStarts) - 1
+			newHunkIndex = len(hunkStarts) - 1
 		} else {
 			newHunkIndex = currentHunkIndex - 1
 		}
 	} else {
-		if currentHunkIndex == len(state.HunkStarts)-1 {
+		if currentHunkIndex == len(hunkStarts)-1 {
 			newHunkIndex = 0
 		} else {
 			newHunkIndex = currentHunkIndex + 1
 		}
 	}
 
-	state.SelectedLine = utils.NextIndex(lineNumbers, state.HunkStarts[newHunkIndex])
+	state.SelectedLine = utils.NextIndex(lineNumbers, hunkStarts[newHunkIndex])
 
-	return gui.focusLineAndHunk()
+	return gui.focusLineAndHunkAndRender()
 }
 
 func (gui *Gui) handleCycleLine(prev bool) error {
 	state := gui.State.Panels.Staging
-	lineNumbers := state.StageableLines
+	lineNumbers := state.DiffState.StageableLines()
 	currentLine := lineNumbers[state.SelectedLine]
 	var newIndex int
 	if prev {
@@ -135,7 +634,19 @@ func (gui *Gui) handleCycleLine(prev bool) error {
 	}
 	state.SelectedLine = newIndex
 
-	return gui.focusLineAndHunk()
+	return gui.focusLineAndHunkAndRender()
+}
+
+// focusLineAndHunkAndRender refocuses the view and, if a range is being
+// selected, re-renders the diff so the highlighted range follows the cursor
+func (gui *Gui) focusLineAndHunkAndRender() error {
+	if err := gui.focusLineAndHunk(); err != nil {
+		return err
+	}
+	if gui.State.Panels.Staging.SelectingRange {
+		return gui.renderStagingDiff()
+	}
+	return nil
 }
 
 // focusLineAndHunk works out the best focus for the staging panel given the
@@ -144,23 +655,24 @@ func (gui *Gui) focusLineAndHunk() error {
 	stagingView := gui.getMainView()
 	state := gui.State.Panels.Staging
 
-	lineNumber := state.StageableLines[state.SelectedLine]
+	lineNumber := state.DiffState.StageableLines()[state.SelectedLine]
+	hunkStarts := state.DiffState.HunkStarts()
 
 	// we want the bottom line of the view buffer to ideally be the bottom line
 	// of the hunk, but if the hunk is too big we'll just go three lines beyond
 	// the currently selected line so that the user can see the context
 	var bottomLine int
-	nextHunkStartIndex := utils.NextIndex(state.HunkStarts, lineNumber)
+	nextHunkStartIndex := utils.NextIndex(hunkStarts, lineNumber)
 	if nextHunkStartIndex == 0 {
 		// for now linesHeight is an efficient means of getting the number of lines
 		// in the patch. However if we introduce word wrap we'll need to update this
 		bottomLine = stagingView.LinesHeight() - 1
 	} else {
-		bottomLine = state.HunkStarts[nextHunkStartIndex] - 1
+		bottomLine = hunkStarts[nextHunkStartIndex] - 1
 	}
 
-	hunkStartIndex := utils.PrevIndex(state.HunkStarts, lineNumber)
-	hunkStart := state.HunkStarts[hunkStartIndex]
+	hunkStartIndex := utils.PrevIndex(hunkStarts, lineNumber)
+	hunkStart := hunkStarts[hunkStartIndex]
 	// if it's the first hunk we'll also show the diff header
 	if hunkStartIndex == 0 {
 		hunkStart = 0
@@ -181,25 +693,17 @@ func (gui *Gui) handleStageHunk(g *gocui.Gui, v *gocui.View) error {
 }
 
 func (gui *Gui) handleStageLine(g *gocui.Gui, v *gocui.View) error {
+	if gui.State.Panels.Staging.Refining {
+		return gui.handleToggleRefinedLines()
+	}
 	return gui.handleStageLineOrHunk(false)
 }
 
 func (gui *Gui) handleStageLineOrHunk(hunk bool) error {
 	state := gui.State.Panels.Staging
-	p, err := git.NewPatchModifier(gui.Log)
+	patch, err := gui.getStagingPatch(hunk, state.ShowingStaged)
 	if err != nil {
-		return err
-	}
-
-	currentLine := state.StageableLines[state.SelectedLine]
-	var patch string
-	if hunk {
-		patch, err = p.ModifyPatchForHunk(state.Diff, state.HunkStarts, currentLine)
-	} else {
-		patch, err = p.ModifyPatchForLine(state.Diff, currentLine)
-	}
-	if err != nil {
-		return err
+		return gui.createErrorPanel(gui.g, err.Error())
 	}
 
 	// for logging purposes
@@ -207,16 +711,150 @@ func (gui *Gui) handleStageLineOrHunk(hunk bool) error {
 
 	// apply the patch then refresh this panel
 	// create a new temp file with the patch, then call git apply with that patch
-	_, err = gui.GitCommand.ApplyPatch(patch)
-	if err != nil {
+	if err := gui.applyStagingPatch(&appliedPatch{patch: patch, cached: true, reverse: state.ShowingStaged, ignoreWhitespace: gui.State.IgnoreWhitespace}); err != nil {
+		return gui.createErrorPanel(gui.g, err.Error())
+	}
+
+	return gui.refreshFilesAndStagingPanel()
+}
+
+// applyStagingPatch applies the patch and records it so that it can be undone.
+// We check the patch applies first so that we can say which hunk is the problem
+func (gui *Gui) applyStagingPatch(p *appliedPatch) error {
+	if err := gui.GitCommand.CheckPatch(p.patch, p.flags()...); err != nil {
+		return err
+	}
+	if _, err := gui.GitCommand.ApplyPatch(p.patch, p.flags()...); err != nil {
 		return err
 	}
+	gui.State.Panels.Staging.PatchHistory.Push(p)
+	return nil
+}
+
+// handleStagingUndo applies the most recently applied patch in the opposite
+// direction, e.g. unstaging a line that was just staged
+func (gui *Gui) handleStagingUndo(g *gocui.Gui, v *gocui.View) error {
+	history := gui.State.Panels.Staging.PatchHistory
+	if history.Len() == 0 {
+		return nil
+	}
+	lastPatch := history.Pop().(*appliedPatch)
+	undoPatch := &appliedPatch{patch: lastPatch.patch, cached: lastPatch.cached, reverse: !lastPatch.reverse, ignoreWhitespace: lastPatch.ignoreWhitespace}
+	if _, err := gui.GitCommand.ApplyPatch(undoPatch.patch, undoPatch.flags()...); err != nil {
+		return gui.createErrorPanel(g, err.Error())
+	}
+
+	return gui.refreshFilesAndStagingPanel()
+}
+
+// getStagingPatch returns the patch for the selected hunk, or otherwise the
+// selected range or line
+func (gui *Gui) getStagingPatch(hunk bool, reverse bool) (string, error) {
+	state := gui.State.Panels.Staging
+	if hunk {
+		return state.DiffState.PatchForHunk(state.DiffState.StageableLines()[state.SelectedLine])
+	}
+
+	lineNumbers := gui.selectedStageableLines()
+	if state.Refining {
+		lineNumbers = []int{}
+		for lineNumber := range state.RefinedLines {
+			lineNumbers = append(lineNumbers, lineNumber)
+		}
+	}
+	return state.DiffState.PatchForLines(lineNumbers, reverse)
+}
 
+func (gui *Gui) refreshFilesAndStagingPanel() error {
 	if err := gui.refreshFiles(); err != nil {
 		return err
 	}
-	if err := gui.refreshStagingPanel(); err != nil {
+	return gui.refreshStagingPanel()
+}
+
+func (gui *Gui) handleDiscardHunk(g *gocui.Gui, v *gocui.View) error {
+	return gui.handleDiscardLineOrHunk(true)
+}
+
+func (gui *Gui) handleDiscardLine(g *gocui.Gui, v *gocui.View) error {
+	return gui.handleDiscardLineOrHunk(false)
+}
+
+// handleDiscardLineOrHunk removes the selected unstaged changes from the
+// working tree by applying their patch to it in reverse
+func (gui *Gui) handleDiscardLineOrHunk(hunk bool) error {
+	state := gui.State.Panels.Staging
+	if state.ShowingStaged {
+		return gui.createErrorPanel(gui.g, gui.Tr.SLocalize("CantDiscardStagedChanges"))
+	}
+
+	return gui.createConfirmationPanel(gui.g, gui.getMainView(), gui.Tr.SLocalize("DiscardChangeTitle"), gui.Tr.SLocalize("DiscardChangePrompt"), func(g *gocui.Gui, v *gocui.View) error {
+		patch, err := gui.getStagingPatch(hunk, true)
+		if err != nil {
+			return gui.createErrorPanel(g, err.Error())
+		}
+		if err := gui.applyStagingPatch(&appliedPatch{patch: patch, reverse: true, ignoreWhitespace: gui.State.IgnoreWhitespace}); err != nil {
+			return gui.createErrorPanel(g, err.Error())
+		}
+		return gui.refreshFilesAndStagingPanel()
+	}, nil)
+}
+
+// handleEditHunk opens the selected hunk in the user's editor, and once the
+// editor is closed applies whatever patch was left behind, much like the `e`
+// option in `git add --patch`
+func (gui *Gui) handleEditHunk(g *gocui.Gui, v *gocui.View) error {
+	state := gui.State.Panels.Staging
+	patch, err := gui.getStagingPatch(true, state.ShowingStaged)
+	if err != nil {
+		return gui.createErrorPanel(g, err.Error())
+	}
+
+	filename, err := gui.OSCommand.CreateTempFile("patch", patch)
+	if err != nil {
+		return gui.createErrorPanel(g, err.Error())
+	}
+
+	sub, err := gui.OSCommand.EditFile(filename)
+	if err != nil {
+		_ = gui.OSCommand.Remove(filename)
+		return gui.createErrorPanel(g, err.Error())
+	}
+
+	reverse := state.ShowingStaged
+	ignoreWhitespace := gui.State.IgnoreWhitespace
+	gui.SubProcess = sub
+	gui.SubProcessCallback = func() error {
+		defer func() { _ = gui.OSCommand.Remove(filename) }()
+
+		editedPatch, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+		// an emptied file means the user has changed their mind
+		if strings.TrimSpace(string(editedPatch)) == "" {
+			return nil
+		}
+		editedAppliedPatch := &appliedPatch{patch: string(editedPatch), cached: true, reverse: reverse, ignoreWhitespace: ignoreWhitespace}
+		_, err = gui.GitCommand.ApplyPatch(editedAppliedPatch.patch, editedAppliedPatch.flags()...)
 		return err
 	}
-	return nil
+
+	// the gui is rebuilt after the subprocess so we return to the files panel
+	gui.State.Panels.Staging = nil
+
+	return gui.Errors.ErrSubProcess
+}
+
+// handleToggleSyntaxHighlighting switches syntax highlighting of diffs on or
+// off, given it can be slow on large diffs
+func (gui *Gui) handleToggleSyntaxHighlighting(g *gocui.Gui, v *gocui.View) error {
+	gui.State.SyntaxHighlighting = !gui.State.SyntaxHighlighting
+	gui.State.StagingDiffs = map[stagingDiffKey]*stagingDiff{}
+
+	if state := gui.State.Panels.Staging; state != nil {
+		state.ColorDiff = gui.renderDiff(state.DiffState.Diff())
+		return gui.renderStagingDiff()
+	}
+	return gui.handleCommitSelect(g, v)
 }
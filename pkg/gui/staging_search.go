@@ -0,0 +1,132 @@
+package gui
+
+import (
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/jesseduffield/gocui"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+)
+
+func (gui *Gui) handleStagingSearch(g *gocui.Gui, v *gocui.View) error {
+	return gui.createPromptPanel(g, v, gui.Tr.SLocalize("SearchTitle"), func(g *gocui.Gui, promptView *gocui.View) error {
+		state := gui.State.Panels.Staging
+		state.SearchString = strings.TrimSpace(promptView.Buffer())
+		gui.updateStagingSearchMatches()
+
+		if state.SearchString == "" {
+			return gui.renderStagingDiff()
+		}
+		if len(state.SearchMatches) == 0 {
+			if err := gui.renderStagingDiff(); err != nil {
+				return err
+			}
+			return gui.createErrorPanel(g, gui.Tr.SLocalize("NoSearchMatches"))
+		}
+
+		// start from the first match at or after the selected line
+		currentLine := state.DiffState.StageableLines()[state.SelectedLine]
+		state.SearchIndex = utils.NextIndex(state.SearchMatches, currentLine-1)
+		return gui.selectSearchMatch()
+	})
+}
+
+func (gui *Gui) handleStagingNextSearchMatch(g *gocui.Gui, v *gocui.View) error {
+	return gui.handleCycleSearchMatch(false)
+}
+
+func (gui *Gui) handleStagingPrevSearchMatch(g *gocui.Gui, v *gocui.View) error {
+	return gui.handleCycleSearchMatch(true)
+}
+
+func (gui *Gui) handleCycleSearchMatch(prev bool) error {
+	state := gui.State.Panels.Staging
+	matchCount := len(state.SearchMatches)
+	if matchCount == 0 {
+		return nil
+	}
+
+	if prev {
+		state.SearchIndex = (state.SearchIndex - 1 + matchCount) % matchCount
+	} else {
+		state.SearchIndex = (state.SearchIndex + 1) % matchCount
+	}
+
+	return gui.selectSearchMatch()
+}
+
+// selectSearchMatch moves the selection to the stageable line nearest to the
+// current search match
+func (gui *Gui) selectSearchMatch() error {
+	state := gui.State.Panels.Staging
+	matchLine := state.SearchMatches[state.SearchIndex]
+
+	nearestIndex := 0
+	for index, lineNumber := range state.DiffState.StageableLines() {
+		if absInt(lineNumber-matchLine) < absInt(state.DiffState.StageableLines()[nearestIndex]-matchLine) {
+			nearestIndex = index
+		}
+	}
+	state.SelectedLine = nearestIndex
+
+	return gui.focusLineAndHunkAndRender()
+}
+
+// updateStagingSearchMatches finds the lines of the diff containing the search
+// string. It needs calling whenever the diff changes
+func (gui *Gui) updateStagingSearchMatches() {
+	state := gui.State.Panels.Staging
+	state.SearchMatches = []int{}
+	state.SearchIndex = 0
+	if state.SearchString == "" {
+		return
+	}
+
+	searchString := strings.ToLower(state.SearchString)
+	for index, line := range strings.Split(state.DiffState.Diff(), "\n") {
+		if strings.Contains(strings.ToLower(line), searchString) {
+			state.SearchMatches = append(state.SearchMatches, index)
+		}
+	}
+}
+
+// highlightSearchMatches highlights every occurrence of the search string in
+// the matching lines. We lose the diff colouring of those lines but the
+// highlighted text is what you'll be looking for anyway
+func (gui *Gui) highlightSearchMatches(lines []string) {
+	state := gui.State.Panels.Staging
+	if state.SearchString == "" {
+		return
+	}
+
+	searchString := strings.ToLower(state.SearchString)
+	highlightColor := color.New(color.FgBlack, color.BgYellow)
+	for _, lineNumber := range state.SearchMatches {
+		if lineNumber >= len(lines) {
+			continue
+		}
+		line := utils.Decolorise(lines[lineNumber])
+		lowerLine := strings.ToLower(line)
+
+		output := ""
+		start := 0
+		for {
+			offset := strings.Index(lowerLine[start:], searchString)
+			if offset == -1 {
+				break
+			}
+			matchStart := start + offset
+			matchEnd := matchStart + len(searchString)
+			output += line[start:matchStart] + utils.ColoredStringDirect(line[matchStart:matchEnd], highlightColor)
+			start = matchEnd
+		}
+		lines[lineNumber] = output + line[start:]
+	}
+}
+
+func absInt(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
@@ -44,7 +44,14 @@ func (gui *Gui) handleStashEntrySelect(g *gocui.Gui, v *gocui.View) error {
 
 func (gui *Gui) refreshStashEntries(g *gocui.Gui) error {
 	g.Update(func(g *gocui.Gui) error {
+		previousCount := len(gui.State.StashEntries)
 		gui.State.StashEntries = gui.GitCommand.GetStashEntries()
+		if len(gui.State.StashEntries) != previousCount {
+			// the status panel shows how many entries there are
+			if err := gui.refreshStatus(g); err != nil {
+				return err
+			}
+		}
 
 		gui.refreshSelectedLine(&gui.State.Panels.Stash.SelectedLine, len(gui.State.StashEntries))
 
@@ -112,6 +119,27 @@ func (gui *Gui) handleStashDrop(g *gocui.Gui, v *gocui.View) error {
 	}, nil)
 }
 
+// handleStashBranch makes a new branch off the commit the selected stash
+// entry was made at, which the entry is sure to apply to cleanly, and pops
+// the entry onto it
+func (gui *Gui) handleStashBranch(g *gocui.Gui, v *gocui.View) error {
+	stashEntry := gui.getSelectedStashEntry(v)
+	if stashEntry == nil {
+		return nil
+	}
+	message := gui.Tr.TemplateLocalize("NewBranchNameBranchOff", Teml{"branchName": fmt.Sprintf("stash@{%d}", stashEntry.Index)})
+	return gui.createPromptPanel(g, v, message, func(g *gocui.Gui, promptView *gocui.View) error {
+		name := gui.trimmedContent(promptView)
+		return gui.confirmLeavingDetachedHead(v, func() error {
+			if err := gui.GitCommand.StashBranch(name, stashEntry.Index); err != nil {
+				return gui.createErrorPanel(gui.g, err.Error())
+			}
+			gui.State.Panels.Branches.SelectedLine = 0
+			return gui.refreshSidePanels(gui.g)
+		})
+	})
+}
+
 func (gui *Gui) stashDo(g *gocui.Gui, v *gocui.View, method string) error {
 	stashEntry := gui.getSelectedStashEntry(v)
 	if stashEntry == nil {
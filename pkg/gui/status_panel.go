@@ -21,6 +21,9 @@ func (gui *Gui) refreshStatus(g *gocui.Gui) error {
 		v.Clear()
 		pushables, pullables := gui.GitCommand.GetCurrentBranchUpstreamDifferenceCount()
 		fmt.Fprint(v, "↑"+pushables+"↓"+pullables)
+		if stashCount := len(gui.State.StashEntries); stashCount > 0 {
+			fmt.Fprint(v, utils.ColoredString(fmt.Sprintf(" ≡%d", stashCount), color.FgMagenta))
+		}
 		branches := gui.State.Branches
 		if err := gui.updateWorkTreeState(); err != nil {
 			return err
@@ -28,12 +31,15 @@ func (gui *Gui) refreshStatus(g *gocui.Gui) error {
 		if gui.State.WorkingTreeState != "normal" {
 			fmt.Fprint(v, utils.ColoredString(fmt.Sprintf(" (%s)", gui.State.WorkingTreeState), color.FgYellow))
 		}
+		if gui.State.Bisect != nil {
+			fmt.Fprint(v, utils.ColoredString(fmt.Sprintf(" (%s)", gui.Tr.SLocalize("Bisecting")), color.FgMagenta))
+		}
 
 		if len(branches) == 0 {
 			return nil
 		}
 		branch := branches[0]
-		name := utils.ColoredString(branch.Name, branch.GetColor())
+		name := branch.ColoredName()
 		repo := utils.GetCurrentRepoName()
 		fmt.Fprint(v, " "+repo+" → "+name)
 		return nil
@@ -92,7 +98,40 @@ func lazygitTitle() string {
                |___/ |___/       `
 }
 
+// operationInProgress says whether a merge, rebase, cherry-pick or revert has
+// stopped part way and is waiting to be continued or aborted
+func (gui *Gui) operationInProgress() bool {
+	return gui.State.WorkingTreeState != "normal" && gui.State.WorkingTreeState != "conflicted"
+}
+
+// updateWorkTreeState works out which operation is in progress. If none is but
+// there are still conflicts, like from popping a stash, we say we're
+// conflicted, as there's nothing for git to continue or abort
 func (gui *Gui) updateWorkTreeState() error {
+	rebaseMode, err := gui.GitCommand.RebaseMode()
+	if err != nil {
+		return err
+	}
+	if rebaseMode != "" {
+		gui.State.WorkingTreeState = "rebasing"
+		return nil
+	}
+	cherryPicking, err := gui.GitCommand.IsCherryPicking()
+	if err != nil {
+		return err
+	}
+	if cherryPicking {
+		gui.State.WorkingTreeState = "cherry-picking"
+		return nil
+	}
+	reverting, err := gui.GitCommand.IsReverting()
+	if err != nil {
+		return err
+	}
+	if reverting {
+		gui.State.WorkingTreeState = "reverting"
+		return nil
+	}
 	merging, err := gui.GitCommand.IsInMergeState()
 	if err != nil {
 		return err
@@ -101,12 +140,12 @@ func (gui *Gui) updateWorkTreeState() error {
 		gui.State.WorkingTreeState = "merging"
 		return nil
 	}
-	rebaseMode, err := gui.GitCommand.RebaseMode()
+	conflicted, err := gui.GitCommand.HasUnmergedFiles()
 	if err != nil {
 		return err
 	}
-	if rebaseMode != "" {
-		gui.State.WorkingTreeState = "rebasing"
+	if conflicted {
+		gui.State.WorkingTreeState = "conflicted"
 		return nil
 	}
 	gui.State.WorkingTreeState = "normal"
@@ -0,0 +1,142 @@
+package gui
+
+import (
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/fatih/color"
+	"github.com/jesseduffield/lazygit/pkg/utils"
+)
+
+// syntaxDefinition describes just enough of a language to colour keywords,
+// strings and comments. It's not a real lexer, so it will get things wrong
+// with multi-line strings and comments, but it's cheap
+type syntaxDefinition struct {
+	keywords    map[string]bool
+	lineComment string
+	quotes      string
+}
+
+func newSyntaxDefinition(lineComment string, quotes string, keywords ...string) *syntaxDefinition {
+	keywordMap := map[string]bool{}
+	for _, keyword := range keywords {
+		keywordMap[keyword] = true
+	}
+	return &syntaxDefinition{
+		keywords:    keywordMap,
+		lineComment: lineComment,
+		quotes:      quotes,
+	}
+}
+
+var (
+	goSyntax = newSyntaxDefinition("//", "\"'`",
+		"break", "case", "chan", "const", "continue", "default", "defer", "else",
+		"fallthrough", "for", "func", "go", "goto", "if", "import", "interface",
+		"map", "package", "range", "return", "select", "struct", "switch", "type",
+		"var", "nil", "true", "false",
+	)
+	javascriptSyntax = newSyntaxDefinition("//", "\"'`",
+		"async", "await", "break", "case", "catch", "class", "const", "continue",
+		"default", "delete", "do", "else", "export", "extends", "finally", "for",
+		"function", "if", "import", "in", "instanceof", "let", "new", "return",
+		"static", "super", "switch", "this", "throw", "try", "typeof", "var",
+		"void", "while", "yield", "null", "undefined", "true", "false",
+	)
+	pythonSyntax = newSyntaxDefinition("#", "\"'",
+		"and", "as", "assert", "async", "await", "break", "class", "continue",
+		"def", "del", "elif", "else", "except", "finally", "for", "from", "global",
+		"if", "import", "in", "is", "lambda", "nonlocal", "not", "or", "pass",
+		"raise", "return", "try", "while", "with", "yield", "None", "True", "False",
+	)
+	rubySyntax = newSyntaxDefinition("#", "\"'",
+		"begin", "break", "case", "class", "def", "do", "else", "elsif", "end",
+		"ensure", "for", "if", "in", "module", "next", "nil", "rescue", "return",
+		"self", "super", "then", "unless", "until", "when", "while", "yield",
+		"true", "false",
+	)
+	rustSyntax = newSyntaxDefinition("//", "\"",
+		"as", "break", "const", "continue", "crate", "else", "enum", "extern",
+		"fn", "for", "if", "impl", "in", "let", "loop", "match", "mod", "move",
+		"mut", "pub", "ref", "return", "self", "Self", "static", "struct", "super",
+		"trait", "type", "unsafe", "use", "where", "while", "true", "false",
+	)
+	cSyntax = newSyntaxDefinition("//", "\"'",
+		"break", "case", "catch", "class", "const", "continue", "default", "delete",
+		"do", "else", "enum", "extends", "final", "for", "if", "implements",
+		"import", "namespace", "new", "private", "protected", "public", "return",
+		"sizeof", "static", "struct", "switch", "template", "this", "throw", "try",
+		"typedef", "union", "void", "while", "null", "true", "false",
+	)
+	shellSyntax = newSyntaxDefinition("#", "\"'",
+		"case", "do", "done", "elif", "else", "esac", "export", "fi", "for",
+		"function", "if", "in", "local", "return", "then", "until", "while",
+	)
+)
+
+var syntaxDefinitions = map[string]*syntaxDefinition{
+	".go":   goSyntax,
+	".js":   javascriptSyntax,
+	".jsx":  javascriptSyntax,
+	".ts":   javascriptSyntax,
+	".tsx":  javascriptSyntax,
+	".py":   pythonSyntax,
+	".rb":   rubySyntax,
+	".rs":   rustSyntax,
+	".c":    cSyntax,
+	".h":    cSyntax,
+	".cpp":  cSyntax,
+	".hpp":  cSyntax,
+	".cs":   cSyntax,
+	".java": cSyntax,
+	".sh":   shellSyntax,
+	".bash": shellSyntax,
+}
+
+// syntaxForFile returns the syntax definition for the file based on its
+// extension, or nil if we don't know the language
+func syntaxForFile(filename string) *syntaxDefinition {
+	return syntaxDefinitions[strings.ToLower(filepath.Ext(filename))]
+}
+
+// highlight colours a single line of code
+func (s *syntaxDefinition) highlight(line string) string {
+	runes := []rune(line)
+	output := ""
+	for i := 0; i < len(runes); {
+		char := runes[i]
+		switch {
+		case strings.HasPrefix(string(runes[i:]), s.lineComment):
+			return output + utils.ColoredString(string(runes[i:]), color.FgHiBlack)
+		case strings.ContainsRune(s.quotes, char):
+			end := i + 1
+			for end < len(runes) && runes[end] != char {
+				if runes[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(runes) {
+				end = len(runes) - 1
+			}
+			output += utils.ColoredString(string(runes[i:end+1]), color.FgYellow)
+			i = end + 1
+		case unicode.IsLetter(char) || char == '_':
+			end := i
+			for end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end]) || runes[end] == '_') {
+				end++
+			}
+			word := string(runes[i:end])
+			if s.keywords[word] {
+				word = utils.ColoredString(word, color.FgMagenta)
+			}
+			output += word
+			i = end
+		default:
+			output += string(char)
+			i++
+		}
+	}
+	return output
+}
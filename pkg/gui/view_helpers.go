@@ -22,6 +22,12 @@ func (gui *Gui) refreshSidePanels(g *gocui.Gui) error {
 	if err := gui.refreshCommits(g); err != nil {
 		return err
 	}
+	if err := gui.refreshReflog(g); err != nil {
+		return err
+	}
+	if err := gui.refreshRemoteBranches(g); err != nil {
+		return err
+	}
 
 	return gui.refreshStashEntries(g)
 }
@@ -31,11 +37,14 @@ func (gui *Gui) nextView(g *gocui.Gui, v *gocui.View) error {
 	if v == nil || v.Name() == cyclableViews[len(cyclableViews)-1] {
 		focusedViewName = cyclableViews[0]
 	} else {
-		// if we're in the commitFiles view we'll act like we're in the commits view
+		// if we're in the commitFiles or reflog view we'll act like we're in the commits view
 		viewName := v.Name()
-		if viewName == "commitFiles" {
+		if viewName == "commitFiles" || viewName == "reflog" {
 			viewName = "commits"
 		}
+		if viewName == "remoteBranches" {
+			viewName = "branches"
+		}
 		for i := range cyclableViews {
 			if viewName == cyclableViews[i] {
 				focusedViewName = cyclableViews[i+1]
@@ -57,6 +66,12 @@ func (gui *Gui) nextView(g *gocui.Gui, v *gocui.View) error {
 	if err != nil {
 		panic(err)
 	}
+	if focusedViewName == "commits" {
+		focusedView = gui.commitsSlotView()
+	}
+	if focusedViewName == "branches" {
+		focusedView = gui.branchesSlotView()
+	}
 	return gui.switchFocus(g, v, focusedView)
 }
 
@@ -65,11 +80,14 @@ func (gui *Gui) previousView(g *gocui.Gui, v *gocui.View) error {
 	if v == nil || v.Name() == cyclableViews[0] {
 		focusedViewName = cyclableViews[len(cyclableViews)-1]
 	} else {
-		// if we're in the commitFiles view we'll act like we're in the commits view
+		// if we're in the commitFiles or reflog view we'll act like we're in the commits view
 		viewName := v.Name()
-		if viewName == "commitFiles" {
+		if viewName == "commitFiles" || viewName == "reflog" {
 			viewName = "commits"
 		}
+		if viewName == "remoteBranches" {
+			viewName = "branches"
+		}
 		for i := range cyclableViews {
 			if viewName == cyclableViews[i] {
 				focusedViewName = cyclableViews[i-1] // TODO: make this work properly
@@ -91,6 +109,12 @@ func (gui *Gui) previousView(g *gocui.Gui, v *gocui.View) error {
 	if err != nil {
 		panic(err)
 	}
+	if focusedViewName == "commits" {
+		focusedView = gui.commitsSlotView()
+	}
+	if focusedViewName == "branches" {
+		focusedView = gui.branchesSlotView()
+	}
 	return gui.switchFocus(g, v, focusedView)
 }
 
@@ -110,6 +134,10 @@ func (gui *Gui) newLineFocused(g *gocui.Gui, v *gocui.View) error {
 		return gui.handleCommitFileSelect(g, v)
 	case "stash":
 		return gui.handleStashEntrySelect(g, v)
+	case "reflog":
+		return gui.handleReflogEntrySelect(g, v)
+	case "remoteBranches":
+		return gui.handleRemoteBranchSelect(g, v)
 	case "confirmation":
 		return nil
 	case "commitMessage":
@@ -120,6 +148,13 @@ func (gui *Gui) newLineFocused(g *gocui.Gui, v *gocui.View) error {
 		if gui.State.Contexts["main"] == "merging" {
 			return gui.refreshMergePanel()
 		}
+		// we're returning from a popup opened in the staging panel, which relies
+		// on the highlight to show the selected line
+		context := gui.State.Contexts["main"]
+		if (context == "staging" || context == "patchBuilding") && gui.State.Panels.Staging != nil {
+			v.Highlight = true
+			return nil
+		}
 		v.Highlight = false
 		return nil
 	default:
@@ -284,10 +319,29 @@ func (gui *Gui) getCommitFilesView() *gocui.View {
 	return v
 }
 
+func (gui *Gui) getReflogView() *gocui.View {
+	v, _ := gui.g.View("reflog")
+	return v
+}
+
+func (gui *Gui) getRemoteBranchesView() *gocui.View {
+	v, _ := gui.g.View("remoteBranches")
+	return v
+}
+
 func (gui *Gui) trimmedContent(v *gocui.View) string {
 	return strings.TrimSpace(v.Buffer())
 }
 
+// setPromptContent replaces what's been typed into a prompt, leaving the
+// cursor at the end
+func (gui *Gui) setPromptContent(v *gocui.View, content string) {
+	v.Clear()
+	fmt.Fprint(v, content)
+	_ = v.SetCursor(len([]rune(content)), 0)
+	_ = v.SetOrigin(0, 0)
+}
+
 func (gui *Gui) currentViewName() string {
 	currentView := gui.g.CurrentView()
 	return currentView.Name()
@@ -265,9 +265,6 @@ func addDutch(i18nObject *i18n.Bundle) error {
 		}, &i18n.Message{
 			ID:    "SureFixupThisCommit",
 			Other: "Weet je zeker dat je fixup wil uitvoeren op deze commit? De commit hieronder zol worden squashed in deze",
-		}, &i18n.Message{
-			ID:    "OnlyRenameTopCommit",
-			Other: "Je kan alleen de bovenste commit hernoemen",
 		}, &i18n.Message{
 			ID:    "renameCommit",
 			Other: "hernoem commit",
@@ -712,12 +709,6 @@ func addDutch(i18nObject *i18n.Bundle) error {
 		}, &i18n.Message{
 			ID:    "SureSquashAboveCommits",
 			Other: `Weet je zeker dat je alles wil squash/fixup! voor de bovenstaand commits {{.commit}}?`,
-		}, &i18n.Message{
-			ID:    "CreateFixupCommit",
-			Other: `Creëer fixup commit`,
-		}, &i18n.Message{
-			ID:    "SureCreateFixupCommit",
-			Other: `Weet je zeker dat je een fixup wil maken! commit voor commit {{.commit}}?`,
 		}, &i18n.Message{
 			ID:    "executeCustomCommand",
 			Other: "voor aangepast commando uit",
@@ -751,6 +742,957 @@ func addDutch(i18nObject *i18n.Bundle) error {
 		}, &i18n.Message{
 			ID:    "notARepository",
 			Other: "Error: must be run inside a git repository",
+		}, &i18n.Message{
+			ID:    "ToggleSelectRange",
+			Other: "toggle range selection",
+		}, &i18n.Message{
+			ID:    "UnstagingMainTitle",
+			Other: "Unstage Lines/Hunks",
+		}, &i18n.Message{
+			ID:    "ToggleStagedSide",
+			Other: "switch between staged and unstaged changes",
+		}, &i18n.Message{
+			ID:    "NoChangesOnOtherSide",
+			Other: "This file has no changes on the other side",
+		}, &i18n.Message{
+			ID:    "EditHunk",
+			Other: "edit hunk in editor",
+		}, &i18n.Message{
+			ID:    "DiscardLine",
+			Other: "discard line from working tree",
+		}, &i18n.Message{
+			ID:    "DiscardHunk",
+			Other: "discard hunk from working tree",
+		}, &i18n.Message{
+			ID:    "DiscardChangeTitle",
+			Other: "Discard change",
+		}, &i18n.Message{
+			ID:    "DiscardChangePrompt",
+			Other: "Are you sure you want to discard this change from the working tree? This cannot be undone",
+		}, &i18n.Message{
+			ID:    "CantDiscardStagedChanges",
+			Other: "Can only discard unstaged changes",
+		}, &i18n.Message{
+			ID:    "PrevFile",
+			Other: "previous file",
+		}, &i18n.Message{
+			ID:    "NextFile",
+			Other: "next file",
+		}, &i18n.Message{
+			ID:    "ToggleSyntaxHighlighting",
+			Other: "toggle syntax highlighting",
+		}, &i18n.Message{
+			ID:    "ToggleIgnoreWhitespace",
+			Other: "toggle ignoring whitespace in diffs",
+		}, &i18n.Message{
+			ID:    "CantSplitHunk",
+			Other: "This hunk can't be split any further",
+		}, &i18n.Message{
+			ID:    "SplitHunk",
+			Other: "split hunk",
+		}, &i18n.Message{
+			ID:    "DecreaseContextSize",
+			Other: "show fewer lines of context",
+		}, &i18n.Message{
+			ID:    "IncreaseContextSize",
+			Other: "show more lines of context",
+		}, &i18n.Message{
+			ID:    "NoPatchError",
+			Other: "No lines have been added to the custom patch. Add lines with 'p' in the staging panel",
+		}, &i18n.Message{
+			ID:    "ApplyPatchToIndex",
+			Other: "apply patch to index",
+		}, &i18n.Message{
+			ID:    "RemovePatchFromWorkingTree",
+			Other: "remove patch from working tree",
+		}, &i18n.Message{
+			ID:    "ResetPatch",
+			Other: "reset patch",
+		}, &i18n.Message{
+			ID:    "ApplyPatchToCommit",
+			Other: "move patch into commit",
+		}, &i18n.Message{
+			ID:    "PatchOptionsTitle",
+			Other: "Custom Patch Options",
+		}, &i18n.Message{
+			ID:    "CustomPatchUnstagedOnly",
+			Other: "Can only add unstaged changes to the custom patch",
+		}, &i18n.Message{
+			ID:    "ToggleLinesInPatch",
+			Other: "add/remove line(s) to/from custom patch",
+		}, &i18n.Message{
+			ID:    "ViewPatchOptions",
+			Other: "view custom patch options",
+		}, &i18n.Message{
+			ID:    "StagingSearch",
+			Other: "search",
+		}, &i18n.Message{
+			ID:    "NextSearchMatch",
+			Other: "next search match",
+		}, &i18n.Message{
+			ID:    "PrevSearchMatch",
+			Other: "previous search match",
+		}, &i18n.Message{
+			ID:    "SearchTitle",
+			Other: "Search:",
+		}, &i18n.Message{
+			ID:    "NoSearchMatches",
+			Other: "No matches found",
+		}, &i18n.Message{
+			ID:    "StageFile",
+			Other: "stage/unstage all lines in file",
+		}, &i18n.Message{
+			ID:    "RefineHunk",
+			Other: "select hunk to refine line by line",
+		}, &i18n.Message{
+			ID:    "ApplyRefinedLines",
+			Other: "stage/unstage refined selection",
+		}, &i18n.Message{
+			ID:    "BinaryFileTitle",
+			Other: "Binary file: lines can't be staged individually",
+		}, &i18n.Message{
+			ID:    "StageBinaryFile",
+			Other: "stage file",
+		}, &i18n.Message{
+			ID:    "UnstageBinaryFile",
+			Other: "unstage file",
+		}, &i18n.Message{
+			ID:    "PatchDoesNotApply",
+			Other: "This hunk in {{.file}} no longer applies:\n{{.hunk}}\n\n{{.error}}",
+		}, &i18n.Message{
+			ID:    "PatchBuildingMainTitle",
+			Other: "Building Custom Patch",
+		}, &i18n.Message{
+			ID:    "SelectLinesForPatch",
+			Other: "select lines for custom patch",
+		}, &i18n.Message{
+			ID:    "ToggleHunkInPatch",
+			Other: "add/remove hunk to/from custom patch",
+		}, &i18n.Message{
+			ID:    "ReturnToCommitFiles",
+			Other: "return to commit files",
+		}, &i18n.Message{
+			ID:    "SplitPatchIntoNewCommit",
+			Other: "move patch out into a new commit",
+		}, &i18n.Message{
+			ID:    "NewCommitMessageTitle",
+			Other: "Message for the new commit:",
+		}, &i18n.Message{
+			ID:    "PatchCommitNotFound",
+			Other: "The commit that the custom patch was built from is no longer in the log",
+		}, &i18n.Message{
+			ID:    "ToggleFileTree",
+			Other: "toggle file tree view",
+		}, &i18n.Message{
+			ID:    "StageLinesOrToggleDirectory",
+			Other: "stage individual hunks/lines, or collapse/expand directory",
+		}, &i18n.Message{
+			ID:    "DirectoryHasMergeConflicts",
+			Other: "This directory contains files with merge conflicts. Resolve them one file at a time",
+		}, &i18n.Message{
+			ID:    "DiscardDirectoryTitle",
+			Other: "Discard directory changes",
+		}, &i18n.Message{
+			ID:    "DiscardDirectoryPrompt",
+			Other: "Are you sure you want to discard all changes in {{.path}}? Untracked files in it will be deleted",
+		}, &i18n.Message{
+			ID:    "viewIgnoreOptions",
+			Other: "view ignore options",
+		}, &i18n.Message{
+			ID:    "excludeFile",
+			Other: "add to .git/info/exclude",
+		}, &i18n.Message{
+			ID:    "ignorePattern",
+			Other: "add a pattern to .gitignore",
+		}, &i18n.Message{
+			ID:    "excludePattern",
+			Other: "add a pattern to .git/info/exclude",
+		}, &i18n.Message{
+			ID:    "IgnorePatternTitle",
+			Other: "Pattern to ignore",
+		}, &i18n.Message{
+			ID:    "ToggleFullscreenMain",
+			Other: "toggle fullscreen diff",
+		}, &i18n.Message{
+			ID:    "stashFile",
+			Other: "stash changes to {{.path}}",
+		}, &i18n.Message{
+			ID:    "copyFilePath",
+			Other: "copy the path to the clipboard",
+		}, &i18n.Message{
+			ID:    "copyAbsoluteFilePath",
+			Other: "copy the absolute path to the clipboard",
+		}, &i18n.Message{
+			ID:    "NoClipboard",
+			Other: "Couldn't find a program to copy to the clipboard with. On Linux, install xclip, xsel or wl-clipboard",
+		}, &i18n.Message{
+			ID:    "toggleFileMarked",
+			Other: "mark/unmark file for staging, discarding or stashing with other marked files",
+		}, &i18n.Message{
+			ID:    "clearMarkedFiles",
+			Other: "unmark all files",
+		}, &i18n.Message{
+			ID:    "stashMarkedFiles",
+			Other: "stash changes to the marked files",
+		}, &i18n.Message{
+			ID:    "DiscardMarkedFilesTitle",
+			Other: "Discard marked files",
+		}, &i18n.Message{
+			ID:    "DiscardMarkedFilesPrompt",
+			Other: "Are you sure you want to discard all changes to the marked files? Untracked files among them will be deleted",
+		}, &i18n.Message{
+			ID:    "MarkedFilesHaveMergeConflicts",
+			Other: "Some of the marked files have merge conflicts. Resolve them one file at a time",
+		}, &i18n.Message{
+			ID:    "UntrackedFileTooBig",
+			Other: "This untracked file is too big to show ({{.size}} KB). Only files up to {{.limit}} KB are shown",
+		}, &i18n.Message{
+			ID:    "UntrackedDirectory",
+			Other: "This untracked directory is probably a repository of its own, so its contents aren't shown",
+		}, &i18n.Message{
+			ID:    "moveFile",
+			Other: "rename/move file",
+		}, &i18n.Message{
+			ID:    "MoveFileTitle",
+			Other: "Move to (tab completes directories)",
+		}, &i18n.Message{
+			ID:    "viewIgnoreChangesOptions",
+			Other: "ignore changes to file (skip-worktree/assume-unchanged)",
+		}, &i18n.Message{
+			ID:    "skipWorktree",
+			Other: "ignore changes in the working tree (skip-worktree)",
+		}, &i18n.Message{
+			ID:    "noSkipWorktree",
+			Other: "stop ignoring changes in the working tree (no-skip-worktree)",
+		}, &i18n.Message{
+			ID:    "assumeUnchanged",
+			Other: "assume the file is unchanged (assume-unchanged)",
+		}, &i18n.Message{
+			ID:    "noAssumeUnchanged",
+			Other: "stop assuming the file is unchanged (no-assume-unchanged)",
+		}, &i18n.Message{
+			ID:    "ChangesIgnored",
+			Other: "Git has been told to ignore changes to this file, with skip-worktree or assume-unchanged. Press u to undo that",
+		}, &i18n.Message{
+			ID:    "sortFiles",
+			Other: "sort files",
+		}, &i18n.Message{
+			ID:    "SortFilesTitle",
+			Other: "Sort files by",
+		}, &i18n.Message{
+			ID:    "sortFilesByPath",
+			Other: "path",
+		}, &i18n.Message{
+			ID:    "sortFilesByStatus",
+			Other: "status: conflicted, unstaged, staged, untracked",
+		}, &i18n.Message{
+			ID:    "sortFilesByModified",
+			Other: "last modified",
+		}, &i18n.Message{
+			ID:    "deletedByBoth",
+			Other: "Merge conflict: deleted by both",
+		}, &i18n.Message{
+			ID:    "addedByUs",
+			Other: "Merge conflict: added by us",
+		}, &i18n.Message{
+			ID:    "deletedByThem",
+			Other: "Merge conflict: deleted by them",
+		}, &i18n.Message{
+			ID:    "addedByThem",
+			Other: "Merge conflict: added by them",
+		}, &i18n.Message{
+			ID:    "deletedByUs",
+			Other: "Merge conflict: deleted by us",
+		}, &i18n.Message{
+			ID:    "addedByBoth",
+			Other: "Merge conflict: both added",
+		}, &i18n.Message{
+			ID:    "modifiedByBoth",
+			Other: "Merge conflict: both modified",
+		}, &i18n.Message{
+			ID:    "PressEnterToResolveConflict",
+			Other: "Press enter to resolve it by keeping or removing the file",
+		}, &i18n.Message{
+			ID:    "keepConflictedFile",
+			Other: "keep the file (git add)",
+		}, &i18n.Message{
+			ID:    "removeConflictedFile",
+			Other: "remove the file (git rm)",
+		}, &i18n.Message{
+			ID:    "CherryPickOptionsTitle",
+			Other: "Cherry-pick Options",
+		}, &i18n.Message{
+			ID:    "RevertOptionsTitle",
+			Other: "Revert Options",
+		}, &i18n.Message{
+			ID:    "SubmoduleTitle",
+			Other: "Submodule {{.path}}",
+		}, &i18n.Message{
+			ID:    "SubmoduleNoCommit",
+			Other: "none",
+		}, &i18n.Message{
+			ID:    "SubmoduleRecordedCommit",
+			Other: "recorded commit:",
+		}, &i18n.Message{
+			ID:    "SubmoduleCheckedOutCommit",
+			Other: "checked out commit:",
+		}, &i18n.Message{
+			ID:    "SubmoduleHasChanges",
+			Other: "The submodule has changes of its own",
+		}, &i18n.Message{
+			ID:    "PressEnterForSubmoduleOptions",
+			Other: "Press enter for submodule options",
+		}, &i18n.Message{
+			ID:    "updateSubmodule",
+			Other: "update submodule to the recorded commit",
+		}, &i18n.Message{
+			ID:    "stashAndUpdateSubmodule",
+			Other: "stash the submodule's changes, then update it",
+		}, &i18n.Message{
+			ID:    "openSubmodule",
+			Other: "open submodule in lazygit",
+		}, &i18n.Message{
+			ID:    "amendFile",
+			Other: "amend last commit with just this file",
+		}, &i18n.Message{
+			ID:    "SureToAmendFile",
+			Other: "Are you sure you want to amend the last commit with the changes to {{.path}}? Nothing else you've staged will be included.",
+		}, &i18n.Message{
+			ID:    "CantAmendFileMidOperation",
+			Other: "You can't amend a file into the last commit in the middle of a merge or rebase",
+		}, &i18n.Message{
+			ID:    "CantAmendFileWithMergeConflicts",
+			Other: "Resolve the merge conflicts before amending the file into the last commit",
+		}, &i18n.Message{
+			ID:    "checkoutFileFromRef",
+			Other: "check out file from a branch or commit",
+		}, &i18n.Message{
+			ID:    "EnterRef",
+			Other: "enter a branch, tag or commit",
+		}, &i18n.Message{
+			ID:    "CheckoutFileFromRefTitle",
+			Other: "Check out {{.path}} from",
+		}, &i18n.Message{
+			ID:    "CheckoutFileFromRef",
+			Other: "Check out file",
+		}, &i18n.Message{
+			ID:    "SureToCheckoutFileFromRef",
+			Other: "This will overwrite your changes to {{.path}} with its content at {{.ref}}. Are you sure?",
+		}, &i18n.Message{
+			ID:    "chooseUntrackedFilesToDelete",
+			Other: "choose untracked files to delete",
+		}, &i18n.Message{
+			ID:    "chooseIgnoredFilesToDelete",
+			Other: "choose untracked and ignored files to delete",
+		}, &i18n.Message{
+			ID:    "NothingToClean",
+			Other: "There are no untracked files to delete",
+		}, &i18n.Message{
+			ID:    "CleanTitle",
+			Other: "Space to select, enter to delete",
+		}, &i18n.Message{
+			ID:    "CleanConfirmTitle",
+			Other: "Delete files",
+		}, &i18n.Message{
+			ID:    "CleanPrompt",
+			Other: "Permanently delete these {{.count}} files and directories? This can't be undone.",
+		}, &i18n.Message{
+			ID:    "stageTracked",
+			Other: "stage all tracked files (leaves untracked files unstaged)",
+		}, &i18n.Message{
+			ID:    "LFSFileTitle",
+			Other: "This file is stored with git LFS, so its diff is of the pointers to its content",
+		}, &i18n.Message{
+			ID:    "LFSOldContent",
+			Other: "before:",
+		}, &i18n.Message{
+			ID:    "LFSNewContent",
+			Other: "after:",
+		}, &i18n.Message{
+			ID:    "LFSNoContent",
+			Other: "(none)",
+		}, &i18n.Message{
+			ID:    "NotAnLFSFile",
+			Other: "This file isn't stored with git LFS",
+		}, &i18n.Message{
+			ID:    "lockLFSFile",
+			Other: "lock file with git LFS",
+		}, &i18n.Message{
+			ID:    "unlockLFSFile",
+			Other: "unlock file with git LFS",
+		}, &i18n.Message{
+			ID:    "LFSStatus",
+			Other: "updating LFS lock",
+		}, &i18n.Message{
+			ID:    "LFSOptionsTitle",
+			Other: "Git LFS: {{.path}}",
+		}, &i18n.Message{
+			ID:    "LFSOptions",
+			Other: "git LFS options",
+		}, &i18n.Message{
+			ID:    "editGitignore",
+			Other: "edit .gitignore",
+		}, &i18n.Message{
+			ID:    "editExcludeFile",
+			Other: "edit .git/info/exclude (not shared with others)",
+		}, &i18n.Message{
+			ID:    "EditIgnoreFilesTitle",
+			Other: "Edit ignore files",
+		}, &i18n.Message{
+			ID:    "editIgnoreFiles",
+			Other: "edit .gitignore or exclude file",
+		}, &i18n.Message{
+			ID:    "RewordCommitTitle",
+			Other: "Reword commit {{.sha}}",
+		}, &i18n.Message{
+			ID:    "RewordingStatus",
+			Other: "rewording",
+		}, &i18n.Message{
+			ID:    "RewordCommitGone",
+			Other: "The commit you were rewording is no longer in the commits list",
+		}, &i18n.Message{
+			ID:    "NoCopiedCommits",
+			Other: "There are no copied commits to paste. Copy some with c or C first",
+		}, &i18n.Message{
+			ID:    "RevertMergeTitle",
+			Other: "Revert merge commit {{.sha}} against which parent?",
+		}, &i18n.Message{
+			ID:    "RevertingStatus",
+			Other: "reverting",
+		}, &i18n.Message{
+			ID:    "filterCommitsByThisPath",
+			Other: "show only the commits that changed this path",
+		}, &i18n.Message{
+			ID:    "filterCommits",
+			Other: "filter commits by path",
+		}, &i18n.Message{
+			ID:    "filterCommitsByPath",
+			Other: "filter by a path",
+		}, &i18n.Message{
+			ID:    "FilterCommitsByPathTitle",
+			Other: "Show the commits that changed this path:",
+		}, &i18n.Message{
+			ID:    "stopFilteringCommits",
+			Other: "stop filtering",
+		}, &i18n.Message{
+			ID:    "FilterCommitsTitle",
+			Other: "Filter commits",
+		}, &i18n.Message{
+			ID:    "CommitsFilteredTitle",
+			Other: "Commits changing {{.path}} (esc to show all)",
+		}, &i18n.Message{
+			ID:    "CantRebaseWhileFiltering",
+			Other: "Can't rewrite history while the commits are filtered by a path. Press esc to stop filtering first",
+		}, &i18n.Message{
+			ID:    "copyCommitShortSha",
+			Other: "short sha",
+		}, &i18n.Message{
+			ID:    "copyCommitFullSha",
+			Other: "full sha",
+		}, &i18n.Message{
+			ID:    "copyCommitSubject",
+			Other: "subject",
+		}, &i18n.Message{
+			ID:    "copyCommitMessage",
+			Other: "whole message",
+		}, &i18n.Message{
+			ID:    "CopyCommitTitle",
+			Other: "Copy {{.sha}} to clipboard",
+		}, &i18n.Message{
+			ID:    "copyCommitToClipboard",
+			Other: "copy sha or message to clipboard",
+		}, &i18n.Message{
+			ID:    "TagNameTitle",
+			Other: "Tag name:",
+		}, &i18n.Message{
+			ID:    "TagMessageTitle",
+			Other: "Tag message (leave empty for a lightweight tag):",
+		}, &i18n.Message{
+			ID:    "PushTagTitle",
+			Other: "Push tag",
+		}, &i18n.Message{
+			ID:    "PushTagPrompt",
+			Other: "Push the tag {{.tagName}} to origin?",
+		}, &i18n.Message{
+			ID:    "tagCommit",
+			Other: "tag commit",
+		}, &i18n.Message{
+			ID:    "checkoutCommit",
+			Other: "checkout commit",
+		}, &i18n.Message{
+			ID:    "CheckoutCommit",
+			Other: "Checkout commit",
+		}, &i18n.Message{
+			ID:    "SureCheckoutCommit",
+			Other: "This will check out {{.sha}} without a branch (detached HEAD). Commits you make there won't belong to any branch. Continue?",
+		}, &i18n.Message{
+			ID:    "DetachedHeadTitle",
+			Other: "Detached HEAD",
+		}, &i18n.Message{
+			ID:    "DetachedHeadPrompt",
+			Other: "You are now at {{.sha}} with a detached HEAD. Press enter to create a branch here, or esc to stay detached",
+		}, &i18n.Message{
+			ID:    "LeaveDetachedHeadTitle",
+			Other: "Leave detached HEAD",
+		}, &i18n.Message{
+			ID:    "LeaveDetachedHeadPrompt",
+			Other: "No branch contains the commit you are at, so commits made here will be hard to find once you check out something else. Continue?",
+		}, &i18n.Message{
+			ID:    "bisectOptions",
+			Other: "bisect options",
+		}, &i18n.Message{
+			ID:    "BisectTitle",
+			Other: "Bisect",
+		}, &i18n.Message{
+			ID:    "bisectMarkBad",
+			Other: "mark {{.sha}} as bad",
+		}, &i18n.Message{
+			ID:    "bisectMarkGood",
+			Other: "mark {{.sha}} as good",
+		}, &i18n.Message{
+			ID:    "bisectSkip",
+			Other: "skip {{.sha}}",
+		}, &i18n.Message{
+			ID:    "bisectReset",
+			Other: "reset bisect",
+		}, &i18n.Message{
+			ID:    "BisectWaiting",
+			Other: "(bisecting: mark a good and a bad commit)",
+		}, &i18n.Message{
+			ID:    "BisectProgress",
+			Other: "(bisecting: {{.remaining}} left, testing {{.sha}})",
+		}, &i18n.Message{
+			ID:    "BisectDone",
+			Other: "(bisecting: {{.sha}} is the first bad commit)",
+		}, &i18n.Message{
+			ID:    "BisectFoundTitle",
+			Other: "Bisect complete",
+		}, &i18n.Message{
+			ID:    "BisectFoundPrompt",
+			Other: "{{.sha}} is the first bad commit. Reset the bisect?",
+		}, &i18n.Message{
+			ID:    "CantRebaseWhileBisecting",
+			Other: "You can't rebase while bisecting. Reset the bisect first",
+		}, &i18n.Message{
+			ID:    "Bisecting",
+			Other: "bisecting",
+		}, &i18n.Message{
+			ID:    "CreateFixupCommitTitle",
+			Other: "Commit staged changes to squash into {{.commit}}",
+		}, &i18n.Message{
+			ID:    "fixupCommitDescription",
+			Other: "keep its message when squashed",
+		}, &i18n.Message{
+			ID:    "squashCommitDescription",
+			Other: "combine the messages when squashed",
+		}, &i18n.Message{
+			ID:    "conventionalCommit",
+			Other: "commit with a conventional commit message",
+		}, &i18n.Message{
+			ID:    "ConventionalCommitTypeTitle",
+			Other: "Commit type",
+		}, &i18n.Message{
+			ID:    "ConventionalCommitScopeMenuTitle",
+			Other: "Scope of the {{.type}} commit",
+		}, &i18n.Message{
+			ID:    "noScope",
+			Other: "no scope",
+		}, &i18n.Message{
+			ID:    "enterScope",
+			Other: "enter a scope...",
+		}, &i18n.Message{
+			ID:    "ConventionalCommitScopeTitle",
+			Other: "Scope:",
+		}, &i18n.Message{
+			ID:    "addCoAuthor",
+			Other: "add co-author",
+		}, &i18n.Message{
+			ID:    "AddCoAuthorTitle",
+			Other: "Add co-author",
+		}, &i18n.Message{
+			ID:    "NoRecentAuthors",
+			Other: "No one else has made commits recently",
+		}, &i18n.Message{
+			ID:    "ResetTitle",
+			Other: "{{.strength}} reset",
+		}, &i18n.Message{
+			ID:    "SureSoftReset",
+			Other: "This will move the branch to {{.commit}}. The changes in the commits after it will be kept and staged. Continue?",
+		}, &i18n.Message{
+			ID:    "SureMixedReset",
+			Other: "This will move the branch to {{.commit}}. The changes in the commits after it will be kept but unstaged, along with anything you've staged. Continue?",
+		}, &i18n.Message{
+			ID:    "SureHardReset",
+			Other: "This will move the branch to {{.commit}} and discard the commits after it, along with all of your uncommitted changes. Continue?",
+		}, &i18n.Message{
+			ID:    "browseBranchCommits",
+			Other: "view commits",
+		}, &i18n.Message{
+			ID:    "CommitsOfBranchTitle",
+			Other: "Commits of {{.branch}} (esc to return)",
+		}, &i18n.Message{
+			ID:    "CantChangeWhileBrowsingBranch",
+			Other: "Can't change your branch while showing the commits of {{.branch}}. Press esc to go back to your branch's commits first",
+		}, &i18n.Message{
+			ID:    "cycleMergeDiff",
+			Other: "show merge commit diff against each parent / combined",
+		}, &i18n.Message{
+			ID:    "NotAMergeCommit",
+			Other: "This isn't a merge commit",
+		}, &i18n.Message{
+			ID:    "MergeDiffAgainstParent",
+			Other: "Diff against parent {{.number}}: {{.sha}} {{.name}} (M to change)",
+		}, &i18n.Message{
+			ID:    "splitCommit",
+			Other: "split commit in two",
+		}, &i18n.Message{
+			ID:    "CantSplitCommitMidOperation",
+			Other: "You can't split a commit while a merge, rebase, cherry-pick or revert is in progress",
+		}, &i18n.Message{
+			ID:    "absorbStagedChanges",
+			Other: "absorb staged changes into the commits they fix",
+		}, &i18n.Message{
+			ID:    "AbsorbChanges",
+			Other: "Absorb changes",
+		}, &i18n.Message{
+			ID:    "SureAbsorbChanges",
+			Other: "Are you sure you want to squash each staged hunk into the commit that last changed its lines? Hunks that don't belong to a single commit are left uncommitted",
+		}, &i18n.Message{
+			ID:    "AbsorbingStatus",
+			Other: "absorbing",
+		}, &i18n.Message{
+			ID:    "CantAbsorbMidOperation",
+			Other: "You can't absorb changes while a merge, rebase, cherry-pick or revert is in progress",
+		}, &i18n.Message{
+			ID:    "NothingToAbsorb",
+			Other: "None of the staged hunks change lines last changed by a single commit in the commits panel",
+		}, &i18n.Message{
+			ID:    "CommitAuthor",
+			Other: "Author",
+		}, &i18n.Message{
+			ID:    "CommitCommitter",
+			Other: "Committer",
+		}, &i18n.Message{
+			ID:    "CommitRefs",
+			Other: "Refs",
+		}, &i18n.Message{
+			ID:    "CommitContainedIn",
+			Other: "Branches",
+		}, &i18n.Message{
+			ID:    "CommitSignature",
+			Other: "Signature",
+		}, &i18n.Message{
+			ID:    "CommitTrailers",
+			Other: "Trailers",
+		}, &i18n.Message{
+			ID:    "AndMore",
+			Other: "and {{.count}} more",
+		}, &i18n.Message{
+			ID:    "NotSigned",
+			Other: "not signed",
+		}, &i18n.Message{
+			ID:    "SignatureGood",
+			Other: "good signature",
+		}, &i18n.Message{
+			ID:    "SignatureBad",
+			Other: "bad signature",
+		}, &i18n.Message{
+			ID:    "SignatureUnknownValidity",
+			Other: "good signature of unknown validity",
+		}, &i18n.Message{
+			ID:    "SignatureExpired",
+			Other: "good signature, but expired",
+		}, &i18n.Message{
+			ID:    "SignatureRevoked",
+			Other: "good signature, but the key is revoked",
+		}, &i18n.Message{
+			ID:    "SignatureUncheckable",
+			Other: "can't check the signature",
+		}, &i18n.Message{
+			ID:    "SignedBy",
+			Other: "by {{.signer}}",
+		}, &i18n.Message{
+			ID:    "applyPatchFile",
+			Other: "apply a patch file",
+		}, &i18n.Message{
+			ID:    "viewPatchFileOptions",
+			Other: "export commits as patch files / apply a patch file",
+		}, &i18n.Message{
+			ID:    "PatchFilesTitle",
+			Other: "Patch files",
+		}, &i18n.Message{
+			ID:    "exportCommitAsPatch",
+			Other: "export {{.sha}} as a patch file",
+		}, &i18n.Message{
+			ID:    "exportCommitsAsPatches",
+			Other: "export {{.sha}} and the commits after it as patch files",
+		}, &i18n.Message{
+			ID:    "applyPatchFileAsCommits",
+			Other: "make commits from a patch file (git am)",
+		}, &i18n.Message{
+			ID:    "PatchDirectoryTitle",
+			Other: "Directory to write the patch files to:",
+		}, &i18n.Message{
+			ID:    "PatchFileTitle",
+			Other: "Path of the patch file:",
+		}, &i18n.Message{
+			ID:    "ExportedPatches",
+			Other: "Wrote {{.count}} patch file(s) to {{.directory}}",
+		}, &i18n.Message{
+			ID:    "ApplyingPatchStatus",
+			Other: "applying patch",
+		}, &i18n.Message{
+			ID:    "gotoCommit",
+			Other: "go to a commit by sha or ref",
+		}, &i18n.Message{
+			ID:    "GotoCommitTitle",
+			Other: "Go to commit (sha, tag, or e.g. HEAD~20):",
+		}, &i18n.Message{
+			ID:    "NoSuchCommit",
+			Other: "There's no commit '{{.ref}}'",
+		}, &i18n.Message{
+			ID:    "CommitNotInHistory",
+			Other: "'{{.ref}}' isn't in this branch's history",
+		}, &i18n.Message{
+			ID:    "CommitNotInList",
+			Other: "That commit isn't among the commits shown",
+		}, &i18n.Message{
+			ID:    "ReflogTitle",
+			Other: "Reflog",
+		}, &i18n.Message{
+			ID:    "NoReflogEntries",
+			Other: "No reflog entries",
+		}, &i18n.Message{
+			ID:    "nextTab",
+			Other: "next tab",
+		}, &i18n.Message{
+			ID:    "prevTab",
+			Other: "previous tab",
+		}, &i18n.Message{
+			ID:    "checkoutReflogEntry",
+			Other: "checkout commit",
+		}, &i18n.Message{
+			ID:    "newBranchAtReflogEntry",
+			Other: "new branch at commit",
+		}, &i18n.Message{
+			ID:    "cherryPickReflogEntry",
+			Other: "cherry-pick commit",
+		}, &i18n.Message{
+			ID:    "hardResetToReflogEntry",
+			Other: "hard reset to commit",
+		}, &i18n.Message{
+			ID:    "backToCommits",
+			Other: "back to commits",
+		}, &i18n.Message{
+			ID:    "SureCherryPickReflogEntry",
+			Other: "Are you sure you want to cherry-pick {{.sha}} onto this branch?",
+		}, &i18n.Message{
+			ID:    "FoundCherryPickConflicts",
+			Other: "Cherry-picking {{.sha}} ({{.name}}) stopped with conflicts. Press 'enter' to resolve them in the files panel, then 'm' to continue or to skip this commit. To abort the cherry-pick press 'esc'",
+		}, &i18n.Message{
+			ID:    "FoundRevertConflicts",
+			Other: "Reverting {{.sha}} ({{.name}}) stopped with conflicts. Press 'enter' to resolve them in the files panel, then 'm' to continue or to skip this commit. To abort the revert press 'esc'",
+		}, &i18n.Message{
+			ID:    "commitDateOptions",
+			Other: "change how commit dates are shown",
+		}, &i18n.Message{
+			ID:    "CommitDateTitle",
+			Other: "Commit dates",
+		}, &i18n.Message{
+			ID:    "hideCommitDates",
+			Other: "don't show dates",
+		}, &i18n.Message{
+			ID:    "showRelativeCommitDates",
+			Other: "show relative dates (e.g. 3 hours ago)",
+		}, &i18n.Message{
+			ID:    "showShortCommitDates",
+			Other: "show short dates (e.g. 2020-01-02)",
+		}, &i18n.Message{
+			ID:    "showFullCommitDates",
+			Other: "show full dates and times",
+		}, &i18n.Message{
+			ID:    "useAuthorDates",
+			Other: "show when commits were authored",
+		}, &i18n.Message{
+			ID:    "useCommitterDates",
+			Other: "show when commits were committed",
+		}, &i18n.Message{
+			ID:    "toggleCommitMarked",
+			Other: "mark/unmark commit for squashing, fixing up or dropping with other marked commits (esc to unmark all)",
+		}, &i18n.Message{
+			ID:    "CantMarkRebasingCommit",
+			Other: "You can't mark a commit that's still to be rebased, change its action in the todo instead",
+		}, &i18n.Message{
+			ID:    "SureSquashMarkedCommits",
+			Other: "Are you sure you want to squash the {{.count}} marked commits into the commits below them?",
+		}, &i18n.Message{
+			ID:    "SureFixupMarkedCommits",
+			Other: "Are you sure you want to fixup the {{.count}} marked commits? Their messages will be discarded.",
+		}, &i18n.Message{
+			ID:    "SureDropMarkedCommits",
+			Other: "Are you sure you want to delete the {{.count}} marked commits?",
+		}, &i18n.Message{
+			ID:    "showContainingRefs",
+			Other: "show branches and tags containing commit",
+		}, &i18n.Message{
+			ID:    "ContainingRefsTitle",
+			Other: "Branches and tags containing {{.sha}}",
+		}, &i18n.Message{
+			ID:    "NoRefsContainCommit",
+			Other: "No branches or tags contain {{.sha}}",
+		}, &i18n.Message{
+			ID:    "CommitSubjectTooLong",
+			Other: "subject is {{.length}} characters, over {{.limit}}",
+		}, &i18n.Message{
+			ID:    "CommitLineTooLong",
+			Other: "line {{.line}} is {{.length}} characters, over {{.limit}}",
+		}, &i18n.Message{
+			ID:    "CommitLineTrailingWhitespace",
+			Other: "line {{.line}} ends in whitespace",
+		}, &i18n.Message{
+			ID:    "CommitLintTitle",
+			Other: "Commit message lint",
+		}, &i18n.Message{
+			ID:    "CommitLintPrompt",
+			Other: "{{.output}}\n\nCommit with this message anyway?",
+		}, &i18n.Message{
+			ID:    "deleteLocalBranch",
+			Other: "delete local branch {{.branch}}",
+		}, &i18n.Message{
+			ID:    "deleteRemoteBranch",
+			Other: "delete remote branch {{.remoteBranch}}",
+		}, &i18n.Message{
+			ID:    "deleteLocalAndRemoteBranch",
+			Other: "delete both",
+		}, &i18n.Message{
+			ID:    "DeleteRemoteBranchMessage",
+			Other: "Are you sure you want to delete the remote branch {{.remoteBranch}}?",
+		}, &i18n.Message{
+			ID:    "DeleteLocalAndRemoteBranchMessage",
+			Other: "Are you sure you want to delete the branch {{.branch}} and the remote branch {{.remoteBranch}}?",
+		}, &i18n.Message{
+			ID:    "DeletingRemoteBranchStatus",
+			Other: "Deleting remote branch...",
+		}, &i18n.Message{
+			ID:    "ForceDeleteBranchUnreachableMessage",
+			Other: "{{.selectedBranchName}} is not fully merged, and {{.count}} of its commits aren't on any other branch or tag, so they'll be lost. Are you sure you want to force delete it?",
+		}, &i18n.Message{
+			ID:    "renameBranch",
+			Other: "rename branch",
+		}, &i18n.Message{
+			ID:    "RenameBranchTitle",
+			Other: "Rename {{.branch}} to:",
+		}, &i18n.Message{
+			ID:    "RenameRemoteBranchTitle",
+			Other: "Rename remote branch",
+		}, &i18n.Message{
+			ID:    "RenameRemoteBranchPrompt",
+			Other: "Do you also want to rename {{.remoteBranch}}? We'll push {{.branch}} to {{.remote}}, delete {{.remoteBranch}} and make {{.branch}} track the new remote branch.",
+		}, &i18n.Message{
+			ID:    "RenamingRemoteBranchStatus",
+			Other: "Renaming remote branch...",
+		}, &i18n.Message{
+			ID:    "setUpstream",
+			Other: "set/unset upstream",
+		}, &i18n.Message{
+			ID:    "SetUpstreamTitle",
+			Other: "Upstream of {{.branch}}",
+		}, &i18n.Message{
+			ID:    "unsetUpstream",
+			Other: "unset upstream ({{.upstream}})",
+		}, &i18n.Message{
+			ID:    "NoRemoteBranches",
+			Other: "There are no remote branches to track. Fetch a remote first",
+		}, &i18n.Message{
+			ID:    "MergeOptionsMenuTitle",
+			Other: "Merge {{.selectedBranch}} into {{.checkedOutBranch}}",
+		}, &i18n.Message{
+			ID:    "SquashMergeStaged",
+			Other: "The changes have been staged. Commit them to finish the squash merge",
+		}, &i18n.Message{
+			ID:    "toggleCompareBase",
+			Other: "mark/unmark as base to compare branches against",
+		}, &i18n.Message{
+			ID:    "BranchesComparingTitle",
+			Other: "Branches - comparing against {{.base}}",
+		}, &i18n.Message{
+			ID:    "CompareFilesTitle",
+			Other: "Files changed {{.base}}...{{.ref}}",
+		}, &i18n.Message{
+			ID:    "CantChangeComparedFiles",
+			Other: "You can't do that with the files of a comparison, as they're not from a single commit",
+		}, &i18n.Message{
+			ID:    "filterBranches",
+			Other: "filter branches",
+		}, &i18n.Message{
+			ID:    "FilterBranchesTitle",
+			Other: "Filter branches by (empty to show them all):",
+		}, &i18n.Message{
+			ID:    "BranchesFilteredSuffix",
+			Other: "matching '{{.filter}}'",
+		}, &i18n.Message{
+			ID:    "sortBranches",
+			Other: "sort branches",
+		}, &i18n.Message{
+			ID:    "SortBranchesTitle",
+			Other: "Sort branches by",
+		}, &i18n.Message{
+			ID:    "sortBranchesByRecency",
+			Other: "when last checked out",
+		}, &i18n.Message{
+			ID:    "sortBranchesByDate",
+			Other: "date of last commit",
+		}, &i18n.Message{
+			ID:    "sortBranchesByName",
+			Other: "name",
+		}, &i18n.Message{
+			ID:    "newBranchAtCommit",
+			Other: "new branch off this commit",
+		}, &i18n.Message{
+			ID:    "newBranchFromStash",
+			Other: "new branch with this stash entry",
+		}, &i18n.Message{
+			ID:    "newBranchFromRef",
+			Other: "new branch off a branch, tag or commit",
+		}, &i18n.Message{
+			ID:    "NewBranchBaseTitle",
+			Other: "Branch off (branch, tag or commit):",
+		}, &i18n.Message{
+			ID:    "createBranchHere",
+			Other: "create a branch here",
+		}, &i18n.Message{
+			ID:    "returnToBranch",
+			Other: "return to {{.branchName}}",
+		}, &i18n.Message{
+			ID:    "DetachedHeadMenuTitle",
+			Other: "HEAD detached at {{.sha}}",
+		}, &i18n.Message{
+			ID:    "CantDoOnDetachedHead",
+			Other: "You can't do this while HEAD is detached. Create a branch first",
+		}, &i18n.Message{
+			ID:    "PullOptionsMenuTitle",
+			Other: "Pull with",
+		}, &i18n.Message{
+			ID:    "RemoteBranchesTitle",
+			Other: "Remote Branches",
+		}, &i18n.Message{
+			ID:    "NoRemoteTrackingBranches",
+			Other: "No remote branches. Fetch a remote to see its branches",
+		}, &i18n.Message{
+			ID:    "checkoutRemoteBranch",
+			Other: "checkout as a local branch",
+		}, &i18n.Message{
+			ID:    "backToBranches",
+			Other: "back to branches",
+		}, &i18n.Message{
+			ID:    "deleteFromRemote",
+			Other: "delete from the remote",
+		}, &i18n.Message{
+			ID:    "ConflictsNotFromOperation",
+			Other: "These conflicts aren't from a merge, rebase, cherry-pick or revert, so there's nothing to continue or abort. Resolve them and stage the files",
+		}, &i18n.Message{
+			ID:    "PatchFilesChanged",
+			Other: "Some files have changed since you selected lines from them, so those lines have been deselected",
 		},
 	)
 }
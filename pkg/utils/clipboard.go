@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/go-errors/errors"
+)
+
+// ErrNoClipboard is returned when none of the clipboard programs we know of is
+// installed
+var ErrNoClipboard = errors.New("no clipboard program found")
+
+// clipboardCommands are the programs that read text from stdin and put it on
+// the clipboard, in the order we try them
+var clipboardCommands = map[string][][]string{
+	"darwin":  {{"pbcopy"}},
+	"windows": {{"clip"}},
+	"linux": {
+		{"xclip", "-selection", "clipboard"},
+		{"xsel", "--clipboard", "--input"},
+		{"wl-copy"},
+	},
+}
+
+// CopyToClipboard puts the text on the system clipboard
+func CopyToClipboard(text string) error {
+	args, err := getClipboardCommand(runtime.GOOS, exec.LookPath)
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = strings.NewReader(text)
+	// we don't capture the output: xclip stays in the background to serve the
+	// clipboard and would keep our pipes open
+	return cmd.Run()
+}
+
+// getClipboardCommand returns the first clipboard program for the platform
+// that lookPath can find. BSDs use the same programs as linux
+func getClipboardCommand(platform string, lookPath func(string) (string, error)) ([]string, error) {
+	candidates, ok := clipboardCommands[platform]
+	if !ok {
+		candidates = clipboardCommands["linux"]
+	}
+	for _, args := range candidates {
+		if _, err := lookPath(args[0]); err == nil {
+			return args, nil
+		}
+	}
+	return nil, ErrNoClipboard
+}
@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/go-errors/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGetClipboardCommand is a function.
+func TestGetClipboardCommand(t *testing.T) {
+	type scenario struct {
+		testName  string
+		platform  string
+		installed []string
+		expected  []string
+		err       error
+	}
+
+	scenarios := []scenario{
+		{
+			"Mac",
+			"darwin",
+			[]string{"pbcopy"},
+			[]string{"pbcopy"},
+			nil,
+		},
+		{
+			"Linux with xclip and xsel installed",
+			"linux",
+			[]string{"xsel", "xclip"},
+			[]string{"xclip", "-selection", "clipboard"},
+			nil,
+		},
+		{
+			"Linux with only wl-copy installed",
+			"linux",
+			[]string{"wl-copy"},
+			[]string{"wl-copy"},
+			nil,
+		},
+		{
+			"BSD uses the linux programs",
+			"freebsd",
+			[]string{"xsel"},
+			[]string{"xsel", "--clipboard", "--input"},
+			nil,
+		},
+		{
+			"Nothing installed",
+			"linux",
+			[]string{},
+			nil,
+			ErrNoClipboard,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			lookPath := func(name string) (string, error) {
+				if IncludesString(s.installed, name) {
+					return "/usr/bin/" + name, nil
+				}
+				return "", errors.New("not found")
+			}
+			args, err := getClipboardCommand(s.platform, lookPath)
+			assert.EqualValues(t, s.expected, args)
+			assert.Equal(t, s.err, err)
+		})
+	}
+}
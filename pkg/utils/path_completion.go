@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// CompleteDirectory completes the last part of a path to the directory it's
+// the start of, like a shell does when you press tab. If several directories
+// start with it we complete as far as they have in common. Files are left out
+// because this is for choosing where to put something
+func CompleteDirectory(path string) string {
+	dir, prefix := filepath.Split(path)
+	searchDir := dir
+	if searchDir == "" {
+		searchDir = "."
+	}
+	fileInfos, err := ioutil.ReadDir(searchDir)
+	if err != nil {
+		return path
+	}
+
+	matches := []string{}
+	for _, fileInfo := range fileInfos {
+		if fileInfo.IsDir() && strings.HasPrefix(fileInfo.Name(), prefix) {
+			matches = append(matches, fileInfo.Name())
+		}
+	}
+	if len(matches) == 0 {
+		return path
+	}
+	if len(matches) == 1 {
+		return dir + matches[0] + "/"
+	}
+	return dir + commonPrefix(matches)
+}
+
+// CompleteName completes the name to the one of the names it's the start of.
+// If several names start with it we complete as far as they have in common
+func CompleteName(name string, names []string) string {
+	matches := []string{}
+	for _, candidate := range names {
+		if strings.HasPrefix(candidate, name) {
+			matches = append(matches, candidate)
+		}
+	}
+	if len(matches) == 0 {
+		return name
+	}
+	return commonPrefix(matches)
+}
+
+// commonPrefix returns the longest string that all the given strings start with
+func commonPrefix(strs []string) string {
+	prefix := strs[0]
+	for _, str := range strs[1:] {
+		for !strings.HasPrefix(str, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCompleteDirectory is a function.
+func TestCompleteDirectory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lazygit-completion")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"pkg/gui", "pkg/guide", "pkg/commands", "docs"} {
+		assert.NoError(t, os.MkdirAll(filepath.Join(dir, name), 0755))
+	}
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "pkg/main.go"), []byte{}, 0644))
+
+	type scenario struct {
+		testName string
+		path     string
+		expected string
+	}
+
+	scenarios := []scenario{
+		{
+			"Single match",
+			"do",
+			"docs/",
+		},
+		{
+			"Several matches completed as far as they agree",
+			"pkg/g",
+			"pkg/gui",
+		},
+		{
+			"Files left out",
+			"pkg/m",
+			"pkg/m",
+		},
+		{
+			"No match",
+			"pkg/x",
+			"pkg/x",
+		},
+		{
+			"Missing directory",
+			"vendor/x",
+			"vendor/x",
+		},
+		{
+			"Nothing after the slash",
+			"pkg/",
+			"pkg/",
+		},
+	}
+
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+	assert.NoError(t, os.Chdir(dir))
+	defer func() { _ = os.Chdir(wd) }()
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			assert.EqualValues(t, s.expected, CompleteDirectory(s.path))
+		})
+	}
+}
+
+// TestCompleteName is a function.
+func TestCompleteName(t *testing.T) {
+	names := []string{"master", "feature/bar", "feature/baz", "origin/master"}
+
+	type scenario struct {
+		testName string
+		name     string
+		expected string
+	}
+
+	scenarios := []scenario{
+		{"Single match", "ma", "master"},
+		{"Several matches completed as far as they agree", "f", "feature/ba"},
+		{"No match", "x", "x"},
+		{"Already complete", "origin/master", "origin/master"},
+		{"Nothing typed", "", ""},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.testName, func(t *testing.T) {
+			assert.EqualValues(t, s.expected, CompleteName(s.name, names))
+		})
+	}
+}
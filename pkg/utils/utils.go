@@ -251,3 +251,36 @@ func AsJson(i interface{}) string {
 	bytes, _ := json.MarshalIndent(i, "", "    ")
 	return string(bytes)
 }
+
+// FormatBytes returns a size in bytes the way people read it, e.g. "1.5 MB"
+func FormatBytes(size int64) string {
+	if size < 1024 {
+		return fmt.Sprintf("%d B", size)
+	}
+	value := float64(size)
+	units := []string{"KB", "MB", "GB", "TB"}
+	unit := ""
+	for _, unit = range units {
+		value /= 1024
+		if value < 1024 {
+			break
+		}
+	}
+	return fmt.Sprintf("%.1f %s", value, unit)
+}
+
+// FuzzyMatch tells us whether the characters of the pattern appear in the
+// string in the same order, though not necessarily next to each other, e.g.
+// "fbar" matches "feature/bar". Case is ignored
+func FuzzyMatch(pattern, str string) bool {
+	remaining := []rune(strings.ToLower(pattern))
+	for _, char := range strings.ToLower(str) {
+		if len(remaining) == 0 {
+			break
+		}
+		if char == remaining[0] {
+			remaining = remaining[1:]
+		}
+	}
+	return len(remaining) == 0
+}
@@ -573,3 +573,47 @@ func TestAsJson(t *testing.T) {
 	// no idea why this is returning empty hashes but it's works in the app ¯\_(ツ)_/¯
 	assert.EqualValues(t, "{}", output)
 }
+
+// TestFormatBytes is a function.
+func TestFormatBytes(t *testing.T) {
+	type scenario struct {
+		size     int64
+		expected string
+	}
+
+	scenarios := []scenario{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KB"},
+		{1536, "1.5 KB"},
+		{5 * 1024 * 1024, "5.0 MB"},
+		{3 * 1024 * 1024 * 1024 * 1024 * 1024, "3072.0 TB"},
+	}
+
+	for _, s := range scenarios {
+		assert.EqualValues(t, s.expected, FormatBytes(s.size))
+	}
+}
+
+// TestFuzzyMatch is a function.
+func TestFuzzyMatch(t *testing.T) {
+	type scenario struct {
+		pattern  string
+		str      string
+		expected bool
+	}
+
+	scenarios := []scenario{
+		{"", "master", true},
+		{"mast", "master", true},
+		{"fbar", "feature/bar", true},
+		{"FBAR", "feature/Bar", true},
+		{"barf", "feature/bar", false},
+		{"masterx", "master", false},
+		{"ü", "büro", true},
+	}
+
+	for _, s := range scenarios {
+		assert.EqualValues(t, s.expected, FuzzyMatch(s.pattern, s.str))
+	}
+}